@@ -40,6 +40,7 @@ const backendDevArgs = [
   `--apiserver-host=${conf.backend.apiServerHost}`,
   `--port=${conf.backend.devServerPort}`,
   `--heapster-host=${conf.backend.heapsterServerHost}`,
+  '--enable-privileged-fallback',
 ];
 
 /**
@@ -51,6 +52,7 @@ const backendArgs = [
   `--apiserver-host=${conf.backend.apiServerHost}`,
   `--port=${conf.frontend.serverPort}`,
   `--heapster-host=${conf.backend.heapsterServerHost}`,
+  '--enable-privileged-fallback',
 ];
 
 /**
@@ -0,0 +1,182 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cert provides TLS certificates of the dashboard server.
+package cert
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"net"
+	"time"
+
+	"k8s.io/kubernetes/pkg/api"
+	k8serrors "k8s.io/kubernetes/pkg/api/errors"
+	client "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
+)
+
+// CertificateValidity is how long generated certificates are valid.
+const CertificateValidity = 365 * 24 * time.Hour
+
+// Certificates that expire sooner than this are regenerated.
+const renewBefore = 30 * 24 * time.Hour
+
+// GenerateSelfSignedCertificate returns PEM encoded certificate and private key that are valid
+// for the given hosts, either DNS names or IP addresses.
+func GenerateSelfSignedCertificate(hosts []string, validity time.Duration) ([]byte, []byte,
+	error) {
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, err
+	}
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	notBefore := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{CommonName: "kubernetes-dashboard"},
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.Add(validity),
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return certPEM, keyPEM, nil
+}
+
+// GetOrCreateCertificateSecret returns PEM encoded certificate and private key stored in the TLS
+// secret with the given name. A self-signed certificate is generated and stored in the secret if
+// it does not exist, or if its certificate expires soon. This way all replicas of the dashboard
+// serve the same certificate, which survives restarts.
+func GetOrCreateCertificateSecret(client client.Interface, namespace, name string,
+	hosts []string) ([]byte, []byte, error) {
+
+	secret, err := client.Core().Secrets(namespace).Get(name)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return nil, nil, err
+	}
+	exists := err == nil
+	if exists {
+		certPEM, keyPEM := secret.Data[api.TLSCertKey], secret.Data[api.TLSPrivateKeyKey]
+		if expiry, err := GetCertificateExpiry(certPEM); err == nil &&
+			time.Now().Add(renewBefore).Before(expiry) {
+			log.Printf("Using certificate from %s secret in %s namespace", name, namespace)
+			return certPEM, keyPEM, nil
+		}
+	}
+
+	log.Printf("Generating self-signed certificate and storing it in %s secret in %s namespace",
+		name, namespace)
+	certPEM, keyPEM, err := GenerateSelfSignedCertificate(hosts, CertificateValidity)
+	if err != nil {
+		return nil, nil, err
+	}
+	data := map[string][]byte{api.TLSCertKey: certPEM, api.TLSPrivateKeyKey: keyPEM}
+
+	if !exists {
+		_, err = client.Core().Secrets(namespace).Create(&api.Secret{
+			ObjectMeta: api.ObjectMeta{Name: name, Namespace: namespace},
+			Type:       api.SecretTypeTLS,
+			Data:       data,
+		})
+	} else {
+		secret.Data = data
+		_, err = client.Core().Secrets(namespace).Update(secret)
+	}
+	if k8serrors.IsAlreadyExists(err) || k8serrors.IsConflict(err) {
+		// Another replica has stored its certificate in the meantime. Use the stored one, so that
+		// all replicas serve the same certificate.
+		secret, err = client.Core().Secrets(namespace).Get(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		log.Printf("Using certificate from %s secret in %s namespace", name, namespace)
+		return secret.Data[api.TLSCertKey], secret.Data[api.TLSPrivateKeyKey], nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return certPEM, keyPEM, nil
+}
+
+// GetCertificateExpiry returns time after which the PEM encoded certificate is no longer valid.
+func GetCertificateExpiry(certPEM []byte) (time.Time, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return time.Time{}, errors.New("No PEM encoded certificate found")
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return certificate.NotAfter, nil
+}
+
+// NewTLSConfig returns TLS configuration of a server with the PEM encoded certificate and private
+// key. When the client CA file is given, clients have to present a certificate signed by one of
+// the CAs from the file.
+func NewTLSConfig(certPEM, keyPEM []byte, clientCAFile string) (*tls.Config, error) {
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if clientCAFile != "" {
+		caPEM, err := ioutil.ReadFile(clientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("No PEM encoded certificates found in %s", clientCAFile)
+		}
+		config.ClientCAs = clientCAs
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return config, nil
+}
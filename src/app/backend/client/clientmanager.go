@@ -0,0 +1,242 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	client "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
+	"k8s.io/kubernetes/pkg/client/restclient"
+	"k8s.io/kubernetes/pkg/client/unversioned/clientcmd"
+	clientcmdapi "k8s.io/kubernetes/pkg/client/unversioned/clientcmd/api"
+)
+
+// Headers used to authenticate users of the dashboard.
+const (
+	AuthorizationHeader    = "Authorization"
+	ImpersonateUserHeader  = "Impersonate-User"
+	ImpersonateGroupHeader = "Impersonate-Group"
+)
+
+var (
+	// ErrMissingCredentials is returned when a request carries no credentials and requests
+	// without credentials are not allowed to use privileges of the dashboard.
+	ErrMissingCredentials = errors.New("Request has no credentials. Provide a bearer token in " +
+		"the Authorization header.")
+
+	// ErrInvalidAuthorization is returned when the Authorization header of a request does not
+	// carry a bearer token.
+	ErrInvalidAuthorization = errors.New("Only bearer tokens are supported in the " +
+		"Authorization header.")
+
+	// ErrUntrustedImpersonation is returned when impersonation headers are sent by a client that
+	// is not a trusted authenticating proxy.
+	ErrUntrustedImpersonation = errors.New("Impersonation headers are accepted only from " +
+		"trusted proxies.")
+)
+
+// ClientManager creates apiserver clients that act on behalf of users of the dashboard, so that
+// apiserver authorization applies to each of them.
+type ClientManager struct {
+	// Config of the dashboard. Base for configs of users.
+	clientConfig clientcmd.ClientConfig
+	config       *restclient.Config
+
+	// Client with privileges of the dashboard.
+	privilegedClient *client.Clientset
+
+	// Networks of authenticating proxies that are allowed to send impersonation headers.
+	trustedProxies []*net.IPNet
+
+	// When true, requests without credentials use privileges of the dashboard.
+	enablePrivilegedFallback bool
+}
+
+// NewClientManager creates client manager. clientConfig and privilegedClient are the config and
+// client of the dashboard, see CreateApiserverClient.
+func NewClientManager(clientConfig clientcmd.ClientConfig, privilegedClient *client.Clientset,
+	trustedProxies []*net.IPNet, enablePrivilegedFallback bool) (*ClientManager, error) {
+
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ClientManager{
+		clientConfig:             clientConfig,
+		config:                   config,
+		privilegedClient:         privilegedClient,
+		trustedProxies:           trustedProxies,
+		enablePrivilegedFallback: enablePrivilegedFallback,
+	}, nil
+}
+
+// ClientForRequest returns apiserver client and its config that act on behalf of the user that
+// made the request. The user is identified by:
+//  1. Impersonate-User and Impersonate-Group headers set by a trusted authenticating proxy.
+//     Requests are made with credentials of the dashboard, which have to allow impersonation.
+//  2. Bearer token from the Authorization header, which is passed to the apiserver.
+//  3. Dashboard itself, when the request has no credentials and privileged fallback is enabled.
+func (self *ClientManager) ClientForRequest(request *http.Request) (*client.Clientset,
+	clientcmd.ClientConfig, error) {
+
+	if user := request.Header.Get(ImpersonateUserHeader); user != "" ||
+		len(request.Header[ImpersonateGroupHeader]) > 0 {
+
+		if !self.isTrustedProxy(request.RemoteAddr) {
+			return nil, nil, ErrUntrustedImpersonation
+		}
+		return self.clientForConfig(
+			impersonatingConfig(*self.config, user, request.Header[ImpersonateGroupHeader]))
+	}
+
+	if authorization := request.Header.Get(AuthorizationHeader); authorization != "" {
+		parts := strings.SplitN(authorization, " ", 2)
+		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" ||
+			strings.TrimSpace(parts[1]) == "" {
+			return nil, nil, ErrInvalidAuthorization
+		}
+		return self.clientForConfig(bearerTokenConfig(*self.config, strings.TrimSpace(parts[1])))
+	}
+
+	if self.enablePrivilegedFallback {
+		return self.privilegedClient, self.clientConfig, nil
+	}
+	return nil, nil, ErrMissingCredentials
+}
+
+// PrivilegedClient returns client with privileges of the dashboard.
+func (self *ClientManager) PrivilegedClient() *client.Clientset {
+	return self.privilegedClient
+}
+
+func (self *ClientManager) clientForConfig(config *restclient.Config) (*client.Clientset,
+	clientcmd.ClientConfig, error) {
+
+	k8sClient, err := client.NewForConfig(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	clientConfig := requestClientConfig{dashboardClientConfig: self.clientConfig, config: config}
+	return k8sClient, clientConfig, nil
+}
+
+// isTrustedProxy returns true when remoteAddr, in the host:port format, belongs to one of the
+// trusted proxy networks.
+func (self *ClientManager) isTrustedProxy(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, network := range self.trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// bearerTokenConfig returns copy of the given config that authenticates with the token only.
+func bearerTokenConfig(config restclient.Config, token string) *restclient.Config {
+	config.Username = ""
+	config.Password = ""
+	config.Impersonate = ""
+	config.AuthProvider = nil
+	config.AuthConfigPersister = nil
+	config.CertFile = ""
+	config.KeyFile = ""
+	config.CertData = nil
+	config.KeyData = nil
+	config.BearerToken = token
+	return &config
+}
+
+// impersonatingConfig returns copy of the given config that impersonates the user and groups.
+func impersonatingConfig(config restclient.Config, user string,
+	groups []string) *restclient.Config {
+
+	wrapTransport := config.WrapTransport
+	config.Impersonate = ""
+	config.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+		if wrapTransport != nil {
+			rt = wrapTransport(rt)
+		}
+		return &impersonatingRoundTripper{user: user, groups: groups, delegate: rt}
+	}
+	return &config
+}
+
+// impersonatingRoundTripper sets impersonation headers on all requests.
+type impersonatingRoundTripper struct {
+	user     string
+	groups   []string
+	delegate http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rt *impersonatingRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	// Requests must not be modified by round trippers.
+	impersonating := new(http.Request)
+	*impersonating = *request
+	impersonating.Header = make(http.Header)
+	for key, values := range request.Header {
+		impersonating.Header[key] = values
+	}
+
+	impersonating.Header.Del(ImpersonateUserHeader)
+	impersonating.Header.Del(ImpersonateGroupHeader)
+	if rt.user != "" {
+		impersonating.Header.Set(ImpersonateUserHeader, rt.user)
+	}
+	for _, group := range rt.groups {
+		impersonating.Header.Add(ImpersonateGroupHeader, group)
+	}
+	return rt.delegate.RoundTrip(impersonating)
+}
+
+// requestClientConfig is a client config of a single user. It returns config of that user and
+// delegates everything else to the client config of the dashboard.
+type requestClientConfig struct {
+	dashboardClientConfig clientcmd.ClientConfig
+	config                *restclient.Config
+}
+
+// ClientConfig returns config of the user.
+func (self requestClientConfig) ClientConfig() (*restclient.Config, error) {
+	config := *self.config
+	return &config, nil
+}
+
+// RawConfig returns raw config of the dashboard.
+func (self requestClientConfig) RawConfig() (clientcmdapi.Config, error) {
+	return self.dashboardClientConfig.RawConfig()
+}
+
+// Namespace returns default namespace of the dashboard.
+func (self requestClientConfig) Namespace() (string, bool, error) {
+	return self.dashboardClientConfig.Namespace()
+}
+
+// ConfigAccess returns config access of the dashboard.
+func (self requestClientConfig) ConfigAccess() clientcmd.ConfigAccess {
+	return self.dashboardClientConfig.ConfigAccess()
+}
@@ -15,8 +15,12 @@
 package client
 
 import (
+	"encoding/json"
+	"fmt"
 	"log"
+	"strings"
 
+	heapster "k8s.io/heapster/metrics/api/v1/types"
 	client "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
 	"k8s.io/kubernetes/pkg/client/restclient"
 )
@@ -81,3 +85,98 @@ func CreateHeapsterRESTClient(heapsterHost string, apiclient *client.Clientset)
 	log.Printf("Creating remote Heapster client for %s", heapsterHost)
 	return RemoteHeapsterClient{client: restClient.Core().RESTClient()}, nil
 }
+
+// HeapsterMetricsProviderImpl is a metrics provider that downloads metrics from the model API of
+// Heapster.
+type HeapsterMetricsProviderImpl struct {
+	client HeapsterClient
+}
+
+// NewHeapsterMetricsProvider creates metrics provider that talks with Heapster using the given
+// client.
+func NewHeapsterMetricsProvider(client HeapsterClient) MetricsProvider {
+	return HeapsterMetricsProviderImpl{client: client}
+}
+
+// DownloadMetric downloads metric from Heapster. Metrics of all pods from the query are downloaded
+// in one request, nodes and namespaces are downloaded one by one in parallel.
+func (self HeapsterMetricsProviderImpl) DownloadMetric(query MetricQuery) ([]MetricSamples, error) {
+	result := make([]MetricSamples, len(query.ResourceNames))
+	if len(query.ResourceNames) == 0 {
+		return result, nil
+	}
+
+	switch query.ResourceKind {
+	case PodMetricResource:
+		rawResults := heapster.MetricResultList{}
+		path := fmt.Sprintf("/model/namespaces/%s/pod-list/%s/metrics/%s", query.Namespace,
+			strings.Join(query.ResourceNames, ","), query.MetricName)
+		if err := self.unmarshalType(path, &rawResults); err != nil {
+			return nil, err
+		}
+		if len(rawResults.Items) != len(query.ResourceNames) {
+			return nil, fmt.Errorf("Received invalid number of resources from heapster. "+
+				"Expected %d received %d", len(query.ResourceNames), len(rawResults.Items))
+		}
+		for i, rawResult := range rawResults.Items {
+			result[i] = samplesFromMetricResult(rawResult)
+		}
+		return result, nil
+	case NodeMetricResource:
+		return self.downloadEach(query, "/model/nodes/%s/metrics/%s")
+	case NamespaceMetricResource:
+		return self.downloadEach(query, "/model/namespaces/%s/metrics/%s")
+	default:
+		return nil, fmt.Errorf("Resource %s is not supported by heapster metrics provider",
+			query.ResourceKind)
+	}
+}
+
+// downloadEach downloads metric for each resource from the query in a separate request. Path
+// format has to take resource name and metric name.
+func (self HeapsterMetricsProviderImpl) downloadEach(query MetricQuery, pathFormat string) (
+	[]MetricSamples, error) {
+
+	result := make([]MetricSamples, len(query.ResourceNames))
+	errors := make(chan error, len(query.ResourceNames))
+	for i, name := range query.ResourceNames {
+		go func(i int, name string) {
+			rawResult := heapster.MetricResult{}
+			err := self.unmarshalType(fmt.Sprintf(pathFormat, name, query.MetricName), &rawResult)
+			if err == nil {
+				result[i] = samplesFromMetricResult(rawResult)
+			}
+			errors <- err
+		}(i, name)
+	}
+
+	var err error
+	for range query.ResourceNames {
+		if e := <-errors; e != nil {
+			err = e
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// unmarshalType performs heapster GET request to the specified path and transfers the data to the
+// interface provided.
+func (self HeapsterMetricsProviderImpl) unmarshalType(path string, v interface{}) error {
+	rawData, err := self.client.Get(path).DoRaw()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(rawData, v)
+}
+
+// samplesFromMetricResult converts metric points used by heapster to metric samples.
+func samplesFromMetricResult(raw heapster.MetricResult) MetricSamples {
+	samples := make(MetricSamples, 0, len(raw.Metrics))
+	for _, point := range raw.Metrics {
+		samples = append(samples, MetricSample{Timestamp: point.Timestamp, Value: point.Value})
+	}
+	return samples
+}
@@ -0,0 +1,100 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"fmt"
+	"time"
+
+	client "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
+)
+
+// Names of supported metrics providers.
+const (
+	HeapsterMetricsProvider   = "heapster"
+	PrometheusMetricsProvider = "prometheus"
+)
+
+// Kinds of resources that metrics can be downloaded for.
+const (
+	PodMetricResource       = "pod"
+	NodeMetricResource      = "node"
+	NamespaceMetricResource = "namespace"
+)
+
+// Names of metrics that every metrics provider supports.
+const (
+	// CPU usage rate in millicores.
+	CPUUsageMetric = "cpu/usage_rate"
+	// Memory usage in bytes.
+	MemoryUsageMetric = "memory/usage"
+)
+
+// MetricsProvider is a source of resource usage metrics, e.g., Heapster or Prometheus.
+type MetricsProvider interface {
+	// DownloadMetric downloads recent samples of a metric for each resource selected by the
+	// query. The result has one entry for each of query.ResourceNames, in the same order.
+	// Resources that have no samples get an empty entry.
+	DownloadMetric(query MetricQuery) ([]MetricSamples, error)
+}
+
+// MetricQuery selects a metric of one or more resources of the same kind.
+type MetricQuery struct {
+	// Name of the metric, e.g., CPUUsageMetric.
+	MetricName string
+
+	// Kind of the resources. One of the *MetricResource constants.
+	ResourceKind string
+
+	// Namespace of the pods. Not used for other kinds of resources.
+	Namespace string
+
+	// Names of the resources.
+	ResourceNames []string
+}
+
+// MetricSample is a value of a metric measured at some moment.
+type MetricSample struct {
+	Timestamp time.Time
+	Value     uint64
+}
+
+// MetricSamples is a list of metric samples sorted by time.
+type MetricSamples []MetricSample
+
+// CreateMetricsProvider creates metrics provider of the given name. Host of the provider is
+// in the format of protocol://address:port, e.g., http://localhost:8082. When it is empty, the
+// provider is reached through the service proxy of the apiserver.
+func CreateMetricsProvider(name string, heapsterHost string, prometheusHost string,
+	apiclient *client.Clientset) (MetricsProvider, error) {
+
+	switch name {
+	case HeapsterMetricsProvider:
+		heapsterClient, err := CreateHeapsterRESTClient(heapsterHost, apiclient)
+		if err != nil {
+			return nil, err
+		}
+		return NewHeapsterMetricsProvider(heapsterClient), nil
+	case PrometheusMetricsProvider:
+		prometheusClient, err := CreatePrometheusRESTClient(prometheusHost, apiclient)
+		if err != nil {
+			return nil, err
+		}
+		return NewPrometheusMetricsProvider(prometheusClient), nil
+	default:
+		return nil, fmt.Errorf("Unknown metrics provider %s, expected one of: %s, %s", name,
+			HeapsterMetricsProvider, PrometheusMetricsProvider)
+	}
+}
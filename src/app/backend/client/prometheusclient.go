@@ -0,0 +1,271 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	client "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
+	"k8s.io/kubernetes/pkg/client/restclient"
+)
+
+const (
+	// Period of time for which metric samples are downloaded from Prometheus. Matches the period
+	// for which Heapster keeps metrics in its model.
+	prometheusQueryRange = 15 * time.Minute
+	// Resolution of metric samples downloaded from Prometheus.
+	prometheusQueryStep = time.Minute
+)
+
+// PrometheusClient is a client used to make requests to a Prometheus instance.
+type PrometheusClient interface {
+	// Creates a new HTTP request to the range query endpoint of the V1 API of Prometheus. The
+	// query param is a PromQL expression evaluated from start to end with the given step.
+	QueryRange(query string, start, end time.Time, step time.Duration) RequestInterface
+}
+
+// InClusterPrometheusClient is an in-cluster implementation of a Prometheus client. Talks with
+// Prometheus through service proxy.
+type InClusterPrometheusClient struct {
+	client restclient.Interface
+}
+
+// QueryRange creates range query request.
+func (c InClusterPrometheusClient) QueryRange(query string, start, end time.Time,
+	step time.Duration) RequestInterface {
+
+	request := c.client.Get().Prefix("proxy").
+		Namespace("kube-system").
+		Resource("services").
+		Name("prometheus").
+		Suffix("/api/v1/query_range")
+	return withRangeQueryParams(request, query, start, end, step)
+}
+
+// RemotePrometheusClient is an implementation of a remote Prometheus client. Talks with Prometheus
+// through raw RESTClient.
+type RemotePrometheusClient struct {
+	client restclient.Interface
+}
+
+// QueryRange creates range query request.
+func (c RemotePrometheusClient) QueryRange(query string, start, end time.Time,
+	step time.Duration) RequestInterface {
+
+	return withRangeQueryParams(c.client.Get().Suffix("query_range"), query, start, end, step)
+}
+
+// withRangeQueryParams adds parameters of range query to the request.
+func withRangeQueryParams(request *restclient.Request, query string, start, end time.Time,
+	step time.Duration) *restclient.Request {
+
+	return request.Param("query", query).
+		Param("start", strconv.FormatInt(start.Unix(), 10)).
+		Param("end", strconv.FormatInt(end.Unix(), 10)).
+		Param("step", strconv.FormatInt(int64(step.Seconds()), 10))
+}
+
+// CreatePrometheusRESTClient creates new Prometheus REST client. When prometheusHost param is
+// empty string the function assumes that it is running inside a Kubernetes cluster and connects
+// via service proxy. prometheusHost param is in the format of protocol://address:port,
+// e.g., http://localhost:9090.
+func CreatePrometheusRESTClient(prometheusHost string, apiclient *client.Clientset) (
+	PrometheusClient, error) {
+
+	if prometheusHost == "" {
+		log.Print("Creating in-cluster Prometheus client")
+		return InClusterPrometheusClient{client: apiclient.Core().RESTClient()}, nil
+	}
+
+	cfg := &restclient.Config{Host: prometheusHost, QPS: defaultQPS, Burst: defaultBurst}
+	restClient, err := client.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Creating remote Prometheus client for %s", prometheusHost)
+	return RemotePrometheusClient{client: restClient.Core().RESTClient()}, nil
+}
+
+// prometheusResourceLabels maps resource kinds to the labels of cAdvisor metrics that identify
+// resources of that kind.
+var prometheusResourceLabels = map[string]string{
+	PodMetricResource:       "pod_name",
+	NodeMetricResource:      "instance",
+	NamespaceMetricResource: "namespace",
+}
+
+// prometheusMetricQueries maps metric names to PromQL expressions computing them. The expression
+// takes a label selector of cAdvisor metrics and a label to sum by.
+var prometheusMetricQueries = map[string]string{
+	// Rate of used CPU seconds is converted to millicores.
+	CPUUsageMetric:    "sum(rate(container_cpu_usage_seconds_total{%s}[2m])) by (%s) * 1000",
+	MemoryUsageMetric: "sum(container_memory_usage_bytes{%s}) by (%s)",
+}
+
+// PrometheusMetricsProviderImpl is a metrics provider that issues PromQL range queries over
+// metrics that Prometheus scrapes from cAdvisor.
+type PrometheusMetricsProviderImpl struct {
+	client PrometheusClient
+}
+
+// NewPrometheusMetricsProvider creates metrics provider that talks with Prometheus using the given
+// client.
+func NewPrometheusMetricsProvider(client PrometheusClient) MetricsProvider {
+	return PrometheusMetricsProviderImpl{client: client}
+}
+
+// prometheusQueryResponse is a response of Prometheus range query endpoint.
+type prometheusQueryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			// Pairs of unix timestamp in seconds and a value formatted as a string.
+			Values [][]interface{} `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// DownloadMetric downloads metric of all resources from the query with a single range query.
+func (self PrometheusMetricsProviderImpl) DownloadMetric(query MetricQuery) ([]MetricSamples, error) {
+	result := make([]MetricSamples, len(query.ResourceNames))
+	if len(query.ResourceNames) == 0 {
+		return result, nil
+	}
+
+	promQL, err := CreatePromQLQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	end := time.Now()
+	rawData, err := self.client.QueryRange(promQL, end.Add(-prometheusQueryRange), end,
+		prometheusQueryStep).DoRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	response := prometheusQueryResponse{}
+	if err := json.Unmarshal(rawData, &response); err != nil {
+		return nil, err
+	}
+	if response.Status != "success" {
+		return nil, fmt.Errorf("Prometheus query failed: %s", response.Error)
+	}
+	if response.Data.ResultType != "matrix" {
+		return nil, fmt.Errorf("Received unexpected result type from prometheus: %s",
+			response.Data.ResultType)
+	}
+
+	samplesByName := make(map[string]MetricSamples)
+	for _, series := range response.Data.Result {
+		samples, err := samplesFromPrometheusValues(series.Values)
+		if err != nil {
+			return nil, err
+		}
+		samplesByName[series.Metric[prometheusResourceLabels[query.ResourceKind]]] = samples
+	}
+
+	for i, name := range query.ResourceNames {
+		if samples, ok := samplesByName[name]; ok {
+			result[i] = samples
+		} else {
+			result[i] = MetricSamples{}
+		}
+	}
+	return result, nil
+}
+
+// CreatePromQLQuery creates PromQL expression that computes metric of every resource from the
+// query. Result has one time series for each resource that has any samples, labeled with the
+// name of the resource.
+func CreatePromQLQuery(query MetricQuery) (string, error) {
+	queryFormat, ok := prometheusMetricQueries[query.MetricName]
+	if !ok {
+		return "", fmt.Errorf("Metric %s is not supported by prometheus metrics provider",
+			query.MetricName)
+	}
+	resourceLabel, ok := prometheusResourceLabels[query.ResourceKind]
+	if !ok {
+		return "", fmt.Errorf("Resource %s is not supported by prometheus metrics provider",
+			query.ResourceKind)
+	}
+
+	names := make([]string, len(query.ResourceNames))
+	for i, name := range query.ResourceNames {
+		names[i] = regexp.QuoteMeta(name)
+	}
+	matchers := []string{fmt.Sprintf(`%s=~"%s"`, resourceLabel,
+		escapePromQLString(strings.Join(names, "|")))}
+
+	switch query.ResourceKind {
+	case NodeMetricResource:
+		// Root cgroup holds usage of the whole node.
+		matchers = append(matchers, `id="/"`)
+	case PodMetricResource:
+		matchers = append([]string{fmt.Sprintf(`namespace="%s"`,
+			escapePromQLString(query.Namespace))}, matchers...)
+		fallthrough
+	default:
+		// Skip infrastructure containers and cgroups that aggregate usage of whole pods.
+		matchers = append(matchers, `container_name!~"POD|"`)
+	}
+
+	return fmt.Sprintf(queryFormat, strings.Join(matchers, ","), resourceLabel), nil
+}
+
+// escapePromQLString escapes the value so that it can be put into double-quoted PromQL string.
+func escapePromQLString(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+}
+
+// samplesFromPrometheusValues converts values of a Prometheus time series to metric samples.
+func samplesFromPrometheusValues(values [][]interface{}) (MetricSamples, error) {
+	samples := make(MetricSamples, 0, len(values))
+	for _, value := range values {
+		if len(value) != 2 {
+			return nil, fmt.Errorf("Received invalid sample from prometheus: %v", value)
+		}
+		timestamp, ok := value[0].(float64)
+		if !ok {
+			return nil, fmt.Errorf("Received invalid sample timestamp from prometheus: %v", value[0])
+		}
+		rawValue, ok := value[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("Received invalid sample value from prometheus: %v", value[1])
+		}
+		parsed, err := strconv.ParseFloat(rawValue, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		sample := MetricSample{Timestamp: time.Unix(int64(timestamp), 0)}
+		// Metric values are non-negative, negative and unknown values are treated as zero.
+		if parsed > 0 && !math.IsNaN(parsed) && !math.IsInf(parsed, 0) {
+			sample.Value = uint64(parsed)
+		}
+		samples = append(samples, sample)
+	}
+	return samples, nil
+}
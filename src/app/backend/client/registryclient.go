@@ -0,0 +1,447 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	distreference "github.com/docker/distribution/reference"
+	"k8s.io/kubernetes/pkg/api"
+	k8serrors "k8s.io/kubernetes/pkg/api/errors"
+)
+
+const (
+	// Host of the registry of images that have no registry in their reference.
+	DefaultRegistryHost = "registry-1.docker.io"
+
+	// Timeout of requests to image registries.
+	registryRequestTimeout = 10 * time.Second
+
+	// Maximal number of tag pages that are followed when tags of a repository are listed.
+	maxRegistryTagPages = 10
+)
+
+// registryManifestMediaTypes are media types of the manifests that are accepted when a manifest
+// is looked up. Registries answer with 404 when the manifest is not in any of them.
+var registryManifestMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v1+prettyjws",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.oci.image.index.v1+json",
+}
+
+// ErrRegistryUnauthorized is returned when the registry denies access to the repository, i.e.,
+// the credentials are missing or wrong, or the repository does not exist.
+var ErrRegistryUnauthorized = errors.New("access to the repository was denied by the registry")
+
+// RegistryError is returned when the registry, or its token server, can not be reached or
+// responds in an unexpected way.
+type RegistryError struct {
+	// Host of the registry or of its token server.
+	Host string
+
+	// Error that the request to the host failed with.
+	Err error
+}
+
+// Error returns message of the error of the request.
+func (self *RegistryError) Error() string {
+	return self.Err.Error()
+}
+
+// RegistryCredentials are credentials used to authenticate to an image registry.
+type RegistryCredentials struct {
+	Username string
+	Password string
+}
+
+// ImageManifest describes manifest of an image in a registry.
+type ImageManifest struct {
+	// True when the registry has a manifest for the image reference.
+	Exists bool `json:"exists"`
+
+	// Digest of the manifest, e.g., sha256:ab12..., if it exists.
+	Digest string `json:"digest"`
+}
+
+// RegistryClient is a client used to make requests to image registries that implement the Docker
+// Registry v2 API. Credentials are optional, anonymous requests are made when they are nil.
+type RegistryClient interface {
+	// GetManifest looks up manifest of the given image reference.
+	GetManifest(reference string, credentials *RegistryCredentials) (*ImageManifest, error)
+
+	// ListTags returns sorted tags of the repository of the given image reference.
+	ListTags(reference string, credentials *RegistryCredentials) ([]string, error)
+}
+
+// RegistryClientImpl is an implementation of the registry client that talks to registries over
+// HTTPS.
+type RegistryClientImpl struct {
+	client *http.Client
+}
+
+// NewRegistryClient creates registry client that makes requests with the given HTTP client. When
+// it is nil, a client with default timeout is used.
+func NewRegistryClient(client *http.Client) RegistryClient {
+	if client == nil {
+		client = &http.Client{Timeout: registryRequestTimeout}
+	}
+	return RegistryClientImpl{client: client}
+}
+
+// GetManifest looks up manifest of the given image reference. Images without tag and digest are
+// looked up by the latest tag.
+func (self RegistryClientImpl) GetManifest(reference string,
+	credentials *RegistryCredentials) (*ImageManifest, error) {
+
+	named, err := distreference.ParseNamed(reference)
+	if err != nil {
+		return nil, err
+	}
+	host, repository := SplitImageName(named.Name())
+
+	version := "latest"
+	if digested, ok := named.(distreference.Digested); ok {
+		version = digested.Digest().String()
+	} else if tagged, ok := named.(distreference.Tagged); ok {
+		version = tagged.Tag()
+	}
+
+	log.Printf("Looking up manifest of %s:%s in %s registry", repository, version, host)
+	request, err := http.NewRequest("HEAD",
+		fmt.Sprintf("https://%s/v2/%s/manifests/%s", host, repository, version), nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Accept", strings.Join(registryManifestMediaTypes, ", "))
+
+	response, err := self.do(request, repository, credentials)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		return &ImageManifest{Exists: true,
+			Digest: response.Header.Get("Docker-Content-Digest")}, nil
+	case http.StatusNotFound:
+		return &ImageManifest{Exists: false}, nil
+	}
+	return nil, getRegistryError(host, response)
+}
+
+// registryTagsResponse is a response of the tags endpoint of the registry.
+type registryTagsResponse struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+// ListTags returns sorted tags of the repository of the given image reference. Tag and digest of
+// the reference are ignored. Pages of tags are followed up to a limit.
+func (self RegistryClientImpl) ListTags(reference string,
+	credentials *RegistryCredentials) ([]string, error) {
+
+	named, err := distreference.ParseNamed(reference)
+	if err != nil {
+		return nil, err
+	}
+	host, repository := SplitImageName(named.Name())
+
+	log.Printf("Listing tags of %s in %s registry", repository, host)
+	tags := make([]string, 0)
+	next := fmt.Sprintf("https://%s/v2/%s/tags/list", host, repository)
+	for page := 0; page < maxRegistryTagPages && len(next) > 0; page++ {
+		request, err := http.NewRequest("GET", next, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := self.do(request, repository, credentials)
+		if err != nil {
+			return nil, err
+		}
+		if response.StatusCode != http.StatusOK {
+			err := getRegistryError(host, response)
+			response.Body.Close()
+			return nil, err
+		}
+
+		tagsResponse := registryTagsResponse{}
+		err = json.NewDecoder(response.Body).Decode(&tagsResponse)
+		response.Body.Close()
+		if err != nil {
+			return nil, &RegistryError{Host: host, Err: err}
+		}
+		tags = append(tags, tagsResponse.Tags...)
+
+		next, err = getNextPageURL(request.URL, response.Header.Get("Link"))
+		if err != nil {
+			return nil, &RegistryError{Host: host, Err: err}
+		}
+	}
+
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// do makes the request to the registry. When the registry challenges the request, it is
+// authenticated as the challenge asks and made again.
+func (self RegistryClientImpl) do(request *http.Request, repository string,
+	credentials *RegistryCredentials) (*http.Response, error) {
+
+	response, err := self.client.Do(request)
+	if err != nil {
+		return nil, &RegistryError{Host: request.URL.Host, Err: err}
+	}
+	if response.StatusCode != http.StatusUnauthorized {
+		return response, nil
+	}
+	challenge := response.Header.Get("WWW-Authenticate")
+	io.Copy(ioutil.Discard, response.Body)
+	response.Body.Close()
+
+	scheme, params := parseRegistryChallenge(challenge)
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if credentials == nil {
+			return nil, ErrRegistryUnauthorized
+		}
+		request.SetBasicAuth(credentials.Username, credentials.Password)
+	case "bearer":
+		token, err := self.getToken(params, repository, credentials)
+		if err != nil {
+			return nil, err
+		}
+		request.Header.Set("Authorization", "Bearer "+token)
+	default:
+		return nil, &RegistryError{Host: request.URL.Host, Err: fmt.Errorf(
+			"registry %s asked for unsupported authentication: %s", request.URL.Host, challenge)}
+	}
+
+	response, err = self.client.Do(request)
+	if err != nil {
+		return nil, &RegistryError{Host: request.URL.Host, Err: err}
+	}
+	if response.StatusCode == http.StatusUnauthorized ||
+		response.StatusCode == http.StatusForbidden {
+		response.Body.Close()
+		return nil, ErrRegistryUnauthorized
+	}
+	return response, nil
+}
+
+// registryTokenResponse is a response of the token server of the registry.
+type registryTokenResponse struct {
+	Token       string `json:"token"`
+	AccessToken string `json:"access_token"`
+}
+
+// getToken gets a bearer token that allows to pull from the repository from the token server that
+// the registry has named in its challenge.
+func (self RegistryClientImpl) getToken(params map[string]string, repository string,
+	credentials *RegistryCredentials) (string, error) {
+
+	realm, err := url.Parse(params["realm"])
+	if err != nil || !realm.IsAbs() {
+		return "", &RegistryError{Err: fmt.Errorf(
+			"registry asked for a token from invalid realm %q", params["realm"])}
+	}
+	query := realm.Query()
+	if service, ok := params["service"]; ok {
+		query.Set("service", service)
+	}
+	query.Set("scope", fmt.Sprintf("repository:%s:pull", repository))
+	realm.RawQuery = query.Encode()
+
+	request, err := http.NewRequest("GET", realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if credentials != nil {
+		request.SetBasicAuth(credentials.Username, credentials.Password)
+	}
+	response, err := self.client.Do(request)
+	if err != nil {
+		return "", &RegistryError{Host: realm.Host, Err: err}
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusUnauthorized ||
+		response.StatusCode == http.StatusForbidden {
+		return "", ErrRegistryUnauthorized
+	}
+	if response.StatusCode != http.StatusOK {
+		return "", getRegistryError(realm.Host, response)
+	}
+
+	tokenResponse := registryTokenResponse{}
+	if err := json.NewDecoder(response.Body).Decode(&tokenResponse); err != nil {
+		return "", &RegistryError{Host: realm.Host, Err: err}
+	}
+	if len(tokenResponse.Token) > 0 {
+		return tokenResponse.Token, nil
+	}
+	if len(tokenResponse.AccessToken) > 0 {
+		return tokenResponse.AccessToken, nil
+	}
+	return "", &RegistryError{Host: realm.Host,
+		Err: fmt.Errorf("token server %s returned no token", realm.Host)}
+}
+
+// registryChallengeParamRegexp matches a single key="value" parameter of an authentication
+// challenge.
+var registryChallengeParamRegexp = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// parseRegistryChallenge returns scheme and parameters of the authentication challenge from the
+// WWW-Authenticate header, e.g., Bearer realm="https://auth.docker.io/token",service="x".
+func parseRegistryChallenge(challenge string) (string, map[string]string) {
+	parts := strings.SplitN(strings.TrimSpace(challenge), " ", 2)
+	params := make(map[string]string)
+	if len(parts) == 2 {
+		for _, match := range registryChallengeParamRegexp.FindAllStringSubmatch(parts[1], -1) {
+			params[strings.ToLower(match[1])] = match[2]
+		}
+	}
+	return parts[0], params
+}
+
+// registryLinkRegexp matches the URL of the next page in the Link header.
+var registryLinkRegexp = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// getNextPageURL returns absolute URL of the next page from the Link header of the response to
+// the request with the given URL, or empty string if it is the last page.
+func getNextPageURL(requestURL *url.URL, link string) (string, error) {
+	match := registryLinkRegexp.FindStringSubmatch(link)
+	if match == nil {
+		return "", nil
+	}
+	next, err := requestURL.Parse(match[1])
+	if err != nil {
+		return "", err
+	}
+	return next.String(), nil
+}
+
+// getRegistryError returns error describing unexpected response of the registry.
+func getRegistryError(host string, response *http.Response) error {
+	return &RegistryError{Host: host,
+		Err: fmt.Errorf("registry %s returned unexpected status: %s", host, response.Status)}
+}
+
+// SplitImageName splits name of an image, e.g., gcr.io/google_containers/pause, into host of the
+// registry and name of the repository in it. The first component of the name is a host when it
+// contains a dot or a port, or when it is localhost, as Docker does. Other names are in Docker
+// Hub, where images without a user are in the library repositories.
+func SplitImageName(name string) (string, string) {
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		if isDockerHubHost(parts[0]) {
+			return DefaultRegistryHost, parts[1]
+		}
+		return parts[0], parts[1]
+	}
+	if len(parts) == 1 {
+		return DefaultRegistryHost, "library/" + name
+	}
+	return DefaultRegistryHost, name
+}
+
+// isDockerHubHost returns true if the host is one of the names of Docker Hub registry.
+func isDockerHubHost(host string) bool {
+	return host == "docker.io" || host == "index.docker.io" || host == DefaultRegistryHost
+}
+
+// dockerConfigEntry is an entry of the docker config file with credentials of a registry.
+type dockerConfigEntry struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Auth     string `json:"auth"`
+}
+
+// GetRegistryCredentials returns credentials of the registry with the given host from the image
+// pull secret. Secrets of both dockercfg and dockerconfigjson type are supported. Nil is returned
+// when the secret has no credentials of the registry.
+func GetRegistryCredentials(secret *api.Secret, host string) (*RegistryCredentials, error) {
+	entries := make(map[string]dockerConfigEntry)
+	switch secret.Type {
+	case api.SecretTypeDockercfg:
+		if err := json.Unmarshal(secret.Data[api.DockerConfigKey], &entries); err != nil {
+			return nil, k8serrors.NewBadRequest(fmt.Sprintf(
+				"secret %s has invalid docker config: %s", secret.Name, err))
+		}
+	case api.SecretTypeDockerConfigJson:
+		config := struct {
+			Auths map[string]dockerConfigEntry `json:"auths"`
+		}{}
+		if err := json.Unmarshal(secret.Data[api.DockerConfigJsonKey], &config); err != nil {
+			return nil, k8serrors.NewBadRequest(fmt.Sprintf(
+				"secret %s has invalid docker config: %s", secret.Name, err))
+		}
+		entries = config.Auths
+	default:
+		return nil, k8serrors.NewBadRequest(fmt.Sprintf(
+			"secret %s is not an image pull secret, its type is %s", secret.Name, secret.Type))
+	}
+
+	for key, entry := range entries {
+		if getRegistryConfigHost(key) != host {
+			continue
+		}
+		if len(entry.Username) == 0 && len(entry.Auth) > 0 {
+			auth, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return nil, k8serrors.NewBadRequest(fmt.Sprintf(
+					"secret %s has invalid auth of %s: %s", secret.Name, key, err))
+			}
+			parts := strings.SplitN(string(auth), ":", 2)
+			if len(parts) != 2 {
+				return nil, k8serrors.NewBadRequest(fmt.Sprintf(
+					"secret %s has invalid auth of %s", secret.Name, key))
+			}
+			entry.Username, entry.Password = parts[0], parts[1]
+		}
+		return &RegistryCredentials{Username: entry.Username, Password: entry.Password}, nil
+	}
+	return nil, nil
+}
+
+// getRegistryConfigHost returns registry host of a key of the docker config file. Keys can be
+// hosts or URLs, e.g., https://index.docker.io/v1/.
+func getRegistryConfigHost(key string) string {
+	host := key
+	if index := strings.Index(host, "://"); index >= 0 {
+		host = host[index+3:]
+	}
+	if index := strings.Index(host, "/"); index >= 0 {
+		host = host[:index]
+	}
+	if isDockerHubHost(host) {
+		return DefaultRegistryHost
+	}
+	return host
+}
@@ -15,16 +15,24 @@
 package main
 
 import (
+	"crypto/tls"
+	"errors"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"log"
+	"net"
 	"net/http"
 	"os"
 
+	"github.com/kubernetes/dashboard/src/app/backend/cert"
 	"github.com/kubernetes/dashboard/src/app/backend/client"
 	"github.com/kubernetes/dashboard/src/app/backend/handler"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/pflag"
+	k8sClient "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
+	"k8s.io/kubernetes/pkg/util/wait"
 )
 
 var (
@@ -37,6 +45,50 @@ var (
 		"to connect to in the format of protocol://address:port, e.g., "+
 		"http://localhost:8082. If not specified, the assumption is that the binary runs inside a "+
 		"Kubernetes cluster and service proxy will be used.")
+	argMetricsProvider = pflag.String("metrics-provider", client.HeapsterMetricsProvider,
+		"The source of pod, node and namespace metrics. One of: heapster, prometheus.")
+	argPrometheusHost = pflag.String("prometheus-host", "", "The address of the Prometheus server "+
+		"to connect to in the format of protocol://address:port, e.g., "+
+		"http://localhost:9090. If not specified, the assumption is that the binary runs inside a "+
+		"Kubernetes cluster and service proxy will be used. Used only with prometheus metrics "+
+		"provider.")
+	argEnableResourceCache = pflag.Bool("enable-resource-cache", false, "When enabled, lists "+
+		"of pods, events, workloads, services, nodes and namespaces are kept in a cache that is "+
+		"updated by watching the apiserver. Requests are served from the cache instead of listing "+
+		"resources every time. Requests are sent directly to the apiserver until the cache is synced. "+
+		"Only requests made with credentials of the dashboard are served from the cache. Requests "+
+		"made with credentials of users, e.g., an Authorization header, always go to the "+
+		"apiserver, so that users see only resources they are allowed to list.")
+	argEnablePrivilegedFallback = pflag.Bool("enable-privileged-fallback", false, "When enabled, "+
+		"API requests that carry neither a bearer token nor impersonation headers are made with "+
+		"credentials of the dashboard. Otherwise they are rejected as unauthorized.")
+	argTrustedProxyCIDRs = pflag.StringSlice("trusted-proxy-cidrs", []string{}, "Comma separated "+
+		"list of networks, in CIDR notation, of authenticating proxies that are allowed to set "+
+		"Impersonate-User and Impersonate-Group headers, e.g., 10.0.0.0/8,127.0.0.1/32. "+
+		"Credentials of the dashboard have to allow impersonation of users and groups.")
+	argTLSCertFile = pflag.String("tls-cert-file", "", "File containing the PEM encoded "+
+		"certificate to serve HTTPS with. Requires --tls-key-file. If neither these files nor "+
+		"--auto-generate-certificates are given, plain HTTP is served.")
+	argTLSKeyFile = pflag.String("tls-key-file", "", "File containing the PEM encoded private "+
+		"key matching --tls-cert-file.")
+	argAutoGenerateCertificates = pflag.Bool("auto-generate-certificates", false, "When enabled "+
+		"and no certificate files are given, HTTPS is served with a self-signed certificate. The "+
+		"certificate is stored in the secret given by --certificate-secret, so that all replicas "+
+		"share it. It is regenerated when it is about to expire.")
+	argCertificateSecret = pflag.String("certificate-secret", "kubernetes-dashboard-certs",
+		"Name of the secret that holds the auto-generated certificate.")
+	argCertificateSecretNamespace = pflag.String("certificate-secret-namespace", "kube-system",
+		"Namespace of the secret that holds the auto-generated certificate.")
+	argClientCAFile = pflag.String("client-ca-file", "", "File containing PEM encoded "+
+		"certificates of CAs. When given, HTTPS clients have to present a certificate signed by "+
+		"one of them. Requires HTTPS to be enabled.")
+	argInsecureMetricsPort = pflag.Int("insecure-metrics-port", 0, "The port to serve the "+
+		"/metrics handler on over plain HTTP, e.g., for Prometheus scraping when HTTPS with client "+
+		"certificates is enabled. If 0, /metrics is served on --port.")
+	argEnableRegistryLookup = pflag.Bool("enable-registry-lookup", false, "When enabled, image "+
+		"references of deployed apps can be looked up in their registries over the Docker "+
+		"Registry v2 API, to check that their manifests exist and to list tags of their "+
+		"repositories. The dashboard has to be able to reach the registries.")
 	argKubeConfigFile = pflag.String("kubeconfig", "", "Path to kubeconfig file with authorization and master location information.")
 )
 
@@ -48,7 +100,7 @@ func main() {
 	pflag.Parse()
 	flag.CommandLine.Parse(make([]string, 0)) // Init for glog calls in kubernetes packages
 
-	log.Printf("Using HTTP port: %d", *argPort)
+	log.Printf("Using port: %d", *argPort)
 	if *argApiserverHost != "" {
 		log.Printf("Using apiserver-host location: %s", *argApiserverHost)
 	}
@@ -67,19 +119,123 @@ func main() {
 	}
 	log.Printf("Successful initial request to the apiserver, version: %s", versionInfo.String())
 
-	heapsterRESTClient, err := client.CreateHeapsterRESTClient(*argHeapsterHost, apiserverClient)
+	switch *argMetricsProvider {
+	case client.HeapsterMetricsProvider, client.PrometheusMetricsProvider:
+		log.Printf("Using metrics provider: %s", *argMetricsProvider)
+	default:
+		log.Fatalf("Unknown metrics provider: %s. Use one of: %s, %s", *argMetricsProvider,
+			client.HeapsterMetricsProvider, client.PrometheusMetricsProvider)
+	}
+	metricsProvider, err := client.CreateMetricsProvider(*argMetricsProvider, *argHeapsterHost,
+		*argPrometheusHost, apiserverClient)
+	if err != nil {
+		log.Printf("Could not create metrics provider: %s. Continuing.", err)
+	}
+
+	if *argEnableResourceCache {
+		log.Print("Starting resource cache")
+		resourceCache := common.NewResourceCache(apiserverClient)
+		resourceCache.Run(wait.NeverStop)
+		common.SetResourceCache(resourceCache)
+	}
+
+	trustedProxies := []*net.IPNet{}
+	for _, cidr := range *argTrustedProxyCIDRs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			log.Fatalf("Invalid trusted proxy CIDR %s: %s", cidr, err)
+		}
+		trustedProxies = append(trustedProxies, network)
+	}
+	if *argEnablePrivilegedFallback {
+		log.Print("Requests without credentials will be made with credentials of the dashboard")
+	}
+	clientManager, err := client.NewClientManager(config, apiserverClient, trustedProxies,
+		*argEnablePrivilegedFallback)
+	if err != nil {
+		handleFatalInitError(err)
+	}
+
+	var registryClient client.RegistryClient
+	if *argEnableRegistryLookup {
+		log.Print("Image registry lookup is enabled")
+		registryClient = client.NewRegistryClient(nil)
+	}
+
+	tlsConfig, err := createTLSConfig(apiserverClient)
 	if err != nil {
-		log.Printf("Could not create heapster client: %s. Continuing.", err)
+		log.Fatalf("Could not configure TLS: %s", err)
 	}
 
 	// Run a HTTP server that serves static public files from './public' and handles API calls.
 	// TODO(bryk): Disable directory listing.
 	http.Handle("/", handler.MakeGzipHandler(handler.CreateLocaleHandler()))
-	http.Handle("/api/", handler.CreateHTTPAPIHandler(apiserverClient, heapsterRESTClient, config))
+	http.Handle("/api/", handler.CreateHTTPAPIHandler(clientManager, metricsProvider,
+		registryClient))
 	// TODO(maciaszczykm): Move to /appConfig.json as it was discussed in #640.
 	http.Handle("/api/appConfig.json", handler.AppHandler(handler.ConfigHandler))
-	http.Handle("/metrics", prometheus.Handler())
-	log.Print(http.ListenAndServe(fmt.Sprintf(":%d", *argPort), nil))
+
+	if *argInsecureMetricsPort > 0 {
+		log.Printf("Serving metrics over HTTP on port: %d", *argInsecureMetricsPort)
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("/metrics", prometheus.Handler())
+		go func() {
+			log.Fatal(http.ListenAndServe(fmt.Sprintf(":%d", *argInsecureMetricsPort),
+				metricsMux))
+		}()
+	} else {
+		http.Handle("/metrics", prometheus.Handler())
+	}
+
+	server := &http.Server{Addr: fmt.Sprintf(":%d", *argPort), TLSConfig: tlsConfig}
+	if tlsConfig != nil {
+		log.Print("Serving HTTPS")
+		// Certificates are already in the TLS config.
+		log.Print(server.ListenAndServeTLS("", ""))
+	} else {
+		log.Print("Serving plain HTTP")
+		log.Print(server.ListenAndServe())
+	}
+}
+
+// createTLSConfig returns TLS config of the server based on the TLS flags, or nil if plain HTTP
+// is served.
+func createTLSConfig(apiserverClient *k8sClient.Clientset) (*tls.Config, error) {
+	var certPEM, keyPEM []byte
+	switch {
+	case *argTLSCertFile != "" || *argTLSKeyFile != "":
+		if *argTLSCertFile == "" || *argTLSKeyFile == "" {
+			return nil, errors.New("Both --tls-cert-file and --tls-key-file have to be given")
+		}
+		log.Printf("Using certificate file: %s", *argTLSCertFile)
+		var err error
+		if certPEM, err = ioutil.ReadFile(*argTLSCertFile); err != nil {
+			return nil, err
+		}
+		if keyPEM, err = ioutil.ReadFile(*argTLSKeyFile); err != nil {
+			return nil, err
+		}
+	case *argAutoGenerateCertificates:
+		namespace := *argCertificateSecretNamespace
+		hosts := []string{"localhost", "127.0.0.1", "kubernetes-dashboard",
+			"kubernetes-dashboard." + namespace, "kubernetes-dashboard." + namespace + ".svc"}
+		var err error
+		certPEM, keyPEM, err = cert.GetOrCreateCertificateSecret(apiserverClient, namespace,
+			*argCertificateSecret, hosts)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		if *argClientCAFile != "" {
+			return nil, errors.New("--client-ca-file requires HTTPS to be enabled")
+		}
+		return nil, nil
+	}
+
+	if *argClientCAFile != "" {
+		log.Printf("Requiring client certificates signed by CAs from: %s", *argClientCAFile)
+	}
+	return cert.NewTLSConfig(certPEM, keyPEM, *argClientCAFile)
 }
 
 /**
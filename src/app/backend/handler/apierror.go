@@ -0,0 +1,131 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	restful "github.com/emicklei/go-restful"
+	k8serrors "k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+)
+
+// APIError is the body of all error responses of the API. It carries the status code and the
+// reason of apiserver errors, so that clients can tell, e.g., a missing object from a denied
+// request.
+type APIError struct {
+	// HTTP status code of the error.
+	Code int32 `json:"code"`
+
+	// Machine-readable reason of the error, e.g., NotFound or Forbidden.
+	Reason unversioned.StatusReason `json:"reason"`
+
+	// Human-readable description of the error.
+	Message string `json:"message"`
+
+	// Details of the error, e.g., fields of an object that did not pass validation.
+	Causes []APIErrorCause `json:"causes,omitempty"`
+
+	// Number of seconds after which the request can be retried, if it is known.
+	RetryAfterSeconds int32 `json:"retryAfterSeconds,omitempty"`
+}
+
+// APIErrorCause is a single cause of an API error.
+type APIErrorCause struct {
+	// Machine-readable type of the cause, e.g., FieldValueRequired.
+	Type unversioned.CauseType `json:"type"`
+
+	// Human-readable description of the cause.
+	Message string `json:"message"`
+
+	// Path of the field that caused the error, e.g., spec.replicas, if any.
+	Field string `json:"field,omitempty"`
+}
+
+// Error implements the error interface.
+func (self *APIError) Error() string {
+	return self.Message
+}
+
+// Returns API error with the given status code and reason and the message of the given error.
+func newAPIError(code int32, reason unversioned.StatusReason, err error) *APIError {
+	return &APIError{Code: code, Reason: reason, Message: err.Error()}
+}
+
+// Maps the given error to an API error. Errors returned by the apiserver keep their status
+// code, reason and details. All other errors are internal errors.
+func toAPIError(err error) *APIError {
+	if apiError, ok := err.(*APIError); ok {
+		return apiError
+	}
+
+	status, ok := err.(k8serrors.APIStatus)
+	if !ok || status.Status().Code == 0 {
+		return newAPIError(http.StatusInternalServerError, unversioned.StatusReasonInternalError,
+			err)
+	}
+
+	result := newAPIError(status.Status().Code, status.Status().Reason, err)
+	if details := status.Status().Details; details != nil {
+		result.RetryAfterSeconds = details.RetryAfterSeconds
+		for _, cause := range details.Causes {
+			result.Causes = append(result.Causes, APIErrorCause{
+				Type:    cause.Type,
+				Message: cause.Message,
+				Field:   cause.Field,
+			})
+		}
+	}
+	if result.Reason == unversioned.StatusReasonUnknown {
+		result.Reason = getDefaultReason(result.Code)
+	}
+	return result
+}
+
+// Returns reason of errors with the given status code, for apiserver errors that do not set one.
+func getDefaultReason(code int32) unversioned.StatusReason {
+	switch code {
+	case http.StatusBadRequest:
+		return unversioned.StatusReasonBadRequest
+	case http.StatusUnauthorized:
+		return unversioned.StatusReasonUnauthorized
+	case http.StatusForbidden:
+		return unversioned.StatusReasonForbidden
+	case http.StatusNotFound:
+		return unversioned.StatusReasonNotFound
+	case http.StatusConflict:
+		return unversioned.StatusReasonConflict
+	case k8serrors.StatusUnprocessableEntity:
+		return unversioned.StatusReasonInvalid
+	case http.StatusServiceUnavailable:
+		return unversioned.StatusReasonServiceUnavailable
+	}
+	if code >= http.StatusInternalServerError {
+		return unversioned.StatusReasonInternalError
+	}
+	return unversioned.StatusReasonUnknown
+}
+
+// Writes the given API error to the response as JSON with its status code. Errors that can be
+// retried later also get the Retry-After header.
+func writeAPIError(response *restful.Response, apiError *APIError) {
+	log.Print(apiError.Message)
+	if apiError.RetryAfterSeconds > 0 {
+		response.AddHeader("Retry-After", strconv.Itoa(int(apiError.RetryAfterSeconds)))
+	}
+	response.WriteHeaderAndJson(int(apiError.Code), apiError, restful.MIME_JSON)
+}
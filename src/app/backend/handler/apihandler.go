@@ -15,7 +15,9 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"strconv"
@@ -34,6 +36,7 @@ import (
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/deployment"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/event"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/export"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/horizontalpodautoscaler/horizontalpodautoscalerdetail"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/horizontalpodautoscaler/horizontalpodautoscalerlist"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/ingress"
@@ -50,6 +53,7 @@ import (
 	"github.com/kubernetes/dashboard/src/app/backend/resource/replicaset/replicasetlist"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/replicationcontroller/replicationcontrollerdetail"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/replicationcontroller/replicationcontrollerlist"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/scaling"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/secret"
 	resourceService "github.com/kubernetes/dashboard/src/app/backend/resource/service"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/servicesanddiscovery"
@@ -57,8 +61,14 @@ import (
 	"github.com/kubernetes/dashboard/src/app/backend/resource/statefulset/statefulsetlist"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/workload"
 	"github.com/kubernetes/dashboard/src/app/backend/validation"
+	"golang.org/x/net/websocket"
+	"k8s.io/kubernetes/pkg/api"
+	k8serrors "k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/api/unversioned"
 	clientK8s "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
 	"k8s.io/kubernetes/pkg/client/unversioned/clientcmd"
+	"k8s.io/kubernetes/pkg/fields"
+	"k8s.io/kubernetes/pkg/labels"
 	"k8s.io/kubernetes/pkg/runtime"
 	utilnet "k8s.io/kubernetes/pkg/util/net"
 )
@@ -69,15 +79,54 @@ const (
 
 	// ResponseLogString is a template for response log message.
 	ResponseLogString = "[%s] Outcoming response to %s with %d status code"
+
+	// Names of request attributes that hold apiserver client and its configuration for the user
+	// that made the request.
+	clientAttribute       = "client"
+	clientConfigAttribute = "clientConfig"
 )
 
-// APIHandler is a representation of API handler. Structure contains client, Heapster client and
-// client configuration.
+// APIHandler is a representation of API handler. Structure contains client manager and metrics
+// provider.
 type APIHandler struct {
-	client         *clientK8s.Clientset
-	heapsterClient client.HeapsterClient
-	clientConfig   clientcmd.ClientConfig
-	verber         common.ResourceVerber
+	clientManager   *client.ClientManager
+	metricsProvider client.MetricsProvider
+	// Client of image registries, nil when registry lookup is disabled.
+	registryClient client.RegistryClient
+}
+
+// wsClient is a web-service filter function that creates apiserver client for the user that made
+// the request. Requests that fail to authenticate are rejected.
+func (apiHandler *APIHandler) wsClient(request *restful.Request, response *restful.Response,
+	chain *restful.FilterChain) {
+
+	k8sClient, clientConfig, err := apiHandler.clientManager.ClientForRequest(request.Request)
+	if err != nil {
+		handleAuthError(response, err)
+		return
+	}
+	request.SetAttribute(clientAttribute, k8sClient)
+	request.SetAttribute(clientConfigAttribute, clientConfig)
+	chain.ProcessFilter(request, response)
+}
+
+// getRequestClient returns apiserver client of the user that made the request.
+func getRequestClient(request *restful.Request) *clientK8s.Clientset {
+	return request.Attribute(clientAttribute).(*clientK8s.Clientset)
+}
+
+// getRequestClientConfig returns apiserver client configuration of the user that made the request.
+func getRequestClientConfig(request *restful.Request) clientcmd.ClientConfig {
+	return request.Attribute(clientConfigAttribute).(clientcmd.ClientConfig)
+}
+
+// getRequestVerber returns resource verber of the user that made the request.
+func getRequestVerber(request *restful.Request) *common.ResourceVerber {
+	k8sClient := getRequestClient(request)
+	verber := common.NewResourceVerber(k8sClient.Core().RESTClient(),
+		k8sClient.ExtensionsClient.RESTClient(), k8sClient.AppsClient.RESTClient(),
+		k8sClient.BatchClient.RESTClient(), k8sClient.AutoscalingClient.RESTClient())
+	return &verber
 }
 
 func wsMetrics(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
@@ -130,13 +179,12 @@ func FormatResponseLog(resp *restful.Response, req *restful.Request) string {
 }
 
 // CreateHTTPAPIHandler creates a new HTTP handler that handles all requests to the API of the backend.
-func CreateHTTPAPIHandler(client *clientK8s.Clientset, heapsterClient client.HeapsterClient,
-	clientConfig clientcmd.ClientConfig) http.Handler {
+// Each request is made to the apiserver with credentials of the user that sent it, see
+// client.ClientManager.
+func CreateHTTPAPIHandler(clientManager *client.ClientManager,
+	metricsProvider client.MetricsProvider, registryClient client.RegistryClient) http.Handler {
 
-	verber := common.NewResourceVerber(client.Core().RESTClient(),
-		client.ExtensionsClient.RESTClient(), client.AppsClient.RESTClient(),
-		client.BatchClient.RESTClient(), client.AutoscalingClient.RESTClient())
-	apiHandler := APIHandler{client, heapsterClient, clientConfig, verber}
+	apiHandler := APIHandler{clientManager, metricsProvider, registryClient}
 	wsContainer := restful.NewContainer()
 	wsContainer.EnableContentEncoding(true)
 
@@ -145,6 +193,7 @@ func CreateHTTPAPIHandler(client *clientK8s.Clientset, heapsterClient client.Hea
 
 	RegisterMetrics()
 	apiV1Ws.Filter(wsMetrics)
+	apiV1Ws.Filter(apiHandler.wsClient)
 	apiV1Ws.Path("/api/v1").
 		Consumes(restful.MIME_JSON).
 		Produces(restful.MIME_JSON)
@@ -154,7 +203,12 @@ func CreateHTTPAPIHandler(client *clientK8s.Clientset, heapsterClient client.Hea
 		apiV1Ws.POST("/appdeployment").
 			To(apiHandler.handleDeploy).
 			Reads(deployment.AppDeploymentSpec{}).
-			Writes(deployment.AppDeploymentSpec{}))
+			Writes(deployment.AppDeploymentResponse{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/appdeployment/validate").
+			To(apiHandler.handleAppDeploymentValidity).
+			Reads(deployment.AppDeploymentSpec{}).
+			Writes(validation.AppDeploymentValidity{}))
 	apiV1Ws.Route(
 		apiV1Ws.POST("/appdeployment/validate/name").
 			To(apiHandler.handleNameValidity).
@@ -165,6 +219,16 @@ func CreateHTTPAPIHandler(client *clientK8s.Clientset, heapsterClient client.Hea
 			To(apiHandler.handleImageReferenceValidity).
 			Reads(validation.ImageReferenceValiditySpec{}).
 			Writes(validation.ImageReferenceValidity{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/appdeployment/validate/imagemanifest").
+			To(apiHandler.handleImageManifestValidity).
+			Reads(validation.ImageLookupSpec{}).
+			Writes(validation.ImageManifestValidity{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/appdeployment/imagetags").
+			To(apiHandler.handleGetImageTags).
+			Reads(validation.ImageLookupSpec{}).
+			Writes(validation.ImageTags{}))
 	apiV1Ws.Route(
 		apiV1Ws.POST("/appdeployment/validate/protocol").
 			To(apiHandler.handleProtocolValidity).
@@ -184,10 +248,12 @@ func CreateHTTPAPIHandler(client *clientK8s.Clientset, heapsterClient client.Hea
 	apiV1Ws.Route(
 		apiV1Ws.GET("/replicationcontroller").
 			To(apiHandler.handleGetReplicationControllerList).
+			Produces(restful.MIME_JSON, export.MIMECSV, export.MIMEYAML).
 			Writes(replicationcontrollerlist.ReplicationControllerList{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/replicationcontroller/{namespace}").
 			To(apiHandler.handleGetReplicationControllerList).
+			Produces(restful.MIME_JSON, export.MIMECSV, export.MIMEYAML).
 			Writes(replicationcontrollerlist.ReplicationControllerList{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/replicationcontroller/{namespace}/{replicationController}").
@@ -245,10 +311,12 @@ func CreateHTTPAPIHandler(client *clientK8s.Clientset, heapsterClient client.Hea
 	apiV1Ws.Route(
 		apiV1Ws.GET("/replicaset").
 			To(apiHandler.handleGetReplicaSets).
+			Produces(restful.MIME_JSON, export.MIMECSV, export.MIMEYAML).
 			Writes(replicasetlist.ReplicaSetList{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/replicaset/{namespace}").
 			To(apiHandler.handleGetReplicaSets).
+			Produces(restful.MIME_JSON, export.MIMECSV, export.MIMEYAML).
 			Writes(replicasetlist.ReplicaSetList{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/replicaset/{namespace}/{replicaSet}").
@@ -266,10 +334,12 @@ func CreateHTTPAPIHandler(client *clientK8s.Clientset, heapsterClient client.Hea
 	apiV1Ws.Route(
 		apiV1Ws.GET("/pod").
 			To(apiHandler.handleGetPods).
+			Produces(restful.MIME_JSON, export.MIMECSV, export.MIMEYAML).
 			Writes(pod.PodList{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/pod/{namespace}").
 			To(apiHandler.handleGetPods).
+			Produces(restful.MIME_JSON, export.MIMECSV, export.MIMEYAML).
 			Writes(pod.PodList{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/pod/{namespace}/{pod}").
@@ -287,14 +357,42 @@ func CreateHTTPAPIHandler(client *clientK8s.Clientset, heapsterClient client.Hea
 		apiV1Ws.GET("/pod/{namespace}/{pod}/log/{container}").
 			To(apiHandler.handleLogs).
 			Writes(logs.Logs{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/pod/{namespace}/{pod}/logfile").
+			To(apiHandler.handleLogFile).
+			Produces("text/plain"))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/pod/{namespace}/{pod}/logfile/{container}").
+			To(apiHandler.handleLogFile).
+			Produces("text/plain"))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/pod/{namespace}/{pod}/logstream").
+			To(apiHandler.handleLogStream).
+			Writes(logs.LogLine{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/pod/{namespace}/{pod}/logstream/{container}").
+			To(apiHandler.handleLogStream).
+			Writes(logs.LogLine{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/pod/{namespace}/{pod}/shell").
+			To(apiHandler.handleExecShell).
+			Reads(container.TerminalMessage{}).
+			Writes(container.TerminalMessage{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/pod/{namespace}/{pod}/shell/{container}").
+			To(apiHandler.handleExecShell).
+			Reads(container.TerminalMessage{}).
+			Writes(container.TerminalMessage{}))
 
 	apiV1Ws.Route(
 		apiV1Ws.GET("/deployment").
 			To(apiHandler.handleGetDeployments).
+			Produces(restful.MIME_JSON, export.MIMECSV, export.MIMEYAML).
 			Writes(deployment.DeploymentList{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/deployment/{namespace}").
 			To(apiHandler.handleGetDeployments).
+			Produces(restful.MIME_JSON, export.MIMECSV, export.MIMEYAML).
 			Writes(deployment.DeploymentList{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/deployment/{namespace}/{deployment}").
@@ -308,14 +406,42 @@ func CreateHTTPAPIHandler(client *clientK8s.Clientset, heapsterClient client.Hea
 		apiV1Ws.GET("/deployment/{namespace}/{deployment}/oldreplicaset").
 			To(apiHandler.handleGetDeploymentOldReplicaSets).
 			Writes(replicasetlist.ReplicaSetList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/deployment/{namespace}/{deployment}/rollout").
+			To(apiHandler.handleGetDeploymentRolloutStatus).
+			Writes(deployment.RolloutStatus{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/deployment/{namespace}/{deployment}/rollout/restart").
+			To(apiHandler.handleRestartDeployment).
+			Writes(deployment.RolloutStatus{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/deployment/{namespace}/{deployment}/rollout/pause").
+			To(apiHandler.handlePauseDeployment).
+			Writes(deployment.RolloutStatus{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/deployment/{namespace}/{deployment}/rollout/resume").
+			To(apiHandler.handleResumeDeployment).
+			Writes(deployment.RolloutStatus{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/deployment/{namespace}/{deployment}/rollout/image").
+			To(apiHandler.handleSetDeploymentImage).
+			Reads(deployment.ContainerImageSpec{}).
+			Writes(deployment.RolloutStatus{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/deployment/{namespace}/{deployment}/rollout/rollback").
+			To(apiHandler.handleRollbackDeployment).
+			Reads(deployment.RollbackSpec{}).
+			Writes(deployment.RolloutStatus{}))
 
 	apiV1Ws.Route(
 		apiV1Ws.GET("/daemonset").
 			To(apiHandler.handleGetDaemonSetList).
+			Produces(restful.MIME_JSON, export.MIMECSV, export.MIMEYAML).
 			Writes(daemonsetlist.DaemonSetList{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/daemonset/{namespace}").
 			To(apiHandler.handleGetDaemonSetList).
+			Produces(restful.MIME_JSON, export.MIMECSV, export.MIMEYAML).
 			Writes(daemonsetlist.DaemonSetList{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/daemonset/{namespace}/{daemonSet}").
@@ -353,10 +479,12 @@ func CreateHTTPAPIHandler(client *clientK8s.Clientset, heapsterClient client.Hea
 	apiV1Ws.Route(
 		apiV1Ws.GET("/job").
 			To(apiHandler.handleGetJobList).
+			Produces(restful.MIME_JSON, export.MIMECSV, export.MIMEYAML).
 			Writes(joblist.JobList{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/job/{namespace}").
 			To(apiHandler.handleGetJobList).
+			Produces(restful.MIME_JSON, export.MIMECSV, export.MIMEYAML).
 			Writes(joblist.JobList{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/job/{namespace}/{job}").
@@ -379,54 +507,122 @@ func CreateHTTPAPIHandler(client *clientK8s.Clientset, heapsterClient client.Hea
 	apiV1Ws.Route(
 		apiV1Ws.GET("/namespace").
 			To(apiHandler.handleGetNamespaces).
+			Produces(restful.MIME_JSON, export.MIMECSV, export.MIMEYAML).
 			Writes(namespace.NamespaceList{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/namespace/{name}").
 			To(apiHandler.handleGetNamespaceDetail).
 			Writes(namespace.NamespaceDetail{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/namespace/{name}/usage").
+			To(apiHandler.handleGetNamespaceUsage).
+			Writes(namespace.NamespaceUsage{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/namespace/{name}/event").
 			To(apiHandler.handleGetNamespaceEvents).
 			Writes(common.EventList{}))
 
+	apiV1Ws.Route(
+		apiV1Ws.GET("/event").
+			To(apiHandler.handleGetEventList).
+			Produces(restful.MIME_JSON, export.MIMECSV, export.MIMEYAML).
+			Writes(common.EventList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/event/{namespace}").
+			To(apiHandler.handleGetEventList).
+			Produces(restful.MIME_JSON, export.MIMECSV, export.MIMEYAML).
+			Writes(common.EventList{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/eventstream").
+			To(apiHandler.handleEventStream).
+			Writes(common.Event{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/eventstream/{namespace}").
+			To(apiHandler.handleEventStream).
+			Writes(common.Event{}))
+
 	apiV1Ws.Route(
 		apiV1Ws.GET("/secret").
 			To(apiHandler.handleGetSecretList).
+			Produces(restful.MIME_JSON, export.MIMECSV, export.MIMEYAML).
 			Writes(secret.SecretList{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/secret/{namespace}").
 			To(apiHandler.handleGetSecretList).
+			Produces(restful.MIME_JSON, export.MIMECSV, export.MIMEYAML).
 			Writes(secret.SecretList{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/secret/{namespace}/{name}").
 			To(apiHandler.handleGetSecretDetail).
 			Writes(secret.SecretDetail{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/secret/{namespace}/{name}/key/{key}").
+			To(apiHandler.handleRevealSecretValue).
+			Writes(secret.SecretValue{}))
 	apiV1Ws.Route(
 		apiV1Ws.POST("/secret").
 			To(apiHandler.handleCreateImagePullSecret).
 			Reads(secret.ImagePullSecretSpec{}).
 			Writes(secret.Secret{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/secret/opaque").
+			To(apiHandler.handleCreateOpaqueSecret).
+			Reads(secret.OpaqueSecretSpec{}).
+			Writes(secret.Secret{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/secret/tls").
+			To(apiHandler.handleCreateTLSSecret).
+			Reads(secret.TLSSecretSpec{}).
+			Writes(secret.Secret{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/secret/basicauth").
+			To(apiHandler.handleCreateBasicAuthSecret).
+			Reads(secret.BasicAuthSecretSpec{}).
+			Writes(secret.Secret{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/secret/sshauth").
+			To(apiHandler.handleCreateSSHAuthSecret).
+			Reads(secret.SSHAuthSecretSpec{}).
+			Writes(secret.Secret{}))
 
 	apiV1Ws.Route(
 		apiV1Ws.GET("/configmap").
 			To(apiHandler.handleGetConfigMapList).
+			Produces(restful.MIME_JSON, export.MIMECSV, export.MIMEYAML).
 			Writes(configmap.ConfigMapList{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/configmap/{namespace}").
 			To(apiHandler.handleGetConfigMapList).
+			Produces(restful.MIME_JSON, export.MIMECSV, export.MIMEYAML).
 			Writes(configmap.ConfigMapList{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/configmap/{namespace}/{configmap}").
 			To(apiHandler.handleGetConfigMapDetail).
 			Writes(configmap.ConfigMapDetail{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/configmap").
+			To(apiHandler.handleCreateConfigMap).
+			Reads(configmap.ConfigMapSpec{}).
+			Writes(configmap.ConfigMapDetail{}))
+	apiV1Ws.Route(
+		apiV1Ws.PUT("/configmap/{namespace}/{configmap}/key/{key}").
+			To(apiHandler.handleSetConfigMapKey).
+			Reads(configmap.ConfigMapKeySpec{}).
+			Writes(configmap.ConfigMapDetail{}))
+	apiV1Ws.Route(
+		apiV1Ws.DELETE("/configmap/{namespace}/{configmap}/key/{key}").
+			To(apiHandler.handleDeleteConfigMapKey).
+			Writes(configmap.ConfigMapDetail{}))
 
 	apiV1Ws.Route(
 		apiV1Ws.GET("/service").
 			To(apiHandler.handleGetServiceList).
+			Produces(restful.MIME_JSON, export.MIMECSV, export.MIMEYAML).
 			Writes(resourceService.ServiceList{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/service/{namespace}").
 			To(apiHandler.handleGetServiceList).
+			Produces(restful.MIME_JSON, export.MIMECSV, export.MIMEYAML).
 			Writes(resourceService.ServiceList{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/service/{namespace}/{service}").
@@ -440,10 +636,12 @@ func CreateHTTPAPIHandler(client *clientK8s.Clientset, heapsterClient client.Hea
 	apiV1Ws.Route(
 		apiV1Ws.GET("/ingress").
 			To(apiHandler.handleGetIngressList).
+			Produces(restful.MIME_JSON, export.MIMECSV, export.MIMEYAML).
 			Writes(ingress.IngressList{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/ingress/{namespace}").
 			To(apiHandler.handleGetIngressList).
+			Produces(restful.MIME_JSON, export.MIMECSV, export.MIMEYAML).
 			Writes(ingress.IngressList{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/ingress/{namespace}/{name}").
@@ -453,10 +651,12 @@ func CreateHTTPAPIHandler(client *clientK8s.Clientset, heapsterClient client.Hea
 	apiV1Ws.Route(
 		apiV1Ws.GET("/statefulset").
 			To(apiHandler.handleGetStatefulSetList).
+			Produces(restful.MIME_JSON, export.MIMECSV, export.MIMEYAML).
 			Writes(statefulsetlist.StatefulSetList{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/statefulset/{namespace}").
 			To(apiHandler.handleGetStatefulSetList).
+			Produces(restful.MIME_JSON, export.MIMECSV, export.MIMEYAML).
 			Writes(statefulsetlist.StatefulSetList{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/statefulset/{namespace}/{statefulset}").
@@ -474,6 +674,7 @@ func CreateHTTPAPIHandler(client *clientK8s.Clientset, heapsterClient client.Hea
 	apiV1Ws.Route(
 		apiV1Ws.GET("/node").
 			To(apiHandler.handleGetNodeList).
+			Produces(restful.MIME_JSON, export.MIMECSV, export.MIMEYAML).
 			Writes(node.NodeList{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/node/{name}").
@@ -487,6 +688,22 @@ func CreateHTTPAPIHandler(client *clientK8s.Clientset, heapsterClient client.Hea
 		apiV1Ws.GET("/node/{name}/pod").
 			To(apiHandler.handleGetNodePods).
 			Writes(pod.PodList{}))
+	apiV1Ws.Route(
+		apiV1Ws.PUT("/node/{name}/cordon").
+			To(apiHandler.handleCordonNode).
+			Writes(node.Node{}))
+	apiV1Ws.Route(
+		apiV1Ws.PUT("/node/{name}/uncordon").
+			To(apiHandler.handleUncordonNode).
+			Writes(node.Node{}))
+	apiV1Ws.Route(
+		apiV1Ws.POST("/node/{name}/drain").
+			To(apiHandler.handleDrainNode).
+			Reads(node.DrainSpec{}).
+			Writes(node.DrainResult{}))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/node/{name}/drainstream").
+			To(apiHandler.handleDrainNodeStream))
 
 	apiV1Ws.Route(
 		apiV1Ws.DELETE("/{kind}/namespace/{namespace}/name/{name}").
@@ -497,10 +714,20 @@ func CreateHTTPAPIHandler(client *clientK8s.Clientset, heapsterClient client.Hea
 	apiV1Ws.Route(
 		apiV1Ws.PUT("/{kind}/namespace/{namespace}/name/{name}").
 			To(apiHandler.handlePutResource))
+	apiV1Ws.Route(
+		apiV1Ws.GET("/{kind}/namespace/{namespace}/name/{name}/scale").
+			To(apiHandler.handleGetReplicaCounts).
+			Writes(scaling.ReplicaCounts{}))
+	apiV1Ws.Route(
+		apiV1Ws.PUT("/{kind}/namespace/{namespace}/name/{name}/scale").
+			To(apiHandler.handleScaleResource).
+			Reads(scaling.ScaleSpec{}).
+			Writes(scaling.ReplicaCounts{}))
 
 	apiV1Ws.Route(
 		apiV1Ws.GET("/persistentvolume").
 			To(apiHandler.handleGetPersistentVolumeList).
+			Produces(restful.MIME_JSON, export.MIMECSV, export.MIMEYAML).
 			Writes(persistentvolume.PersistentVolumeList{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/persistentvolume/{persistentvolume}").
@@ -514,16 +741,23 @@ func CreateHTTPAPIHandler(client *clientK8s.Clientset, heapsterClient client.Hea
 	apiV1Ws.Route(
 		apiV1Ws.GET("/persistentvolumeclaim/").
 			To(apiHandler.handleGetPersistentVolumeClaimList).
+			Produces(restful.MIME_JSON, export.MIMECSV, export.MIMEYAML).
 			Writes(persistentvolumeclaim.PersistentVolumeClaimList{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/persistentvolumeclaim/{namespace}").
 			To(apiHandler.handleGetPersistentVolumeClaimList).
+			Produces(restful.MIME_JSON, export.MIMECSV, export.MIMEYAML).
 			Writes(persistentvolumeclaim.PersistentVolumeClaimList{}))
 	apiV1Ws.Route(
 		apiV1Ws.GET("/persistentvolumeclaim/{namespace}/{name}").
 			To(apiHandler.handleGetPersistentVolumeClaimDetail).
 			Writes(persistentvolumeclaim.PersistentVolumeClaimDetail{}))
 
+	apiV1Ws.Route(
+		apiV1Ws.GET("/cache").
+			To(apiHandler.handleGetResourceCacheStatus).
+			Writes(common.ResourceCacheStatus{}))
+
 	return wsContainer
 }
 
@@ -533,13 +767,18 @@ func (apiHandler *APIHandler) handleGetStatefulSetList(request *restful.Request,
 	namespace := parseNamespacePathParameter(request)
 	dataSelect := parseDataSelectPathParameter(request)
 	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := statefulsetlist.GetStatefulSetList(apiHandler.client, namespace, dataSelect, &apiHandler.heapsterClient)
+	listOptions, err := parseListOptions(request)
+	if err != nil {
+		handleBadRequestError(response, err)
+		return
+	}
+	result, err := statefulsetlist.GetStatefulSetList(getRequestClient(request), namespace, listOptions, dataSelect, &apiHandler.metricsProvider)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 
-	response.WriteHeaderAndEntity(http.StatusCreated, result)
+	writeList(request, response, http.StatusCreated, dataSelect, common.ResourceKindStatefulSet, result)
 }
 
 // Handles get pet set detail API call.
@@ -548,10 +787,10 @@ func (apiHandler *APIHandler) handleGetStatefulSetDetail(request *restful.Reques
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("statefulset")
 
-	result, err := statefulsetdetail.GetStatefulSetDetail(apiHandler.client, apiHandler.heapsterClient,
+	result, err := statefulsetdetail.GetStatefulSetDetail(getRequestClient(request), apiHandler.metricsProvider,
 		namespace, name)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 	response.WriteHeaderAndEntity(http.StatusCreated, result)
@@ -563,10 +802,10 @@ func (apiHandler *APIHandler) handleGetStatefulSetPods(request *restful.Request,
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("statefulset")
 	dataSelect := parseDataSelectPathParameter(request)
-	result, err := statefulsetdetail.GetStatefulSetPods(apiHandler.client, apiHandler.heapsterClient,
+	result, err := statefulsetdetail.GetStatefulSetPods(getRequestClient(request), apiHandler.metricsProvider,
 		dataSelect, name, namespace)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 	response.WriteHeaderAndEntity(http.StatusCreated, result)
@@ -578,10 +817,10 @@ func (apiHandler *APIHandler) handleGetStatefulSetEvents(request *restful.Reques
 	name := request.PathParameter("statefulset")
 	dataSelect := parseDataSelectPathParameter(request)
 
-	result, err := statefulsetdetail.GetStatefulSetEvents(apiHandler.client, dataSelect, namespace,
+	result, err := statefulsetdetail.GetStatefulSetEvents(getRequestClient(request), dataSelect, namespace,
 		name)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 	response.WriteHeaderAndEntity(http.StatusCreated, result)
@@ -591,13 +830,18 @@ func (apiHandler *APIHandler) handleGetStatefulSetEvents(request *restful.Reques
 func (apiHandler *APIHandler) handleGetServiceList(request *restful.Request, response *restful.Response) {
 	namespace := parseNamespacePathParameter(request)
 	dataSelect := parseDataSelectPathParameter(request)
-	result, err := resourceService.GetServiceList(apiHandler.client, namespace, dataSelect)
+	listOptions, err := parseListOptions(request)
+	if err != nil {
+		handleBadRequestError(response, err)
+		return
+	}
+	result, err := resourceService.GetServiceList(getRequestClient(request), namespace, listOptions, dataSelect)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 
-	response.WriteHeaderAndEntity(http.StatusCreated, result)
+	writeList(request, response, http.StatusCreated, dataSelect, common.ResourceKindService, result)
 }
 
 // Handles get service detail API call.
@@ -605,10 +849,10 @@ func (apiHandler *APIHandler) handleGetServiceDetail(request *restful.Request, r
 	namespace := request.PathParameter("namespace")
 	service := request.PathParameter("service")
 	dataSelect := parseDataSelectPathParameter(request)
-	result, err := resourceService.GetServiceDetail(apiHandler.client, apiHandler.heapsterClient,
+	result, err := resourceService.GetServiceDetail(getRequestClient(request), apiHandler.metricsProvider,
 		namespace, service, dataSelect)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 	response.WriteHeaderAndEntity(http.StatusCreated, result)
@@ -617,9 +861,9 @@ func (apiHandler *APIHandler) handleGetServiceDetail(request *restful.Request, r
 func (apiHandler *APIHandler) handleGetIngressDetail(request *restful.Request, response *restful.Response) {
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("name")
-	result, err := ingress.GetIngressDetail(apiHandler.client, namespace, name)
+	result, err := ingress.GetIngressDetail(getRequestClient(request), namespace, name)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 	response.WriteHeaderAndEntity(http.StatusCreated, result)
@@ -628,12 +872,17 @@ func (apiHandler *APIHandler) handleGetIngressDetail(request *restful.Request, r
 func (apiHandler *APIHandler) handleGetIngressList(request *restful.Request, response *restful.Response) {
 	dataSelect := parseDataSelectPathParameter(request)
 	namespace := parseNamespacePathParameter(request)
-	result, err := ingress.GetIngressList(apiHandler.client, namespace, dataSelect)
+	listOptions, err := parseListOptions(request)
 	if err != nil {
-		handleInternalError(response, err)
+		handleBadRequestError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	result, err := ingress.GetIngressList(getRequestClient(request), namespace, listOptions, dataSelect)
+	if err != nil {
+		handleError(response, err)
+		return
+	}
+	writeList(request, response, http.StatusOK, dataSelect, common.ResourceKindIngress, result)
 }
 
 // Handles get service pods API call.
@@ -643,10 +892,10 @@ func (apiHandler *APIHandler) handleGetServicePods(request *restful.Request,
 	namespace := request.PathParameter("namespace")
 	service := request.PathParameter("service")
 	dataSelect := parseDataSelectPathParameter(request)
-	result, err := resourceService.GetServicePods(apiHandler.client, apiHandler.heapsterClient,
+	result, err := resourceService.GetServicePods(getRequestClient(request), apiHandler.metricsProvider,
 		namespace, service, dataSelect)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 	response.WriteHeaderAndEntity(http.StatusCreated, result)
@@ -657,19 +906,30 @@ func (apiHandler *APIHandler) handleGetNodeList(request *restful.Request, respon
 	dataSelect := parseDataSelectPathParameter(request)
 	dataSelect.MetricQuery = dataselect.StandardMetrics
 
-	result, err := node.GetNodeList(apiHandler.client, dataSelect, &apiHandler.heapsterClient)
+	listOptions, err := parseListOptions(request)
+	if err != nil {
+		handleBadRequestError(response, err)
+		return
+	}
+	result, err := node.GetNodeList(getRequestClient(request), listOptions, dataSelect, &apiHandler.metricsProvider)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 
-	response.WriteHeaderAndEntity(http.StatusCreated, result)
+	writeList(request, response, http.StatusCreated, dataSelect, common.ResourceKindNode, result)
+}
+
+// Handles get resource cache status API call.
+func (apiHandler *APIHandler) handleGetResourceCacheStatus(request *restful.Request,
+	response *restful.Response) {
+	response.WriteHeaderAndEntity(http.StatusOK, common.GetResourceCacheStatus())
 }
 
 func (apiHandler *APIHandler) handleGetAdmin(request *restful.Request, response *restful.Response) {
-	result, err := admin.GetAdmin(apiHandler.client)
+	result, err := admin.GetAdmin(getRequestClient(request))
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 
@@ -680,9 +940,9 @@ func (apiHandler *APIHandler) handleGetAdmin(request *restful.Request, response
 func (apiHandler *APIHandler) handleGetNodeDetail(request *restful.Request, response *restful.Response) {
 	name := request.PathParameter("name")
 
-	result, err := node.GetNodeDetail(apiHandler.client, apiHandler.heapsterClient, name)
+	result, err := node.GetNodeDetail(getRequestClient(request), apiHandler.metricsProvider, name)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 	response.WriteHeaderAndEntity(http.StatusCreated, result)
@@ -693,9 +953,9 @@ func (apiHandler *APIHandler) handleGetNodeEvents(request *restful.Request, resp
 	name := request.PathParameter("name")
 	dataSelect := parseDataSelectPathParameter(request)
 
-	result, err := event.GetNodeEvents(apiHandler.client, dataSelect, name)
+	result, err := event.GetNodeEvents(getRequestClient(request), dataSelect, name)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 	response.WriteHeaderAndEntity(http.StatusCreated, result)
@@ -706,67 +966,162 @@ func (apiHandler *APIHandler) handleGetNodePods(request *restful.Request, respon
 	name := request.PathParameter("name")
 	dataSelect := parseDataSelectPathParameter(request)
 
-	result, err := node.GetNodePods(apiHandler.client, apiHandler.heapsterClient, dataSelect, name)
+	result, err := node.GetNodePods(getRequestClient(request), apiHandler.metricsProvider, dataSelect, name)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 	response.WriteHeaderAndEntity(http.StatusCreated, result)
 }
 
+// Handles node cordon API call.
+func (apiHandler *APIHandler) handleCordonNode(request *restful.Request, response *restful.Response) {
+	result, err := node.CordonNode(getRequestClient(request), request.PathParameter("name"))
+	if err != nil {
+		handleError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+// Handles node uncordon API call.
+func (apiHandler *APIHandler) handleUncordonNode(request *restful.Request, response *restful.Response) {
+	result, err := node.UncordonNode(getRequestClient(request), request.PathParameter("name"))
+	if err != nil {
+		handleError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+// Handles node drain API call. Responds once all pods are evicted, or deleted if the spec has a
+// timeout.
+func (apiHandler *APIHandler) handleDrainNode(request *restful.Request, response *restful.Response) {
+	drainSpec := new(node.DrainSpec)
+	if err := request.ReadEntity(drainSpec); err != nil {
+		handleBadRequestError(response, err)
+		return
+	}
+
+	result, err := node.DrainNode(getRequestClient(request), request.PathParameter("name"),
+		drainSpec, nil)
+	if err != nil {
+		handleError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+// Handles node drain stream API call. The drain spec is read from query parameters named after
+// its JSON fields. Upgrades the connection to a WebSocket and sends every pod status as a JSON
+// encoded node.DrainPodStatus as soon as it is known, followed by the node.DrainResult, or an
+// APIError when the drain fails.
+func (apiHandler *APIHandler) handleDrainNodeStream(request *restful.Request, response *restful.Response) {
+	drainSpec, err := parseDrainSpec(request)
+	if err != nil {
+		handleBadRequestError(response, err)
+		return
+	}
+
+	serveWebSocket(request, response, func(ws *websocket.Conn) {
+		result, err := node.DrainNode(getRequestClient(request), request.PathParameter("name"),
+			drainSpec, func(status node.DrainPodStatus) {
+				if err := websocket.JSON.Send(ws, status); err != nil {
+					log.Print(err)
+				}
+			})
+		sendDrainResult(ws, result, err)
+	})
+}
+
+// Sends the result of a node drain as the last message of the drain stream. Errors of the drain
+// are sent as an APIError.
+func sendDrainResult(ws *websocket.Conn, result *node.DrainResult, err error) {
+	var message interface{} = result
+	if err != nil {
+		log.Print(err)
+		message = toAPIError(err)
+	}
+	if err := websocket.JSON.Send(ws, message); err != nil {
+		log.Print(err)
+	}
+}
+
 // Handles deploy API call.
 func (apiHandler *APIHandler) handleDeploy(request *restful.Request, response *restful.Response) {
 	appDeploymentSpec := new(deployment.AppDeploymentSpec)
 	if err := request.ReadEntity(appDeploymentSpec); err != nil {
-		handleInternalError(response, err)
+		handleBadRequestError(response, err)
 		return
 	}
-	if err := deployment.DeployApp(appDeploymentSpec, apiHandler.client); err != nil {
-		handleInternalError(response, err)
+	result, err := deployment.DeployApp(appDeploymentSpec, getRequestClient(request))
+	if err != nil {
+		handleError(response, err)
 		return
 	}
 
-	response.WriteHeaderAndEntity(http.StatusCreated, appDeploymentSpec)
+	writeDeployResponse(response, result.RolledBack, result)
 }
 
 // Handles deploy from file API call.
 func (apiHandler *APIHandler) handleDeployFromFile(request *restful.Request, response *restful.Response) {
 	deploymentSpec := new(deployment.AppDeploymentFromFileSpec)
 	if err := request.ReadEntity(deploymentSpec); err != nil {
-		handleInternalError(response, err)
+		handleBadRequestError(response, err)
 		return
 	}
 
-	isDeployed, err := deployment.DeployAppFromFile(
-		deploymentSpec, deployment.CreateObjectFromInfoFn, apiHandler.clientConfig)
-	if !isDeployed {
-		handleInternalError(response, err)
+	result, err := deployment.DeployAppFromFile(deploymentSpec, deployment.CreateObjectFromInfoFn,
+		deployment.PatchObjectFromInfoFn, deployment.DeleteObjectFromInfoFn,
+		deployment.GetObjectFromInfoFn, getRequestClientConfig(request))
+	if err != nil && (result == nil || !result.RolledBack) {
+		handleError(response, err)
 		return
 	}
 
-	errorMessage := ""
-	if err != nil {
-		errorMessage = err.Error()
-	}
+	writeDeployResponse(response, result.RolledBack, result)
+}
 
-	response.WriteHeaderAndEntity(http.StatusCreated, deployment.AppDeploymentFromFileResponse{
-		Name:    deploymentSpec.Name,
-		Content: deploymentSpec.Content,
-		Error:   errorMessage,
-	})
+// Writes the result of an application deployment to the response. Deployments that have been
+// rolled back get 422 Unprocessable Entity, so that they are not taken for created, together with
+// the status of every object, so that clients can show which one failed.
+func writeDeployResponse(response *restful.Response, rolledBack bool, result interface{}) {
+	status := http.StatusCreated
+	if rolledBack {
+		status = k8serrors.StatusUnprocessableEntity
+	}
+	response.WriteHeaderAndEntity(status, result)
 }
 
 // Handles app name validation API call.
 func (apiHandler *APIHandler) handleNameValidity(request *restful.Request, response *restful.Response) {
 	spec := new(validation.AppNameValiditySpec)
 	if err := request.ReadEntity(spec); err != nil {
-		handleInternalError(response, err)
+		handleBadRequestError(response, err)
+		return
+	}
+
+	validity, err := validation.ValidateAppName(spec, getRequestClient(request))
+	if err != nil {
+		handleError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusCreated, validity)
+}
+
+// Handles app deployment validation API call.
+func (apiHandler *APIHandler) handleAppDeploymentValidity(request *restful.Request,
+	response *restful.Response) {
+	spec := new(deployment.AppDeploymentSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		handleBadRequestError(response, err)
 		return
 	}
 
-	validity, err := validation.ValidateAppName(spec, apiHandler.client)
+	validity, err := validation.ValidateAppDeployment(spec, getRequestClient(request))
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 
@@ -777,23 +1132,69 @@ func (apiHandler *APIHandler) handleNameValidity(request *restful.Request, respo
 func (APIHandler *APIHandler) handleImageReferenceValidity(request *restful.Request, response *restful.Response) {
 	spec := new(validation.ImageReferenceValiditySpec)
 	if err := request.ReadEntity(spec); err != nil {
-		handleInternalError(response, err)
+		handleBadRequestError(response, err)
 		return
 	}
 
 	validity, err := validation.ValidateImageReference(spec)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusCreated, validity)
+}
+
+// Handles image manifest validation API call.
+func (apiHandler *APIHandler) handleImageManifestValidity(request *restful.Request,
+	response *restful.Response) {
+	if apiHandler.registryClient == nil {
+		handleRegistryLookupDisabled(response)
+		return
+	}
+	spec := new(validation.ImageLookupSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		handleBadRequestError(response, err)
+		return
+	}
+
+	validity, err := validation.ValidateImageManifest(spec, getRequestClient(request),
+		apiHandler.registryClient)
+	if err != nil {
+		handleRegistryError(response, err)
 		return
 	}
+
 	response.WriteHeaderAndEntity(http.StatusCreated, validity)
 }
 
+// Handles get image tags API call.
+func (apiHandler *APIHandler) handleGetImageTags(request *restful.Request,
+	response *restful.Response) {
+	if apiHandler.registryClient == nil {
+		handleRegistryLookupDisabled(response)
+		return
+	}
+	spec := new(validation.ImageLookupSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		handleBadRequestError(response, err)
+		return
+	}
+
+	result, err := validation.GetImageTags(spec, getRequestClient(request),
+		apiHandler.registryClient)
+	if err != nil {
+		handleRegistryError(response, err)
+		return
+	}
+
+	response.WriteHeaderAndEntity(http.StatusCreated, result)
+}
+
 // Handles protocol validation API call.
 func (apiHandler *APIHandler) handleProtocolValidity(request *restful.Request, response *restful.Response) {
 	spec := new(validation.ProtocolValiditySpec)
 	if err := request.ReadEntity(spec); err != nil {
-		handleInternalError(response, err)
+		handleBadRequestError(response, err)
 		return
 	}
 
@@ -812,13 +1213,18 @@ func (apiHandler *APIHandler) handleGetReplicationControllerList(
 	namespace := parseNamespacePathParameter(request)
 	dataSelect := parseDataSelectPathParameter(request)
 	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := replicationcontrollerlist.GetReplicationControllerList(apiHandler.client, namespace, dataSelect, &apiHandler.heapsterClient)
+	listOptions, err := parseListOptions(request)
 	if err != nil {
-		handleInternalError(response, err)
+		handleBadRequestError(response, err)
+		return
+	}
+	result, err := replicationcontrollerlist.GetReplicationControllerList(getRequestClient(request), namespace, listOptions, dataSelect, &apiHandler.metricsProvider)
+	if err != nil {
+		handleError(response, err)
 		return
 	}
 
-	response.WriteHeaderAndEntity(http.StatusCreated, result)
+	writeList(request, response, http.StatusCreated, dataSelect, common.ResourceKindReplicationController, result)
 }
 
 // Handles get Workloads list API call.
@@ -826,9 +1232,15 @@ func (apiHandler *APIHandler) handleGetWorkloads(
 	request *restful.Request, response *restful.Response) {
 
 	namespace := parseNamespacePathParameter(request)
-	result, err := workload.GetWorkloads(apiHandler.client, apiHandler.heapsterClient, namespace, dataselect.StandardMetrics)
+	listOptions, err := parseAggregateListOptions(request)
 	if err != nil {
-		handleInternalError(response, err)
+		handleBadRequestError(response, err)
+		return
+	}
+	result, err := workload.GetWorkloads(getRequestClient(request), apiHandler.metricsProvider, namespace, listOptions,
+		dataselect.StandardMetrics)
+	if err != nil {
+		handleError(response, err)
 		return
 	}
 
@@ -839,9 +1251,14 @@ func (apiHandler *APIHandler) handleGetServicesAndDiscovery(
 	request *restful.Request, response *restful.Response) {
 
 	namespace := parseNamespacePathParameter(request)
-	result, err := servicesanddiscovery.GetServicesAndDiscovery(apiHandler.client, namespace)
+	listOptions, err := parseAggregateListOptions(request)
+	if err != nil {
+		handleBadRequestError(response, err)
+		return
+	}
+	result, err := servicesanddiscovery.GetServicesAndDiscovery(getRequestClient(request), namespace, listOptions)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 
@@ -852,9 +1269,14 @@ func (apiHandler *APIHandler) handleGetConfig(
 	request *restful.Request, response *restful.Response) {
 
 	namespace := parseNamespacePathParameter(request)
-	result, err := config.GetConfig(apiHandler.client, namespace)
+	listOptions, err := parseAggregateListOptions(request)
+	if err != nil {
+		handleBadRequestError(response, err)
+		return
+	}
+	result, err := config.GetConfig(getRequestClient(request), namespace, listOptions)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 
@@ -868,13 +1290,18 @@ func (apiHandler *APIHandler) handleGetReplicaSets(
 	namespace := parseNamespacePathParameter(request)
 	dataSelect := parseDataSelectPathParameter(request)
 	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := replicasetlist.GetReplicaSetList(apiHandler.client, namespace, dataSelect, &apiHandler.heapsterClient)
+	listOptions, err := parseListOptions(request)
+	if err != nil {
+		handleBadRequestError(response, err)
+		return
+	}
+	result, err := replicasetlist.GetReplicaSetList(getRequestClient(request), namespace, listOptions, dataSelect, &apiHandler.metricsProvider)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 
-	response.WriteHeaderAndEntity(http.StatusCreated, result)
+	writeList(request, response, http.StatusCreated, dataSelect, common.ResourceKindReplicaSet, result)
 }
 
 // Handles get Replica Sets Detail API call.
@@ -884,11 +1311,11 @@ func (apiHandler *APIHandler) handleGetReplicaSetDetail(
 	namespace := request.PathParameter("namespace")
 	replicaSet := request.PathParameter("replicaSet")
 
-	result, err := replicasetdetail.GetReplicaSetDetail(apiHandler.client, apiHandler.heapsterClient,
+	result, err := replicasetdetail.GetReplicaSetDetail(getRequestClient(request), apiHandler.metricsProvider,
 		namespace, replicaSet)
 
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 
@@ -902,11 +1329,11 @@ func (apiHandler *APIHandler) handleGetReplicaSetPods(
 	namespace := request.PathParameter("namespace")
 	replicaSet := request.PathParameter("replicaSet")
 	dataSelect := parseDataSelectPathParameter(request)
-	result, err := replicasetdetail.GetReplicaSetPods(apiHandler.client, apiHandler.heapsterClient,
+	result, err := replicasetdetail.GetReplicaSetPods(getRequestClient(request), apiHandler.metricsProvider,
 		dataSelect, replicaSet, namespace)
 
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 
@@ -920,10 +1347,10 @@ func (apiHandler *APIHandler) handleGetReplicaSetServices(
 	namespace := request.PathParameter("namespace")
 	replicaSet := request.PathParameter("replicaSet")
 	dataSelect := parseDataSelectPathParameter(request)
-	result, err := replicasetdetail.GetReplicaSetServices(apiHandler.client, dataSelect, namespace,
+	result, err := replicasetdetail.GetReplicaSetServices(getRequestClient(request), dataSelect, namespace,
 		replicaSet)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 
@@ -936,10 +1363,10 @@ func (apiHandler *APIHandler) handleGetReplicaSetEvents(request *restful.Request
 	name := request.PathParameter("replicaSet")
 	dataSelect := parseDataSelectPathParameter(request)
 
-	result, err := replicasetdetail.GetReplicaSetEvents(apiHandler.client, dataSelect, namespace,
+	result, err := replicasetdetail.GetReplicaSetEvents(getRequestClient(request), dataSelect, namespace,
 		name)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 	response.WriteHeaderAndEntity(http.StatusCreated, result)
@@ -952,13 +1379,18 @@ func (apiHandler *APIHandler) handleGetDeployments(
 	namespace := parseNamespacePathParameter(request)
 	dataSelect := parseDataSelectPathParameter(request)
 	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := deployment.GetDeploymentList(apiHandler.client, namespace, dataSelect, &apiHandler.heapsterClient)
+	listOptions, err := parseListOptions(request)
+	if err != nil {
+		handleBadRequestError(response, err)
+		return
+	}
+	result, err := deployment.GetDeploymentList(getRequestClient(request), namespace, listOptions, dataSelect, &apiHandler.metricsProvider)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 
-	response.WriteHeaderAndEntity(http.StatusCreated, result)
+	writeList(request, response, http.StatusCreated, dataSelect, common.ResourceKindDeployment, result)
 }
 
 // Handles get Deployment detail API call.
@@ -968,9 +1400,9 @@ func (apiHandler *APIHandler) handleGetDeploymentDetail(
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("deployment")
 
-	result, err := deployment.GetDeploymentDetail(apiHandler.client, apiHandler.heapsterClient, namespace, name)
+	result, err := deployment.GetDeploymentDetail(getRequestClient(request), apiHandler.metricsProvider, namespace, name)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 
@@ -983,10 +1415,10 @@ func (apiHandler *APIHandler) handleGetDeploymentEvents(request *restful.Request
 	name := request.PathParameter("deployment")
 	dataSelect := parseDataSelectPathParameter(request)
 
-	result, err := deployment.GetDeploymentEvents(apiHandler.client, dataSelect, namespace,
+	result, err := deployment.GetDeploymentEvents(getRequestClient(request), dataSelect, namespace,
 		name)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 	response.WriteHeaderAndEntity(http.StatusCreated, result)
@@ -998,15 +1430,111 @@ func (apiHandler *APIHandler) handleGetDeploymentOldReplicaSets(request *restful
 	name := request.PathParameter("deployment")
 	dataSelect := parseDataSelectPathParameter(request)
 
-	result, err := deployment.GetDeploymentOldReplicaSets(apiHandler.client, dataSelect, namespace,
+	result, err := deployment.GetDeploymentOldReplicaSets(getRequestClient(request), dataSelect, namespace,
 		name)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 	response.WriteHeaderAndEntity(http.StatusCreated, result)
 }
 
+// Handles get deployment rollout status API call.
+func (apiHandler *APIHandler) handleGetDeploymentRolloutStatus(request *restful.Request,
+	response *restful.Response) {
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("deployment")
+	result, err := deployment.GetRolloutStatus(getRequestClient(request), namespace, name)
+	if err != nil {
+		handleError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+// Handles deployment restart API call.
+func (apiHandler *APIHandler) handleRestartDeployment(request *restful.Request,
+	response *restful.Response) {
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("deployment")
+	result, err := deployment.RestartDeployment(getRequestClient(request), namespace, name)
+	if err != nil {
+		handleError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+// Handles deployment rollout pause API call.
+func (apiHandler *APIHandler) handlePauseDeployment(request *restful.Request,
+	response *restful.Response) {
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("deployment")
+	result, err := deployment.PauseDeployment(getRequestClient(request), namespace, name)
+	if err != nil {
+		handleError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+// Handles deployment rollout resume API call.
+func (apiHandler *APIHandler) handleResumeDeployment(request *restful.Request,
+	response *restful.Response) {
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("deployment")
+	result, err := deployment.ResumeDeployment(getRequestClient(request), namespace, name)
+	if err != nil {
+		handleError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+// Handles set deployment container image API call.
+func (apiHandler *APIHandler) handleSetDeploymentImage(request *restful.Request,
+	response *restful.Response) {
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("deployment")
+	spec := new(deployment.ContainerImageSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		handleBadRequestError(response, err)
+		return
+	}
+
+	result, err := deployment.SetDeploymentImage(getRequestClient(request), namespace, name, spec)
+	if err != nil {
+		handleError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+// Handles deployment rollback API call.
+func (apiHandler *APIHandler) handleRollbackDeployment(request *restful.Request,
+	response *restful.Response) {
+
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("deployment")
+	spec := new(deployment.RollbackSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		handleBadRequestError(response, err)
+		return
+	}
+
+	result, err := deployment.RollbackDeployment(getRequestClient(request), namespace, name, spec)
+	if err != nil {
+		handleError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
 // Handles get Pod list API call.
 func (apiHandler *APIHandler) handleGetPods(
 	request *restful.Request, response *restful.Response) {
@@ -1014,13 +1542,18 @@ func (apiHandler *APIHandler) handleGetPods(
 	namespace := parseNamespacePathParameter(request)
 	dataSelect := parseDataSelectPathParameter(request)
 	dataSelect.MetricQuery = dataselect.StandardMetrics // download standard metrics - cpu, and memory - by default
-	result, err := pod.GetPodList(apiHandler.client, apiHandler.heapsterClient, namespace, dataSelect)
+	listOptions, err := parseListOptions(request)
 	if err != nil {
-		handleInternalError(response, err)
+		handleBadRequestError(response, err)
+		return
+	}
+	result, err := pod.GetPodList(getRequestClient(request), apiHandler.metricsProvider, namespace, listOptions, dataSelect)
+	if err != nil {
+		handleError(response, err)
 		return
 	}
 
-	response.WriteHeaderAndEntity(http.StatusCreated, result)
+	writeList(request, response, http.StatusCreated, dataSelect, common.ResourceKindPod, result)
 }
 
 // Handles get Pod detail API call.
@@ -1028,9 +1561,9 @@ func (apiHandler *APIHandler) handleGetPodDetail(request *restful.Request, respo
 
 	namespace := request.PathParameter("namespace")
 	podName := request.PathParameter("pod")
-	result, err := pod.GetPodDetail(apiHandler.client, apiHandler.heapsterClient, namespace, podName)
+	result, err := pod.GetPodDetail(getRequestClient(request), apiHandler.metricsProvider, namespace, podName)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 
@@ -1044,10 +1577,10 @@ func (apiHandler *APIHandler) handleGetReplicationControllerDetail(
 	namespace := request.PathParameter("namespace")
 	replicationController := request.PathParameter("replicationController")
 
-	result, err := replicationcontrollerdetail.GetReplicationControllerDetail(apiHandler.client,
-		apiHandler.heapsterClient, namespace, replicationController)
+	result, err := replicationcontrollerdetail.GetReplicationControllerDetail(getRequestClient(request),
+		apiHandler.metricsProvider, namespace, replicationController)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 
@@ -1063,13 +1596,13 @@ func (apiHandler *APIHandler) handleUpdateReplicasCount(
 	replicationControllerSpec := new(replicationcontrollerdetail.ReplicationControllerSpec)
 
 	if err := request.ReadEntity(replicationControllerSpec); err != nil {
-		handleInternalError(response, err)
+		handleBadRequestError(response, err)
 		return
 	}
 
-	if err := replicationcontrollerdetail.UpdateReplicasCount(apiHandler.client, namespace, replicationControllerName,
+	if err := replicationcontrollerdetail.UpdateReplicasCount(getRequestClient(request), namespace, replicationControllerName,
 		replicationControllerSpec); err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 
@@ -1082,9 +1615,9 @@ func (apiHandler *APIHandler) handleGetResource(
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("name")
 
-	result, err := apiHandler.verber.Get(kind, namespace, name)
+	result, err := getRequestVerber(request).Get(kind, namespace, name)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 
@@ -1098,26 +1631,63 @@ func (apiHandler *APIHandler) handlePutResource(
 	name := request.PathParameter("name")
 	putSpec := &runtime.Unknown{}
 	if err := request.ReadEntity(putSpec); err != nil {
-		handleInternalError(response, err)
+		handleBadRequestError(response, err)
 		return
 	}
 
-	if err := apiHandler.verber.Put(kind, namespace, name, putSpec); err != nil {
-		handleInternalError(response, err)
+	if err := getRequestVerber(request).Put(kind, namespace, name, putSpec); err != nil {
+		handleError(response, err)
 		return
 	}
 
 	response.WriteHeader(http.StatusOK)
 }
 
+// Handles get replica counts of a scalable resource API call.
+func (apiHandler *APIHandler) handleGetReplicaCounts(request *restful.Request,
+	response *restful.Response) {
+
+	kind := request.PathParameter("kind")
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+
+	result, err := scaling.GetReplicaCounts(getRequestVerber(request), kind, namespace, name)
+	if err != nil {
+		handleError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+// Handles scale resource API call.
+func (apiHandler *APIHandler) handleScaleResource(request *restful.Request,
+	response *restful.Response) {
+
+	kind := request.PathParameter("kind")
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	spec := new(scaling.ScaleSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		handleBadRequestError(response, err)
+		return
+	}
+
+	result, err := scaling.ScaleResource(getRequestVerber(request), kind, namespace, name, spec)
+	if err != nil {
+		handleError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
 func (apiHandler *APIHandler) handleDeleteResource(
 	request *restful.Request, response *restful.Response) {
 	kind := request.PathParameter("kind")
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("name")
 
-	if err := apiHandler.verber.Delete(kind, namespace, name); err != nil {
-		handleInternalError(response, err)
+	if err := getRequestVerber(request).Delete(kind, namespace, name); err != nil {
+		handleError(response, err)
 		return
 	}
 
@@ -1132,10 +1702,10 @@ func (apiHandler *APIHandler) handleGetReplicationControllerPods(
 	replicationController := request.PathParameter("replicationController")
 	dataSelect := parseDataSelectPathParameter(request)
 
-	result, err := replicationcontrollerdetail.GetReplicationControllerPods(apiHandler.client, apiHandler.heapsterClient,
+	result, err := replicationcontrollerdetail.GetReplicationControllerPods(getRequestClient(request), apiHandler.metricsProvider,
 		dataSelect, replicationController, namespace)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 
@@ -1147,11 +1717,11 @@ func (apiHandler *APIHandler) handleCreateNamespace(request *restful.Request,
 	response *restful.Response) {
 	namespaceSpec := new(namespace.NamespaceSpec)
 	if err := request.ReadEntity(namespaceSpec); err != nil {
-		handleInternalError(response, err)
+		handleBadRequestError(response, err)
 		return
 	}
-	if err := namespace.CreateNamespace(namespaceSpec, apiHandler.client); err != nil {
-		handleInternalError(response, err)
+	if err := namespace.CreateNamespace(namespaceSpec, getRequestClient(request)); err != nil {
+		handleError(response, err)
 		return
 	}
 
@@ -1163,61 +1733,167 @@ func (apiHandler *APIHandler) handleGetNamespaces(
 	request *restful.Request, response *restful.Response) {
 
 	dataSelect := parseDataSelectPathParameter(request)
-	result, err := namespace.GetNamespaceList(apiHandler.client, dataSelect)
+	listOptions, err := parseListOptions(request)
+	if err != nil {
+		handleBadRequestError(response, err)
+		return
+	}
+	result, err := namespace.GetNamespaceList(getRequestClient(request), listOptions, dataSelect)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 
-	response.WriteHeaderAndEntity(http.StatusCreated, result)
+	writeList(request, response, http.StatusCreated, dataSelect, common.ResourceKindNamespace, result)
 }
 
 // Handles get namespace detail API call.
 func (apiHandler *APIHandler) handleGetNamespaceDetail(request *restful.Request,
 	response *restful.Response) {
 	name := request.PathParameter("name")
-	result, err := namespace.GetNamespaceDetail(apiHandler.client, apiHandler.heapsterClient, name)
+	result, err := namespace.GetNamespaceDetail(getRequestClient(request), apiHandler.metricsProvider, name)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 	response.WriteHeaderAndEntity(http.StatusCreated, result)
 }
 
+// Handles get namespace resource usage API call.
+func (apiHandler *APIHandler) handleGetNamespaceUsage(request *restful.Request,
+	response *restful.Response) {
+	name := request.PathParameter("name")
+	result, err := namespace.GetNamespaceUsage(getRequestClient(request), apiHandler.metricsProvider,
+		name)
+	if err != nil {
+		handleError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
 // Handles get namespace events API call.
 func (apiHandler *APIHandler) handleGetNamespaceEvents(request *restful.Request, response *restful.Response) {
 	name := request.PathParameter("name")
 	dataSelect := parseDataSelectPathParameter(request)
 
-	result, err := event.GetNamespaceEvents(apiHandler.client, dataSelect, name)
+	result, err := event.GetNamespaceEvents(getRequestClient(request), dataSelect, name)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 	response.WriteHeaderAndEntity(http.StatusCreated, result)
 }
 
+// Handles get event list API call. Events of all namespaces are listed when the namespace path
+// parameter is not set. Events are sorted from the most recently seen unless sortby is given.
+func (apiHandler *APIHandler) handleGetEventList(request *restful.Request, response *restful.Response) {
+	namespace := parseNamespacePathParameter(request)
+	listOptions, err := parseListOptions(request)
+	if err != nil {
+		handleBadRequestError(response, err)
+		return
+	}
+	dataSelect := parseDataSelectPathParameter(request)
+	if request.QueryParameter("sortby") == "" {
+		dataSelect.SortQuery = dataselect.NewSortQuery([]string{"d", "lastSeen"})
+	}
+
+	result, err := event.GetEventList(getRequestClient(request), namespace, listOptions, dataSelect)
+	if err != nil {
+		handleError(response, err)
+		return
+	}
+	writeList(request, response, http.StatusOK, dataSelect, common.ResourceKindEvent, result)
+}
+
+// Handles event stream API call. Upgrades the connection to a WebSocket and sends every new or
+// repeated event that matches the filterby query parameter as a JSON encoded common.Event.
+func (apiHandler *APIHandler) handleEventStream(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	listOptions, err := parseListOptions(request)
+	if err != nil {
+		handleBadRequestError(response, err)
+		return
+	}
+	filterQuery := parseFilterPathParameter(request)
+
+	serveWebSocket(request, response, func(ws *websocket.Conn) {
+		err := event.WatchEvents(getRequestClient(request), namespace, listOptions, filterQuery,
+			waitForWebSocketClose(ws), func(e common.Event) error {
+				return websocket.JSON.Send(ws, e)
+			})
+		if err != nil {
+			log.Print(err)
+		}
+	})
+}
+
 // Handles image pull secret creation API call.
 func (apiHandler *APIHandler) handleCreateImagePullSecret(request *restful.Request, response *restful.Response) {
-	secretSpec := new(secret.ImagePullSecretSpec)
+	handleCreateSecret(request, response, new(secret.ImagePullSecretSpec))
+}
+
+// Handles opaque secret creation API call.
+func (apiHandler *APIHandler) handleCreateOpaqueSecret(request *restful.Request, response *restful.Response) {
+	handleCreateSecret(request, response, new(secret.OpaqueSecretSpec))
+}
+
+// Handles TLS secret creation API call.
+func (apiHandler *APIHandler) handleCreateTLSSecret(request *restful.Request, response *restful.Response) {
+	handleCreateSecret(request, response, new(secret.TLSSecretSpec))
+}
+
+// Handles basic authentication secret creation API call.
+func (apiHandler *APIHandler) handleCreateBasicAuthSecret(request *restful.Request, response *restful.Response) {
+	handleCreateSecret(request, response, new(secret.BasicAuthSecretSpec))
+}
+
+// Handles SSH authentication secret creation API call.
+func (apiHandler *APIHandler) handleCreateSSHAuthSecret(request *restful.Request, response *restful.Response) {
+	handleCreateSecret(request, response, new(secret.SSHAuthSecretSpec))
+}
+
+// Reads the secret spec from the request and creates the secret. Invalid specs are rejected as bad
+// requests.
+func handleCreateSecret(request *restful.Request, response *restful.Response,
+	secretSpec secret.SecretSpec) {
+
 	if err := request.ReadEntity(secretSpec); err != nil {
-		handleInternalError(response, err)
+		handleBadRequestError(response, err)
+		return
+	}
+	if err := secretSpec.Validate(); err != nil {
+		handleBadRequestError(response, err)
 		return
 	}
-	secret, err := secret.CreateSecret(apiHandler.client, secretSpec)
+	secret, err := secret.CreateSecret(getRequestClient(request), secretSpec)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 	response.WriteHeaderAndEntity(http.StatusCreated, secret)
 }
 
+// Handles reveal secret value API call. Secret detail has only names and sizes of the keys.
+func (apiHandler *APIHandler) handleRevealSecretValue(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("name")
+	key := request.PathParameter("key")
+	result, err := secret.RevealSecretValue(getRequestClient(request), namespace, name, key)
+	if err != nil {
+		handleError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
 func (apiHandler *APIHandler) handleGetSecretDetail(request *restful.Request, response *restful.Response) {
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("name")
-	result, err := secret.GetSecretDetail(apiHandler.client, namespace, name)
+	result, err := secret.GetSecretDetail(getRequestClient(request), namespace, name)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 	response.WriteHeaderAndEntity(http.StatusCreated, result)
@@ -1227,51 +1903,115 @@ func (apiHandler *APIHandler) handleGetSecretDetail(request *restful.Request, re
 func (apiHandler *APIHandler) handleGetSecretList(request *restful.Request, response *restful.Response) {
 	dataSelect := parseDataSelectPathParameter(request)
 	namespace := parseNamespacePathParameter(request)
-	result, err := secret.GetSecretList(apiHandler.client, namespace, dataSelect)
+	listOptions, err := parseListOptions(request)
 	if err != nil {
-		handleInternalError(response, err)
+		handleBadRequestError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	result, err := secret.GetSecretList(getRequestClient(request), namespace, listOptions, dataSelect)
+	if err != nil {
+		handleError(response, err)
+		return
+	}
+	writeList(request, response, http.StatusOK, dataSelect, common.ResourceKindSecret, result)
 }
 
 func (apiHandler *APIHandler) handleGetConfigMapList(request *restful.Request, response *restful.Response) {
 	namespace := parseNamespacePathParameter(request)
 	dataSelect := parseDataSelectPathParameter(request)
-	result, err := configmap.GetConfigMapList(apiHandler.client, namespace, dataSelect)
+	listOptions, err := parseListOptions(request)
 	if err != nil {
-		handleInternalError(response, err)
+		handleBadRequestError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	result, err := configmap.GetConfigMapList(getRequestClient(request), namespace, listOptions, dataSelect)
+	if err != nil {
+		handleError(response, err)
+		return
+	}
+	writeList(request, response, http.StatusOK, dataSelect, common.ResourceKindConfigMap, result)
 }
 
 func (apiHandler *APIHandler) handleGetConfigMapDetail(request *restful.Request, response *restful.Response) {
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("configmap")
-	result, err := configmap.GetConfigMapDetail(apiHandler.client, namespace, name)
+	result, err := configmap.GetConfigMapDetail(getRequestClient(request), namespace, name)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 	response.WriteHeaderAndEntity(http.StatusCreated, result)
 }
 
+// Handles config map creation API call.
+func (apiHandler *APIHandler) handleCreateConfigMap(request *restful.Request, response *restful.Response) {
+	spec := new(configmap.ConfigMapSpec)
+	if err := request.ReadEntity(spec); err != nil {
+		handleBadRequestError(response, err)
+		return
+	}
+	result, err := configmap.CreateConfigMap(getRequestClient(request), spec)
+	if err != nil {
+		handleError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusCreated, result)
+}
+
+// Handles set config map key API call. The key is added if it does not exist.
+func (apiHandler *APIHandler) handleSetConfigMapKey(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("configmap")
+	key := request.PathParameter("key")
+	spec := new(configmap.ConfigMapKeySpec)
+	if err := request.ReadEntity(spec); err != nil {
+		handleBadRequestError(response, err)
+		return
+	}
+	result, err := configmap.SetConfigMapKey(getRequestClient(request), namespace, name, key, spec)
+	if err != nil {
+		handleError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
+// Handles delete config map key API call. Resource version of the config map is given by the
+// resourceVersion query parameter.
+func (apiHandler *APIHandler) handleDeleteConfigMapKey(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	name := request.PathParameter("configmap")
+	key := request.PathParameter("key")
+	resourceVersion := request.QueryParameter("resourceVersion")
+	result, err := configmap.DeleteConfigMapKey(getRequestClient(request), namespace, name, key,
+		resourceVersion)
+	if err != nil {
+		handleError(response, err)
+		return
+	}
+	response.WriteHeaderAndEntity(http.StatusOK, result)
+}
+
 func (apiHandler *APIHandler) handleGetPersistentVolumeList(request *restful.Request, response *restful.Response) {
 	dataSelect := parseDataSelectPathParameter(request)
-	result, err := persistentvolume.GetPersistentVolumeList(apiHandler.client, dataSelect)
+	listOptions, err := parseListOptions(request)
 	if err != nil {
-		handleInternalError(response, err)
+		handleBadRequestError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	result, err := persistentvolume.GetPersistentVolumeList(getRequestClient(request), listOptions, dataSelect)
+	if err != nil {
+		handleError(response, err)
+		return
+	}
+	writeList(request, response, http.StatusOK, dataSelect, common.ResourceKindPersistentVolume, result)
 }
 
 func (apiHandler *APIHandler) handleGetPersistentVolumeDetail(request *restful.Request, response *restful.Response) {
 	name := request.PathParameter("persistentvolume")
-	result, err := persistentvolume.GetPersistentVolumeDetail(apiHandler.client, name)
+	result, err := persistentvolume.GetPersistentVolumeDetail(getRequestClient(request), name)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 	response.WriteHeaderAndEntity(http.StatusCreated, result)
@@ -1281,20 +2021,25 @@ func (apiHandler *APIHandler) handleGetPersistentVolumeClaimList(request *restfu
 
 	namespace := parseNamespacePathParameter(request)
 	dataSelect := parseDataSelectPathParameter(request)
-	result, err := persistentvolumeclaim.GetPersistentVolumeClaimList(apiHandler.client, namespace, dataSelect)
+	listOptions, err := parseListOptions(request)
 	if err != nil {
-		handleInternalError(response, err)
+		handleBadRequestError(response, err)
 		return
 	}
-	response.WriteHeaderAndEntity(http.StatusOK, result)
+	result, err := persistentvolumeclaim.GetPersistentVolumeClaimList(getRequestClient(request), namespace, listOptions, dataSelect)
+	if err != nil {
+		handleError(response, err)
+		return
+	}
+	writeList(request, response, http.StatusOK, dataSelect, common.ResourceKindPersistentVolumeClaim, result)
 }
 
 func (apiHandler *APIHandler) handleGetPersistentVolumeClaimDetail(request *restful.Request, response *restful.Response) {
 	namespace := request.PathParameter("namespace")
 	name := request.PathParameter("name")
-	result, err := persistentvolumeclaim.GetPersistentVolumeClaimDetail(apiHandler.client, namespace, name)
+	result, err := persistentvolumeclaim.GetPersistentVolumeClaimDetail(getRequestClient(request), namespace, name)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 	response.WriteHeaderAndEntity(http.StatusCreated, result)
@@ -1334,21 +2079,133 @@ func (apiHandler *APIHandler) handleLogs(request *restful.Request, response *res
 		}
 	}
 
-	result, err := container.GetPodLogs(apiHandler.client, namespace, podID, containerID, logSelector)
+	logOptions, err := parsePodLogOptions(request)
 	if err != nil {
-		handleInternalError(response, err)
+		handleBadRequestError(response, err)
+		return
+	}
+
+	result, err := container.GetPodLogs(getRequestClient(request), namespace, podID, containerID,
+		logOptions, logSelector)
+	if err != nil {
+		handleError(response, err)
 		return
 	}
 	response.WriteHeaderAndEntity(http.StatusCreated, result)
 }
 
+// Handles log file API call. Streams raw logs of the container as a plain text attachment, e.g.,
+// to save them to a file. Accepts the same log options as the log API call. Timestamps are
+// included only when the timestamps query parameter is true.
+func (apiHandler *APIHandler) handleLogFile(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	podID := request.PathParameter("pod")
+
+	logOptions, err := parsePodLogOptions(request)
+	if err != nil {
+		handleBadRequestError(response, err)
+		return
+	}
+	if value := request.QueryParameter("timestamps"); value != "" {
+		if logOptions.Timestamps, err = strconv.ParseBool(value); err != nil {
+			handleBadRequestError(response, fmt.Errorf("invalid timestamps: %s", err))
+			return
+		}
+	}
+
+	stream, containerID, err := container.StreamPodLogs(getRequestClient(request), namespace,
+		podID, request.PathParameter("container"), logOptions)
+	if err != nil {
+		handleError(response, err)
+		return
+	}
+	defer stream.Close()
+
+	fileName := podID + "-" + containerID
+	if logOptions.Previous {
+		fileName += "-previous"
+	}
+	response.AddHeader("Content-Type", "text/plain")
+	response.AddHeader("Content-Disposition", fmt.Sprintf("attachment; filename=%q",
+		fileName+".log"))
+	response.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(response, stream); err != nil {
+		log.Print(err)
+	}
+}
+
+// Handles log stream API call. Upgrades the connection to a WebSocket and sends every new log line
+// as a JSON encoded logs.LogLine message. When referenceTimestamp and referenceLineNum query
+// parameters are set, streaming starts right after the referenced line.
+func (apiHandler *APIHandler) handleLogStream(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	podID := request.PathParameter("pod")
+	containerID := request.PathParameter("container")
+
+	var reference *logs.LogLineId
+	if refTimestamp := request.QueryParameter("referenceTimestamp"); refTimestamp != "" {
+		refLineNum, err := strconv.Atoi(request.QueryParameter("referenceLineNum"))
+		if err != nil {
+			refLineNum = 0
+		}
+		reference = &logs.LogLineId{
+			LogTimestamp: logs.LogTimestamp(refTimestamp),
+			LineNum:      refLineNum,
+		}
+	}
+
+	serveWebSocket(request, response, func(ws *websocket.Conn) {
+		err := container.FollowPodLogs(getRequestClient(request), namespace, podID, containerID, reference,
+			waitForWebSocketClose(ws), func(line logs.LogLine) error {
+				return websocket.JSON.Send(ws, line)
+			})
+		if err != nil {
+			log.Print(err)
+		}
+	})
+}
+
+// Handles exec shell API call. Upgrades the connection to a WebSocket and proxies the standard
+// streams of a process started in the container. Messages in both directions are JSON encoded
+// container.TerminalMessages. The process is an interactive shell unless command query parameters
+// are given, and it gets a TTY unless the tty query parameter is false.
+func (apiHandler *APIHandler) handleExecShell(request *restful.Request, response *restful.Response) {
+	namespace := request.PathParameter("namespace")
+	podID := request.PathParameter("pod")
+
+	tty, err := strconv.ParseBool(request.QueryParameter("tty"))
+	if err != nil {
+		tty = true
+	}
+	spec := &container.ShellSpec{
+		Container: request.PathParameter("container"),
+		Command:   request.Request.URL.Query()["command"],
+		TTY:       tty,
+	}
+
+	config, err := getRequestClientConfig(request).ClientConfig()
+	if err != nil {
+		handleError(response, err)
+		return
+	}
+
+	serveWebSocket(request, response, func(ws *websocket.Conn) {
+		session := &webSocketTerminalSession{conn: ws}
+		err := container.StartShell(getRequestClient(request), config, namespace, podID, spec, session)
+		if err != nil {
+			log.Print(err)
+			session.Write(&container.TerminalMessage{Op: container.TerminalError, Data: err.Error()})
+		}
+	})
+}
+
 func (apiHandler *APIHandler) handleGetPodContainers(request *restful.Request, response *restful.Response) {
 	namespace := request.PathParameter("namespace")
 	podID := request.PathParameter("pod")
 
-	result, err := container.GetPodContainers(apiHandler.client, namespace, podID)
+	result, err := container.GetPodContainers(getRequestClient(request), namespace, podID)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 	response.WriteHeaderAndEntity(http.StatusCreated, result)
@@ -1360,10 +2217,10 @@ func (apiHandler *APIHandler) handleGetReplicationControllerEvents(request *rest
 	replicationController := request.PathParameter("replicationController")
 	dataSelect := parseDataSelectPathParameter(request)
 
-	result, err := replicationcontrollerdetail.GetReplicationControllerEvents(apiHandler.client, dataSelect, namespace,
+	result, err := replicationcontrollerdetail.GetReplicationControllerEvents(getRequestClient(request), dataSelect, namespace,
 		replicationController)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 	response.WriteHeaderAndEntity(http.StatusCreated, result)
@@ -1376,20 +2233,96 @@ func (apiHandler *APIHandler) handleGetReplicationControllerServices(request *re
 	replicationController := request.PathParameter("replicationController")
 	dataSelect := parseDataSelectPathParameter(request)
 
-	result, err := replicationcontrollerdetail.GetReplicationControllerServices(apiHandler.client, dataSelect,
+	result, err := replicationcontrollerdetail.GetReplicationControllerServices(getRequestClient(request), dataSelect,
 		namespace, replicationController)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 	response.WriteHeaderAndEntity(http.StatusCreated, result)
 }
 
-// Handler that writes the given error to the response and sets appropriate HTTP status headers.
-func handleInternalError(response *restful.Response, err error) {
-	log.Print(err)
-	response.AddHeader("Content-Type", "text/plain")
-	response.WriteErrorString(http.StatusInternalServerError, err.Error()+"\n")
+// Writes the list to the response. When the request asks for an export format, data cells
+// collected by the data select query are written instead, as a file to download.
+func writeList(request *restful.Request, response *restful.Response, status int,
+	dataSelect *dataselect.DataSelectQuery, kind string, list interface{}) {
+
+	format := parseExportFormat(request)
+	if format == "" {
+		response.WriteHeaderAndEntity(status, list)
+		return
+	}
+
+	var write func(io.Writer, []dataselect.DataCell) error
+	var contentType string
+	switch format {
+	case export.FormatCSV:
+		write, contentType = export.WriteCSV, export.MIMECSV
+	case export.FormatYAML:
+		write, contentType = export.WriteYAML, export.MIMEYAML
+	default:
+		handleBadRequestError(response, fmt.Errorf("invalid format: %s", format))
+		return
+	}
+
+	response.AddHeader("Content-Type", contentType)
+	response.AddHeader("Content-Disposition",
+		fmt.Sprintf("attachment; filename=%s-list.%s", kind, format))
+	response.WriteHeader(http.StatusOK)
+	if err := write(response, dataSelect.Collector.Cells); err != nil {
+		log.Printf("Could not export %s list: %s", kind, err)
+	}
+}
+
+// Handler that writes the given error to the response as an APIError. Errors returned by the
+// apiserver keep their status code, e.g., 403 Forbidden when the user is not allowed to access an
+// object or 409 Conflict when it has been modified concurrently. Other errors are internal
+// errors.
+func handleError(response *restful.Response, err error) {
+	writeAPIError(response, toAPIError(err))
+}
+
+// Handler that writes the given authentication error to the response. Requests from untrusted
+// proxies are forbidden, requests with missing or invalid credentials are unauthorized.
+func handleAuthError(response *restful.Response, err error) {
+	if err == client.ErrUntrustedImpersonation {
+		writeAPIError(response, newAPIError(http.StatusForbidden,
+			unversioned.StatusReasonForbidden, err))
+		return
+	}
+	writeAPIError(response, newAPIError(http.StatusUnauthorized,
+		unversioned.StatusReasonUnauthorized, err))
+}
+
+// Handler that writes the given error to the response as a bad request, e.g., when query
+// parameters of the request are invalid.
+func handleBadRequestError(response *restful.Response, err error) {
+	writeAPIError(response, newAPIError(http.StatusBadRequest, unversioned.StatusReasonBadRequest,
+		err))
+}
+
+// Handler that writes not found error to the response, for image registry API calls made when
+// registry lookup is disabled.
+func handleRegistryLookupDisabled(response *restful.Response) {
+	writeAPIError(response, newAPIError(http.StatusNotFound, unversioned.StatusReasonNotFound,
+		errors.New("image registry lookup is disabled")))
+}
+
+// Handler that writes the given error of an image registry lookup to the response. Registries
+// that deny access get 403 Forbidden, registries that can not be reached or respond unexpectedly
+// get 502 Bad Gateway. Other errors are handled by handleError.
+func handleRegistryError(response *restful.Response, err error) {
+	if err == client.ErrRegistryUnauthorized {
+		writeAPIError(response, newAPIError(http.StatusForbidden,
+			unversioned.StatusReasonForbidden, err))
+		return
+	}
+	if _, ok := err.(*client.RegistryError); ok {
+		writeAPIError(response, newAPIError(http.StatusBadGateway,
+			unversioned.StatusReasonInternalError, err))
+		return
+	}
+	handleError(response, err)
 }
 
 // Handles get Daemon Set list API call.
@@ -1399,13 +2332,18 @@ func (apiHandler *APIHandler) handleGetDaemonSetList(
 	namespace := parseNamespacePathParameter(request)
 	dataSelect := parseDataSelectPathParameter(request)
 	dataSelect.MetricQuery = dataselect.StandardMetrics
-	result, err := daemonsetlist.GetDaemonSetList(apiHandler.client, namespace, dataSelect, &apiHandler.heapsterClient)
+	listOptions, err := parseListOptions(request)
+	if err != nil {
+		handleBadRequestError(response, err)
+		return
+	}
+	result, err := daemonsetlist.GetDaemonSetList(getRequestClient(request), namespace, listOptions, dataSelect, &apiHandler.metricsProvider)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 
-	response.WriteHeaderAndEntity(http.StatusCreated, result)
+	writeList(request, response, http.StatusCreated, dataSelect, common.ResourceKindDaemonSet, result)
 }
 
 // Handles get Daemon Set detail API call.
@@ -1415,10 +2353,10 @@ func (apiHandler *APIHandler) handleGetDaemonSetDetail(
 	namespace := request.PathParameter("namespace")
 	daemonSet := request.PathParameter("daemonSet")
 
-	result, err := daemonsetdetail.GetDaemonSetDetail(apiHandler.client, apiHandler.heapsterClient,
+	result, err := daemonsetdetail.GetDaemonSetDetail(getRequestClient(request), apiHandler.metricsProvider,
 		namespace, daemonSet)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 
@@ -1432,10 +2370,10 @@ func (apiHandler *APIHandler) handleGetDaemonSetPods(
 	namespace := request.PathParameter("namespace")
 	daemonSet := request.PathParameter("daemonSet")
 	dataSelect := parseDataSelectPathParameter(request)
-	result, err := daemonsetdetail.GetDaemonSetPods(apiHandler.client, apiHandler.heapsterClient,
+	result, err := daemonsetdetail.GetDaemonSetPods(getRequestClient(request), apiHandler.metricsProvider,
 		dataSelect, daemonSet, namespace)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 
@@ -1449,10 +2387,10 @@ func (apiHandler *APIHandler) handleGetDaemonSetServices(
 	namespace := request.PathParameter("namespace")
 	daemonSet := request.PathParameter("daemonSet")
 	dataSelect := parseDataSelectPathParameter(request)
-	result, err := daemonsetdetail.GetDaemonSetServices(apiHandler.client, dataSelect, namespace,
+	result, err := daemonsetdetail.GetDaemonSetServices(getRequestClient(request), dataSelect, namespace,
 		daemonSet)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 
@@ -1465,10 +2403,10 @@ func (apiHandler *APIHandler) handleGetDaemonSetEvents(request *restful.Request,
 	name := request.PathParameter("daemonSet")
 	dataSelect := parseDataSelectPathParameter(request)
 
-	result, err := daemonsetdetail.GetDaemonSetEvents(apiHandler.client, dataSelect, namespace,
+	result, err := daemonsetdetail.GetDaemonSetEvents(getRequestClient(request), dataSelect, namespace,
 		name)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 	response.WriteHeaderAndEntity(http.StatusCreated, result)
@@ -1482,13 +2420,13 @@ func (apiHandler *APIHandler) handleDeleteDaemonSet(
 	daemonSet := request.PathParameter("daemonSet")
 	deleteServices, err := strconv.ParseBool(request.QueryParameter("deleteServices"))
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 
-	if err := daemonsetdetail.DeleteDaemonSet(apiHandler.client, namespace,
+	if err := daemonsetdetail.DeleteDaemonSet(getRequestClient(request), namespace,
 		daemonSet, deleteServices); err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 
@@ -1500,9 +2438,15 @@ func (apiHandler *APIHandler) handleGetHorizontalPodAutoscalerList(request *rest
 	response *restful.Response) {
 	namespace := parseNamespacePathParameter(request)
 
-	result, err := horizontalpodautoscalerlist.GetHorizontalPodAutoscalerList(apiHandler.client, namespace)
+	listOptions, err := parseListOptions(request)
+	if err != nil {
+		handleBadRequestError(response, err)
+		return
+	}
+	result, err := horizontalpodautoscalerlist.GetHorizontalPodAutoscalerList(getRequestClient(request), namespace,
+		listOptions)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 
@@ -1513,9 +2457,9 @@ func (apiHandler *APIHandler) handleGetHorizontalPodAutoscalerDetail(request *re
 	namespace := request.PathParameter("namespace")
 	horizontalpodautoscalerParam := request.PathParameter("horizontalpodautoscaler")
 
-	result, err := horizontalpodautoscalerdetail.GetHorizontalPodAutoscalerDetail(apiHandler.client, namespace, horizontalpodautoscalerParam)
+	result, err := horizontalpodautoscalerdetail.GetHorizontalPodAutoscalerDetail(getRequestClient(request), namespace, horizontalpodautoscalerParam)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 
@@ -1529,13 +2473,18 @@ func (apiHandler *APIHandler) handleGetJobList(request *restful.Request,
 	dataSelect := parseDataSelectPathParameter(request)
 	dataSelect.MetricQuery = dataselect.StandardMetrics
 
-	result, err := joblist.GetJobList(apiHandler.client, namespace, dataSelect, &apiHandler.heapsterClient)
+	listOptions, err := parseListOptions(request)
 	if err != nil {
-		handleInternalError(response, err)
+		handleBadRequestError(response, err)
+		return
+	}
+	result, err := joblist.GetJobList(getRequestClient(request), namespace, listOptions, dataSelect, &apiHandler.metricsProvider)
+	if err != nil {
+		handleError(response, err)
 		return
 	}
 
-	response.WriteHeaderAndEntity(http.StatusCreated, result)
+	writeList(request, response, http.StatusCreated, dataSelect, common.ResourceKindJob, result)
 }
 
 func (apiHandler *APIHandler) handleGetJobDetail(request *restful.Request, response *restful.Response) {
@@ -1544,9 +2493,9 @@ func (apiHandler *APIHandler) handleGetJobDetail(request *restful.Request, respo
 	dataSelect := parseDataSelectPathParameter(request)
 	dataSelect.MetricQuery = dataselect.StandardMetrics
 
-	result, err := jobdetail.GetJobDetail(apiHandler.client, apiHandler.heapsterClient, namespace, jobParam)
+	result, err := jobdetail.GetJobDetail(getRequestClient(request), apiHandler.metricsProvider, namespace, jobParam)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 
@@ -1561,10 +2510,10 @@ func (apiHandler *APIHandler) handleGetJobPods(request *restful.Request,
 	jobParam := request.PathParameter("job")
 	dataSelect := parseDataSelectPathParameter(request)
 
-	result, err := jobdetail.GetJobPods(apiHandler.client, apiHandler.heapsterClient, dataSelect,
+	result, err := jobdetail.GetJobPods(getRequestClient(request), apiHandler.metricsProvider, dataSelect,
 		namespace, jobParam)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 
@@ -1577,10 +2526,10 @@ func (apiHandler *APIHandler) handleGetJobEvents(request *restful.Request, respo
 	name := request.PathParameter("job")
 	dataSelect := parseDataSelectPathParameter(request)
 
-	result, err := jobdetail.GetJobEvents(apiHandler.client, dataSelect, namespace,
+	result, err := jobdetail.GetJobEvents(getRequestClient(request), dataSelect, namespace,
 		name)
 	if err != nil {
-		handleInternalError(response, err)
+		handleError(response, err)
 		return
 	}
 	response.WriteHeaderAndEntity(http.StatusCreated, result)
@@ -1618,7 +2567,128 @@ func parsePaginationPathParameter(request *restful.Request) *dataselect.Paginati
 }
 
 func parseFilterPathParameter(request *restful.Request) *dataselect.FilterQuery {
-	return dataselect.NewFilterQuery(strings.Split(request.QueryParameter("filterby"), ","))
+	return dataselect.NewFilterQuery(dataselect.SplitFilterByList(request.QueryParameter("filterby")))
+}
+
+// Parses labelSelector and fieldSelector query parameters of the request and returns list options
+// that are passed to the apiserver. Everything is selected when the parameters are not set.
+func parseListOptions(request *restful.Request) (api.ListOptions, error) {
+	labelSelector, err := labels.Parse(request.QueryParameter("labelSelector"))
+	if err != nil {
+		return api.ListOptions{}, fmt.Errorf("invalid labelSelector: %s", err)
+	}
+
+	fieldSelector, err := fields.ParseSelector(request.QueryParameter("fieldSelector"))
+	if err != nil {
+		return api.ListOptions{}, fmt.Errorf("invalid fieldSelector: %s", err)
+	}
+
+	return api.ListOptions{
+		LabelSelector: labelSelector,
+		FieldSelector: fieldSelector,
+	}, nil
+}
+
+// Fields that can be selected by list calls of resource categories, e.g., workloads. Every kind
+// in a category has them, while other fields are supported only by some kinds.
+var aggregateSelectableFields = map[string]bool{
+	"metadata.name":      true,
+	"metadata.namespace": true,
+}
+
+// Parses list options like parseListOptions does, for list calls that return resources of several
+// kinds. Field selectors are limited to aggregateSelectableFields.
+func parseAggregateListOptions(request *restful.Request) (api.ListOptions, error) {
+	listOptions, err := parseListOptions(request)
+	if err != nil {
+		return listOptions, err
+	}
+
+	for _, requirement := range listOptions.FieldSelector.Requirements() {
+		if !aggregateSelectableFields[requirement.Field] {
+			return api.ListOptions{}, fmt.Errorf("invalid fieldSelector: field %q is not "+
+				"supported for resource categories, only metadata.name and metadata.namespace are",
+				requirement.Field)
+		}
+	}
+
+	return listOptions, nil
+}
+
+// Parses pod log options from previous, sinceSeconds, sinceTime, tailLines and limitBytes query
+// parameters of the request. sinceTime is in RFC 3339 format and can not be combined with
+// sinceSeconds.
+func parsePodLogOptions(request *restful.Request) (*api.PodLogOptions, error) {
+	logOptions := new(api.PodLogOptions)
+	if value := request.QueryParameter("previous"); value != "" {
+		previous, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid previous: %s", err)
+		}
+		logOptions.Previous = previous
+	}
+	for name, option := range map[string]**int64{
+		"sinceSeconds": &logOptions.SinceSeconds,
+		"tailLines":    &logOptions.TailLines,
+		"limitBytes":   &logOptions.LimitBytes,
+	} {
+		if value := request.QueryParameter(name); value != "" {
+			number, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %s", name, err)
+			}
+			if number < 0 || (number == 0 && name != "tailLines") {
+				return nil, fmt.Errorf("invalid %s: must be greater than 0", name)
+			}
+			*option = &number
+		}
+	}
+	if value := request.QueryParameter("sinceTime"); value != "" {
+		if logOptions.SinceSeconds != nil {
+			return nil, fmt.Errorf("sinceTime and sinceSeconds can not be used together")
+		}
+		sinceTime, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sinceTime: %s", err)
+		}
+		since := unversioned.NewTime(sinceTime)
+		logOptions.SinceTime = &since
+	}
+	return logOptions, nil
+}
+
+// Parses drain spec from gracePeriodSeconds, force, ignoreDaemonSets, deleteLocalData and
+// timeoutSeconds query parameters of the request. Parameters that are not set keep their defaults.
+func parseDrainSpec(request *restful.Request) (*node.DrainSpec, error) {
+	spec := new(node.DrainSpec)
+	if value := request.QueryParameter("gracePeriodSeconds"); value != "" {
+		gracePeriodSeconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gracePeriodSeconds: %s", err)
+		}
+		spec.GracePeriodSeconds = &gracePeriodSeconds
+	}
+	if value := request.QueryParameter("timeoutSeconds"); value != "" {
+		timeoutSeconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeoutSeconds: %s", err)
+		}
+		spec.TimeoutSeconds = timeoutSeconds
+	}
+	for name, option := range map[string]*bool{
+		"force":            &spec.Force,
+		"ignoreDaemonSets": &spec.IgnoreDaemonSets,
+		"deleteLocalData":  &spec.DeleteLocalData,
+	} {
+		if value := request.QueryParameter(name); value != "" {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %s", name, err)
+			}
+			*option = enabled
+		}
+	}
+	return spec, nil
 }
 
 // Parses query parameters of the request and returns a SortQuery object
@@ -1656,5 +2726,28 @@ func parseDataSelectPathParameter(request *restful.Request) *dataselect.DataSele
 	sortQuery := parseSortPathParameter(request)
 	filterQuery := parseFilterPathParameter(request)
 	metricQuery := parseMetricPathParameter(request)
-	return dataselect.NewDataSelectQuery(paginationQuery, sortQuery, filterQuery, metricQuery)
+	dataSelect := dataselect.NewDataSelectQuery(paginationQuery, sortQuery, filterQuery, metricQuery)
+	if parseExportFormat(request) != "" {
+		dataSelect.Collector = new(dataselect.DataCellCollector)
+	}
+	return dataSelect
+}
+
+// Parses the format query parameter of the request, or its Accept header if the parameter is not
+// set, and returns the requested export format. Returns an empty string if the request asks for
+// the JSON list.
+func parseExportFormat(request *restful.Request) string {
+	if format := strings.ToLower(request.QueryParameter("format")); format == "json" {
+		return ""
+	} else if format != "" {
+		return format
+	}
+	accept := request.HeaderParameter("Accept")
+	if strings.Contains(accept, export.MIMECSV) {
+		return export.FormatCSV
+	}
+	if strings.Contains(accept, export.MIMEYAML) || strings.Contains(accept, "text/yaml") {
+		return export.FormatYAML
+	}
+	return ""
 }
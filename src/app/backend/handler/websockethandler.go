@@ -0,0 +1,88 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	restful "github.com/emicklei/go-restful"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/container"
+	"golang.org/x/net/websocket"
+)
+
+// serveWebSocket upgrades given request to a WebSocket connection and passes the connection to
+// handlerFn. The connection is closed when handlerFn returns.
+func serveWebSocket(request *restful.Request, response *restful.Response,
+	handlerFn func(*websocket.Conn)) {
+
+	server := websocket.Server{
+		Handler:   handlerFn,
+		Handshake: checkWebSocketOrigin,
+	}
+	server.ServeHTTP(response.ResponseWriter, request.Request)
+}
+
+// checkWebSocketOrigin accepts connections from clients that do not send the Origin header, e.g.,
+// scripts, and from pages served from the same host as the dashboard. Connections opened by pages
+// from other sites are rejected.
+func checkWebSocketOrigin(config *websocket.Config, request *http.Request) error {
+	origin, err := websocket.Origin(config, request)
+	if err != nil {
+		return err
+	}
+	if origin == nil {
+		return nil
+	}
+
+	if origin.Host != request.Host && origin.Host != request.Header.Get("X-Forwarded-Host") {
+		return fmt.Errorf("WebSocket connection from origin %s is not allowed", origin)
+	}
+	config.Origin = origin
+	return nil
+}
+
+// waitForWebSocketClose returns a channel that is closed when the client closes given connection.
+// Everything the client sends is discarded, so it can be used only for one-way connections.
+func waitForWebSocketClose(ws *websocket.Conn) <-chan struct{} {
+	closed := make(chan struct{})
+	go func() {
+		var message []byte
+		for websocket.Message.Receive(ws, &message) == nil {
+		}
+		close(closed)
+	}()
+	return closed
+}
+
+// webSocketTerminalSession is a terminal session of a client connected over a WebSocket. Each
+// WebSocket message holds one JSON encoded container.TerminalMessage.
+type webSocketTerminalSession struct {
+	conn *websocket.Conn
+}
+
+// Read implements container.TerminalSession.
+func (self *webSocketTerminalSession) Read() (*container.TerminalMessage, error) {
+	message := &container.TerminalMessage{}
+	if err := websocket.JSON.Receive(self.conn, message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// Write implements container.TerminalSession.
+func (self *webSocketTerminalSession) Write(message *container.TerminalMessage) error {
+	return websocket.JSON.Send(self.conn, message)
+}
@@ -61,4 +61,19 @@ type Event struct {
 
 	// Event type (at the moment only normal and warning are supported).
 	Type string `json:"type"`
+
+	// Object that the event is about.
+	InvolvedObject InvolvedObject `json:"involvedObject"`
+}
+
+// InvolvedObject is a reference to the object that an event is about.
+type InvolvedObject struct {
+	// Kind of the object, e.g., Pod.
+	Kind string `json:"kind"`
+
+	// Name of the object.
+	Name string `json:"name"`
+
+	// Namespace of the object. Empty for objects that are not namespaced.
+	Namespace string `json:"namespace"`
 }
@@ -15,20 +15,15 @@
 package common
 
 import (
-	"encoding/json"
-	"fmt"
 	"log"
-	"strings"
 	"time"
 
 	"github.com/kubernetes/dashboard/src/app/backend/client"
-
-	heapster "k8s.io/heapster/metrics/api/v1/types"
 )
 
 const (
-	CpuUsage    = "cpu/usage_rate"
-	MemoryUsage = "memory/usage"
+	CpuUsage    = client.CPUUsageMetric
+	MemoryUsage = client.MemoryUsageMetric
 )
 
 // MetricsByPod is a metrics map by pod name.
@@ -58,32 +53,22 @@ type PodMetrics struct {
 }
 
 // Return Pods metrics for the given list of pods. Returns error in case of errors when talking
-// with heapster.
+// with metrics provider.
 func getPodListMetrics(podNamesByNamespace map[string][]string,
-	heapsterClient client.HeapsterClient) (*MetricsByPod, error) {
+	metricsProvider client.MetricsProvider) (*MetricsByPod, error) {
 	log.Printf("Getting pod metrics")
 
 	result := &MetricsByPod{MetricsMap: make(map[string]map[string]PodMetrics)}
 
 	for namespace, podNames := range podNamesByNamespace {
-		metricCPUUsagePath := createMetricPath(namespace, podNames, CpuUsage)
-		metricMemUsagePath := createMetricPath(namespace, podNames, MemoryUsage)
-
-		resultCPUUsageRaw, err := getRawMetrics(heapsterClient, metricCPUUsagePath)
+		cpuMetricResult, err := metricsProvider.DownloadMetric(
+			createPodMetricQuery(namespace, podNames, CpuUsage))
 		if err != nil {
 			return nil, err
 		}
 
-		resultMemUsageRaw, err := getRawMetrics(heapsterClient, metricMemUsagePath)
-		if err != nil {
-			return nil, err
-		}
-
-		cpuMetricResult, err := unmarshalMetrics(resultCPUUsageRaw)
-		if err != nil {
-			return nil, err
-		}
-		memMetricResult, err := unmarshalMetrics(resultMemUsageRaw)
+		memMetricResult, err := metricsProvider.DownloadMetric(
+			createPodMetricQuery(namespace, podNames, MemoryUsage))
 		if err != nil {
 			return nil, err
 		}
@@ -99,43 +84,25 @@ func getPodListMetrics(podNamesByNamespace map[string][]string,
 	return result, nil
 }
 
-// Create URL path for metrics.
-func createMetricPath(namespace string, podNames []string, metricName string) string {
-	return fmt.Sprintf("/model/namespaces/%s/pod-list/%s/metrics/%s",
-		namespace,
-		strings.Join(podNames, ","),
-		metricName)
-}
-
-// Retrieves raw metrics from Heapster.
-func getRawMetrics(heapsterClient client.HeapsterClient, metricPath string) ([]byte, error) {
-	resultRaw, err := heapsterClient.Get(metricPath).DoRaw()
-
-	if err != nil {
-		return make([]byte, 0), err
-	}
-	return resultRaw, nil
-}
-
-// Deserialize raw metrics to object.
-func unmarshalMetrics(rawData []byte) ([]heapster.MetricResult, error) {
-	metricResultList := &heapster.MetricResultList{}
-	err := json.Unmarshal(rawData, metricResultList)
-	if err != nil {
-		return make([]heapster.MetricResult, 0), err
+// Create metrics provider query for the given pods.
+func createPodMetricQuery(namespace string, podNames []string, metricName string) client.MetricQuery {
+	return client.MetricQuery{
+		MetricName:    metricName,
+		ResourceKind:  client.PodMetricResource,
+		Namespace:     namespace,
+		ResourceNames: podNames,
 	}
-	return metricResultList.Items, nil
 }
 
 // Create response structure for API call.
-func fillPodMetrics(cpuMetrics []heapster.MetricResult, memMetrics []heapster.MetricResult,
+func fillPodMetrics(cpuMetrics []client.MetricSamples, memMetrics []client.MetricSamples,
 	podNames []string, result map[string]PodMetrics) {
 	if len(cpuMetrics) == len(podNames) && len(memMetrics) == len(podNames) {
 		for iterator, podName := range podNames {
 			var memValue *uint64
 			var cpuValue *uint64
-			memMetricsList := memMetrics[iterator].Metrics
-			cpuMetricsList := cpuMetrics[iterator].Metrics
+			memMetricsList := memMetrics[iterator]
+			cpuMetricsList := cpuMetrics[iterator]
 
 			if len(memMetricsList) > 0 {
 				memValue = &memMetricsList[len(memMetricsList)-1].Value
@@ -0,0 +1,367 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/meta"
+	"k8s.io/kubernetes/pkg/apis/apps"
+	"k8s.io/kubernetes/pkg/apis/batch"
+	"k8s.io/kubernetes/pkg/apis/extensions"
+	"k8s.io/kubernetes/pkg/client/cache"
+	client "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
+	"k8s.io/kubernetes/pkg/labels"
+	"k8s.io/kubernetes/pkg/runtime"
+	"k8s.io/kubernetes/pkg/watch"
+)
+
+// resourceCache is a cache used by Get*ListChannel functions. Nil when the cache is disabled.
+var resourceCache *ResourceCache
+var resourceCacheMutex sync.RWMutex
+
+// ResourceCache is a cache of resource lists that is shared by all API requests. It is kept up
+// to date by watching the apiserver, so that requests do not have to list resources on their own.
+type ResourceCache struct {
+	// Client used to list and watch resources. Only requests made with the same client are
+	// served from the cache. Requests made with credentials of users are always sent to the
+	// apiserver, so that users see only resources they are authorized to list.
+	client client.Interface
+
+	resources map[ResourceKind]*cachedResource
+}
+
+// cachedResource is an informer of a single resource kind together with its cache age.
+type cachedResource struct {
+	informer cache.SharedIndexInformer
+
+	mutex      sync.RWMutex
+	syncedAt   time.Time
+	lastUpdate time.Time
+}
+
+// ResourceCacheStatus describes the state of the resource cache.
+type ResourceCacheStatus struct {
+	// True when the resource cache is enabled.
+	Enabled bool `json:"enabled"`
+
+	// Status of cache of every resource kind. Empty when the cache is disabled.
+	Resources []CachedResourceStatus `json:"resources"`
+}
+
+// CachedResourceStatus describes the state of the cache of a single resource kind.
+type CachedResourceStatus struct {
+	Kind ResourceKind `json:"kind"`
+
+	// True when the initial list of resources has been loaded. Requests are sent directly to the
+	// apiserver until then.
+	Synced bool `json:"synced"`
+
+	// Time when the initial list of resources has been loaded.
+	SyncedAt *time.Time `json:"syncedAt,omitempty"`
+
+	// Time when the cache last received data from the apiserver.
+	LastUpdate *time.Time `json:"lastUpdate,omitempty"`
+
+	// Age of the cache, i.e., number of seconds since the last update.
+	AgeSeconds int64 `json:"ageSeconds"`
+}
+
+// NewResourceCache creates resource cache for pods, events, replication controllers, replica
+// sets, deployments, daemon sets, jobs, stateful sets, services, nodes and namespaces. The cache
+// has to be started with Run.
+func NewResourceCache(client client.Interface) *ResourceCache {
+	listWatches := map[ResourceKind]struct {
+		objType   runtime.Object
+		listWatch *cache.ListWatch
+	}{
+		ResourceKindPod: {&api.Pod{}, &cache.ListWatch{
+			ListFunc: func(options api.ListOptions) (runtime.Object, error) {
+				return client.Core().Pods(api.NamespaceAll).List(options)
+			},
+			WatchFunc: func(options api.ListOptions) (watch.Interface, error) {
+				return client.Core().Pods(api.NamespaceAll).Watch(options)
+			},
+		}},
+		ResourceKindEvent: {&api.Event{}, &cache.ListWatch{
+			ListFunc: func(options api.ListOptions) (runtime.Object, error) {
+				return client.Core().Events(api.NamespaceAll).List(options)
+			},
+			WatchFunc: func(options api.ListOptions) (watch.Interface, error) {
+				return client.Core().Events(api.NamespaceAll).Watch(options)
+			},
+		}},
+		ResourceKindReplicationController: {&api.ReplicationController{}, &cache.ListWatch{
+			ListFunc: func(options api.ListOptions) (runtime.Object, error) {
+				return client.Core().ReplicationControllers(api.NamespaceAll).List(options)
+			},
+			WatchFunc: func(options api.ListOptions) (watch.Interface, error) {
+				return client.Core().ReplicationControllers(api.NamespaceAll).Watch(options)
+			},
+		}},
+		ResourceKindReplicaSet: {&extensions.ReplicaSet{}, &cache.ListWatch{
+			ListFunc: func(options api.ListOptions) (runtime.Object, error) {
+				return client.Extensions().ReplicaSets(api.NamespaceAll).List(options)
+			},
+			WatchFunc: func(options api.ListOptions) (watch.Interface, error) {
+				return client.Extensions().ReplicaSets(api.NamespaceAll).Watch(options)
+			},
+		}},
+		ResourceKindDeployment: {&extensions.Deployment{}, &cache.ListWatch{
+			ListFunc: func(options api.ListOptions) (runtime.Object, error) {
+				return client.Extensions().Deployments(api.NamespaceAll).List(options)
+			},
+			WatchFunc: func(options api.ListOptions) (watch.Interface, error) {
+				return client.Extensions().Deployments(api.NamespaceAll).Watch(options)
+			},
+		}},
+		ResourceKindDaemonSet: {&extensions.DaemonSet{}, &cache.ListWatch{
+			ListFunc: func(options api.ListOptions) (runtime.Object, error) {
+				return client.Extensions().DaemonSets(api.NamespaceAll).List(options)
+			},
+			WatchFunc: func(options api.ListOptions) (watch.Interface, error) {
+				return client.Extensions().DaemonSets(api.NamespaceAll).Watch(options)
+			},
+		}},
+		ResourceKindJob: {&batch.Job{}, &cache.ListWatch{
+			ListFunc: func(options api.ListOptions) (runtime.Object, error) {
+				return client.Batch().Jobs(api.NamespaceAll).List(options)
+			},
+			WatchFunc: func(options api.ListOptions) (watch.Interface, error) {
+				return client.Batch().Jobs(api.NamespaceAll).Watch(options)
+			},
+		}},
+		ResourceKindStatefulSet: {&apps.StatefulSet{}, &cache.ListWatch{
+			ListFunc: func(options api.ListOptions) (runtime.Object, error) {
+				return client.Apps().StatefulSets(api.NamespaceAll).List(options)
+			},
+			WatchFunc: func(options api.ListOptions) (watch.Interface, error) {
+				return client.Apps().StatefulSets(api.NamespaceAll).Watch(options)
+			},
+		}},
+		ResourceKindService: {&api.Service{}, &cache.ListWatch{
+			ListFunc: func(options api.ListOptions) (runtime.Object, error) {
+				return client.Core().Services(api.NamespaceAll).List(options)
+			},
+			WatchFunc: func(options api.ListOptions) (watch.Interface, error) {
+				return client.Core().Services(api.NamespaceAll).Watch(options)
+			},
+		}},
+		ResourceKindNode: {&api.Node{}, &cache.ListWatch{
+			ListFunc: func(options api.ListOptions) (runtime.Object, error) {
+				return client.Core().Nodes().List(options)
+			},
+			WatchFunc: func(options api.ListOptions) (watch.Interface, error) {
+				return client.Core().Nodes().Watch(options)
+			},
+		}},
+		ResourceKindNamespace: {&api.Namespace{}, &cache.ListWatch{
+			ListFunc: func(options api.ListOptions) (runtime.Object, error) {
+				return client.Core().Namespaces().List(options)
+			},
+			WatchFunc: func(options api.ListOptions) (watch.Interface, error) {
+				return client.Core().Namespaces().Watch(options)
+			},
+		}},
+	}
+
+	resourceCache := &ResourceCache{
+		client:    client,
+		resources: make(map[ResourceKind]*cachedResource),
+	}
+	for kind, lw := range listWatches {
+		resource := &cachedResource{
+			informer: cache.NewSharedIndexInformer(lw.listWatch, lw.objType, 0,
+				cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}),
+		}
+		resource.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { resource.touch() },
+			UpdateFunc: func(oldObj, newObj interface{}) { resource.touch() },
+			DeleteFunc: func(obj interface{}) { resource.touch() },
+		})
+		resourceCache.resources[kind] = resource
+	}
+
+	return resourceCache
+}
+
+// Run starts watching resources. Watches are stopped when stopCh is closed.
+func (self *ResourceCache) Run(stopCh <-chan struct{}) {
+	for kind, resource := range self.resources {
+		go resource.informer.Run(stopCh)
+		go func(kind ResourceKind, resource *cachedResource) {
+			if cache.WaitForCacheSync(stopCh, resource.informer.HasSynced) {
+				log.Printf("Resource cache for %s synced", kind)
+				resource.markSynced()
+			}
+		}(kind, resource)
+	}
+}
+
+// HasSynced returns true when initial lists of all resources have been loaded.
+func (self *ResourceCache) HasSynced() bool {
+	for _, resource := range self.resources {
+		if !resource.informer.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
+// GetStatus returns status of the cache of every resource kind.
+func (self *ResourceCache) GetStatus() ResourceCacheStatus {
+	status := ResourceCacheStatus{Enabled: true, Resources: make([]CachedResourceStatus, 0)}
+	now := time.Now()
+	for kind, resource := range self.resources {
+		status.Resources = append(status.Resources, resource.getStatus(kind, now))
+	}
+	sort.Sort(cachedResourceStatusByKind(status.Resources))
+	return status
+}
+
+// list returns copies of resources of the given kind that match the namespace query and list
+// options. The second value is false when the cache cannot serve the request and it has to be sent
+// directly to the apiserver.
+func (self *ResourceCache) list(client client.Interface, kind ResourceKind,
+	nsQuery *NamespaceQuery, options api.ListOptions) ([]interface{}, bool) {
+
+	resource, ok := self.resources[kind]
+	if !ok || client != self.client || !resource.informer.HasSynced() {
+		return nil, false
+	}
+	// Field selectors are evaluated by the apiserver only.
+	if options.FieldSelector != nil && !options.FieldSelector.Empty() {
+		return nil, false
+	}
+
+	var items []interface{}
+	indexer := resource.informer.GetIndexer()
+	if nsQuery != nil && nsQuery.ToRequestParam() != api.NamespaceAll {
+		var err error
+		items, err = indexer.ByIndex(cache.NamespaceIndex, nsQuery.ToRequestParam())
+		if err != nil {
+			return nil, false
+		}
+	} else {
+		items = indexer.List()
+	}
+
+	result := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		objectMeta, err := meta.Accessor(item)
+		if err != nil {
+			return nil, false
+		}
+		if nsQuery != nil && !nsQuery.Matches(objectMeta.GetNamespace()) {
+			continue
+		}
+		if options.LabelSelector != nil &&
+			!options.LabelSelector.Matches(labels.Set(objectMeta.GetLabels())) {
+			continue
+		}
+		// Objects of the cache are shared by all requests, callers get their own copies.
+		copied, err := api.Scheme.DeepCopy(item)
+		if err != nil {
+			return nil, false
+		}
+		result = append(result, copied)
+	}
+
+	// Keep the order of the apiserver, i.e., by namespace and name.
+	sort.Sort(objectsByKey(result))
+	return result, true
+}
+
+func (self *cachedResource) touch() {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	self.lastUpdate = time.Now()
+}
+
+func (self *cachedResource) markSynced() {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	self.syncedAt = time.Now()
+	if self.lastUpdate.Before(self.syncedAt) {
+		self.lastUpdate = self.syncedAt
+	}
+}
+
+func (self *cachedResource) getStatus(kind ResourceKind, now time.Time) CachedResourceStatus {
+	self.mutex.RLock()
+	defer self.mutex.RUnlock()
+
+	status := CachedResourceStatus{Kind: kind}
+	if !self.syncedAt.IsZero() {
+		syncedAt := self.syncedAt
+		lastUpdate := self.lastUpdate
+		status.Synced = true
+		status.SyncedAt = &syncedAt
+		status.LastUpdate = &lastUpdate
+		status.AgeSeconds = int64(now.Sub(lastUpdate).Seconds())
+	}
+	return status
+}
+
+type cachedResourceStatusByKind []CachedResourceStatus
+
+func (a cachedResourceStatusByKind) Len() int           { return len(a) }
+func (a cachedResourceStatusByKind) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a cachedResourceStatusByKind) Less(i, j int) bool { return a[i].Kind < a[j].Kind }
+
+type objectsByKey []interface{}
+
+func (a objectsByKey) Len() int      { return len(a) }
+func (a objectsByKey) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a objectsByKey) Less(i, j int) bool {
+	iKey, _ := cache.MetaNamespaceKeyFunc(a[i])
+	jKey, _ := cache.MetaNamespaceKeyFunc(a[j])
+	return iKey < jKey
+}
+
+// SetResourceCache makes Get*ListChannel functions read resources from the given cache whenever
+// it is synced. Passing nil disables the cache.
+func SetResourceCache(cache *ResourceCache) {
+	resourceCacheMutex.Lock()
+	defer resourceCacheMutex.Unlock()
+	resourceCache = cache
+}
+
+// GetResourceCacheStatus returns status of the resource cache.
+func GetResourceCacheStatus() ResourceCacheStatus {
+	resourceCacheMutex.RLock()
+	defer resourceCacheMutex.RUnlock()
+	if resourceCache == nil {
+		return ResourceCacheStatus{Enabled: false, Resources: make([]CachedResourceStatus, 0)}
+	}
+	return resourceCache.GetStatus()
+}
+
+// listFromCache returns resources from the resource cache, see ResourceCache.list. The second
+// value is false when the cache is disabled or cannot serve the request.
+func listFromCache(client client.Interface, kind ResourceKind, nsQuery *NamespaceQuery,
+	options api.ListOptions) ([]interface{}, bool) {
+
+	resourceCacheMutex.RLock()
+	defer resourceCacheMutex.RUnlock()
+	if resourceCache == nil {
+		return nil, false
+	}
+	return resourceCache.list(client, kind, nsQuery, options)
+}
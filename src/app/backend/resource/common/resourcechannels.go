@@ -38,6 +38,9 @@ import (
 // When a channel is nil, it means that no resource list is available for getting.
 //
 // Each channel pair can be read up to N times. N is specified upon creation of the channels.
+//
+// Lists of pods, events, workloads, services, nodes and namespaces are read from the shared
+// resource cache when it is enabled and synced, see ResourceCache.
 type ResourceChannels struct {
 	// List and error channels to Replication Controllers.
 	ReplicationControllerList ReplicationControllerListChannel
@@ -108,15 +111,29 @@ type ServiceListChannel struct {
 
 // GetServiceListChannel returns a pair of channels to a Service list and errors that both
 // must be read numReads times.
-func GetServiceListChannel(client client.Interface,
-	nsQuery *NamespaceQuery, numReads int) ServiceListChannel {
+func GetServiceListChannel(client client.Interface, nsQuery *NamespaceQuery, numReads int) ServiceListChannel {
+	return GetServiceListChannelWithOptions(client, nsQuery, ListEverything, numReads)
+}
+
+// GetServiceListChannelWithOptions is GetServiceListChannel plus list options.
+func GetServiceListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery, options api.ListOptions,
+	numReads int) ServiceListChannel {
 
 	channel := ServiceListChannel{
 		List:  make(chan *api.ServiceList, numReads),
 		Error: make(chan error, numReads),
 	}
 	go func() {
-		list, err := client.Core().Services(nsQuery.ToRequestParam()).List(listEverything)
+		var list *api.ServiceList
+		var err error
+		if items, ok := listFromCache(client, ResourceKindService, nsQuery, options); ok {
+			list = &api.ServiceList{}
+			for _, item := range items {
+				list.Items = append(list.Items, *item.(*api.Service))
+			}
+		} else {
+			list, err = client.Core().Services(nsQuery.ToRequestParam()).List(options)
+		}
 		var filteredItems []api.Service
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -141,7 +158,12 @@ type IngressListChannel struct {
 
 // GetIngressListChannel returns a pair of channels to a Ingress list and errors that both
 // must be read numReads times.
-func GetIngressListChannel(client client.Interface, nsQuery *NamespaceQuery,
+func GetIngressListChannel(client client.Interface, nsQuery *NamespaceQuery, numReads int) IngressListChannel {
+	return GetIngressListChannelWithOptions(client, nsQuery, ListEverything, numReads)
+}
+
+// GetIngressListChannelWithOptions is GetIngressListChannel plus list options.
+func GetIngressListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery, options api.ListOptions,
 	numReads int) IngressListChannel {
 
 	channel := IngressListChannel{
@@ -149,7 +171,7 @@ func GetIngressListChannel(client client.Interface, nsQuery *NamespaceQuery,
 		Error: make(chan error, numReads),
 	}
 	go func() {
-		list, err := client.Extensions().Ingresses(nsQuery.ToRequestParam()).List(listEverything)
+		list, err := client.Extensions().Ingresses(nsQuery.ToRequestParam()).List(options)
 		var filteredItems []extensions.Ingress
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -174,7 +196,12 @@ type LimitRangeListChannel struct {
 
 // GetLimitRangeListChannel returns a pair of channels to a LimitRange list and errors that
 // both must be read numReads times.
-func GetLimitRangeListChannel(client client.Interface, nsQuery *NamespaceQuery,
+func GetLimitRangeListChannel(client client.Interface, nsQuery *NamespaceQuery, numReads int) LimitRangeListChannel {
+	return GetLimitRangeListChannelWithOptions(client, nsQuery, ListEverything, numReads)
+}
+
+// GetLimitRangeListChannelWithOptions is GetLimitRangeListChannel plus list options.
+func GetLimitRangeListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery, options api.ListOptions,
 	numReads int) LimitRangeListChannel {
 
 	channel := LimitRangeListChannel{
@@ -183,7 +210,7 @@ func GetLimitRangeListChannel(client client.Interface, nsQuery *NamespaceQuery,
 	}
 
 	go func() {
-		list, err := client.Core().LimitRanges(nsQuery.ToRequestParam()).List(listEverything)
+		list, err := client.Core().LimitRanges(nsQuery.ToRequestParam()).List(options)
 		for i := 0; i < numReads; i++ {
 			channel.List <- list
 			channel.Error <- err
@@ -202,13 +229,28 @@ type NodeListChannel struct {
 // GetNodeListChannel returns a pair of channels to a Node list and errors that both must be read
 // numReads times.
 func GetNodeListChannel(client client.Interface, numReads int) NodeListChannel {
+	return GetNodeListChannelWithOptions(client, ListEverything, numReads)
+}
+
+// GetNodeListChannelWithOptions is GetNodeListChannel plus list options.
+func GetNodeListChannelWithOptions(client client.Interface, options api.ListOptions,
+	numReads int) NodeListChannel {
 	channel := NodeListChannel{
 		List:  make(chan *api.NodeList, numReads),
 		Error: make(chan error, numReads),
 	}
 
 	go func() {
-		list, err := client.Core().Nodes().List(listEverything)
+		var list *api.NodeList
+		var err error
+		if items, ok := listFromCache(client, ResourceKindNode, nil, options); ok {
+			list = &api.NodeList{}
+			for _, item := range items {
+				list.Items = append(list.Items, *item.(*api.Node))
+			}
+		} else {
+			list, err = client.Core().Nodes().List(options)
+		}
 		for i := 0; i < numReads; i++ {
 			channel.List <- list
 			channel.Error <- err
@@ -227,13 +269,28 @@ type NamespaceListChannel struct {
 // GetNamespaceListChannel returns a pair of channels to a Namespace list and errors that both must be read
 // numReads times.
 func GetNamespaceListChannel(client client.Interface, numReads int) NamespaceListChannel {
+	return GetNamespaceListChannelWithOptions(client, ListEverything, numReads)
+}
+
+// GetNamespaceListChannelWithOptions is GetNamespaceListChannel plus list options.
+func GetNamespaceListChannelWithOptions(client client.Interface, options api.ListOptions,
+	numReads int) NamespaceListChannel {
 	channel := NamespaceListChannel{
 		List:  make(chan *api.NamespaceList, numReads),
 		Error: make(chan error, numReads),
 	}
 
 	go func() {
-		list, err := client.Core().Namespaces().List(listEverything)
+		var list *api.NamespaceList
+		var err error
+		if items, ok := listFromCache(client, ResourceKindNamespace, nil, options); ok {
+			list = &api.NamespaceList{}
+			for _, item := range items {
+				list.Items = append(list.Items, *item.(*api.Namespace))
+			}
+		} else {
+			list, err = client.Core().Namespaces().List(options)
+		}
 		for i := 0; i < numReads; i++ {
 			channel.List <- list
 			channel.Error <- err
@@ -253,7 +310,7 @@ type EventListChannel struct {
 // numReads times.
 func GetEventListChannel(client client.Interface,
 	nsQuery *NamespaceQuery, numReads int) EventListChannel {
-	return GetEventListChannelWithOptions(client, nsQuery, listEverything, numReads)
+	return GetEventListChannelWithOptions(client, nsQuery, ListEverything, numReads)
 }
 
 // GetEventListChannelWithOptions is GetEventListChannel plus list options.
@@ -265,7 +322,16 @@ func GetEventListChannelWithOptions(client client.Interface,
 	}
 
 	go func() {
-		list, err := client.Core().Events(nsQuery.ToRequestParam()).List(options)
+		var list *api.EventList
+		var err error
+		if items, ok := listFromCache(client, ResourceKindEvent, nsQuery, options); ok {
+			list = &api.EventList{}
+			for _, item := range items {
+				list.Items = append(list.Items, *item.(*api.Event))
+			}
+		} else {
+			list, err = client.Core().Events(nsQuery.ToRequestParam()).List(options)
+		}
 		var filteredItems []api.Event
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -292,7 +358,7 @@ type PodListChannel struct {
 // numReads times.
 func GetPodListChannel(client client.Interface,
 	nsQuery *NamespaceQuery, numReads int) PodListChannel {
-	return GetPodListChannelWithOptions(client, nsQuery, listEverything, numReads)
+	return GetPodListChannelWithOptions(client, nsQuery, ListEverything, numReads)
 }
 
 // GetPodListChannelWithOptions is GetPodListChannel plus listing options.
@@ -305,7 +371,16 @@ func GetPodListChannelWithOptions(client client.Interface, nsQuery *NamespaceQue
 	}
 
 	go func() {
-		list, err := client.Core().Pods(nsQuery.ToRequestParam()).List(options)
+		var list *api.PodList
+		var err error
+		if items, ok := listFromCache(client, ResourceKindPod, nsQuery, options); ok {
+			list = &api.PodList{}
+			for _, item := range items {
+				list.Items = append(list.Items, *item.(*api.Pod))
+			}
+		} else {
+			list, err = client.Core().Pods(nsQuery.ToRequestParam()).List(options)
+		}
 		var filteredItems []api.Pod
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -331,8 +406,13 @@ type ReplicationControllerListChannel struct {
 // GetReplicationControllerListChannel Returns a pair of channels to a
 // Replication Controller list and errors that both must be read
 // numReads times.
-func GetReplicationControllerListChannel(client client.Interface,
-	nsQuery *NamespaceQuery, numReads int) ReplicationControllerListChannel {
+func GetReplicationControllerListChannel(client client.Interface, nsQuery *NamespaceQuery, numReads int) ReplicationControllerListChannel {
+	return GetReplicationControllerListChannelWithOptions(client, nsQuery, ListEverything, numReads)
+}
+
+// GetReplicationControllerListChannelWithOptions is GetReplicationControllerListChannel plus list options.
+func GetReplicationControllerListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery, options api.ListOptions,
+	numReads int) ReplicationControllerListChannel {
 
 	channel := ReplicationControllerListChannel{
 		List:  make(chan *api.ReplicationControllerList, numReads),
@@ -340,7 +420,16 @@ func GetReplicationControllerListChannel(client client.Interface,
 	}
 
 	go func() {
-		list, err := client.Core().ReplicationControllers(nsQuery.ToRequestParam()).List(listEverything)
+		var list *api.ReplicationControllerList
+		var err error
+		if items, ok := listFromCache(client, ResourceKindReplicationController, nsQuery, options); ok {
+			list = &api.ReplicationControllerList{}
+			for _, item := range items {
+				list.Items = append(list.Items, *item.(*api.ReplicationController))
+			}
+		} else {
+			list, err = client.Core().ReplicationControllers(nsQuery.ToRequestParam()).List(options)
+		}
 		var filteredItems []api.ReplicationController
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -365,8 +454,13 @@ type DeploymentListChannel struct {
 
 // GetDeploymentListChannel returns a pair of channels to a Deployment list and errors
 // that both must be read numReads times.
-func GetDeploymentListChannel(client client.Interface,
-	nsQuery *NamespaceQuery, numReads int) DeploymentListChannel {
+func GetDeploymentListChannel(client client.Interface, nsQuery *NamespaceQuery, numReads int) DeploymentListChannel {
+	return GetDeploymentListChannelWithOptions(client, nsQuery, ListEverything, numReads)
+}
+
+// GetDeploymentListChannelWithOptions is GetDeploymentListChannel plus list options.
+func GetDeploymentListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery, options api.ListOptions,
+	numReads int) DeploymentListChannel {
 
 	channel := DeploymentListChannel{
 		List:  make(chan *extensions.DeploymentList, numReads),
@@ -374,7 +468,16 @@ func GetDeploymentListChannel(client client.Interface,
 	}
 
 	go func() {
-		list, err := client.Extensions().Deployments(nsQuery.ToRequestParam()).List(listEverything)
+		var list *extensions.DeploymentList
+		var err error
+		if items, ok := listFromCache(client, ResourceKindDeployment, nsQuery, options); ok {
+			list = &extensions.DeploymentList{}
+			for _, item := range items {
+				list.Items = append(list.Items, *item.(*extensions.Deployment))
+			}
+		} else {
+			list, err = client.Extensions().Deployments(nsQuery.ToRequestParam()).List(options)
+		}
 		var filteredItems []extensions.Deployment
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -401,7 +504,7 @@ type ReplicaSetListChannel struct {
 // errors that both must be read numReads times.
 func GetReplicaSetListChannel(client client.Interface,
 	nsQuery *NamespaceQuery, numReads int) ReplicaSetListChannel {
-	return GetReplicaSetListChannelWithOptions(client, nsQuery, listEverything, numReads)
+	return GetReplicaSetListChannelWithOptions(client, nsQuery, ListEverything, numReads)
 }
 
 // GetReplicaSetListChannelWithOptions returns a pair of channels to a ReplicaSet list filtered
@@ -414,7 +517,16 @@ func GetReplicaSetListChannelWithOptions(client client.Interface,
 	}
 
 	go func() {
-		list, err := client.Extensions().ReplicaSets(nsQuery.ToRequestParam()).List(options)
+		var list *extensions.ReplicaSetList
+		var err error
+		if items, ok := listFromCache(client, ResourceKindReplicaSet, nsQuery, options); ok {
+			list = &extensions.ReplicaSetList{}
+			for _, item := range items {
+				list.Items = append(list.Items, *item.(*extensions.ReplicaSet))
+			}
+		} else {
+			list, err = client.Extensions().ReplicaSets(nsQuery.ToRequestParam()).List(options)
+		}
 		var filteredItems []extensions.ReplicaSet
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -439,15 +551,29 @@ type DaemonSetListChannel struct {
 
 // GetDaemonSetListChannel returns a pair of channels to a DaemonSet list and errors that
 // both must be read numReads times.
-func GetDaemonSetListChannel(client client.Interface,
-	nsQuery *NamespaceQuery, numReads int) DaemonSetListChannel {
+func GetDaemonSetListChannel(client client.Interface, nsQuery *NamespaceQuery, numReads int) DaemonSetListChannel {
+	return GetDaemonSetListChannelWithOptions(client, nsQuery, ListEverything, numReads)
+}
+
+// GetDaemonSetListChannelWithOptions is GetDaemonSetListChannel plus list options.
+func GetDaemonSetListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery, options api.ListOptions,
+	numReads int) DaemonSetListChannel {
 	channel := DaemonSetListChannel{
 		List:  make(chan *extensions.DaemonSetList, numReads),
 		Error: make(chan error, numReads),
 	}
 
 	go func() {
-		list, err := client.Extensions().DaemonSets(nsQuery.ToRequestParam()).List(listEverything)
+		var list *extensions.DaemonSetList
+		var err error
+		if items, ok := listFromCache(client, ResourceKindDaemonSet, nsQuery, options); ok {
+			list = &extensions.DaemonSetList{}
+			for _, item := range items {
+				list.Items = append(list.Items, *item.(*extensions.DaemonSet))
+			}
+		} else {
+			list, err = client.Extensions().DaemonSets(nsQuery.ToRequestParam()).List(options)
+		}
 		var filteredItems []extensions.DaemonSet
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -472,15 +598,29 @@ type JobListChannel struct {
 
 // GetJobListChannel returns a pair of channels to a Job list and errors that
 // both must be read numReads times.
-func GetJobListChannel(client client.Interface,
-	nsQuery *NamespaceQuery, numReads int) JobListChannel {
+func GetJobListChannel(client client.Interface, nsQuery *NamespaceQuery, numReads int) JobListChannel {
+	return GetJobListChannelWithOptions(client, nsQuery, ListEverything, numReads)
+}
+
+// GetJobListChannelWithOptions is GetJobListChannel plus list options.
+func GetJobListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery, options api.ListOptions,
+	numReads int) JobListChannel {
 	channel := JobListChannel{
 		List:  make(chan *batch.JobList, numReads),
 		Error: make(chan error, numReads),
 	}
 
 	go func() {
-		list, err := client.Batch().Jobs(nsQuery.ToRequestParam()).List(listEverything)
+		var list *batch.JobList
+		var err error
+		if items, ok := listFromCache(client, ResourceKindJob, nsQuery, options); ok {
+			list = &batch.JobList{}
+			for _, item := range items {
+				list.Items = append(list.Items, *item.(*batch.Job))
+			}
+		} else {
+			list, err = client.Batch().Jobs(nsQuery.ToRequestParam()).List(options)
+		}
 		var filteredItems []batch.Job
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -505,15 +645,29 @@ type StatefulSetListChannel struct {
 
 // GetStatefulSetListChannel returns a pair of channels to a StatefulSet list and errors that
 // both must be read numReads times.
-func GetStatefulSetListChannel(client client.Interface,
-	nsQuery *NamespaceQuery, numReads int) StatefulSetListChannel {
+func GetStatefulSetListChannel(client client.Interface, nsQuery *NamespaceQuery, numReads int) StatefulSetListChannel {
+	return GetStatefulSetListChannelWithOptions(client, nsQuery, ListEverything, numReads)
+}
+
+// GetStatefulSetListChannelWithOptions is GetStatefulSetListChannel plus list options.
+func GetStatefulSetListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery, options api.ListOptions,
+	numReads int) StatefulSetListChannel {
 	channel := StatefulSetListChannel{
 		List:  make(chan *apps.StatefulSetList, numReads),
 		Error: make(chan error, numReads),
 	}
 
 	go func() {
-		statefulSets, err := client.Apps().StatefulSets(nsQuery.ToRequestParam()).List(listEverything)
+		var statefulSets *apps.StatefulSetList
+		var err error
+		if items, ok := listFromCache(client, ResourceKindStatefulSet, nsQuery, options); ok {
+			statefulSets = &apps.StatefulSetList{}
+			for _, item := range items {
+				statefulSets.Items = append(statefulSets.Items, *item.(*apps.StatefulSet))
+			}
+		} else {
+			statefulSets, err = client.Apps().StatefulSets(nsQuery.ToRequestParam()).List(options)
+		}
 		var filteredItems []apps.StatefulSet
 		for _, item := range statefulSets.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -539,6 +693,12 @@ type ConfigMapListChannel struct {
 // GetConfigMapListChannel returns a pair of channels to a ConfigMap list and errors that
 // both must be read numReads times.
 func GetConfigMapListChannel(client client.Interface, nsQuery *NamespaceQuery, numReads int) ConfigMapListChannel {
+	return GetConfigMapListChannelWithOptions(client, nsQuery, ListEverything, numReads)
+}
+
+// GetConfigMapListChannelWithOptions is GetConfigMapListChannel plus list options.
+func GetConfigMapListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery, options api.ListOptions,
+	numReads int) ConfigMapListChannel {
 
 	channel := ConfigMapListChannel{
 		List:  make(chan *api.ConfigMapList, numReads),
@@ -546,7 +706,7 @@ func GetConfigMapListChannel(client client.Interface, nsQuery *NamespaceQuery, n
 	}
 
 	go func() {
-		list, err := client.Core().ConfigMaps(nsQuery.ToRequestParam()).List(listEverything)
+		list, err := client.Core().ConfigMaps(nsQuery.ToRequestParam()).List(options)
 		var filteredItems []api.ConfigMap
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -572,6 +732,12 @@ type SecretListChannel struct {
 // GetSecretListChannel returns a pair of channels to a Secret list and errors that
 // both must be read numReads times.
 func GetSecretListChannel(client client.Interface, nsQuery *NamespaceQuery, numReads int) SecretListChannel {
+	return GetSecretListChannelWithOptions(client, nsQuery, ListEverything, numReads)
+}
+
+// GetSecretListChannelWithOptions is GetSecretListChannel plus list options.
+func GetSecretListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery, options api.ListOptions,
+	numReads int) SecretListChannel {
 
 	channel := SecretListChannel{
 		List:  make(chan *api.SecretList, numReads),
@@ -579,7 +745,7 @@ func GetSecretListChannel(client client.Interface, nsQuery *NamespaceQuery, numR
 	}
 
 	go func() {
-		list, err := client.Core().Secrets(nsQuery.ToRequestParam()).List(listEverything)
+		list, err := client.Core().Secrets(nsQuery.ToRequestParam()).List(options)
 		var filteredItems []api.Secret
 		for _, item := range list.Items {
 			if nsQuery.Matches(item.ObjectMeta.Namespace) {
@@ -605,13 +771,19 @@ type PersistentVolumeListChannel struct {
 // GetPersistentVolumeListChannel returns a pair of channels to a PersistentVolume list and errors that
 // both must be read numReads times.
 func GetPersistentVolumeListChannel(client client.Interface, numReads int) PersistentVolumeListChannel {
+	return GetPersistentVolumeListChannelWithOptions(client, ListEverything, numReads)
+}
+
+// GetPersistentVolumeListChannelWithOptions is GetPersistentVolumeListChannel plus list options.
+func GetPersistentVolumeListChannelWithOptions(client client.Interface, options api.ListOptions,
+	numReads int) PersistentVolumeListChannel {
 	channel := PersistentVolumeListChannel{
 		List:  make(chan *api.PersistentVolumeList, numReads),
 		Error: make(chan error, numReads),
 	}
 
 	go func() {
-		list, err := client.Core().PersistentVolumes().List(listEverything)
+		list, err := client.Core().PersistentVolumes().List(options)
 		for i := 0; i < numReads; i++ {
 			channel.List <- list
 			channel.Error <- err
@@ -629,7 +801,12 @@ type PersistentVolumeClaimListChannel struct {
 
 // GetPersistentVolumeClaimListChannel returns a pair of channels to a PersistentVolumeClaim list and errors that
 // both must be read numReads times.
-func GetPersistentVolumeClaimListChannel(client client.Interface, nsQuery *NamespaceQuery,
+func GetPersistentVolumeClaimListChannel(client client.Interface, nsQuery *NamespaceQuery, numReads int) PersistentVolumeClaimListChannel {
+	return GetPersistentVolumeClaimListChannelWithOptions(client, nsQuery, ListEverything, numReads)
+}
+
+// GetPersistentVolumeClaimListChannelWithOptions is GetPersistentVolumeClaimListChannel plus list options.
+func GetPersistentVolumeClaimListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery, options api.ListOptions,
 	numReads int) PersistentVolumeClaimListChannel {
 
 	channel := PersistentVolumeClaimListChannel{
@@ -638,7 +815,7 @@ func GetPersistentVolumeClaimListChannel(client client.Interface, nsQuery *Names
 	}
 
 	go func() {
-		list, err := client.Core().PersistentVolumeClaims(nsQuery.ToRequestParam()).List(listEverything)
+		list, err := client.Core().PersistentVolumeClaims(nsQuery.ToRequestParam()).List(options)
 		for i := 0; i < numReads; i++ {
 			channel.List <- list
 			channel.Error <- err
@@ -656,7 +833,12 @@ type ResourceQuotaListChannel struct {
 
 // GetResourceQuotaListChannel returns a pair of channels to a ResourceQuota list and errors that
 // both must be read numReads times.
-func GetResourceQuotaListChannel(client client.Interface, nsQuery *NamespaceQuery,
+func GetResourceQuotaListChannel(client client.Interface, nsQuery *NamespaceQuery, numReads int) ResourceQuotaListChannel {
+	return GetResourceQuotaListChannelWithOptions(client, nsQuery, ListEverything, numReads)
+}
+
+// GetResourceQuotaListChannelWithOptions is GetResourceQuotaListChannel plus list options.
+func GetResourceQuotaListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery, options api.ListOptions,
 	numReads int) ResourceQuotaListChannel {
 
 	channel := ResourceQuotaListChannel{
@@ -665,7 +847,7 @@ func GetResourceQuotaListChannel(client client.Interface, nsQuery *NamespaceQuer
 	}
 
 	go func() {
-		list, err := client.Core().ResourceQuotas(nsQuery.ToRequestParam()).List(listEverything)
+		list, err := client.Core().ResourceQuotas(nsQuery.ToRequestParam()).List(options)
 		for i := 0; i < numReads; i++ {
 			channel.List <- list
 			channel.Error <- err
@@ -683,7 +865,7 @@ type PodMetricsChannel struct {
 
 // GetPodListMetricsChannel returns a pair of channels to MetricsByPod and errors that
 // both must be read numReads times.
-func GetPodListMetricsChannel(heapsterClient kdClient.HeapsterClient, pods []api.Pod, numReads int) PodMetricsChannel {
+func GetPodListMetricsChannel(metricsProvider kdClient.MetricsProvider, pods []api.Pod, numReads int) PodMetricsChannel {
 	channel := PodMetricsChannel{
 		MetricsByPod: make(chan *MetricsByPod, numReads),
 		Error:        make(chan error, numReads),
@@ -696,7 +878,7 @@ func GetPodListMetricsChannel(heapsterClient kdClient.HeapsterClient, pods []api
 				append(podNamesByNamespace[pod.ObjectMeta.Namespace], pod.Name)
 		}
 
-		metrics, err := getPodListMetrics(podNamesByNamespace, heapsterClient)
+		metrics, err := getPodListMetrics(podNamesByNamespace, metricsProvider)
 		for i := 0; i < numReads; i++ {
 			channel.MetricsByPod <- metrics
 			channel.Error <- err
@@ -708,7 +890,7 @@ func GetPodListMetricsChannel(heapsterClient kdClient.HeapsterClient, pods []api
 
 // GetPodMetricsChannel returns a pair of channels to MetricsByPod and errors that
 // both must be read 1 time.
-func GetPodMetricsChannel(heapsterClient kdClient.HeapsterClient, name string, namespace string) PodMetricsChannel {
+func GetPodMetricsChannel(metricsProvider kdClient.MetricsProvider, name string, namespace string) PodMetricsChannel {
 	channel := PodMetricsChannel{
 		MetricsByPod: make(chan *MetricsByPod, 1),
 		Error:        make(chan error, 1),
@@ -716,7 +898,7 @@ func GetPodMetricsChannel(heapsterClient kdClient.HeapsterClient, name string, n
 
 	go func() {
 		podNamesByNamespace := map[string][]string{namespace: []string{name}}
-		metrics, err := getPodListMetrics(podNamesByNamespace, heapsterClient)
+		metrics, err := getPodListMetrics(podNamesByNamespace, metricsProvider)
 		channel.MetricsByPod <- metrics
 		channel.Error <- err
 	}()
@@ -733,13 +915,19 @@ type HorizontalPodAutoscalerListChannel struct {
 // GetPodListMetricsChannel returns a pair of channels to MetricsByPod and errors that
 // both must be read numReads times.
 func GetHorizontalPodAutoscalerListChannel(client client.Interface, nsQuery *NamespaceQuery, numReads int) HorizontalPodAutoscalerListChannel {
+	return GetHorizontalPodAutoscalerListChannelWithOptions(client, nsQuery, ListEverything, numReads)
+}
+
+// GetHorizontalPodAutoscalerListChannelWithOptions is GetHorizontalPodAutoscalerListChannel plus list options.
+func GetHorizontalPodAutoscalerListChannelWithOptions(client client.Interface, nsQuery *NamespaceQuery, options api.ListOptions,
+	numReads int) HorizontalPodAutoscalerListChannel {
 	channel := HorizontalPodAutoscalerListChannel{
 		List:  make(chan *autoscaling.HorizontalPodAutoscalerList, numReads),
 		Error: make(chan error, numReads),
 	}
 
 	go func() {
-		list, err := client.Autoscaling().HorizontalPodAutoscalers(nsQuery.ToRequestParam()).List(listEverything)
+		list, err := client.Autoscaling().HorizontalPodAutoscalers(nsQuery.ToRequestParam()).List(options)
 		for i := 0; i < numReads; i++ {
 			channel.List <- list
 			channel.Error <- err
@@ -749,7 +937,8 @@ func GetHorizontalPodAutoscalerListChannel(client client.Interface, nsQuery *Nam
 	return channel
 }
 
-var listEverything = api.ListOptions{
+// ListEverything is a list options that select all objects.
+var ListEverything = api.ListOptions{
 	LabelSelector: labels.Everything(),
 	FieldSelector: fields.Everything(),
 }
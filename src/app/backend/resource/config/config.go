@@ -21,6 +21,7 @@ import (
 	"github.com/kubernetes/dashboard/src/app/backend/resource/configmap"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/secret"
+	"k8s.io/kubernetes/pkg/api"
 	k8sClient "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
 )
 
@@ -31,14 +32,14 @@ type Config struct {
 	SecretList secret.SecretList `json:"secretList"`
 }
 
-// GetConfig returns a list of all config resources in the cluster.
-func GetConfig(client *k8sClient.Clientset, nsQuery *common.NamespaceQuery) (
-	*Config, error) {
+// GetConfig returns a list of all config resources in the cluster, that match given list options.
+func GetConfig(client *k8sClient.Clientset, nsQuery *common.NamespaceQuery,
+	listOptions api.ListOptions) (*Config, error) {
 
 	log.Print("Getting config category")
 	channels := &common.ResourceChannels{
-		ConfigMapList: common.GetConfigMapListChannel(client, nsQuery, 1),
-		SecretList:    common.GetSecretListChannel(client, nsQuery, 1),
+		ConfigMapList: common.GetConfigMapListChannelWithOptions(client, nsQuery, listOptions, 1),
+		SecretList:    common.GetSecretListChannelWithOptions(client, nsQuery, listOptions, 1),
 	}
 
 	return GetConfigFromChannels(channels)
@@ -31,6 +31,10 @@ type ConfigMapDetail struct {
 	// Data contains the configuration data.
 	// Each key must be a valid DNS_SUBDOMAIN with an optional leading dot.
 	Data map[string]string `json:"data,omitempty"`
+
+	// ResourceVersion of the config map. It has to be given when keys are changed, so that
+	// concurrent changes are detected.
+	ResourceVersion string `json:"resourceVersion"`
 }
 
 // GetConfigMapDetail returns returns detailed information about a config map
@@ -48,8 +52,9 @@ func GetConfigMapDetail(client *client.Clientset, namespace, name string) (*Conf
 
 func getConfigMapDetail(rawConfigMap *api.ConfigMap) *ConfigMapDetail {
 	return &ConfigMapDetail{
-		ObjectMeta: common.NewObjectMeta(rawConfigMap.ObjectMeta),
-		TypeMeta:   common.NewTypeMeta(common.ResourceKindConfigMap),
-		Data:       rawConfigMap.Data,
+		ObjectMeta:      common.NewObjectMeta(rawConfigMap.ObjectMeta),
+		TypeMeta:        common.NewTypeMeta(common.ResourceKindConfigMap),
+		Data:            rawConfigMap.Data,
+		ResourceVersion: rawConfigMap.ResourceVersion,
 	}
 }
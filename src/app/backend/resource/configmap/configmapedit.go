@@ -0,0 +1,172 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmap
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"k8s.io/kubernetes/pkg/api"
+	k8serrors "k8s.io/kubernetes/pkg/api/errors"
+	client "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
+	"k8s.io/kubernetes/pkg/util/validation"
+)
+
+// ConfigMapSpec is a specification of a config map to create.
+type ConfigMapSpec struct {
+	// Name of the config map.
+	Name string `json:"name"`
+
+	// Namespace of the config map.
+	Namespace string `json:"namespace"`
+
+	// Labels of the config map.
+	Labels map[string]string `json:"labels"`
+
+	// Key value pairs of the config map.
+	Data map[string]string `json:"data"`
+
+	// Contents of uploaded files by their file names, which become keys of the config map. They
+	// must be Base64 encoded text files.
+	Files map[string][]byte `json:"files"`
+}
+
+// ConfigMapKeySpec is a specification of a new value of a config map key.
+type ConfigMapKeySpec struct {
+	// New value of the key. It must be Base64 encoded text, so that binary content is detected
+	// instead of being corrupted by JSON decoding.
+	Value []byte `json:"value"`
+
+	// Resource version of the config map the value is based on. The value is not set if the
+	// config map has been modified since.
+	ResourceVersion string `json:"resourceVersion"`
+}
+
+// CreateConfigMap creates a config map from key value pairs and uploaded files of the spec.
+func CreateConfigMap(client client.Interface, spec *ConfigMapSpec) (*ConfigMapDetail, error) {
+	log.Printf("Creating %s config map in %s namespace", spec.Name, spec.Namespace)
+
+	data := make(map[string]string)
+	for key, value := range spec.Data {
+		data[key] = value
+	}
+	for fileName, content := range spec.Files {
+		if _, ok := data[fileName]; ok {
+			return nil, k8serrors.NewBadRequest(fmt.Sprintf(
+				"Key %s is given both as a value and as a file", fileName))
+		}
+		if !utf8.Valid(content) {
+			return nil, k8serrors.NewBadRequest(fmt.Sprintf(
+				"File %s has binary data. Config maps can hold only text", fileName))
+		}
+		data[fileName] = string(content)
+	}
+	for key := range data {
+		if err := validateKey(key); err != nil {
+			return nil, err
+		}
+	}
+
+	configMap, err := client.Core().ConfigMaps(spec.Namespace).Create(&api.ConfigMap{
+		ObjectMeta: api.ObjectMeta{
+			Name:      spec.Name,
+			Namespace: spec.Namespace,
+			Labels:    spec.Labels,
+		},
+		Data: data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return getConfigMapDetail(configMap), nil
+}
+
+// SetConfigMapKey adds the key to the config map, or updates its value if the key exists.
+func SetConfigMapKey(client client.Interface, namespace, name, key string,
+	spec *ConfigMapKeySpec) (*ConfigMapDetail, error) {
+
+	log.Printf("Setting %s key of %s config map in %s namespace", key, name, namespace)
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+	if !utf8.Valid(spec.Value) {
+		return nil, k8serrors.NewBadRequest(fmt.Sprintf(
+			"Value of key %s has binary data. Config maps can hold only text", key))
+	}
+
+	return updateConfigMap(client, namespace, name, spec.ResourceVersion,
+		func(configMap *api.ConfigMap) error {
+			if configMap.Data == nil {
+				configMap.Data = make(map[string]string)
+			}
+			configMap.Data[key] = string(spec.Value)
+			return nil
+		})
+}
+
+// DeleteConfigMapKey removes the key from the config map.
+func DeleteConfigMapKey(client client.Interface, namespace, name, key,
+	resourceVersion string) (*ConfigMapDetail, error) {
+
+	log.Printf("Deleting %s key of %s config map in %s namespace", key, name, namespace)
+	return updateConfigMap(client, namespace, name, resourceVersion,
+		func(configMap *api.ConfigMap) error {
+			if _, ok := configMap.Data[key]; !ok {
+				return k8serrors.NewNotFound(api.Resource("configmaps/key"), key)
+			}
+			delete(configMap.Data, key)
+			return nil
+		})
+}
+
+// updateConfigMap modifies the config map, if it has not been modified since the given resource
+// version. The resource version is passed to the apiserver as well, so that modifications made
+// between reading and updating the config map are detected too.
+func updateConfigMap(client client.Interface, namespace, name, resourceVersion string,
+	modify func(configMap *api.ConfigMap) error) (*ConfigMapDetail, error) {
+
+	if resourceVersion == "" {
+		return nil, k8serrors.NewBadRequest("Resource version of the config map has to be given")
+	}
+
+	configMap, err := client.Core().ConfigMaps(namespace).Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if configMap.ResourceVersion != resourceVersion {
+		return nil, k8serrors.NewConflict(api.Resource("configmaps"), name, fmt.Errorf(
+			"Config map has been modified since version %s. Reload it and retry",
+			resourceVersion))
+	}
+
+	if err := modify(configMap); err != nil {
+		return nil, err
+	}
+	updated, err := client.Core().ConfigMaps(namespace).Update(configMap)
+	if err != nil {
+		return nil, err
+	}
+	return getConfigMapDetail(updated), nil
+}
+
+func validateKey(key string) error {
+	if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+		return k8serrors.NewBadRequest(fmt.Sprintf("Invalid key %s: %s", key,
+			strings.Join(errs, ", ")))
+	}
+	return nil
+}
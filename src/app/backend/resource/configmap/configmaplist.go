@@ -41,11 +41,11 @@ type ConfigMap struct {
 }
 
 // GetConfigMapList returns a list of all ConfigMaps in the cluster.
-func GetConfigMapList(client *client.Clientset, nsQuery *common.NamespaceQuery,
+func GetConfigMapList(client *client.Clientset, nsQuery *common.NamespaceQuery, listOptions api.ListOptions,
 	dsQuery *dataselect.DataSelectQuery) (*ConfigMapList, error) {
 	log.Printf("Getting list config maps in the namespace %s", nsQuery.ToRequestParam())
 	channels := &common.ResourceChannels{
-		ConfigMapList: common.GetConfigMapListChannel(client, nsQuery, 1),
+		ConfigMapList: common.GetConfigMapListChannelWithOptions(client, nsQuery, listOptions, 1),
 	}
 
 	return GetConfigMapListFromChannels(channels, dsQuery)
@@ -69,11 +69,12 @@ func GetConfigMapListFromChannels(channels *common.ResourceChannels, dsQuery *da
 func getConfigMapList(configMaps []api.ConfigMap, dsQuery *dataselect.DataSelectQuery) *ConfigMapList {
 
 	result := &ConfigMapList{
-		Items:    make([]ConfigMap, 0),
-		ListMeta: common.ListMeta{TotalItems: len(configMaps)},
+		Items: make([]ConfigMap, 0),
 	}
 
-	configMaps = fromCells(dataselect.GenericDataSelect(toCells(configMaps), dsQuery))
+	cells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(configMaps), dsQuery)
+	configMaps = fromCells(cells)
+	result.ListMeta = common.ListMeta{TotalItems: filteredTotal}
 
 	for _, item := range configMaps {
 		result.Items = append(result.Items,
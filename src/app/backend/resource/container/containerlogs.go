@@ -15,6 +15,7 @@
 package container
 
 import (
+	"io"
 	"io/ioutil"
 
 	"github.com/kubernetes/dashboard/src/app/backend/resource/logs"
@@ -45,9 +46,11 @@ func GetPodContainers(client *client.Clientset, namespace, podID string) (*PodCo
 }
 
 // GetPodLogs returns logs for particular pod and container. When container
-// is null, logs for the first one are returned.
+// is null, logs for the first one are returned. Log options select, e.g., logs of the previous
+// instance of the container or only its recent logs. Nil options select all logs of the current
+// instance.
 func GetPodLogs(client *client.Clientset, namespace, podID string, container string,
-	logSelector *logs.LogViewSelector) (*logs.Logs, error) {
+	logOptions *api.PodLogOptions, logSelector *logs.LogViewSelector) (*logs.Logs, error) {
 	pod, err := client.Pods(namespace).Get(podID)
 	if err != nil {
 		return nil, err
@@ -57,14 +60,15 @@ func GetPodLogs(client *client.Clientset, namespace, podID string, container str
 		container = pod.Spec.Containers[0].Name
 	}
 
-	logOptions := &api.PodLogOptions{
-		Container:  container,
-		Follow:     false,
-		Previous:   false,
-		Timestamps: true,
+	options := &api.PodLogOptions{}
+	if logOptions != nil {
+		*options = *logOptions
 	}
+	options.Container = container
+	options.Follow = false
+	options.Timestamps = true
 
-	rawLogs, err := getRawPodLogs(client, namespace, podID, logOptions)
+	rawLogs, err := getRawPodLogs(client, namespace, podID, options)
 	if err != nil {
 		return nil, err
 	}
@@ -72,17 +76,39 @@ func GetPodLogs(client *client.Clientset, namespace, podID string, container str
 	return ConstructLogs(podID, rawLogs, container, logSelector), nil
 }
 
+// StreamPodLogs returns raw logs of particular pod and container as a stream, e.g., to download
+// them as a file, together with the name of the container. When container is empty, logs of the
+// first one are returned. Log options are the same as in GetPodLogs, except that timestamps are
+// included only when the options ask for them. The caller has to close the stream.
+func StreamPodLogs(client *client.Clientset, namespace, podID string, container string,
+	logOptions *api.PodLogOptions) (io.ReadCloser, string, error) {
+	pod, err := client.Pods(namespace).Get(podID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(container) == 0 {
+		container = pod.Spec.Containers[0].Name
+	}
+
+	options := &api.PodLogOptions{}
+	if logOptions != nil {
+		*options = *logOptions
+	}
+	options.Container = container
+	options.Follow = false
+
+	readCloser, err := openPodLogStream(client, namespace, podID, options)
+	if err != nil {
+		return nil, "", err
+	}
+	return readCloser, container, nil
+}
+
 // Construct a request for getting the logs for a pod and retrieves the logs.
 func getRawPodLogs(client *client.Clientset, namespace, podID string, logOptions *api.PodLogOptions) (
 	string, error) {
-	req := client.Core().RESTClient().Get().
-		Namespace(namespace).
-		Name(podID).
-		Resource("pods").
-		SubResource("log").
-		VersionedParams(logOptions, api.ParameterCodec)
-
-	readCloser, err := req.Stream()
+	readCloser, err := openPodLogStream(client, namespace, podID, logOptions)
 	if err != nil {
 		return err.Error(), nil
 	}
@@ -97,6 +123,17 @@ func getRawPodLogs(client *client.Clientset, namespace, podID string, logOptions
 	return string(result), nil
 }
 
+func openPodLogStream(client *client.Clientset, namespace, podID string,
+	logOptions *api.PodLogOptions) (io.ReadCloser, error) {
+	return client.Core().RESTClient().Get().
+		Namespace(namespace).
+		Name(podID).
+		Resource("pods").
+		SubResource("log").
+		VersionedParams(logOptions, api.ParameterCodec).
+		Stream()
+}
+
 // ConstructLogs constructs logs structure for given parameters.
 func ConstructLogs(podID string, rawLogs string, container string, logSelector *logs.LogViewSelector) *logs.Logs {
 	logLines, firstLogLineReference, lastLogLineReference, logViewInfo := logs.ToLogLines(rawLogs).SelectLogs(logSelector)
@@ -0,0 +1,132 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"bufio"
+	"io"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/logs"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	client "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
+)
+
+// Maximum length of a single log line that can be streamed.
+const maxLogLineSize = 1024 * 1024
+
+// FollowPodLogs follows logs of particular pod and container and calls lineFn for every new log
+// line. When container is empty, logs of the first one are followed. When reference points to an
+// existing line, streaming resumes right after it. Otherwise only lines written from now on are
+// sent. Returns when the log stream ends, lineFn returns an error or stop channel gets closed.
+func FollowPodLogs(client *client.Clientset, namespace, podID, container string,
+	reference *logs.LogLineId, stop <-chan struct{}, lineFn func(logs.LogLine) error) error {
+
+	pod, err := client.Pods(namespace).Get(podID)
+	if err != nil {
+		return err
+	}
+
+	if len(container) == 0 {
+		container = pod.Spec.Containers[0].Name
+	}
+
+	logOptions := &api.PodLogOptions{
+		Container:  container,
+		Follow:     true,
+		Previous:   false,
+		Timestamps: true,
+	}
+	reference = setLogStreamStart(logOptions, reference)
+
+	readCloser, err := client.Core().RESTClient().Get().
+		Namespace(namespace).
+		Name(podID).
+		Resource("pods").
+		SubResource("log").
+		VersionedParams(logOptions, api.ParameterCodec).
+		Stream()
+	if err != nil {
+		return err
+	}
+	defer readCloser.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-stop:
+			// Unblocks reading of the stream.
+			readCloser.Close()
+		case <-done:
+		}
+	}()
+
+	err = ReadLogStream(readCloser, reference, lineFn)
+	select {
+	case <-stop:
+		return nil
+	default:
+		return err
+	}
+}
+
+// ReadLogStream reads raw log stream line by line and calls lineFn for each line that follows
+// the reference line.
+func ReadLogStream(stream io.Reader, reference *logs.LogLineId, lineFn func(logs.LogLine) error) error {
+	filter := logs.NewLogStreamFilter(reference)
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLogLineSize)
+	for scanner.Scan() {
+		for _, line := range filter.Next(scanner.Text()) {
+			if err := lineFn(line); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, line := range filter.Flush() {
+		if err := lineFn(line); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+// setLogStreamStart sets log options so that the stream starts at the reference line and returns
+// the reference that lines of the stream should be filtered with. Lines before the reference line
+// that are returned because of the limited precision of SinceTime are dropped later by
+// logs.LogStreamFilter.
+func setLogStreamStart(logOptions *api.PodLogOptions, reference *logs.LogLineId) *logs.LogLineId {
+	if reference != nil && reference.LogTimestamp == logs.OldestTimestamp {
+		return nil
+	}
+
+	if reference != nil {
+		sinceTime, err := time.Parse(time.RFC3339Nano, string(reference.LogTimestamp))
+		if err == nil {
+			since := unversioned.NewTime(sinceTime)
+			logOptions.SinceTime = &since
+			return reference
+		}
+	}
+
+	var tailLines int64
+	logOptions.TailLines = &tailLines
+	return nil
+}
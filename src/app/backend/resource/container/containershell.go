@@ -0,0 +1,373 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/websocket"
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	client "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
+	"k8s.io/kubernetes/pkg/client/restclient"
+)
+
+// Operations of terminal messages.
+const (
+	// TerminalStdin is sent by the client with data for the standard input of the process.
+	TerminalStdin = "stdin"
+	// TerminalResize is sent by the client when the size of its terminal changes.
+	TerminalResize = "resize"
+	// TerminalStdout is sent to the client with the standard output of the process.
+	TerminalStdout = "stdout"
+	// TerminalStderr is sent to the client with the standard error of the process. When a TTY is
+	// allocated, all output is sent as stdout.
+	TerminalStderr = "stderr"
+	// TerminalExit is sent to the client when the process ends. Data holds the exit code.
+	TerminalExit = "exit"
+	// TerminalError is sent to the client when the session fails. Data holds the error message.
+	TerminalError = "error"
+)
+
+// TerminalMessage is the JSON envelope of all messages exchanged between a terminal client and the
+// dashboard over a shell WebSocket connection. Every WebSocket message carries exactly one
+// TerminalMessage, for example:
+//
+//	{"op": "stdin", "data": "ls -l\n"}      client -> dashboard
+//	{"op": "resize", "cols": 80, "rows": 24} client -> dashboard
+//	{"op": "stdout", "data": "total 0\n"}    dashboard -> client
+//	{"op": "exit", "data": "0"}              dashboard -> client
+//
+// Messages with an unknown op are ignored.
+type TerminalMessage struct {
+	// Operation of the message. One of the Terminal* constants.
+	Op string `json:"op"`
+
+	// Payload of stdin, stdout, stderr, exit and error messages.
+	Data string `json:"data,omitempty"`
+
+	// Terminal size of resize messages.
+	Cols uint16 `json:"cols,omitempty"`
+	Rows uint16 `json:"rows,omitempty"`
+}
+
+// TerminalSession is a connection to a terminal client.
+type TerminalSession interface {
+	// Read returns next message sent by the client. Returns io.EOF when the client is gone.
+	Read() (*TerminalMessage, error)
+	// Write sends a message to the client.
+	Write(*TerminalMessage) error
+}
+
+// ShellSpec describes the process that should be started in a container.
+type ShellSpec struct {
+	// Container in which the process is started. When empty, the first container of the pod is
+	// used.
+	Container string
+
+	// Command to run. When empty, an interactive shell is started.
+	Command []string
+
+	// Whether a TTY should be allocated for the process.
+	TTY bool
+}
+
+// DefaultShellCommand starts bash when it is available in the container and falls back to sh.
+var DefaultShellCommand = []string{"/bin/sh", "-c",
+	"TERM=xterm; export TERM; [ -x /bin/bash ] && exec /bin/bash || exec /bin/sh"}
+
+// Channels of the Kubernetes streaming protocol. Every binary WebSocket message exchanged with the
+// apiserver starts with one byte identifying the channel.
+const (
+	stdinChannel = iota
+	stdoutChannel
+	stderrChannel
+	errorChannel
+	resizeChannel
+)
+
+// Streaming subprotocols supported by the dashboard, in the order of preference. The first one
+// supports terminal resizing and reports errors as JSON encoded statuses.
+const (
+	channelProtocolV4 = "v4.channel.k8s.io"
+	channelProtocol   = "channel.k8s.io"
+)
+
+// Cause type and status reason used by the apiserver to report exit code of a process.
+const (
+	exitCodeCauseType     = "ExitCode"
+	nonZeroExitCodeReason = "NonZeroExitCode"
+)
+
+// channelStream is a connection to the exec subresource of a pod.
+type channelStream interface {
+	// ReadFrame returns next frame received from the apiserver.
+	ReadFrame() (channel byte, data []byte, err error)
+	// WriteFrame sends a frame to the apiserver.
+	WriteFrame(channel byte, data []byte) error
+	// Protocol returns the negotiated streaming subprotocol.
+	Protocol() string
+}
+
+// StartShell starts a process in a pod container through the exec subresource of the pod and
+// proxies its standard streams to the terminal session. Returns when the process ends or either
+// of the sides closes the connection.
+func StartShell(client *client.Clientset, config *restclient.Config, namespace, podID string,
+	spec *ShellSpec, session TerminalSession) error {
+
+	container := spec.Container
+	if len(container) == 0 {
+		pod, err := client.Pods(namespace).Get(podID)
+		if err != nil {
+			return err
+		}
+		container = pod.Spec.Containers[0].Name
+	}
+
+	command := spec.Command
+	if len(command) == 0 {
+		command = DefaultShellCommand
+	}
+
+	execURL := client.Core().RESTClient().Post().
+		Namespace(namespace).
+		Name(podID).
+		Resource("pods").
+		SubResource("exec").
+		VersionedParams(&api.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdin:     true,
+			Stdout:    true,
+			Stderr:    !spec.TTY,
+			TTY:       spec.TTY,
+		}, api.ParameterCodec).
+		URL()
+
+	stream, err := dialExec(config, execURL.String())
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	return proxyTerminal(session, stream)
+}
+
+// proxyTerminal copies messages between the terminal session and the exec stream until the
+// process ends or the client goes away.
+func proxyTerminal(session TerminalSession, stream channelStream) error {
+	done := make(chan error, 2)
+	go func() {
+		done <- copyFromTerminal(session, stream)
+	}()
+	go func() {
+		done <- copyToTerminal(stream, session)
+	}()
+	return <-done
+}
+
+// copyFromTerminal forwards stdin and resize messages of the client to the exec stream.
+func copyFromTerminal(session TerminalSession, stream channelStream) error {
+	for {
+		message, err := session.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch message.Op {
+		case TerminalStdin:
+			err = stream.WriteFrame(stdinChannel, []byte(message.Data))
+		case TerminalResize:
+			if stream.Protocol() != channelProtocolV4 {
+				// Older apiservers do not support resizing.
+				continue
+			}
+			size, _ := json.Marshal(struct {
+				Width  uint16
+				Height uint16
+			}{message.Cols, message.Rows})
+			err = stream.WriteFrame(resizeChannel, size)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// copyToTerminal forwards output of the process and its exit status to the client.
+func copyToTerminal(stream channelStream, session TerminalSession) error {
+	for {
+		channel, data, err := stream.ReadFrame()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var message *TerminalMessage
+		switch channel {
+		case stdoutChannel:
+			message = &TerminalMessage{Op: TerminalStdout, Data: string(data)}
+		case stderrChannel:
+			message = &TerminalMessage{Op: TerminalStderr, Data: string(data)}
+		case errorChannel:
+			message = getExitMessage(stream.Protocol(), data)
+		}
+		if message == nil {
+			continue
+		}
+		if err := session.Write(message); err != nil {
+			return err
+		}
+		if message.Op == TerminalExit || message.Op == TerminalError {
+			return nil
+		}
+	}
+}
+
+// getExitMessage converts content of the error channel to a terminal message. Returns nil when
+// there is nothing to report.
+func getExitMessage(protocol string, data []byte) *TerminalMessage {
+	if len(data) == 0 {
+		return nil
+	}
+	if protocol != channelProtocolV4 {
+		return &TerminalMessage{Op: TerminalError, Data: string(data)}
+	}
+
+	status := &unversioned.Status{}
+	if err := json.Unmarshal(data, status); err != nil {
+		return &TerminalMessage{Op: TerminalError, Data: string(data)}
+	}
+	if status.Status == unversioned.StatusSuccess {
+		return &TerminalMessage{Op: TerminalExit, Data: "0"}
+	}
+	if status.Reason == nonZeroExitCodeReason && status.Details != nil {
+		for _, cause := range status.Details.Causes {
+			if cause.Type == exitCodeCauseType {
+				return &TerminalMessage{Op: TerminalExit, Data: cause.Message}
+			}
+		}
+	}
+	return &TerminalMessage{Op: TerminalError, Data: status.Message}
+}
+
+// webSocketChannelStream is a channelStream over a WebSocket connection to the apiserver.
+type webSocketChannelStream struct {
+	conn *websocket.Conn
+}
+
+// ReadFrame implements channelStream.
+func (self *webSocketChannelStream) ReadFrame() (byte, []byte, error) {
+	for {
+		var frame []byte
+		if err := websocket.Message.Receive(self.conn, &frame); err != nil {
+			return 0, nil, err
+		}
+		if len(frame) > 0 {
+			return frame[0], frame[1:], nil
+		}
+	}
+}
+
+// WriteFrame implements channelStream.
+func (self *webSocketChannelStream) WriteFrame(channel byte, data []byte) error {
+	return websocket.Message.Send(self.conn, append([]byte{channel}, data...))
+}
+
+// Protocol implements channelStream.
+func (self *webSocketChannelStream) Protocol() string {
+	if protocols := self.conn.Config().Protocol; len(protocols) > 0 {
+		return protocols[0]
+	}
+	return channelProtocol
+}
+
+// Close closes the connection.
+func (self *webSocketChannelStream) Close() error {
+	return self.conn.Close()
+}
+
+// dialExec opens a WebSocket connection to the given exec URL of the apiserver, authenticating
+// the same way as the REST client created from config.
+func dialExec(config *restclient.Config, execURL string) (*webSocketChannelStream, error) {
+	location := strings.Replace(strings.Replace(execURL, "https://", "wss://", 1), "http://", "ws://", 1)
+	wsConfig, err := websocket.NewConfig(location, config.Host)
+	if err != nil {
+		return nil, err
+	}
+	wsConfig.Protocol = []string{channelProtocolV4, channelProtocol}
+
+	wsConfig.TlsConfig, err = restclient.TLSConfigFor(config)
+	if err != nil {
+		return nil, err
+	}
+
+	wsConfig.Header, err = getAuthHeaders(config)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := websocket.DialConfig(wsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("could not open exec stream: %s", err)
+	}
+	conn.PayloadType = websocket.BinaryFrame
+	return &webSocketChannelStream{conn: conn}, nil
+}
+
+// getAuthHeaders returns HTTP headers, such as Authorization or Impersonate-User, that the REST
+// client created from config adds to its requests.
+func getAuthHeaders(config *restclient.Config) (http.Header, error) {
+	capture := &headerCapturingRoundTripper{}
+	roundTripper, err := restclient.HTTPWrappersForConfig(config, capture)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequest("GET", config.Host, nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := roundTripper.RoundTrip(request); err != nil {
+		return nil, err
+	}
+	return capture.header, nil
+}
+
+// headerCapturingRoundTripper records headers of a request instead of sending it.
+type headerCapturingRoundTripper struct {
+	header http.Header
+}
+
+// RoundTrip implements http.RoundTripper.
+func (self *headerCapturingRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	self.header = request.Header
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(&bytes.Buffer{}),
+		Request:    request,
+	}, nil
+}
@@ -23,6 +23,7 @@ import (
 	client "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
 	"k8s.io/kubernetes/pkg/fields"
 	"k8s.io/kubernetes/pkg/labels"
+	"k8s.io/kubernetes/pkg/runtime"
 )
 
 // Based on given selector returns list of services that are candidates for deletion.
@@ -69,6 +70,8 @@ func (self DaemonSetCell) GetProperty(name dataselect.PropertyName) dataselect.C
 		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
 	case dataselect.NamespaceProperty:
 		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.LabelsProperty:
+		return dataselect.StdComparableLabels(self.ObjectMeta.Labels)
 	default:
 		// if name is not supported then just return a constant dummy value, sort will have no effect.
 		return nil
@@ -99,3 +102,8 @@ func FromCells(cells []dataselect.DataCell) []extensions.DaemonSet {
 	}
 	return std
 }
+
+func (self DaemonSetCell) GetObject() runtime.Object {
+	daemonSet := extensions.DaemonSet(self)
+	return &daemonSet
+}
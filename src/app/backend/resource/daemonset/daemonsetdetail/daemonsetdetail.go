@@ -56,7 +56,7 @@ type DaemonSetDetail struct {
 }
 
 // Returns detailed information about the given daemon set in the given namespace.
-func GetDaemonSetDetail(client k8sClient.Interface, heapsterClient client.HeapsterClient,
+func GetDaemonSetDetail(client k8sClient.Interface, metricsProvider client.MetricsProvider,
 	namespace, name string) (*DaemonSetDetail, error) {
 	log.Printf("Getting details of %s daemon set in %s namespace", name, namespace)
 
@@ -65,7 +65,7 @@ func GetDaemonSetDetail(client k8sClient.Interface, heapsterClient client.Heapst
 		return nil, err
 	}
 
-	podList, err := GetDaemonSetPods(client, heapsterClient, dataselect.DefaultDataSelectWithMetrics, name, namespace)
+	podList, err := GetDaemonSetPods(client, metricsProvider, dataselect.DefaultDataSelectWithMetrics, name, namespace)
 	if err != nil {
 		return nil, err
 	}
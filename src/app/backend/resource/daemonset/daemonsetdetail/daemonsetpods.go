@@ -28,7 +28,7 @@ import (
 )
 
 // GetDaemonSetPods return list of pods targeting daemon set.
-func GetDaemonSetPods(client k8sClient.Interface, heapsterClient client.HeapsterClient,
+func GetDaemonSetPods(client k8sClient.Interface, metricsProvider client.MetricsProvider,
 	dsQuery *dataselect.DataSelectQuery, daemonSetName, namespace string) (*pod.PodList, error) {
 	log.Printf("Getting replication controller %s pods in namespace %s", daemonSetName, namespace)
 
@@ -37,7 +37,7 @@ func GetDaemonSetPods(client k8sClient.Interface, heapsterClient client.Heapster
 		return nil, err
 	}
 
-	podList := pod.CreatePodList(pods, []api.Event{}, dsQuery, heapsterClient)
+	podList := pod.CreatePodList(pods, []api.Event{}, dsQuery, metricsProvider)
 	return &podList, nil
 }
 
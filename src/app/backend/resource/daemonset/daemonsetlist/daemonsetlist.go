@@ -17,7 +17,7 @@ package daemonsetlist
 import (
 	"log"
 
-	heapster "github.com/kubernetes/dashboard/src/app/backend/client"
+	kdClient "github.com/kubernetes/dashboard/src/app/backend/client"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/event"
 	"k8s.io/kubernetes/pkg/api"
@@ -52,23 +52,23 @@ type DaemonSet struct {
 }
 
 // GetDaemonSetList returns a list of all Daemon Set in the cluster.
-func GetDaemonSetList(client *client.Clientset, nsQuery *common.NamespaceQuery,
-	dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) (*DaemonSetList, error) {
+func GetDaemonSetList(client *client.Clientset, nsQuery *common.NamespaceQuery, listOptions api.ListOptions,
+	dsQuery *dataselect.DataSelectQuery, metricsProvider *kdClient.MetricsProvider) (*DaemonSetList, error) {
 	log.Print("Getting list of all daemon sets in the cluster")
 	channels := &common.ResourceChannels{
-		DaemonSetList: common.GetDaemonSetListChannel(client, nsQuery, 1),
+		DaemonSetList: common.GetDaemonSetListChannelWithOptions(client, nsQuery, listOptions, 1),
 		ServiceList:   common.GetServiceListChannel(client, nsQuery, 1),
 		PodList:       common.GetPodListChannel(client, nsQuery, 1),
 		EventList:     common.GetEventListChannel(client, nsQuery, 1),
 	}
 
-	return GetDaemonSetListFromChannels(channels, dsQuery, heapsterClient)
+	return GetDaemonSetListFromChannels(channels, dsQuery, metricsProvider)
 }
 
 // GetDaemonSetListFromChannels returns a list of all Daemon Seet in the cluster
 // reading required resource list once from the channels.
 func GetDaemonSetListFromChannels(channels *common.ResourceChannels,
-	dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) (*DaemonSetList, error) {
+	dsQuery *dataselect.DataSelectQuery, metricsProvider *kdClient.MetricsProvider) (*DaemonSetList, error) {
 
 	daemonSets := <-channels.DaemonSetList.List
 	if err := <-channels.DaemonSetList.Error; err != nil {
@@ -85,25 +85,25 @@ func GetDaemonSetListFromChannels(channels *common.ResourceChannels,
 		return nil, err
 	}
 
-	result := CreateDaemonSetList(daemonSets.Items, pods.Items, events.Items, dsQuery, heapsterClient)
+	result := CreateDaemonSetList(daemonSets.Items, pods.Items, events.Items, dsQuery, metricsProvider)
 	return result, nil
 }
 
 // CreateDaemonSetList returns a list of all Daemon Set model objects in the cluster, based on all
 // Kubernetes Daemon Set API objects.
 func CreateDaemonSetList(daemonSets []extensions.DaemonSet, pods []api.Pod,
-	events []api.Event, dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) *DaemonSetList {
+	events []api.Event, dsQuery *dataselect.DataSelectQuery, metricsProvider *kdClient.MetricsProvider) *DaemonSetList {
 
 	daemonSetList := &DaemonSetList{
 		DaemonSets: make([]DaemonSet, 0),
-		ListMeta:   common.ListMeta{TotalItems: len(daemonSets)},
 	}
 
 	cachedResources := &dataselect.CachedResources{
 		Pods: pods,
 	}
-	replicationControllerCells, metricPromises := dataselect.GenericDataSelectWithMetrics(daemonset.ToCells(daemonSets), dsQuery, cachedResources, heapsterClient)
+	replicationControllerCells, metricPromises, filteredTotal := dataselect.GenericDataSelectWithFilterAndMetrics(daemonset.ToCells(daemonSets), dsQuery, cachedResources, metricsProvider)
 	daemonSets = daemonset.FromCells(replicationControllerCells)
+	daemonSetList.ListMeta = common.ListMeta{TotalItems: filteredTotal}
 
 	for _, daemonSet := range daemonSets {
 		matchingPods := common.FilterNamespacedPodsByLabelSelector(pods, daemonSet.Namespace,
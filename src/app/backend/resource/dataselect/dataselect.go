@@ -19,6 +19,7 @@ import (
 	"github.com/kubernetes/dashboard/src/app/backend/client"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/metric"
 	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/runtime"
 	"sort"
 )
 
@@ -49,10 +50,26 @@ type MetricDataCell interface {
 	// Value returned has to have Compare method which is required by Sort functionality of DataSelect.
 	GetProperty(PropertyName) ComparableValue
 	// GetResourceSelector returns ResourceSelector for this resource. The ResourceSelector can be used to get,
-	// HeapsterSelector which in turn can be used to download metrics.
+	// MetricSelector which in turn can be used to download metrics.
 	GetResourceSelector() *metric.ResourceSelector
 }
 
+// ObjectDataCell extends interface of DataCells and additionally gives access to the Kubernetes
+// object the cell holds, e.g., to export selected objects.
+type ObjectDataCell interface {
+	// GetPropertyAtIndex returns the property of this data cell.
+	// Value returned has to have Compare method which is required by Sort functionality of DataSelect.
+	GetProperty(PropertyName) ComparableValue
+	// GetObject returns the Kubernetes object of this data cell.
+	GetObject() runtime.Object
+}
+
+// DataCellCollector collects data cells selected by DataSelect, so that they can be rendered in
+// another format than the list they are selected for.
+type DataCellCollector struct {
+	Cells []DataCell
+}
+
 // ComparableValue hold any value that can be compared to its own kind.
 type ComparableValue interface {
 	// Compares self with other value. Returns 1 if other value is smaller, 0 if they are the same, -1 if other is larger.
@@ -114,19 +131,7 @@ func (self *DataSelector) Filter() *DataSelector {
 	filteredList := []DataCell{}
 
 	for _, c := range self.GenericDataList {
-		matches := true
-		for _, filterBy := range self.DataSelectQuery.FilterQuery.FilterByList {
-			v := c.GetProperty(filterBy.Property)
-			if v == nil {
-				matches = false
-				continue
-			}
-			if filterBy.Value.Compare(v) != 0 {
-				matches = false
-				continue
-			}
-		}
-		if matches {
+		if self.DataSelectQuery.FilterQuery.Matches(c) {
 			filteredList = append(filteredList, c)
 		}
 	}
@@ -137,35 +142,35 @@ func (self *DataSelector) Filter() *DataSelector {
 
 // GetCumulativeMetrics downloads and aggregates metrics for data cells currently present in self.GenericDataList as instructed
 // by MetricQuery and inserts resulting MetricPromises to self.CumulativeMetricsPromises.
-func (self *DataSelector) GetCumulativeMetrics(heapsterClient *client.HeapsterClient) *DataSelector {
+func (self *DataSelector) GetCumulativeMetrics(metricsProvider *client.MetricsProvider) *DataSelector {
 	metricNames := self.DataSelectQuery.MetricQuery.MetricNames
 	if metricNames == nil {
 		// Don't download any metrics
 		return self
 	}
 	aggregations := self.DataSelectQuery.MetricQuery.Aggregations
-	heapsterSelectors := make(metric.HeapsterSelectors, len(self.GenericDataList))
-	// get all heapster queries
+	metricSelectors := make(metric.MetricSelectors, len(self.GenericDataList))
+	// get all metric queries
 	for i, dataCell := range self.GenericDataList {
 		// make sure data cells support metrics
 		metricDataCell := dataCell.(MetricDataCell)
 
-		// get its heapster selector
-		heapsterSelector, err := metricDataCell.GetResourceSelector().GetHeapsterSelector(self.CachedResources.Pods)
+		// get its metric selector
+		metricSelector, err := metricDataCell.GetResourceSelector().GetMetricSelector(self.CachedResources.Pods)
 		if err != nil {
 			// Programming error. Notify immediately.
-			panic(fmt.Sprintf(`Failed to create heapster selector for resource "%s". Error: %s`, metricDataCell.GetResourceSelector().ResourceType, err))
+			panic(fmt.Sprintf(`Failed to create metric selector for resource "%s". Error: %s`, metricDataCell.GetResourceSelector().ResourceType, err))
 		}
-		heapsterSelectors[i] = heapsterSelector
+		metricSelectors[i] = metricSelector
 	}
 	if aggregations == nil {
 		aggregations = metric.OnlyDefaultAggregation
 	}
-	// panic if someone tries to download metrics without providing heapster client.
-	if heapsterClient == nil {
-		panic("Tried to download metrics without providing heapster client. Use dataselect.NoMetrics or provide heapster!")
+	// panic if someone tries to download metrics without providing metrics provider.
+	if metricsProvider == nil {
+		panic("Tried to download metrics without providing metrics provider. Use dataselect.NoMetrics or provide metrics provider!")
 	}
-	self.CumulativeMetricsPromises = heapsterSelectors.DownloadAndAggregate(*heapsterClient, metricNames, aggregations)
+	self.CumulativeMetricsPromises = metricSelectors.DownloadAndAggregate(*metricsProvider, metricNames, aggregations)
 	return self
 }
 
@@ -189,39 +194,62 @@ func (self *DataSelector) Paginate() *DataSelector {
 	return self
 }
 
+// Collect passes the data inside to the collector of DataSelectQuery, if it has one, and returns itself to allow method
+// chaining.
+func (self *DataSelector) Collect() *DataSelector {
+	if collector := self.DataSelectQuery.Collector; collector != nil {
+		collector.Cells = append(collector.Cells, self.GenericDataList...)
+	}
+	return self
+}
+
 // GenericDataSelect takes a list of GenericDataCells and DataSelectQuery and returns selected data as instructed by dsQuery.
 func GenericDataSelect(dataList []DataCell, dsQuery *DataSelectQuery) []DataCell {
 	SelectableData := DataSelector{
 		GenericDataList: dataList,
 		DataSelectQuery: dsQuery,
 	}
-	return SelectableData.Sort().Paginate().GenericDataList
+	return SelectableData.Sort().Paginate().Collect().GenericDataList
+}
+
+// GenericDataSelectWithFilter takes a list of GenericDataCells and DataSelectQuery and returns selected data as instructed by
+// dsQuery together with the number of items that matched the filter.
+func GenericDataSelectWithFilter(dataList []DataCell, dsQuery *DataSelectQuery) ([]DataCell, int) {
+	SelectableData := DataSelector{
+		GenericDataList: dataList,
+		DataSelectQuery: dsQuery,
+	}
+	// Pipeline is Filter -> Sort -> Paginate -> Collect
+	filtered := SelectableData.Filter()
+	filteredTotal := len(filtered.GenericDataList)
+	processed := filtered.Sort().Paginate().Collect()
+	return processed.GenericDataList, filteredTotal
 }
 
 // GenericDataSelect takes a list of GenericDataCells and DataSelectQuery and returns selected data as instructed by dsQuery.
 func GenericDataSelectWithMetrics(dataList []DataCell, dsQuery *DataSelectQuery,
-	cachedResources *CachedResources, heapsterClient *client.HeapsterClient) ([]DataCell, metric.MetricPromises) {
+	cachedResources *CachedResources, metricsProvider *client.MetricsProvider) ([]DataCell, metric.MetricPromises) {
 	SelectableData := DataSelector{
 		GenericDataList: dataList,
 		DataSelectQuery: dsQuery,
 		CachedResources: cachedResources,
 	}
-	// Pipeline is Filter -> Sort -> CollectMetrics -> Paginate
-	processed := SelectableData.Sort().GetCumulativeMetrics(heapsterClient).Paginate()
+	// Pipeline is Filter -> Sort -> CollectMetrics -> Paginate -> Collect
+	processed := SelectableData.Sort().GetCumulativeMetrics(metricsProvider).Paginate().Collect()
 	return processed.GenericDataList, processed.CumulativeMetricsPromises
 }
 
 func GenericDataSelectWithFilterAndMetrics(dataList []DataCell, dsQuery *DataSelectQuery,
-	cachedResources *CachedResources, heapsterClient *client.HeapsterClient) ([]DataCell, metric.MetricPromises, int) {
+	cachedResources *CachedResources, metricsProvider *client.MetricsProvider) ([]DataCell, metric.MetricPromises, int) {
 	SelectableData := DataSelector{
 		GenericDataList: dataList,
 		DataSelectQuery: dsQuery,
 		CachedResources: cachedResources,
 	}
-	// Pipeline is Filter -> Sort -> CollectMetrics -> Paginate
+	// Pipeline is Filter -> Sort -> CollectMetrics -> Paginate -> Collect
 	filtered := SelectableData.Filter()
 	filteredTotal := len(filtered.GenericDataList)
-	processed := filtered.Sort().GetCumulativeMetrics(heapsterClient).Paginate()
+	processed := filtered.Sort().GetCumulativeMetrics(metricsProvider).Paginate().Collect()
 	return processed.GenericDataList, processed.CumulativeMetricsPromises, filteredTotal
 
 }
@@ -27,6 +27,8 @@ type DataSelectQuery struct {
 	SortQuery       *SortQuery
 	FilterQuery     *FilterQuery
 	MetricQuery     *MetricQuery
+	// Collector, if not nil, receives the selected data cells. Nil by default.
+	Collector *DataCellCollector
 }
 
 var NoMetrics = NewMetricQuery(nil, nil)
@@ -40,8 +42,8 @@ var StandardMetrics = NewMetricQuery([]string{common.CpuUsage, common.MemoryUsag
 // It accepts list of metrics to be downloaded and a list of aggregations that should be performed for each metric.
 // Query has this format  metrics=metric1,metric2,...&aggregations=aggregation1,aggregation2,...
 type MetricQuery struct {
-	// Metrics to download, all available metric names can be found here:
-	// https://github.com/kubernetes/heapster/blob/master/docs/storage-schema.md
+	// Metrics to download. Names of metrics supported by all metrics providers are defined in
+	// the client package, e.g., client.CPUUsageMetric.
 	MetricNames []string
 	// Aggregations to be performed for each metric. Check available aggregations in aggregation.go.
 	// If empty, default aggregation will be used (sum).
@@ -72,13 +74,24 @@ var NoSort = &SortQuery{
 	SortByList: []SortBy{},
 }
 
+// FilterQuery holds options for filter functionality of data select. An item is selected only when
+// it matches all FilterBy groups.
 type FilterQuery struct {
 	FilterByList []FilterBy
 }
 
+// FilterBy is a group of alternative filter conditions. It matches an item when at least one of
+// its conditions matches.
 type FilterBy struct {
+	Conditions []FilterCondition
+}
+
+// FilterCondition compares value of the given property with a raw value using the operator. The
+// raw value is interpreted according to the type of the property, e.g., as a time for timestamps.
+type FilterCondition struct {
 	Property PropertyName
-	Value    ComparableValue
+	Operator FilterOperator
+	Value    string
 }
 
 var NoFilter = &FilterQuery{
@@ -143,24 +156,49 @@ func NewSortQuery(sortByListRaw []string) *SortQuery {
 	}
 }
 
-// NewFilterQuery takes raw filter options list and returns FilterQuery object. For example:
-// ["parameter1", "value1", "parameter2", "value2"] - means that the data should be filtered by
-// parameter1 equals value1 and parameter2 equals value2
+// NewFilterQuery takes raw filter options list and returns FilterQuery object. Each element of
+// the list is a group of conditions separated by "|", of which at least one has to match. Each
+// condition has the form of property, operator and value, where the value can be double quoted
+// to contain separators, e.g., `labels="app in (nginx,web)"`. For example:
+// ["name~=nginx|name^=web", "creationTimestamp>2016-10-01T00:00:00Z"] - means that the data
+// should be filtered by name containing nginx or starting with web, and created after the
+// first of October. See FilterOperator for the list of supported operators.
+// For backward compatibility a condition without operator is treated as a property name followed
+// by a value it should be equal to, so ["parameter1", "value1"] means parameter1 equals value1.
+// When the list can not be parsed, no filter is applied.
 func NewFilterQuery(filterByListRaw []string) *FilterQuery {
-	if filterByListRaw == nil || len(filterByListRaw)%2 == 1 {
-		return NoFilter
-	}
 	filterByList := []FilterBy{}
-	for i := 0; i+1 < len(filterByListRaw); i += 2 {
-		propertyName := filterByListRaw[i]
-		propertyValue := filterByListRaw[i+1]
-		filterBy := FilterBy{
-			Property: PropertyName(propertyName),
-			Value:    StdComparableString(propertyValue),
+	for i := 0; i < len(filterByListRaw); i++ {
+		rawGroup := filterByListRaw[i]
+		if rawGroup == "" {
+			continue
+		}
+
+		filterBy := FilterBy{}
+		rawConditions := splitUnquoted(rawGroup, '|')
+		for _, rawCondition := range rawConditions {
+			condition, ok := parseFilterCondition(rawCondition)
+			if !ok && len(rawConditions) == 1 && i+1 < len(filterByListRaw) {
+				// Legacy format, property name and value are subsequent elements of the list.
+				i++
+				condition, ok = FilterCondition{
+					Property: PropertyName(rawCondition),
+					Operator: EqualOperator,
+					Value:    filterByListRaw[i],
+				}, true
+			}
+			if !ok {
+				return NoFilter
+			}
+			filterBy.Conditions = append(filterBy.Conditions, condition)
 		}
 		// Add to the filter options.
 		filterByList = append(filterByList, filterBy)
 	}
+
+	if len(filterByList) == 0 {
+		return NoFilter
+	}
 	return &FilterQuery{
 		FilterByList: filterByList,
 	}
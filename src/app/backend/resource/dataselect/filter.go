@@ -0,0 +1,243 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataselect
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/kubernetes/pkg/labels"
+)
+
+// FilterOperator describes how property value is compared with the value of a filter condition.
+type FilterOperator string
+
+// List of all supported filter operators. Operators are listed so that longer ones come first,
+// which is the order in which they are matched when parsing a condition.
+const (
+	// EqualOperator matches values that are equal. For labels it matches labels selected by the
+	// label selector given as the value, e.g., "labels=app=nginx".
+	EqualOperator FilterOperator = "="
+	// DoubleEqualOperator is an alias of EqualOperator.
+	DoubleEqualOperator FilterOperator = "=="
+	// NotEqualOperator matches values that are not equal, or labels not selected by the selector.
+	NotEqualOperator FilterOperator = "!="
+	// ContainsOperator matches values that contain the filter value.
+	ContainsOperator FilterOperator = "~="
+	// PrefixOperator matches values that start with the filter value.
+	PrefixOperator FilterOperator = "^="
+	// GreaterOperator and others compare values as numbers, times or strings, depending on the
+	// type of the property.
+	GreaterOperator        FilterOperator = ">"
+	GreaterOrEqualOperator FilterOperator = ">="
+	LessOperator           FilterOperator = "<"
+	LessOrEqualOperator    FilterOperator = "<="
+)
+
+var filterOperators = []FilterOperator{DoubleEqualOperator, NotEqualOperator, ContainsOperator,
+	PrefixOperator, GreaterOrEqualOperator, LessOrEqualOperator, EqualOperator, GreaterOperator,
+	LessOperator}
+
+// SplitFilterByList splits raw filterby query parameter into the list accepted by NewFilterQuery.
+// Elements are separated by commas, except for commas of double quoted values, so
+// `labels="app=nginx,tier in (web,api)",name~=nginx` is a list of two elements.
+func SplitFilterByList(raw string) []string {
+	return splitUnquoted(raw, ',')
+}
+
+// splitUnquoted splits s at every separator that is not inside a double quoted string. Inside
+// quotes a backslash escapes the character that follows it.
+func splitUnquoted(s string, separator byte) []string {
+	parts := []string{}
+	start := 0
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quoted && s[i] == '\\':
+			i++
+		case s[i] == '"':
+			quoted = !quoted
+		case !quoted && s[i] == separator:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// parseFilterCondition parses a single filter condition of the form property, operator, value,
+// e.g., "name~=nginx". The value can be a double quoted Go string literal, e.g.,
+// `labels="app=nginx,tier=web"`. Returns false when the condition does not contain any operator
+// or the quoted value is malformed.
+func parseFilterCondition(rawCondition string) (FilterCondition, bool) {
+	start := strings.IndexAny(rawCondition, "=!~^<>")
+	if start <= 0 {
+		return FilterCondition{}, false
+	}
+
+	for _, operator := range filterOperators {
+		if strings.HasPrefix(rawCondition[start:], string(operator)) {
+			value := rawCondition[start+len(operator):]
+			if strings.HasPrefix(value, `"`) {
+				unquoted, err := strconv.Unquote(value)
+				if err != nil {
+					return FilterCondition{}, false
+				}
+				value = unquoted
+			}
+			return FilterCondition{
+				Property: PropertyName(rawCondition[:start]),
+				Operator: operator,
+				Value:    value,
+			}, true
+		}
+	}
+	return FilterCondition{}, false
+}
+
+// Matches returns true when data cell matches all filter groups of the query.
+func (self *FilterQuery) Matches(cell DataCell) bool {
+	if self == nil {
+		return true
+	}
+	for _, filterBy := range self.FilterByList {
+		if !filterBy.Matches(cell) {
+			return false
+		}
+	}
+	return true
+}
+
+// Matches returns true when data cell matches at least one of the conditions.
+func (self FilterBy) Matches(cell DataCell) bool {
+	for _, condition := range self.Conditions {
+		value := cell.GetProperty(condition.Property)
+		if value != nil && condition.Matches(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches returns true when the property value satisfies the condition. Values that can not be
+// compared with the condition value, e.g., a time with a non-time value, never match.
+func (self FilterCondition) Matches(value ComparableValue) bool {
+	if labelsValue, ok := value.(StdComparableLabels); ok {
+		return self.matchesLabels(labelsValue)
+	}
+
+	valueString := filterValueString(value)
+	switch self.Operator {
+	case ContainsOperator:
+		return strings.Contains(valueString, self.Value)
+	case PrefixOperator:
+		return strings.HasPrefix(valueString, self.Value)
+	}
+
+	cmp, ok := compareFilterValue(value, self.Value)
+	if !ok {
+		return false
+	}
+	switch self.Operator {
+	case EqualOperator, DoubleEqualOperator:
+		return cmp == 0
+	case NotEqualOperator:
+		return cmp != 0
+	case GreaterOperator:
+		return cmp > 0
+	case GreaterOrEqualOperator:
+		return cmp >= 0
+	case LessOperator:
+		return cmp < 0
+	case LessOrEqualOperator:
+		return cmp <= 0
+	}
+	return false
+}
+
+// matchesLabels matches labels against the label selector held by the condition value.
+func (self FilterCondition) matchesLabels(value StdComparableLabels) bool {
+	selector, err := labels.Parse(self.Value)
+	if err != nil {
+		return false
+	}
+	matches := selector.Matches(labels.Set(value))
+	switch self.Operator {
+	case EqualOperator, DoubleEqualOperator:
+		return matches
+	case NotEqualOperator:
+		return !matches
+	}
+	return false
+}
+
+// compareFilterValue compares property value with raw filter value interpreted according to the
+// property type. Returns 1 if property value is larger, 0 if they are the same, -1 if it is
+// smaller. Returns false when raw value can not be interpreted.
+func compareFilterValue(value ComparableValue, raw string) (int, bool) {
+	switch v := value.(type) {
+	case StdComparableString:
+		return strings.Compare(string(v), raw), true
+	case StdComparableInt:
+		other, err := strconv.Atoi(raw)
+		if err != nil {
+			return 0, false
+		}
+		return intsCompare(int(v), other), true
+	case StdComparableTime:
+		other, ok := parseFilterTime(raw)
+		if !ok {
+			return 0, false
+		}
+		return ints64Compare(time.Time(v).Unix(), other.Unix()), true
+	case StdComparableRFC3339Timestamp:
+		selfTime, err := time.Parse(time.RFC3339, string(v))
+		other, ok := parseFilterTime(raw)
+		if err != nil || !ok {
+			return strings.Compare(string(v), raw), true
+		}
+		return ints64Compare(selfTime.Unix(), other.Unix()), true
+	default:
+		return strings.Compare(filterValueString(value), raw), true
+	}
+}
+
+// parseFilterTime parses time value of a filter condition. It is either an RFC3339 time or a
+// duration, e.g., "2h", which stands for the time that long ago. This way
+// "creationTimestamp>2h" selects items created within the last two hours.
+func parseFilterTime(raw string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, true
+	}
+	if d, err := time.ParseDuration(raw); err == nil {
+		return time.Now().Add(-d), true
+	}
+	return time.Time{}, false
+}
+
+// filterValueString returns string representation of property value used by substring and
+// prefix matching.
+func filterValueString(value ComparableValue) string {
+	switch v := value.(type) {
+	case StdComparableString:
+		return string(v)
+	case StdComparableTime:
+		return time.Time(v).UTC().Format(time.RFC3339)
+	default:
+		return fmt.Sprint(value)
+	}
+}
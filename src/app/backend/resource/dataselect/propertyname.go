@@ -24,4 +24,12 @@ const (
 	CreationTimestampProperty = "creationTimestamp"
 	NamespaceProperty         = "namespace"
 	StatusProperty            = "status"
+	LabelsProperty            = "labels"
+
+	// Properties of events.
+	TypeProperty               = "type"
+	ReasonProperty             = "reason"
+	LastSeenProperty           = "lastSeen"
+	InvolvedObjectKindProperty = "involvedObjectKind"
+	InvolvedObjectNameProperty = "involvedObjectName"
 )
@@ -17,6 +17,8 @@ package dataselect
 import (
 	"strings"
 	"time"
+
+	"k8s.io/kubernetes/pkg/labels"
 )
 
 // ----------------------- Standard Comparable Types ------------------------
@@ -63,6 +65,15 @@ func (self StdComparableTime) Compare(otherV ComparableValue) int {
 	return ints64Compare(time.Time(self).Unix(), time.Time(other).Unix())
 }
 
+// StdComparableLabels holds labels of an object. Labels are compared by their string
+// representation, filters match them with label selectors.
+type StdComparableLabels map[string]string
+
+func (self StdComparableLabels) Compare(otherV ComparableValue) int {
+	other := otherV.(StdComparableLabels)
+	return strings.Compare(labels.Set(self).String(), labels.Set(other).String())
+}
+
 // Int comparison functions. Similar to strings.Compare.
 func intsCompare(a, b int) int {
 	if a > b {
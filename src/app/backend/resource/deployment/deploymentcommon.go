@@ -19,6 +19,7 @@ import (
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/metric"
 	"k8s.io/kubernetes/pkg/apis/extensions"
+	"k8s.io/kubernetes/pkg/runtime"
 )
 
 // The code below allows to perform complex data section on []extensions.Deployment
@@ -33,6 +34,8 @@ func (self DeploymentCell) GetProperty(name dataselect.PropertyName) dataselect.
 		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
 	case dataselect.NamespaceProperty:
 		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.LabelsProperty:
+		return dataselect.StdComparableLabels(self.ObjectMeta.Labels)
 	default:
 		// if name is not supported then just return a constant dummy value, sort will have no effect.
 		return nil
@@ -63,3 +66,8 @@ func fromCells(cells []dataselect.DataCell) []extensions.Deployment {
 	}
 	return std
 }
+
+func (self DeploymentCell) GetObject() runtime.Object {
+	deployment := extensions.Deployment(self)
+	return &deployment
+}
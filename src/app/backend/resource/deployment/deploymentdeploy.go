@@ -15,18 +15,26 @@
 package deployment
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
 
+	jsonpatch "github.com/evanphx/json-patch"
 	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/annotations"
+	k8serrors "k8s.io/kubernetes/pkg/api/errors"
 	"k8s.io/kubernetes/pkg/api/resource"
 	"k8s.io/kubernetes/pkg/apis/extensions"
 	client "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
 	"k8s.io/kubernetes/pkg/client/unversioned/clientcmd"
+	"k8s.io/kubernetes/pkg/kubectl"
 	cmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
 	kubectlResource "k8s.io/kubernetes/pkg/kubectl/resource"
+	"k8s.io/kubernetes/pkg/runtime"
 	"k8s.io/kubernetes/pkg/util/intstr"
+	"k8s.io/kubernetes/pkg/util/strategicpatch"
 )
 
 const (
@@ -34,6 +42,28 @@ const (
 	DescriptionAnnotationKey = "description"
 )
 
+// errDeployFailed stops deployment from file after the first object that failed to deploy.
+var errDeployFailed = errors.New("deployment failed")
+
+// Modes of deployment from file.
+const (
+	// CreateDeploymentMode creates objects from the file. Objects that already exist fail.
+	CreateDeploymentMode = "create"
+
+	// ApplyDeploymentMode creates objects from the file that do not exist and updates the ones
+	// that do.
+	ApplyDeploymentMode = "apply"
+)
+
+// Actions taken on deployed objects.
+const (
+	ObjectCreated    = "created"
+	ObjectUpdated    = "updated"
+	ObjectUnchanged  = "unchanged"
+	ObjectFailed     = "failed"
+	ObjectRolledBack = "rolledBack"
+)
+
 // AppDeploymentSpec is a specification for an app deployment.
 type AppDeploymentSpec struct {
 	// Name of the application.
@@ -82,6 +112,154 @@ type AppDeploymentSpec struct {
 
 	// Whether to run the container as privileged user (essentially equivalent to root on the host).
 	RunAsPrivileged bool `json:"runAsPrivileged"`
+
+	// Optional memory limit for the container.
+	MemoryLimit *resource.Quantity `json:"memoryLimit"`
+
+	// Optional CPU limit for the container.
+	CpuLimit *resource.Quantity `json:"cpuLimit"`
+
+	// Optional probe that restarts the container when it fails.
+	LivenessProbe *ProbeSpec `json:"livenessProbe"`
+
+	// Optional probe that removes pods from service endpoints while it fails.
+	ReadinessProbe *ProbeSpec `json:"readinessProbe"`
+
+	// Volumes of the pods. Containers mount them by name.
+	Volumes []VolumeSpec `json:"volumes"`
+
+	// Volumes mounted into the container.
+	VolumeMounts []VolumeMountSpec `json:"volumeMounts"`
+
+	// Containers that run to completion, one after another, before the container is started.
+	InitContainers []ContainerSpec `json:"initContainers"`
+
+	// Containers that run next to the container in the same pods.
+	Sidecars []ContainerSpec `json:"sidecars"`
+
+	// Labels that nodes must have to run the pods.
+	NodeSelector []Label `json:"nodeSelector"`
+
+	// Taints of nodes that the pods tolerate.
+	Tolerations []api.Toleration `json:"tolerations"`
+}
+
+// ContainerSpec is a specification of an init or a sidecar container of an app deployment.
+type ContainerSpec struct {
+	// Name of the container. Must be unique within the pods.
+	Name string `json:"name"`
+
+	// Docker image path for the container.
+	Image string `json:"image"`
+
+	// Command that is executed instead of the image entrypoint, if specified.
+	Command []string `json:"command"`
+
+	// Arguments for the command or the image entrypoint.
+	Args []string `json:"args"`
+
+	// List of user-defined environment variables.
+	Variables []EnvironmentVariable `json:"variables"`
+
+	// Optional memory requirement for the container.
+	MemoryRequirement *resource.Quantity `json:"memoryRequirement"`
+
+	// Optional CPU requirement for the container.
+	CpuRequirement *resource.Quantity `json:"cpuRequirement"`
+
+	// Optional memory limit for the container.
+	MemoryLimit *resource.Quantity `json:"memoryLimit"`
+
+	// Optional CPU limit for the container.
+	CpuLimit *resource.Quantity `json:"cpuLimit"`
+
+	// Optional probe that restarts the container when it fails. Not allowed for init containers.
+	LivenessProbe *ProbeSpec `json:"livenessProbe"`
+
+	// Optional probe that marks the pods as not ready while it fails. Not allowed for init
+	// containers.
+	ReadinessProbe *ProbeSpec `json:"readinessProbe"`
+
+	// Volumes mounted into the container.
+	VolumeMounts []VolumeMountSpec `json:"volumeMounts"`
+}
+
+// Types of container probes.
+const (
+	// HTTPProbe succeeds when a GET request to the path and port of the container succeeds.
+	HTTPProbe = "http"
+
+	// TCPProbe succeeds when a TCP connection to the port of the container can be opened.
+	TCPProbe = "tcp"
+
+	// ExecProbe succeeds when the command executed in the container exits with 0.
+	ExecProbe = "exec"
+)
+
+// ProbeSpec is a specification of a periodic check of a container.
+type ProbeSpec struct {
+	// Type of the probe, one of: http, tcp, exec.
+	Type string `json:"type"`
+
+	// Path requested by http probes.
+	Path string `json:"path"`
+
+	// Port of the container checked by http and tcp probes.
+	Port int32 `json:"port"`
+
+	// Command executed by exec probes.
+	Command []string `json:"command"`
+
+	// Number of seconds after the container has started before the first check.
+	InitialDelaySeconds int32 `json:"initialDelaySeconds"`
+
+	// Number of seconds between checks. Defaults to 10.
+	PeriodSeconds int32 `json:"periodSeconds"`
+
+	// Number of seconds after which a check times out. Defaults to 1.
+	TimeoutSeconds int32 `json:"timeoutSeconds"`
+
+	// Number of failed checks in a row after which the probe fails. Defaults to 3.
+	FailureThreshold int32 `json:"failureThreshold"`
+}
+
+// Types of volumes.
+const (
+	// ConfigMapVolume contains keys of a config map as files.
+	ConfigMapVolume = "configMap"
+
+	// SecretVolume contains keys of a secret as files.
+	SecretVolume = "secret"
+
+	// PersistentVolumeClaimVolume is a persistent volume bound to a claim.
+	PersistentVolumeClaimVolume = "persistentVolumeClaim"
+)
+
+// VolumeSpec is a specification of a volume of the pods of an app deployment.
+type VolumeSpec struct {
+	// Name of the volume. Must be unique within the pods.
+	Name string `json:"name"`
+
+	// Type of the volume, one of: configMap, secret, persistentVolumeClaim.
+	Type string `json:"type"`
+
+	// Name of the config map, secret or persistent volume claim in the target namespace.
+	SourceName string `json:"sourceName"`
+
+	// Whether the persistent volume is mounted read-only.
+	ReadOnly bool `json:"readOnly"`
+}
+
+// VolumeMountSpec is a specification of a volume mounted into a container.
+type VolumeMountSpec struct {
+	// Name of the mounted volume.
+	Name string `json:"name"`
+
+	// Absolute path in the container at which the volume is mounted.
+	MountPath string `json:"mountPath"`
+
+	// Whether the volume is mounted read-only.
+	ReadOnly bool `json:"readOnly"`
 }
 
 // AppDeploymentFromFileSpec is a specification for deployment from file
@@ -94,6 +272,12 @@ type AppDeploymentFromFileSpec struct {
 
 	// Whether validate content before creation or not
 	Validate bool `json:"validate"`
+
+	// Deployment mode, one of: create, apply. Defaults to create.
+	Mode string `json:"mode"`
+
+	// Whether to only report what would be deployed, without changing any objects
+	DryRun bool `json:"dryRun"`
 }
 
 // AppDeploymentFromFileResponse is a specification for deployment from file
@@ -104,7 +288,57 @@ type AppDeploymentFromFileResponse struct {
 	// File content
 	Content string `json:"content"`
 
-	// Error after create resource
+	// Errors of all objects that failed to deploy
+	Error string `json:"error"`
+
+	// Whether the deployment was a dry run
+	DryRun bool `json:"dryRun"`
+
+	// Whether objects created from the file were deleted, because a later object failed to deploy
+	RolledBack bool `json:"rolledBack"`
+
+	// Status of each object from the file
+	Objects []DeployedObjectStatus `json:"objects"`
+}
+
+// AppDeploymentResponse is a result of an app deployment.
+type AppDeploymentResponse struct {
+	// Name of the application.
+	Name string `json:"name"`
+
+	// Target namespace of the application.
+	Namespace string `json:"namespace"`
+
+	// Errors of objects that failed to deploy or to roll back
+	Error string `json:"error"`
+
+	// Whether created objects were deleted, because a later object failed to deploy
+	RolledBack bool `json:"rolledBack"`
+
+	// Status of each object of the application, in order of deployment
+	Objects []DeployedObjectStatus `json:"objects"`
+}
+
+// DeployedObjectStatus is a result of deploying a single object from file.
+type DeployedObjectStatus struct {
+	// Kind of the object, e.g., Deployment.
+	Kind string `json:"kind"`
+
+	// Name of the object.
+	Name string `json:"name"`
+
+	// Namespace of the object. Empty for objects that are not namespaced.
+	Namespace string `json:"namespace"`
+
+	// Action taken on the object, or the action that would be taken in case of a dry run. One of:
+	// created, updated, unchanged, failed, rolledBack.
+	Action string `json:"action"`
+
+	// Differences between the live object and the object from the file. Computed only for
+	// objects that already exist, when deploying in apply mode.
+	Diff []FieldDiff `json:"diff"`
+
+	// Error that occurred while deploying the object.
 	Error string `json:"error"`
 }
 
@@ -146,10 +380,67 @@ type Protocols struct {
 
 // DeployApp deploys an app based on the given configuration. The app is deployed using the given
 // client. App deployment consists of a deployment and an optional service. Both of them
-// share common labels.
-func DeployApp(spec *AppDeploymentSpec, client client.Interface) error {
+// share common labels. When the service can not be created, the deployment is deleted again and
+// the response reports the rollback. Error is returned when the spec is invalid or the deployment
+// can not be created.
+func DeployApp(spec *AppDeploymentSpec, client client.Interface) (*AppDeploymentResponse, error) {
 	log.Printf("Deploying %s application into %s namespace", spec.Name, spec.Namespace)
 
+	if errs := ValidateAppDeploymentSpec(spec); len(errs) > 0 {
+		return nil, k8serrors.NewInvalid(extensions.Kind("Deployment"), spec.Name, errs)
+	}
+
+	deployment, service, err := GetAppObjects(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction := new(deployTransaction)
+	if _, err := client.Extensions().Deployments(spec.Namespace).Create(deployment); err != nil {
+		return nil, err
+	}
+	orphanDependents := false
+	transaction.add(DeployedObjectStatus{
+		Kind:      "Deployment",
+		Name:      spec.Name,
+		Namespace: spec.Namespace,
+		Action:    ObjectCreated,
+	}, func() error {
+		return client.Extensions().Deployments(spec.Namespace).Delete(spec.Name,
+			&api.DeleteOptions{OrphanDependents: &orphanDependents})
+	})
+
+	if service != nil {
+		status := DeployedObjectStatus{
+			Kind:      "Service",
+			Name:      spec.Name,
+			Namespace: spec.Namespace,
+			Action:    ObjectCreated,
+		}
+		if _, err := client.Core().Services(spec.Namespace).Create(service); err != nil {
+			status.Action = ObjectFailed
+			status.Error = err.Error()
+		}
+		transaction.add(status, func() error {
+			return client.Core().Services(spec.Namespace).Delete(spec.Name, nil)
+		})
+	}
+
+	response := &AppDeploymentResponse{
+		Name:      spec.Name,
+		Namespace: spec.Namespace,
+	}
+	if transaction.failed() {
+		response.RolledBack = transaction.rollBack()
+	}
+	response.Objects = transaction.objects
+	response.Error = strings.Join(transaction.errors(), "\n")
+	return response, nil
+}
+
+// GetAppObjects returns the deployment and the service that DeployApp creates for the given
+// spec. The service is nil when the spec has no port mappings.
+func GetAppObjects(spec *AppDeploymentSpec) (*extensions.Deployment, *api.Service, error) {
 	annotations := map[string]string{}
 	if spec.Description != nil {
 		annotations[DescriptionAnnotationKey] = *spec.Description
@@ -161,16 +452,136 @@ func DeployApp(spec *AppDeploymentSpec, client client.Interface) error {
 		Labels:      labels,
 	}
 
+	podTemplate, err := getPodTemplate(spec, objectMeta)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deployment := &extensions.Deployment{
+		ObjectMeta: objectMeta,
+		Spec: extensions.DeploymentSpec{
+			Replicas: spec.Replicas,
+			Template: *podTemplate,
+		},
+	}
+	if len(spec.PortMappings) == 0 {
+		return deployment, nil, nil
+	}
+
+	service := &api.Service{
+		ObjectMeta: objectMeta,
+		Spec: api.ServiceSpec{
+			Selector: labels,
+		},
+	}
+
+	if spec.IsExternal {
+		service.Spec.Type = api.ServiceTypeLoadBalancer
+	} else {
+		service.Spec.Type = api.ServiceTypeClusterIP
+	}
+
+	for _, portMapping := range spec.PortMappings {
+		servicePort :=
+			api.ServicePort{
+				Protocol: portMapping.Protocol,
+				Port:     portMapping.Port,
+				Name:     generatePortMappingName(portMapping),
+				TargetPort: intstr.IntOrString{
+					Type:   intstr.Int,
+					IntVal: portMapping.TargetPort,
+				},
+			}
+		service.Spec.Ports = append(service.Spec.Ports, servicePort)
+	}
+	return deployment, service, nil
+}
+
+// deployTransaction tracks objects of a deployment, so that the ones it has created can be
+// deleted again when a later object fails to deploy.
+type deployTransaction struct {
+	objects []DeployedObjectStatus
+
+	// Functions that delete the objects, at the same indices as the objects.
+	deleteFns []func() error
+}
+
+// add adds status of an object that has been deployed, or has failed to deploy, to the
+// transaction. The delete function is called on rollback, if the object has been created.
+func (self *deployTransaction) add(status DeployedObjectStatus, deleteFn func() error) {
+	self.objects = append(self.objects, status)
+	self.deleteFns = append(self.deleteFns, deleteFn)
+}
+
+// failed returns true if any object of the transaction has failed to deploy.
+func (self *deployTransaction) failed() bool {
+	for _, status := range self.objects {
+		if status.Action == ObjectFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// deployedAny returns true if any object of the transaction has been deployed and has not been
+// rolled back.
+func (self *deployTransaction) deployedAny() bool {
+	for _, status := range self.objects {
+		if status.Action != ObjectFailed && status.Action != ObjectRolledBack {
+			return true
+		}
+	}
+	return false
+}
+
+// rollBack deletes created objects in reverse order of their creation and marks them as rolled
+// back. Objects that can not be deleted keep their action and get the error. Returns true if any
+// object has been rolled back.
+func (self *deployTransaction) rollBack() bool {
+	rolledBack := false
+	for i := len(self.objects) - 1; i >= 0; i-- {
+		status := &self.objects[i]
+		if status.Action != ObjectCreated || self.deleteFns[i] == nil {
+			continue
+		}
+		if err := self.deleteFns[i](); err != nil && !k8serrors.IsNotFound(err) {
+			log.Printf("Rollback of %s %s failed: %s", status.Kind, status.Name, err)
+			status.Error = fmt.Sprintf("Rollback failed: %s", err)
+			continue
+		}
+		log.Printf("%s %s is rolled back", status.Kind, status.Name)
+		status.Action = ObjectRolledBack
+		rolledBack = true
+	}
+	return rolledBack
+}
+
+// errors returns errors of all objects of the transaction.
+func (self *deployTransaction) errors() []string {
+	result := make([]string, 0)
+	for _, status := range self.objects {
+		if status.Error != "" {
+			result = append(result, status.Error)
+		}
+	}
+	return result
+}
+
+// getPodTemplate returns template of the pods of an app deployment with the given metadata.
+func getPodTemplate(spec *AppDeploymentSpec, objectMeta api.ObjectMeta) (*api.PodTemplateSpec,
+	error) {
 	containerSpec := api.Container{
 		Name:  spec.Name,
 		Image: spec.ContainerImage,
 		SecurityContext: &api.SecurityContext{
 			Privileged: &spec.RunAsPrivileged,
 		},
-		Resources: api.ResourceRequirements{
-			Requests: make(map[api.ResourceName]resource.Quantity),
-		},
-		Env: convertEnvVarsSpec(spec.Variables),
+		Resources: getResourceRequirements(spec.CpuRequirement, spec.MemoryRequirement,
+			spec.CpuLimit, spec.MemoryLimit),
+		Env:            convertEnvVarsSpec(spec.Variables),
+		LivenessProbe:  getProbe(spec.LivenessProbe),
+		ReadinessProbe: getProbe(spec.ReadinessProbe),
+		VolumeMounts:   getVolumeMounts(spec.VolumeMounts),
 	}
 
 	if spec.ContainerCommand != nil {
@@ -180,73 +591,135 @@ func DeployApp(spec *AppDeploymentSpec, client client.Interface) error {
 		containerSpec.Args = []string{*spec.ContainerCommandArgs}
 	}
 
-	if spec.CpuRequirement != nil {
-		containerSpec.Resources.Requests[api.ResourceCPU] = *spec.CpuRequirement
-	}
-	if spec.MemoryRequirement != nil {
-		containerSpec.Resources.Requests[api.ResourceMemory] = *spec.MemoryRequirement
-	}
 	podSpec := api.PodSpec{
 		Containers: []api.Container{containerSpec},
 	}
 	if spec.ImagePullSecret != nil {
 		podSpec.ImagePullSecrets = []api.LocalObjectReference{{Name: *spec.ImagePullSecret}}
 	}
+	for _, sidecar := range spec.Sidecars {
+		podSpec.Containers = append(podSpec.Containers, getContainer(sidecar))
+	}
+	for _, initContainer := range spec.InitContainers {
+		podSpec.InitContainers = append(podSpec.InitContainers, getContainer(initContainer))
+	}
+	for _, volume := range spec.Volumes {
+		podSpec.Volumes = append(podSpec.Volumes, getVolume(volume))
+	}
+	if len(spec.NodeSelector) > 0 {
+		podSpec.NodeSelector = getLabelsMap(spec.NodeSelector)
+	}
 
-	podTemplate := api.PodTemplateSpec{
-		ObjectMeta: objectMeta,
-		Spec:       podSpec,
+	// Tolerations are an alpha feature, that is set by annotation of the pods. The annotations
+	// are copied, so that they are not set on the deployment and the service.
+	if len(spec.Tolerations) > 0 {
+		tolerations, err := json.Marshal(spec.Tolerations)
+		if err != nil {
+			return nil, err
+		}
+		annotations := map[string]string{api.TolerationsAnnotationKey: string(tolerations)}
+		for key, value := range objectMeta.Annotations {
+			annotations[key] = value
+		}
+		objectMeta.Annotations = annotations
 	}
 
-	deployment := &extensions.Deployment{
+	return &api.PodTemplateSpec{
 		ObjectMeta: objectMeta,
-		Spec: extensions.DeploymentSpec{
-			Replicas: spec.Replicas,
-			Template: podTemplate,
-		},
+		Spec:       podSpec,
+	}, nil
+}
+
+func getContainer(spec ContainerSpec) api.Container {
+	return api.Container{
+		Name:    spec.Name,
+		Image:   spec.Image,
+		Command: spec.Command,
+		Args:    spec.Args,
+		Resources: getResourceRequirements(spec.CpuRequirement, spec.MemoryRequirement,
+			spec.CpuLimit, spec.MemoryLimit),
+		Env:            convertEnvVarsSpec(spec.Variables),
+		LivenessProbe:  getProbe(spec.LivenessProbe),
+		ReadinessProbe: getProbe(spec.ReadinessProbe),
+		VolumeMounts:   getVolumeMounts(spec.VolumeMounts),
 	}
-	_, err := client.Extensions().Deployments(spec.Namespace).Create(deployment)
+}
 
-	if err != nil {
-		// TODO(bryk): Roll back created resources in case of error.
-		return err
+func getResourceRequirements(cpuRequirement, memoryRequirement, cpuLimit,
+	memoryLimit *resource.Quantity) api.ResourceRequirements {
+	result := api.ResourceRequirements{
+		Requests: make(map[api.ResourceName]resource.Quantity),
+	}
+	if cpuRequirement != nil {
+		result.Requests[api.ResourceCPU] = *cpuRequirement
 	}
+	if memoryRequirement != nil {
+		result.Requests[api.ResourceMemory] = *memoryRequirement
+	}
+	if cpuLimit != nil || memoryLimit != nil {
+		result.Limits = make(map[api.ResourceName]resource.Quantity)
+	}
+	if cpuLimit != nil {
+		result.Limits[api.ResourceCPU] = *cpuLimit
+	}
+	if memoryLimit != nil {
+		result.Limits[api.ResourceMemory] = *memoryLimit
+	}
+	return result
+}
 
-	if len(spec.PortMappings) > 0 {
-		service := &api.Service{
-			ObjectMeta: objectMeta,
-			Spec: api.ServiceSpec{
-				Selector: labels,
-			},
+func getProbe(spec *ProbeSpec) *api.Probe {
+	if spec == nil {
+		return nil
+	}
+	probe := &api.Probe{
+		InitialDelaySeconds: spec.InitialDelaySeconds,
+		PeriodSeconds:       spec.PeriodSeconds,
+		TimeoutSeconds:      spec.TimeoutSeconds,
+		FailureThreshold:    spec.FailureThreshold,
+	}
+	switch spec.Type {
+	case HTTPProbe:
+		probe.HTTPGet = &api.HTTPGetAction{
+			Path: spec.Path,
+			Port: intstr.FromInt(int(spec.Port)),
 		}
+	case TCPProbe:
+		probe.TCPSocket = &api.TCPSocketAction{Port: intstr.FromInt(int(spec.Port))}
+	case ExecProbe:
+		probe.Exec = &api.ExecAction{Command: spec.Command}
+	}
+	return probe
+}
 
-		if spec.IsExternal {
-			service.Spec.Type = api.ServiceTypeLoadBalancer
-		} else {
-			service.Spec.Type = api.ServiceTypeClusterIP
+func getVolume(spec VolumeSpec) api.Volume {
+	volume := api.Volume{Name: spec.Name}
+	switch spec.Type {
+	case ConfigMapVolume:
+		volume.ConfigMap = &api.ConfigMapVolumeSource{
+			LocalObjectReference: api.LocalObjectReference{Name: spec.SourceName},
 		}
-
-		for _, portMapping := range spec.PortMappings {
-			servicePort :=
-				api.ServicePort{
-					Protocol: portMapping.Protocol,
-					Port:     portMapping.Port,
-					Name:     generatePortMappingName(portMapping),
-					TargetPort: intstr.IntOrString{
-						Type:   intstr.Int,
-						IntVal: portMapping.TargetPort,
-					},
-				}
-			service.Spec.Ports = append(service.Spec.Ports, servicePort)
+	case SecretVolume:
+		volume.Secret = &api.SecretVolumeSource{SecretName: spec.SourceName}
+	case PersistentVolumeClaimVolume:
+		volume.PersistentVolumeClaim = &api.PersistentVolumeClaimVolumeSource{
+			ClaimName: spec.SourceName,
+			ReadOnly:  spec.ReadOnly,
 		}
-
-		_, err = client.Core().Services(spec.Namespace).Create(service)
-
-		// TODO(bryk): Roll back created resources in case of error.
-		return err
 	}
+	return volume
+}
 
-	return nil
+func getVolumeMounts(specs []VolumeMountSpec) []api.VolumeMount {
+	var result []api.VolumeMount
+	for _, spec := range specs {
+		result = append(result, api.VolumeMount{
+			Name:      spec.Name,
+			MountPath: spec.MountPath,
+			ReadOnly:  spec.ReadOnly,
+		})
+	}
+	return result
 }
 
 // GetAvailableProtocols returns list of available protocols. Currently it is TCP and UDP.
@@ -288,16 +761,188 @@ func CreateObjectFromInfoFn(info *kubectlResource.Info) (bool, error) {
 	return createdResource != nil, err
 }
 
-// DeployAppFromFile deploys an app based on the given yaml or json file.
+type patchObjectFromInfo func(info *kubectlResource.Info, patch *ApplyPatch) error
+
+type deleteObjectFromInfo func(info *kubectlResource.Info) error
+
+// DeleteObjectFromInfoFn is an implementation of deleteObjectFromInfo.
+func DeleteObjectFromInfoFn(info *kubectlResource.Info) error {
+	return kubectlResource.NewHelper(info.Client, info.Mapping).Delete(info.Namespace, info.Name)
+}
+
+// PatchObjectFromInfoFn is an implementation of patchObjectFromInfo. Like the live object, the
+// patched one is not decoded, so that objects of every kind can be patched.
+func PatchObjectFromInfoFn(info *kubectlResource.Info, patch *ApplyPatch) error {
+	helper := kubectlResource.NewHelper(info.Client, info.Mapping)
+	return helper.RESTClient.Patch(patch.Type).
+		NamespaceIfScoped(info.Namespace, helper.NamespaceScoped).
+		Resource(helper.Resource).
+		Name(info.Name).
+		Body(patch.Data).
+		Do().
+		Error()
+}
+
+// ApplyPatch is a patch that applies an object from file to its live object.
+type ApplyPatch struct {
+	// Type of the patch, strategic merge patch or JSON merge patch.
+	Type api.PatchType
+
+	// JSON encoded patch.
+	Data []byte
+}
+
+// getApplyPatch returns patch that applies the object from file to the live object, the way
+// kubectl apply does, and the live object with the patch applied. Fields set in the file are set
+// on the live object. Fields set by the previous apply, as recorded in the last applied
+// configuration annotation, that are no longer in the file are removed. Fields set by the
+// apiserver or controllers are kept. Kinds unknown to the scheme have no patch metadata and are
+// patched with JSON merge patch, that replaces lists as a whole.
+func getApplyPatch(info *kubectlResource.Info, live runtime.Object) (*ApplyPatch, []byte,
+	error) {
+
+	current, err := toJSON(live)
+	if err != nil {
+		return nil, nil, err
+	}
+	original, err := getLastAppliedConfiguration(current)
+	if err != nil {
+		return nil, nil, err
+	}
+	modified, err := kubectl.GetModifiedConfiguration(info, true, getApplyCodec(info))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	versionedObject, err := api.Scheme.New(info.Mapping.GroupVersionKind)
+	if runtime.IsNotRegisteredError(err) {
+		data, err := createThreeWayJSONMergePatch(original, modified)
+		if err != nil {
+			return nil, nil, err
+		}
+		patched, err := jsonpatch.MergePatch(current, data)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &ApplyPatch{Type: api.MergePatchType, Data: data}, patched, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := strategicpatch.CreateThreeWayMergePatch(original, modified, current,
+		versionedObject, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	patched, err := strategicpatch.StrategicMergePatch(current, data, versionedObject)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &ApplyPatch{Type: api.StrategicMergePatchType, Data: data}, patched, nil
+}
+
+// getApplyCodec returns codec that encodes the object from file in its version.
+func getApplyCodec(info *kubectlResource.Info) runtime.Encoder {
+	return api.Codecs.LegacyCodec(info.Mapping.GroupVersionKind.GroupVersion())
+}
+
+// getLastAppliedConfiguration returns configuration of the previous apply recorded in the
+// annotation of the JSON encoded live object, or nil when it has not been applied before.
+func getLastAppliedConfiguration(current []byte) ([]byte, error) {
+	object := struct {
+		Metadata struct {
+			Annotations map[string]string `json:"annotations"`
+		} `json:"metadata"`
+	}{}
+	if err := json.Unmarshal(current, &object); err != nil {
+		return nil, err
+	}
+	original, ok := object.Metadata.Annotations[annotations.LastAppliedConfigAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	return []byte(original), nil
+}
+
+// createThreeWayJSONMergePatch returns JSON merge patch that sets fields of the modified
+// configuration and removes fields of the original configuration that are not in the modified
+// one.
+func createThreeWayJSONMergePatch(original, modified []byte) ([]byte, error) {
+	patch := make(map[string]interface{})
+	if err := json.Unmarshal(modified, &patch); err != nil {
+		return nil, err
+	}
+	if len(original) > 0 {
+		originalMap := make(map[string]interface{})
+		if err := json.Unmarshal(original, &originalMap); err != nil {
+			return nil, err
+		}
+		addRemovedFields(originalMap, patch)
+	}
+	return json.Marshal(patch)
+}
+
+// addRemovedFields sets fields of the original map that are not in the patch to null in the
+// patch, so that the patch removes them.
+func addRemovedFields(original, patch map[string]interface{}) {
+	for key, value := range original {
+		patchValue, ok := patch[key]
+		if !ok {
+			patch[key] = nil
+			continue
+		}
+		originalMap, isOriginalMap := value.(map[string]interface{})
+		patchMap, isPatchMap := patchValue.(map[string]interface{})
+		if isOriginalMap && isPatchMap {
+			addRemovedFields(originalMap, patchMap)
+		}
+	}
+}
+
+type getObjectFromInfo func(info *kubectlResource.Info) (runtime.Object, error)
+
+// GetObjectFromInfoFn is an implementation of getObjectFromInfo. The live object is got through
+// the REST mapping of the info, like kubectlResource.Helper does, so that objects of every kind
+// known to the apiserver can be got. It is returned as JSON of the version from the file.
+func GetObjectFromInfoFn(info *kubectlResource.Info) (runtime.Object, error) {
+	helper := kubectlResource.NewHelper(info.Client, info.Mapping)
+	raw, err := helper.RESTClient.Get().
+		NamespaceIfScoped(info.Namespace, helper.NamespaceScoped).
+		Resource(helper.Resource).
+		Name(info.Name).
+		SetHeader("Accept", "application/json").
+		Do().
+		Raw()
+	if err != nil {
+		return nil, err
+	}
+	return &runtime.Unknown{Raw: raw, ContentType: runtime.ContentTypeJSON}, nil
+}
+
+// DeployAppFromFile deploys an app based on the given yaml or json file. Each object from the
+// file is deployed separately and its status is reported in the response. When an object fails
+// to deploy, the remaining objects are skipped and the objects created before it are deleted in
+// reverse order. Objects patched in apply mode are not rolled back. Error is returned when the
+// file can not be read or none of its objects could be deployed.
 func DeployAppFromFile(spec *AppDeploymentFromFileSpec,
-	createObjectFromInfoFn createObjectFromInfo, clientConfig clientcmd.ClientConfig) (bool, error) {
+	createObjectFromInfoFn createObjectFromInfo, patchObjectFromInfoFn patchObjectFromInfo,
+	deleteObjectFromInfoFn deleteObjectFromInfo, getObjectFromInfoFn getObjectFromInfo,
+	clientConfig clientcmd.ClientConfig) (
+	*AppDeploymentFromFileResponse, error) {
+
 	const emptyCacheDir = ""
 	validate := spec.Validate
 
+	if spec.Mode != "" && spec.Mode != CreateDeploymentMode && spec.Mode != ApplyDeploymentMode {
+		return nil, fmt.Errorf("Unknown deployment mode %s, expected one of: %s, %s", spec.Mode,
+			CreateDeploymentMode, ApplyDeploymentMode)
+	}
+
 	factory := cmdutil.NewFactory(clientConfig)
 	schema, err := factory.Validator(validate, emptyCacheDir)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
 	mapper, typer := factory.Object()
@@ -310,16 +955,120 @@ func DeployAppFromFile(spec *AppDeploymentFromFileSpec,
 		Flatten().
 		Do()
 
-	deployedResourcesCount := 0
+	response := &AppDeploymentFromFileResponse{
+		Name:    spec.Name,
+		Content: spec.Content,
+		DryRun:  spec.DryRun,
+		Objects: make([]DeployedObjectStatus, 0),
+	}
+	transaction := new(deployTransaction)
 
 	err = r.Visit(func(info *kubectlResource.Info, err error) error {
-		isDeployed, err := createObjectFromInfoFn(info)
-		if isDeployed {
-			deployedResourcesCount++
-			log.Printf("%s is deployed", info.Name)
+		if err != nil {
+			return err
+		}
+		status := deployObjectFromInfo(spec, info, createObjectFromInfoFn,
+			patchObjectFromInfoFn, getObjectFromInfoFn)
+		transaction.add(status, func() error {
+			return deleteObjectFromInfoFn(info)
+		})
+		if status.Action != ObjectFailed && !spec.DryRun {
+			log.Printf("%s %s is %s", status.Kind, status.Name, status.Action)
+		}
+		if status.Action == ObjectFailed && !spec.DryRun {
+			return errDeployFailed
 		}
-		return err
+		return nil
 	})
+	if err == errDeployFailed {
+		err = nil
+	}
+	if !spec.DryRun && (err != nil || transaction.failed()) {
+		response.RolledBack = transaction.rollBack()
+	}
+	response.Objects = append(response.Objects, transaction.objects...)
+	errs := transaction.errors()
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	response.Error = strings.Join(errs, "\n")
+
+	if err != nil {
+		return response, err
+	}
+	if len(response.Objects) > 0 && transaction.failed() && !transaction.deployedAny() {
+		return response, errors.New(response.Error)
+	}
+	return response, nil
+}
+
+// deployObjectFromInfo deploys a single object from file according to the spec. Objects created
+// in apply mode get the last applied configuration annotation, so that later applies can remove
+// fields that are removed from the file.
+func deployObjectFromInfo(spec *AppDeploymentFromFileSpec, info *kubectlResource.Info,
+	createObjectFromInfoFn createObjectFromInfo, patchObjectFromInfoFn patchObjectFromInfo,
+	getObjectFromInfoFn getObjectFromInfo) DeployedObjectStatus {
+
+	status := DeployedObjectStatus{
+		Kind:      info.Mapping.GroupVersionKind.Kind,
+		Name:      info.Name,
+		Namespace: info.Namespace,
+	}
+	fail := func(err error) DeployedObjectStatus {
+		status.Action = ObjectFailed
+		status.Error = err.Error()
+		return status
+	}
+
+	if spec.Mode != ApplyDeploymentMode && !spec.DryRun {
+		if _, err := createObjectFromInfoFn(info); err != nil {
+			return fail(err)
+		}
+		status.Action = ObjectCreated
+		return status
+	}
+
+	live, err := getObjectFromInfoFn(info)
+	if k8serrors.IsNotFound(err) {
+		if !spec.DryRun {
+			if spec.Mode == ApplyDeploymentMode {
+				if err := kubectl.CreateApplyAnnotation(info, getApplyCodec(info)); err != nil {
+					return fail(err)
+				}
+			}
+			if _, err := createObjectFromInfoFn(info); err != nil {
+				return fail(err)
+			}
+		}
+		status.Action = ObjectCreated
+		return status
+	}
+	if err != nil {
+		return fail(err)
+	}
+	if spec.Mode != ApplyDeploymentMode {
+		return fail(fmt.Errorf("%s %s already exists", status.Kind, status.Name))
+	}
 
-	return deployedResourcesCount > 0, err
+	patch, patched, err := getApplyPatch(info, live)
+	if err != nil {
+		return fail(err)
+	}
+	status.Diff, err = GetObjectDiff(live,
+		&runtime.Unknown{Raw: patched, ContentType: runtime.ContentTypeJSON})
+	if err != nil {
+		return fail(err)
+	}
+	if len(status.Diff) == 0 {
+		status.Action = ObjectUnchanged
+		return status
+	}
+
+	if !spec.DryRun {
+		if err := patchObjectFromInfoFn(info, patch); err != nil {
+			return fail(err)
+		}
+	}
+	status.Action = ObjectUpdated
+	return status
 }
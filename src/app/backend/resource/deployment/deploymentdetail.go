@@ -17,7 +17,7 @@ package deployment
 import (
 	"log"
 
-	heapster "github.com/kubernetes/dashboard/src/app/backend/client"
+	kdClient "github.com/kubernetes/dashboard/src/app/backend/client"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/replicaset"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/replicaset/replicasetlist"
@@ -94,7 +94,7 @@ type DeploymentDetail struct {
 }
 
 // GetDeploymentDetail returns model object of deployment and error, if any.
-func GetDeploymentDetail(client client.Interface, heapsterClient heapster.HeapsterClient, namespace string,
+func GetDeploymentDetail(client client.Interface, metricsProvider kdClient.MetricsProvider, namespace string,
 	deploymentName string) (*DeploymentDetail, error) {
 
 	log.Printf("Getting details of %s deployment in %s namespace", deploymentName, namespace)
@@ -127,7 +127,7 @@ func GetDeploymentDetail(client client.Interface, heapsterClient heapster.Heapst
 	}
 
 	// Pods
-	podList, err := GetDeploymentPods(client, heapsterClient, dataselect.DefaultDataSelectWithMetrics, namespace, deploymentName)
+	podList, err := GetDeploymentPods(client, metricsProvider, dataselect.DefaultDataSelectWithMetrics, namespace, deploymentName)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,184 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"k8s.io/kubernetes/pkg/api/annotations"
+	"k8s.io/kubernetes/pkg/runtime"
+)
+
+// Operations that applying an object performs on its fields.
+const (
+	FieldAdded    = "add"
+	FieldReplaced = "replace"
+	FieldRemoved  = "remove"
+)
+
+// FieldDiff is a difference between a field of the live object and the same field of the
+// object that applying the submitted one results in.
+type FieldDiff struct {
+	// Path of the field, e.g., spec.template.spec.containers[0].image.
+	Path string `json:"path"`
+
+	// Operation performed on the field by applying the submitted object. One of: add, replace,
+	// remove.
+	Operation string `json:"operation"`
+
+	// Value of the field in the live object. Empty for added fields.
+	Live interface{} `json:"live,omitempty"`
+
+	// Value of the field in the submitted object. Empty for removed fields.
+	Submitted interface{} `json:"submitted,omitempty"`
+}
+
+// Metadata fields that are set by users. All other metadata fields are managed by the apiserver.
+var userMetadataFields = map[string]bool{
+	"name":        true,
+	"namespace":   true,
+	"labels":      true,
+	"annotations": true,
+}
+
+// GetObjectDiff returns differences between the live object and the object that applying the
+// submitted one results in, i.e., the live object with the apply patch applied. Fields of both
+// objects are compared, so that fields removed by apply are reported as well. Status, metadata
+// managed by the apiserver and the last applied configuration annotation are ignored.
+func GetObjectDiff(live runtime.Object, submitted interface{}) ([]FieldDiff, error) {
+	liveValue, err := toUserFields(live)
+	if err != nil {
+		return nil, err
+	}
+	submittedValue, err := toUserFields(submitted)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := make([]FieldDiff, 0)
+	diffValues("", liveValue, submittedValue, &diff)
+	return diff, nil
+}
+
+// toJSON returns JSON representation of the object. Raw content of unknown objects is used as is.
+func toJSON(object interface{}) ([]byte, error) {
+	if unknown, ok := object.(*runtime.Unknown); ok {
+		return unknown.Raw, nil
+	}
+	return json.Marshal(object)
+}
+
+// toUserFields converts the object to its generic JSON representation that has only the fields
+// set by users.
+func toUserFields(object interface{}) (map[string]interface{}, error) {
+	raw, err := toJSON(object)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]interface{})
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, err
+	}
+
+	delete(result, "status")
+	if metadata, ok := result["metadata"].(map[string]interface{}); ok {
+		for field := range metadata {
+			if !userMetadataFields[field] {
+				delete(metadata, field)
+			}
+		}
+		if userAnnotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+			delete(userAnnotations, annotations.LastAppliedConfigAnnotation)
+			if len(userAnnotations) == 0 {
+				delete(metadata, "annotations")
+			}
+		}
+	}
+	return result, nil
+}
+
+// diffValues appends differences between the live and submitted values of the field at the given
+// path to the diff. Keys of maps that are only in the live value are reported as removed.
+func diffValues(path string, live, submitted interface{}, diff *[]FieldDiff) {
+	switch submittedValue := submitted.(type) {
+	case nil:
+		if live != nil {
+			*diff = append(*diff, FieldDiff{Path: path, Operation: FieldRemoved, Live: live})
+		}
+	case map[string]interface{}:
+		liveValue, ok := live.(map[string]interface{})
+		if !ok {
+			diffScalars(path, live, submitted, diff)
+			return
+		}
+		keys := make([]string, 0, len(submittedValue))
+		for key := range submittedValue {
+			keys = append(keys, key)
+		}
+		for key := range liveValue {
+			if _, ok := submittedValue[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+			diffValues(fieldPath, liveValue[key], submittedValue[key], diff)
+		}
+	case []interface{}:
+		liveValue, ok := live.([]interface{})
+		if !ok {
+			diffScalars(path, live, submitted, diff)
+			return
+		}
+		for i, item := range submittedValue {
+			var liveItem interface{}
+			if i < len(liveValue) {
+				liveItem = liveValue[i]
+			}
+			diffValues(fmt.Sprintf("%s[%d]", path, i), liveItem, item, diff)
+		}
+		for i := len(submittedValue); i < len(liveValue); i++ {
+			*diff = append(*diff, FieldDiff{
+				Path:      fmt.Sprintf("%s[%d]", path, i),
+				Operation: FieldRemoved,
+				Live:      liveValue[i],
+			})
+		}
+	default:
+		diffScalars(path, live, submitted, diff)
+	}
+}
+
+// diffScalars appends difference between values that are compared as a whole.
+func diffScalars(path string, live, submitted interface{}, diff *[]FieldDiff) {
+	if live == nil {
+		*diff = append(*diff, FieldDiff{Path: path, Operation: FieldAdded, Submitted: submitted})
+	} else if !reflect.DeepEqual(live, submitted) {
+		*diff = append(*diff, FieldDiff{
+			Path:      path,
+			Operation: FieldReplaced,
+			Live:      live,
+			Submitted: submitted,
+		})
+	}
+}
@@ -20,7 +20,7 @@ import (
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/event"
 
-	heapster "github.com/kubernetes/dashboard/src/app/backend/client"
+	kdClient "github.com/kubernetes/dashboard/src/app/backend/client"
 	"k8s.io/kubernetes/pkg/api"
 	k8serrors "k8s.io/kubernetes/pkg/api/errors"
 	"k8s.io/kubernetes/pkg/apis/extensions"
@@ -54,23 +54,23 @@ type Deployment struct {
 }
 
 // GetDeploymentList returns a list of all Deployments in the cluster.
-func GetDeploymentList(client client.Interface, nsQuery *common.NamespaceQuery,
-	dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) (*DeploymentList, error) {
+func GetDeploymentList(client client.Interface, nsQuery *common.NamespaceQuery, listOptions api.ListOptions,
+	dsQuery *dataselect.DataSelectQuery, metricsProvider *kdClient.MetricsProvider) (*DeploymentList, error) {
 	log.Print("Getting list of all deployments in the cluster")
 
 	channels := &common.ResourceChannels{
-		DeploymentList: common.GetDeploymentListChannel(client, nsQuery, 1),
+		DeploymentList: common.GetDeploymentListChannelWithOptions(client, nsQuery, listOptions, 1),
 		PodList:        common.GetPodListChannel(client, nsQuery, 1),
 		EventList:      common.GetEventListChannel(client, nsQuery, 1),
 	}
 
-	return GetDeploymentListFromChannels(channels, dsQuery, heapsterClient)
+	return GetDeploymentListFromChannels(channels, dsQuery, metricsProvider)
 }
 
 // GetDeploymentList returns a list of all Deployments in the cluster
 // reading required resource list once from the channels.
 func GetDeploymentListFromChannels(channels *common.ResourceChannels,
-	dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) (*DeploymentList, error) {
+	dsQuery *dataselect.DataSelectQuery, metricsProvider *kdClient.MetricsProvider) (*DeploymentList, error) {
 
 	deployments := <-channels.DeploymentList.List
 	if err := <-channels.DeploymentList.Error; err != nil {
@@ -96,24 +96,24 @@ func GetDeploymentListFromChannels(channels *common.ResourceChannels,
 		return nil, err
 	}
 
-	return CreateDeploymentList(deployments.Items, pods.Items, events.Items, dsQuery, heapsterClient), nil
+	return CreateDeploymentList(deployments.Items, pods.Items, events.Items, dsQuery, metricsProvider), nil
 }
 
 // CreateDeploymentList returns a list of all Deployment model objects in the cluster, based on all
 // Kubernetes Deployment API objects.
 func CreateDeploymentList(deployments []extensions.Deployment, pods []api.Pod,
-	events []api.Event, dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) *DeploymentList {
+	events []api.Event, dsQuery *dataselect.DataSelectQuery, metricsProvider *kdClient.MetricsProvider) *DeploymentList {
 
 	deploymentList := &DeploymentList{
 		Deployments: make([]Deployment, 0),
-		ListMeta:    common.ListMeta{TotalItems: len(deployments)},
 	}
 
 	cachedResources := &dataselect.CachedResources{
 		Pods: pods,
 	}
-	replicationControllerCells, metricPromises := dataselect.GenericDataSelectWithMetrics(toCells(deployments), dsQuery, cachedResources, heapsterClient)
+	replicationControllerCells, metricPromises, filteredTotal := dataselect.GenericDataSelectWithFilterAndMetrics(toCells(deployments), dsQuery, cachedResources, metricsProvider)
 	deployments = fromCells(replicationControllerCells)
+	deploymentList.ListMeta = common.ListMeta{TotalItems: filteredTotal}
 
 	for _, deployment := range deployments {
 
@@ -15,7 +15,7 @@
 package deployment
 
 import (
-	heapster "github.com/kubernetes/dashboard/src/app/backend/client"
+	kdClient "github.com/kubernetes/dashboard/src/app/backend/client"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/pod"
 	client "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
@@ -26,7 +26,7 @@ import (
 )
 
 // getJobPods returns list of pods targeting deployment.
-func GetDeploymentPods(client client.Interface, heapsterClient heapster.HeapsterClient,
+func GetDeploymentPods(client client.Interface, metricsProvider kdClient.MetricsProvider,
 	dsQuery *dataselect.DataSelectQuery, namespace string, deploymentName string) (*pod.PodList, error) {
 
 	deployment, err := client.Extensions().Deployments(namespace).Get(deploymentName)
@@ -53,6 +53,6 @@ func GetDeploymentPods(client client.Interface, heapsterClient heapster.Heapster
 	pods := common.FilterNamespacedPodsBySelector(rawPods.Items, deployment.ObjectMeta.Namespace,
 		deployment.Spec.Selector.MatchLabels)
 
-	podList := pod.CreatePodList(pods, []api.Event{}, dsQuery, heapsterClient)
+	podList := pod.CreatePodList(pods, []api.Event{}, dsQuery, metricsProvider)
 	return &podList, nil
 }
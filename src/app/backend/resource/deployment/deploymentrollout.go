@@ -0,0 +1,285 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"k8s.io/kubernetes/pkg/api"
+	k8serrors "k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/apis/extensions"
+	client "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
+	"k8s.io/kubernetes/pkg/client/retry"
+	deploymentutil "k8s.io/kubernetes/pkg/controller/deployment/util"
+)
+
+// RestartedAtAnnotation is an annotation of the pod template that is set to the time of the last
+// restart of a deployment. Changing it makes the deployment roll out new pods.
+const RestartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// RolloutStatus is the status of the rollout of a deployment.
+type RolloutStatus struct {
+	ObjectMeta common.ObjectMeta `json:"objectMeta"`
+	TypeMeta   common.TypeMeta   `json:"typeMeta"`
+
+	// Revision of the deployment. Increased by every rollout.
+	Revision int64 `json:"revision"`
+
+	// Whether the rollout is paused.
+	Paused bool `json:"paused"`
+
+	// Whether the rollout is complete, i.e., the deployment controller observed the latest spec
+	// of the deployment and all replicas are updated and available.
+	Complete bool `json:"complete"`
+
+	// Status information on the deployment.
+	StatusInfo `json:"statusInfo"`
+
+	// Container images of the deployment.
+	ContainerImages []string `json:"containerImages"`
+
+	// Human readable description of the rollout status.
+	Message string `json:"message"`
+}
+
+// ContainerImageSpec is a specification of a new image of a container of a deployment.
+type ContainerImageSpec struct {
+	// Name of the container.
+	Container string `json:"container"`
+
+	// New image of the container.
+	Image string `json:"image"`
+}
+
+// RollbackSpec is a specification of a rollback of a deployment.
+type RollbackSpec struct {
+	// Revision to roll back to. Has to be a revision of one of the old replica sets of the
+	// deployment.
+	Revision int64 `json:"revision"`
+}
+
+// GetRolloutStatus returns rollout status of the deployment with the given name in the given
+// namespace.
+func GetRolloutStatus(client client.Interface, namespace, name string) (*RolloutStatus, error) {
+	deployment, err := client.Extensions().Deployments(namespace).Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return ToRolloutStatus(deployment), nil
+}
+
+// RestartDeployment rolls out new pods of the deployment, without changing their spec.
+func RestartDeployment(client client.Interface, namespace, name string) (*RolloutStatus, error) {
+	log.Printf("Restarting %s deployment in %s namespace", name, namespace)
+	return updateDeployment(client, namespace, name, func(deployment *extensions.Deployment) error {
+		if deployment.Spec.Template.Annotations == nil {
+			deployment.Spec.Template.Annotations = make(map[string]string)
+		}
+		deployment.Spec.Template.Annotations[RestartedAtAnnotation] =
+			time.Now().Format(time.RFC3339)
+		return nil
+	})
+}
+
+// PauseDeployment pauses rollout of the deployment. Changes of the deployment are not rolled out
+// until it is resumed.
+func PauseDeployment(client client.Interface, namespace, name string) (*RolloutStatus, error) {
+	log.Printf("Pausing rollout of %s deployment in %s namespace", name, namespace)
+	return updateDeployment(client, namespace, name, func(deployment *extensions.Deployment) error {
+		deployment.Spec.Paused = true
+		return nil
+	})
+}
+
+// ResumeDeployment resumes paused rollout of the deployment.
+func ResumeDeployment(client client.Interface, namespace, name string) (*RolloutStatus, error) {
+	log.Printf("Resuming rollout of %s deployment in %s namespace", name, namespace)
+	return updateDeployment(client, namespace, name, func(deployment *extensions.Deployment) error {
+		deployment.Spec.Paused = false
+		return nil
+	})
+}
+
+// SetDeploymentImage changes image of a container of the deployment, which rolls out new pods.
+func SetDeploymentImage(client client.Interface, namespace, name string,
+	spec *ContainerImageSpec) (*RolloutStatus, error) {
+
+	log.Printf("Setting image of %s container of %s deployment in %s namespace to %s",
+		spec.Container, name, namespace, spec.Image)
+	if spec.Image == "" {
+		return nil, k8serrors.NewBadRequest(fmt.Sprintf("Image of %s container can not be empty",
+			spec.Container))
+	}
+	return updateDeployment(client, namespace, name, func(deployment *extensions.Deployment) error {
+		containers := deployment.Spec.Template.Spec.Containers
+		for i := range containers {
+			if containers[i].Name == spec.Container {
+				containers[i].Image = spec.Image
+				return nil
+			}
+		}
+		return k8serrors.NewBadRequest(fmt.Sprintf("Deployment %s has no container named %s",
+			name, spec.Container))
+	})
+}
+
+// RollbackDeployment rolls the deployment back to the given revision. The revision has to belong
+// to one of the old replica sets of the deployment, including the ones scaled down to zero
+// replicas that GetDeploymentOldReplicaSets does not list.
+func RollbackDeployment(client client.Interface, namespace, name string,
+	spec *RollbackSpec) (*RolloutStatus, error) {
+
+	log.Printf("Rolling back %s deployment in %s namespace to revision %d", name, namespace,
+		spec.Revision)
+
+	deployment, err := client.Extensions().Deployments(namespace).Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if deployment.Spec.Paused {
+		return nil, k8serrors.NewConflict(extensions.Resource("deployments"), name,
+			errors.New("deployment is paused, resume it before rolling back"))
+	}
+
+	oldReplicaSets, err := getAllOldReplicaSets(client, deployment)
+	if err != nil {
+		return nil, err
+	}
+	if !hasRevision(oldReplicaSets, spec.Revision) {
+		return nil, k8serrors.NewBadRequest(fmt.Sprintf(
+			"Deployment %s has no old replica set of revision %d", name, spec.Revision))
+	}
+
+	err = client.Extensions().Deployments(namespace).Rollback(&extensions.DeploymentRollback{
+		Name:       name,
+		RollbackTo: extensions.RollbackConfig{Revision: spec.Revision},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return GetRolloutStatus(client, namespace, name)
+}
+
+// ToRolloutStatus returns rollout status of the given deployment.
+func ToRolloutStatus(deployment *extensions.Deployment) *RolloutStatus {
+	revision, err := deploymentutil.Revision(deployment)
+	if err != nil {
+		log.Printf("Could not parse revision of %s deployment: %s", deployment.Name, err)
+	}
+
+	status := &RolloutStatus{
+		ObjectMeta:      common.NewObjectMeta(deployment.ObjectMeta),
+		TypeMeta:        common.NewTypeMeta(common.ResourceKindDeployment),
+		Revision:        revision,
+		Paused:          deployment.Spec.Paused,
+		StatusInfo:      GetStatusInfo(&deployment.Status),
+		ContainerImages: common.GetContainerImages(&deployment.Spec.Template.Spec),
+	}
+
+	replicas := deployment.Spec.Replicas
+	switch {
+	case deployment.Spec.Paused:
+		status.Message = "Rollout is paused"
+	case deployment.Status.ObservedGeneration < deployment.Generation:
+		status.Message = "Waiting for rollout to start"
+	case deployment.Status.UpdatedReplicas < replicas:
+		status.Message = fmt.Sprintf("%d of %d replicas have been updated",
+			deployment.Status.UpdatedReplicas, replicas)
+	case deployment.Status.Replicas > deployment.Status.UpdatedReplicas:
+		status.Message = fmt.Sprintf("%d old replicas are pending termination",
+			deployment.Status.Replicas-deployment.Status.UpdatedReplicas)
+	case deployment.Status.AvailableReplicas < deployment.Status.UpdatedReplicas:
+		status.Message = fmt.Sprintf("%d of %d updated replicas are available",
+			deployment.Status.AvailableReplicas, deployment.Status.UpdatedReplicas)
+	default:
+		status.Complete = true
+		status.Message = "Rollout is complete"
+	}
+
+	return status
+}
+
+// updateDeployment gets the deployment, modifies it with the given function and updates it. When
+// the deployment has been changed in the meantime, e.g., by the deployment controller, the update
+// is retried with the latest version of the deployment.
+func updateDeployment(client client.Interface, namespace, name string,
+	modify func(deployment *extensions.Deployment) error) (*RolloutStatus, error) {
+
+	var updated *extensions.Deployment
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		deployment, err := client.Extensions().Deployments(namespace).Get(name)
+		if err != nil {
+			return err
+		}
+		if err := modify(deployment); err != nil {
+			return err
+		}
+
+		updated, err = client.Extensions().Deployments(namespace).Update(deployment)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ToRolloutStatus(updated), nil
+}
+
+// getAllOldReplicaSets returns all replica sets of the deployment except for the new one.
+func getAllOldReplicaSets(client client.Interface,
+	deployment *extensions.Deployment) ([]*extensions.ReplicaSet, error) {
+
+	selector, err := unversioned.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	replicaSetList, err := client.Extensions().ReplicaSets(deployment.Namespace).
+		List(api.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+
+	replicaSets := make([]*extensions.ReplicaSet, 0)
+	for i := range replicaSetList.Items {
+		replicaSets = append(replicaSets, &replicaSetList.Items[i])
+	}
+	newReplicaSet, err := deploymentutil.FindNewReplicaSet(deployment, replicaSets)
+	if err != nil {
+		return nil, err
+	}
+
+	oldReplicaSets := make([]*extensions.ReplicaSet, 0)
+	for _, replicaSet := range replicaSets {
+		if replicaSet != newReplicaSet {
+			oldReplicaSets = append(oldReplicaSets, replicaSet)
+		}
+	}
+	return oldReplicaSets, nil
+}
+
+// hasRevision returns true when one of the replica sets is of the given revision.
+func hasRevision(replicaSets []*extensions.ReplicaSet, revision int64) bool {
+	for _, replicaSet := range replicaSets {
+		if parsed, err := deploymentutil.Revision(replicaSet); err == nil && parsed == revision {
+			return true
+		}
+	}
+	return false
+}
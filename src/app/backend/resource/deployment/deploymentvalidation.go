@@ -0,0 +1,317 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"fmt"
+	"path"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/resource"
+	"k8s.io/kubernetes/pkg/util/sets"
+	"k8s.io/kubernetes/pkg/util/validation"
+	"k8s.io/kubernetes/pkg/util/validation/field"
+)
+
+// ValidateAppDeploymentSpec validates the given app deployment spec. Paths of the returned errors
+// are JSON paths of the invalid spec fields, e.g., sidecars[0].volumeMounts[1].name, so that
+// they can be shown next to the form fields. Checks that need the cluster, e.g., whether the
+// referenced config maps exist, are not done.
+func ValidateAppDeploymentSpec(spec *AppDeploymentSpec) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for _, msg := range validation.IsDNS1123Label(spec.Name) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("name"), spec.Name, msg))
+	}
+	if len(spec.ContainerImage) == 0 {
+		allErrs = append(allErrs, field.Required(field.NewPath("containerImage"), ""))
+	}
+	if spec.Replicas < 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("replicas"), spec.Replicas,
+			"must be greater than or equal to 0"))
+	}
+
+	allErrs = append(allErrs, validatePortMappings(spec.PortMappings,
+		field.NewPath("portMappings"))...)
+	allErrs = append(allErrs, validateVariables(spec.Variables, field.NewPath("variables"))...)
+	allErrs = append(allErrs, validateResources(spec.CpuRequirement, spec.MemoryRequirement,
+		spec.CpuLimit, spec.MemoryLimit, nil)...)
+	allErrs = append(allErrs, validateProbe(spec.LivenessProbe,
+		field.NewPath("livenessProbe"))...)
+	allErrs = append(allErrs, validateProbe(spec.ReadinessProbe,
+		field.NewPath("readinessProbe"))...)
+
+	volumes, errs := validateVolumes(spec.Volumes, field.NewPath("volumes"))
+	allErrs = append(allErrs, errs...)
+	allErrs = append(allErrs, validateVolumeMounts(spec.VolumeMounts, volumes,
+		field.NewPath("volumeMounts"))...)
+
+	containers := sets.NewString(spec.Name)
+	for i, container := range spec.Sidecars {
+		allErrs = append(allErrs, validateContainer(container, containers, volumes,
+			field.NewPath("sidecars").Index(i))...)
+	}
+	for i, container := range spec.InitContainers {
+		fldPath := field.NewPath("initContainers").Index(i)
+		allErrs = append(allErrs, validateContainer(container, containers, volumes, fldPath)...)
+		if container.LivenessProbe != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("livenessProbe"),
+				"may not be set for init containers"))
+		}
+		if container.ReadinessProbe != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("readinessProbe"),
+				"may not be set for init containers"))
+		}
+	}
+
+	allErrs = append(allErrs, validateLabels(spec.Labels, field.NewPath("labels"))...)
+	allErrs = append(allErrs, validateLabels(spec.NodeSelector,
+		field.NewPath("nodeSelector"))...)
+	allErrs = append(allErrs, validateTolerations(spec.Tolerations,
+		field.NewPath("tolerations"))...)
+
+	return allErrs
+}
+
+// Validates the given container. Names of the containers validated so far are used to detect
+// duplicates, the name of this container is added to them.
+func validateContainer(container ContainerSpec, containers, volumes sets.String,
+	fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for _, msg := range validation.IsDNS1123Label(container.Name) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), container.Name, msg))
+	}
+	if containers.Has(container.Name) {
+		allErrs = append(allErrs, field.Duplicate(fldPath.Child("name"), container.Name))
+	}
+	containers.Insert(container.Name)
+	if len(container.Image) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("image"), ""))
+	}
+
+	allErrs = append(allErrs, validateVariables(container.Variables,
+		fldPath.Child("variables"))...)
+	allErrs = append(allErrs, validateResources(container.CpuRequirement,
+		container.MemoryRequirement, container.CpuLimit, container.MemoryLimit, fldPath)...)
+	allErrs = append(allErrs, validateProbe(container.LivenessProbe,
+		fldPath.Child("livenessProbe"))...)
+	allErrs = append(allErrs, validateProbe(container.ReadinessProbe,
+		fldPath.Child("readinessProbe"))...)
+	allErrs = append(allErrs, validateVolumeMounts(container.VolumeMounts, volumes,
+		fldPath.Child("volumeMounts"))...)
+
+	return allErrs
+}
+
+// Validates the given port mappings. Mappings of the same port and protocol conflict, as they
+// would be the same port of the service.
+func validatePortMappings(portMappings []PortMapping, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	ports := sets.NewString()
+	for i, portMapping := range portMappings {
+		idxPath := fldPath.Index(i)
+		for _, msg := range validation.IsValidPortNum(int(portMapping.Port)) {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("port"), portMapping.Port,
+				msg))
+		}
+		for _, msg := range validation.IsValidPortNum(int(portMapping.TargetPort)) {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("targetPort"),
+				portMapping.TargetPort, msg))
+		}
+		if portMapping.Protocol != api.ProtocolTCP && portMapping.Protocol != api.ProtocolUDP {
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("protocol"),
+				portMapping.Protocol, []string{string(api.ProtocolTCP), string(api.ProtocolUDP)}))
+		}
+		port := fmt.Sprintf("%d/%s", portMapping.Port, portMapping.Protocol)
+		if ports.Has(port) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("port"), port))
+		}
+		ports.Insert(port)
+	}
+	return allErrs
+}
+
+func validateVariables(variables []EnvironmentVariable, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, variable := range variables {
+		for _, msg := range validation.IsCIdentifier(variable.Name) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("name"),
+				variable.Name, msg))
+		}
+	}
+	return allErrs
+}
+
+// Validates the given resource requirements and limits. Limits may not be lower than the
+// requirements.
+func validateResources(cpuRequirement, memoryRequirement, cpuLimit,
+	memoryLimit *resource.Quantity, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := []string{"cpuRequirement", "memoryRequirement", "cpuLimit", "memoryLimit"}
+	for i, quantity := range []*resource.Quantity{cpuRequirement, memoryRequirement, cpuLimit,
+		memoryLimit} {
+		if quantity != nil && quantity.Sign() < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child(names[i]), quantity.String(),
+				"must be greater than or equal to 0"))
+		}
+	}
+	if cpuRequirement != nil && cpuLimit != nil && cpuLimit.Cmp(*cpuRequirement) < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("cpuLimit"), cpuLimit.String(),
+			"must be greater than or equal to cpuRequirement"))
+	}
+	if memoryRequirement != nil && memoryLimit != nil &&
+		memoryLimit.Cmp(*memoryRequirement) < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("memoryLimit"),
+			memoryLimit.String(), "must be greater than or equal to memoryRequirement"))
+	}
+	return allErrs
+}
+
+func validateProbe(probe *ProbeSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if probe == nil {
+		return allErrs
+	}
+
+	switch probe.Type {
+	case HTTPProbe, TCPProbe:
+		for _, msg := range validation.IsValidPortNum(int(probe.Port)) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("port"), probe.Port, msg))
+		}
+	case ExecProbe:
+		if len(probe.Command) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("command"), ""))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("type"), probe.Type,
+			[]string{HTTPProbe, TCPProbe, ExecProbe}))
+	}
+
+	names := []string{"initialDelaySeconds", "periodSeconds", "timeoutSeconds",
+		"failureThreshold"}
+	for i, value := range []int32{probe.InitialDelaySeconds, probe.PeriodSeconds,
+		probe.TimeoutSeconds, probe.FailureThreshold} {
+		if value < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child(names[i]), value,
+				"must be greater than or equal to 0"))
+		}
+	}
+	return allErrs
+}
+
+// Validates the given volumes and returns their names.
+func validateVolumes(volumes []VolumeSpec, fldPath *field.Path) (sets.String, field.ErrorList) {
+	allErrs := field.ErrorList{}
+	names := sets.NewString()
+	for i, volume := range volumes {
+		idxPath := fldPath.Index(i)
+		for _, msg := range validation.IsDNS1123Label(volume.Name) {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("name"), volume.Name, msg))
+		}
+		if names.Has(volume.Name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), volume.Name))
+		}
+		names.Insert(volume.Name)
+
+		switch volume.Type {
+		case ConfigMapVolume, SecretVolume, PersistentVolumeClaimVolume:
+		default:
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("type"), volume.Type,
+				[]string{ConfigMapVolume, SecretVolume, PersistentVolumeClaimVolume}))
+		}
+		if len(volume.SourceName) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("sourceName"), ""))
+		}
+	}
+	return names, allErrs
+}
+
+// Validates volume mounts of a container. Mounted volumes must be among the given volumes.
+func validateVolumeMounts(mounts []VolumeMountSpec, volumes sets.String,
+	fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	mountPaths := sets.NewString()
+	for i, mount := range mounts {
+		idxPath := fldPath.Index(i)
+		if !volumes.Has(mount.Name) {
+			allErrs = append(allErrs, field.NotFound(idxPath.Child("name"), mount.Name))
+		}
+		if !path.IsAbs(mount.MountPath) {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("mountPath"),
+				mount.MountPath, "must be an absolute path"))
+		}
+		if mountPaths.Has(mount.MountPath) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("mountPath"),
+				mount.MountPath))
+		}
+		mountPaths.Insert(mount.MountPath)
+	}
+	return allErrs
+}
+
+func validateLabels(labels []Label, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, label := range labels {
+		idxPath := fldPath.Index(i)
+		for _, msg := range validation.IsQualifiedName(label.Key) {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("key"), label.Key, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(label.Value) {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("value"), label.Value, msg))
+		}
+	}
+	return allErrs
+}
+
+func validateTolerations(tolerations []api.Toleration, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, toleration := range tolerations {
+		idxPath := fldPath.Index(i)
+		// Tolerations without key tolerate all taints and need the Exists operator.
+		if len(toleration.Key) > 0 || toleration.Operator != api.TolerationOpExists {
+			for _, msg := range validation.IsQualifiedName(toleration.Key) {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("key"), toleration.Key,
+					msg))
+			}
+		}
+
+		switch toleration.Operator {
+		case api.TolerationOpEqual, "":
+			for _, msg := range validation.IsValidLabelValue(toleration.Value) {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("value"),
+					toleration.Value, msg))
+			}
+		case api.TolerationOpExists:
+			if len(toleration.Value) > 0 {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("value"),
+					toleration.Value, "must be empty when operator is Exists"))
+			}
+		default:
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("operator"),
+				toleration.Operator, []string{string(api.TolerationOpEqual),
+					string(api.TolerationOpExists)}))
+		}
+
+		switch toleration.Effect {
+		case "", api.TaintEffectNoSchedule, api.TaintEffectPreferNoSchedule:
+		default:
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("effect"),
+				toleration.Effect, []string{string(api.TaintEffectNoSchedule),
+					string(api.TaintEffectPreferNoSchedule)}))
+		}
+	}
+	return allErrs
+}
@@ -23,6 +23,7 @@ import (
 	client "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
 	"k8s.io/kubernetes/pkg/fields"
 	"k8s.io/kubernetes/pkg/labels"
+	"k8s.io/kubernetes/pkg/runtime"
 	"k8s.io/kubernetes/pkg/types"
 )
 
@@ -111,6 +112,31 @@ func GetNamespaceEvents(client client.Interface, dsQuery *dataselect.DataSelectQ
 	return CreateEventList(events.Items, dsQuery), nil
 }
 
+// GetEventList returns list of events from the namespaces selected by the query. Events can be
+// filtered by type, reason, kind and name of the involved object and time when they were last
+// seen, e.g., "type=Warning,lastSeen>1h".
+func GetEventList(client client.Interface, nsQuery *common.NamespaceQuery,
+	listOptions api.ListOptions, dsQuery *dataselect.DataSelectQuery) (*common.EventList, error) {
+
+	log.Print("Getting list of events")
+
+	channels := &common.ResourceChannels{
+		EventList: common.GetEventListChannelWithOptions(client, nsQuery, listOptions, 1),
+	}
+
+	eventList := <-channels.EventList.List
+	if err := <-channels.EventList.Error; err != nil {
+		return nil, err
+	}
+
+	events := eventList.Items
+	if !IsTypeFilled(events) {
+		events = FillEventsType(events)
+	}
+	result := CreateEventList(events, dsQuery)
+	return &result, nil
+}
+
 // Based on event Reason fills event Type in order to allow correct filtering by Type.
 func FillEventsType(events []api.Event) []api.Event {
 	for i := range events {
@@ -154,6 +180,11 @@ func ToEvent(event api.Event) common.Event {
 		LastSeen:        event.LastTimestamp,
 		Reason:          event.Reason,
 		Type:            event.Type,
+		InvolvedObject: common.InvolvedObject{
+			Kind:      event.InvolvedObject.Kind,
+			Name:      event.InvolvedObject.Name,
+			Namespace: event.InvolvedObject.Namespace,
+		},
 	}
 
 	return result
@@ -163,11 +194,12 @@ func ToEvent(event api.Event) common.Event {
 func CreateEventList(events []api.Event, dsQuery *dataselect.DataSelectQuery) common.EventList {
 
 	eventList := common.EventList{
-		Events:   make([]common.Event, 0),
-		ListMeta: common.ListMeta{TotalItems: len(events)},
+		Events: make([]common.Event, 0),
 	}
 
-	events = fromCells(dataselect.GenericDataSelect(toCells(events), dsQuery))
+	cells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(events), dsQuery)
+	events = fromCells(cells)
+	eventList.ListMeta = common.ListMeta{TotalItems: filteredTotal}
 
 	for _, event := range events {
 		eventDetail := ToEvent(event)
@@ -189,6 +221,18 @@ func (self EventCell) GetProperty(name dataselect.PropertyName) dataselect.Compa
 		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
 	case dataselect.NamespaceProperty:
 		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.LabelsProperty:
+		return dataselect.StdComparableLabels(self.ObjectMeta.Labels)
+	case dataselect.TypeProperty:
+		return dataselect.StdComparableString(self.Type)
+	case dataselect.ReasonProperty:
+		return dataselect.StdComparableString(self.Reason)
+	case dataselect.LastSeenProperty:
+		return dataselect.StdComparableTime(self.LastTimestamp.Time)
+	case dataselect.InvolvedObjectKindProperty:
+		return dataselect.StdComparableString(self.InvolvedObject.Kind)
+	case dataselect.InvolvedObjectNameProperty:
+		return dataselect.StdComparableString(self.InvolvedObject.Name)
 	default:
 		// if name is not supported then just return a constant dummy value, sort will have no effect.
 		return nil
@@ -210,3 +254,8 @@ func fromCells(cells []dataselect.DataCell) []api.Event {
 	}
 	return std
 }
+
+func (self EventCell) GetObject() runtime.Object {
+	event := api.Event(self)
+	return &event
+}
@@ -0,0 +1,108 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package event
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	client "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
+	"k8s.io/kubernetes/pkg/watch"
+)
+
+// WatchEvents sends every new event from the given namespace, or from all namespaces if it is
+// empty, that matches the filter query. Events that occur again are sent again with updated count
+// and last seen time. Events that happened before the call are not sent. Watching continues until
+// stop is closed, send returns an error or the watch fails.
+func WatchEvents(client client.Interface, namespace string, listOptions api.ListOptions,
+	filterQuery *dataselect.FilterQuery, stop <-chan struct{},
+	send func(event common.Event) error) error {
+
+	log.Printf("Watching events in namespace %q", namespace)
+
+	// List events first, so that the watch starts right after the existing ones.
+	list, err := client.Core().Events(namespace).List(listOptions)
+	if err != nil {
+		return err
+	}
+	resourceVersion := list.ResourceVersion
+
+	for {
+		options := listOptions
+		options.ResourceVersion = resourceVersion
+		watcher, err := client.Core().Events(namespace).Watch(options)
+		if err != nil {
+			return err
+		}
+
+		var stopped bool
+		resourceVersion, stopped, err = sendWatchedEvents(watcher, resourceVersion, filterQuery,
+			stop, send)
+		watcher.Stop()
+		if err != nil || stopped {
+			return err
+		}
+		// Watch was closed by the apiserver, e.g., after a timeout. It is resumed from the last
+		// known resource version, so that no event is missed or sent twice.
+	}
+}
+
+// sendWatchedEvents sends events received by the watcher until the watch is closed or stop is
+// closed. Returns resource version of the last received event, or the given resource version
+// when no event has been received, and whether watching should stop.
+func sendWatchedEvents(watcher watch.Interface, resourceVersion string,
+	filterQuery *dataselect.FilterQuery, stop <-chan struct{},
+	send func(event common.Event) error) (string, bool, error) {
+
+	for {
+		select {
+		case <-stop:
+			return resourceVersion, true, nil
+		case watchEvent, ok := <-watcher.ResultChan():
+			if !ok {
+				return resourceVersion, false, nil
+			}
+
+			switch watchEvent.Type {
+			case watch.Added, watch.Modified:
+				event, ok := watchEvent.Object.(*api.Event)
+				if !ok {
+					continue
+				}
+				resourceVersion = event.ResourceVersion
+				if len(event.Type) == 0 {
+					event.Type = FillEventsType([]api.Event{*event})[0].Type
+				}
+				if !filterQuery.Matches(EventCell(*event)) {
+					continue
+				}
+				if err := send(ToEvent(*event)); err != nil {
+					return resourceVersion, true, err
+				}
+			case watch.Error:
+				if status, ok := watchEvent.Object.(*unversioned.Status); ok {
+					return resourceVersion, true,
+						fmt.Errorf("Watch of events failed: %s", status.Message)
+				}
+				return resourceVersion, true,
+					fmt.Errorf("Watch of events failed: %v", watchEvent.Object)
+			}
+		}
+	}
+}
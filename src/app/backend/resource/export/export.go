@@ -0,0 +1,323 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package export renders data cells selected for resource lists as CSV spreadsheets or as YAML
+// documents of the underlying Kubernetes objects.
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/meta"
+	"k8s.io/kubernetes/pkg/apimachinery/registered"
+	"k8s.io/kubernetes/pkg/apis/apps"
+	"k8s.io/kubernetes/pkg/apis/batch"
+	"k8s.io/kubernetes/pkg/apis/extensions"
+	"k8s.io/kubernetes/pkg/runtime"
+
+	// Register types of the exported objects, so that they can be encoded.
+	_ "k8s.io/kubernetes/pkg/api/install"
+	_ "k8s.io/kubernetes/pkg/apis/apps/install"
+	_ "k8s.io/kubernetes/pkg/apis/batch/install"
+	_ "k8s.io/kubernetes/pkg/apis/extensions/install"
+)
+
+// Export formats.
+const (
+	// FormatCSV is a spreadsheet with one row per object.
+	FormatCSV = "csv"
+
+	// FormatYAML is a stream of YAML documents, one per object.
+	FormatYAML = "yaml"
+)
+
+// MIME types of the export formats.
+const (
+	MIMECSV  = "text/csv"
+	MIMEYAML = "application/yaml"
+)
+
+// WriteCSV writes objects of the data cells as CSV with a header row. Columns common to all
+// objects, e.g., name and labels, are followed by columns specific to the kind of the objects.
+func WriteCSV(writer io.Writer, cells []dataselect.DataCell) error {
+	objects, err := getObjects(cells)
+	if err != nil {
+		return err
+	}
+
+	csvWriter := csv.NewWriter(writer)
+	for i, object := range objects {
+		record, err := toRecord(object)
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			if err := csvWriter.Write(record.header); err != nil {
+				return err
+			}
+		}
+		if err := csvWriter.Write(record.values); err != nil {
+			return err
+		}
+	}
+	if len(objects) == 0 {
+		if err := csvWriter.Write(newRecord().header); err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// WriteYAML writes objects of the data cells as YAML documents in their preferred API versions.
+// Data of secrets is left out, so that exports do not reveal secret values.
+func WriteYAML(writer io.Writer, cells []dataselect.DataCell) error {
+	objects, err := getObjects(cells)
+	if err != nil {
+		return err
+	}
+
+	codec := api.Codecs.LegacyCodec(registered.EnabledVersions()...)
+	for i, object := range objects {
+		if secret, ok := object.(*api.Secret); ok {
+			withoutData := *secret
+			withoutData.Data = nil
+			object = &withoutData
+		}
+		data, err := runtime.Encode(codec, object)
+		if err != nil {
+			return err
+		}
+		data, err = yaml.JSONToYAML(data)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			if _, err := io.WriteString(writer, "---\n"); err != nil {
+				return err
+			}
+		}
+		if _, err := writer.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func getObjects(cells []dataselect.DataCell) ([]runtime.Object, error) {
+	objects := make([]runtime.Object, 0, len(cells))
+	for _, cell := range cells {
+		objectCell, ok := cell.(dataselect.ObjectDataCell)
+		if !ok {
+			return nil, fmt.Errorf("Data cells of type %T can not be exported", cell)
+		}
+		objects = append(objects, objectCell.GetObject())
+	}
+	return objects, nil
+}
+
+// record is a CSV row together with the header of its columns.
+type record struct {
+	header []string
+	values []string
+}
+
+func newRecord() *record {
+	return &record{
+		header: []string{"Name", "Namespace", "Labels", "Created"},
+		values: make([]string, 0),
+	}
+}
+
+func (self *record) add(column, value string) {
+	self.header = append(self.header, column)
+	self.values = append(self.values, value)
+}
+
+func toRecord(object runtime.Object) (*record, error) {
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		return nil, err
+	}
+	result := newRecord()
+	result.values = append(result.values, accessor.GetName(), accessor.GetNamespace(),
+		formatLabels(accessor.GetLabels()),
+		accessor.GetCreationTimestamp().UTC().Format(time.RFC3339))
+
+	switch o := object.(type) {
+	case *api.Pod:
+		requests, _, err := api.PodRequestsAndLimits(o)
+		if err != nil {
+			return nil, err
+		}
+		restarts := int32(0)
+		for _, status := range o.Status.ContainerStatuses {
+			restarts += status.RestartCount
+		}
+		result.add("Status", string(o.Status.Phase))
+		result.add("Node", o.Spec.NodeName)
+		result.add("IP", o.Status.PodIP)
+		result.add("Restarts", formatInt(restarts))
+		result.add("CPU Requests", formatQuantity(requests, api.ResourceCPU))
+		result.add("Memory Requests", formatQuantity(requests, api.ResourceMemory))
+		result.add("Images", formatImages(o.Spec))
+	case *extensions.Deployment:
+		result.add("Desired", formatInt(o.Spec.Replicas))
+		result.add("Current", formatInt(o.Status.Replicas))
+		result.add("Available", formatInt(o.Status.AvailableReplicas))
+		result.add("Images", formatImages(o.Spec.Template.Spec))
+	case *extensions.ReplicaSet:
+		result.add("Desired", formatInt(o.Spec.Replicas))
+		result.add("Current", formatInt(o.Status.Replicas))
+		result.add("Ready", formatInt(o.Status.ReadyReplicas))
+		result.add("Images", formatImages(o.Spec.Template.Spec))
+	case *api.ReplicationController:
+		result.add("Desired", formatInt(o.Spec.Replicas))
+		result.add("Current", formatInt(o.Status.Replicas))
+		result.add("Ready", formatInt(o.Status.ReadyReplicas))
+		if o.Spec.Template != nil {
+			result.add("Images", formatImages(o.Spec.Template.Spec))
+		} else {
+			result.add("Images", "")
+		}
+	case *apps.StatefulSet:
+		result.add("Desired", formatInt(o.Spec.Replicas))
+		result.add("Current", formatInt(o.Status.Replicas))
+		result.add("Images", formatImages(o.Spec.Template.Spec))
+	case *extensions.DaemonSet:
+		result.add("Desired", formatInt(o.Status.DesiredNumberScheduled))
+		result.add("Current", formatInt(o.Status.CurrentNumberScheduled))
+		result.add("Images", formatImages(o.Spec.Template.Spec))
+	case *batch.Job:
+		completions := ""
+		if o.Spec.Completions != nil {
+			completions = formatInt(*o.Spec.Completions)
+		}
+		result.add("Completions", completions)
+		result.add("Succeeded", formatInt(o.Status.Succeeded))
+		result.add("Failed", formatInt(o.Status.Failed))
+		result.add("Images", formatImages(o.Spec.Template.Spec))
+	case *api.Service:
+		ports := make([]string, 0)
+		for _, port := range o.Spec.Ports {
+			ports = append(ports, fmt.Sprintf("%d/%s", port.Port, port.Protocol))
+		}
+		result.add("Type", string(o.Spec.Type))
+		result.add("Cluster IP", o.Spec.ClusterIP)
+		result.add("Ports", strings.Join(ports, " "))
+	case *extensions.Ingress:
+		hosts := make([]string, 0)
+		for _, rule := range o.Spec.Rules {
+			hosts = append(hosts, rule.Host)
+		}
+		endpoints := make([]string, 0)
+		for _, ingress := range o.Status.LoadBalancer.Ingress {
+			if ingress.IP != "" {
+				endpoints = append(endpoints, ingress.IP)
+			} else {
+				endpoints = append(endpoints, ingress.Hostname)
+			}
+		}
+		result.add("Hosts", strings.Join(hosts, " "))
+		result.add("Endpoints", strings.Join(endpoints, " "))
+	case *api.Node:
+		ready := string(api.ConditionUnknown)
+		for _, condition := range o.Status.Conditions {
+			if condition.Type == api.NodeReady {
+				ready = string(condition.Status)
+			}
+		}
+		result.add("Ready", ready)
+		result.add("Unschedulable", strconv.FormatBool(o.Spec.Unschedulable))
+		result.add("Kubelet Version", o.Status.NodeInfo.KubeletVersion)
+		result.add("CPU Capacity", formatQuantity(o.Status.Capacity, api.ResourceCPU))
+		result.add("Memory Capacity", formatQuantity(o.Status.Capacity, api.ResourceMemory))
+	case *api.Namespace:
+		result.add("Status", string(o.Status.Phase))
+	case *api.Event:
+		result.add("Type", o.Type)
+		result.add("Reason", o.Reason)
+		result.add("Object", fmt.Sprintf("%s/%s", o.InvolvedObject.Kind, o.InvolvedObject.Name))
+		result.add("Message", o.Message)
+		result.add("Count", formatInt(o.Count))
+		result.add("Last Seen", o.LastTimestamp.UTC().Format(time.RFC3339))
+	case *api.Secret:
+		result.add("Type", string(o.Type))
+		result.add("Keys", strconv.Itoa(len(o.Data)))
+	case *api.ConfigMap:
+		result.add("Keys", strconv.Itoa(len(o.Data)))
+	case *api.PersistentVolume:
+		claim := ""
+		if o.Spec.ClaimRef != nil {
+			claim = o.Spec.ClaimRef.Namespace + "/" + o.Spec.ClaimRef.Name
+		}
+		result.add("Status", string(o.Status.Phase))
+		result.add("Capacity", formatQuantity(o.Spec.Capacity, api.ResourceStorage))
+		result.add("Access Modes", formatAccessModes(o.Spec.AccessModes))
+		result.add("Claim", claim)
+	case *api.PersistentVolumeClaim:
+		result.add("Status", string(o.Status.Phase))
+		result.add("Volume", o.Spec.VolumeName)
+		result.add("Capacity", formatQuantity(o.Status.Capacity, api.ResourceStorage))
+		result.add("Access Modes", formatAccessModes(o.Spec.AccessModes))
+	}
+
+	return result, nil
+}
+
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func formatImages(spec api.PodSpec) string {
+	images := make([]string, 0, len(spec.Containers))
+	for _, container := range spec.Containers {
+		images = append(images, container.Image)
+	}
+	return strings.Join(images, " ")
+}
+
+func formatQuantity(resources api.ResourceList, name api.ResourceName) string {
+	if quantity, ok := resources[name]; ok {
+		return quantity.String()
+	}
+	return ""
+}
+
+func formatAccessModes(modes []api.PersistentVolumeAccessMode) string {
+	result := make([]string, 0, len(modes))
+	for _, mode := range modes {
+		result = append(result, string(mode))
+	}
+	return strings.Join(result, " ")
+}
+
+func formatInt(value int32) string {
+	return strconv.FormatInt(int64(value), 10)
+}
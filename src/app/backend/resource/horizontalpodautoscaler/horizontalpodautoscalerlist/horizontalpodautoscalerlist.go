@@ -17,6 +17,7 @@ package horizontalpodautoscalerlist
 import (
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/horizontalpodautoscaler"
+	"k8s.io/kubernetes/pkg/api"
 	"k8s.io/kubernetes/pkg/apis/autoscaling"
 	k8sClient "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
 )
@@ -42,9 +43,10 @@ type HorizontalPodAutoscaler struct {
 	TargetCPUUtilizationPercentage  *int32 `json:"targetCPUUtilizationPercentage"`
 }
 
-func GetHorizontalPodAutoscalerList(client k8sClient.Interface, nsQuery *common.NamespaceQuery) (*HorizontalPodAutoscalerList, error) {
+func GetHorizontalPodAutoscalerList(client k8sClient.Interface, nsQuery *common.NamespaceQuery,
+	listOptions api.ListOptions) (*HorizontalPodAutoscalerList, error) {
 
-	channel := common.GetHorizontalPodAutoscalerListChannel(client, nsQuery, 1)
+	channel := common.GetHorizontalPodAutoscalerListChannelWithOptions(client, nsQuery, listOptions, 1)
 	hpaList := <-channel.List
 	if err := <-channel.Error; err != nil {
 		return nil, err
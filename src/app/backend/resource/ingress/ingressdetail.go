@@ -15,25 +15,104 @@
 package ingress
 
 import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
 	"log"
+	"strconv"
 
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/service"
+	"k8s.io/kubernetes/pkg/api"
+	k8serrors "k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/api/unversioned"
 	"k8s.io/kubernetes/pkg/apis/extensions"
 	client "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
 )
 
+// Statuses of ingress backends.
+const (
+	// Backend service exists and exposes the backend port.
+	BackendResolved = "Resolved"
+
+	// Backend service does not exist.
+	BackendServiceMissing = "ServiceMissing"
+
+	// Backend service exists, but does not expose the backend port.
+	BackendPortMissing = "PortMissing"
+
+	// Backend service could not be read, e.g., because access to it is forbidden.
+	BackendUnknown = "Unknown"
+)
+
 // IngressDetail API resource provides mechanisms to inject containers with configuration data while keeping
 // containers agnostic of Kubernetes
 type IngressDetail struct {
 	ObjectMeta common.ObjectMeta `json:"objectMeta"`
 	TypeMeta   common.TypeMeta   `json:"typeMeta"`
 
-	// TODO(bryk): replace this with UI specific fields.
-	// Spec is the desired state of the Ingress.
-	Spec extensions.IngressSpec `json:"spec"`
+	// External endpoints of the ingress, assigned by its load balancer.
+	Endpoints []common.Endpoint `json:"endpoints"`
+
+	// Backend that serves requests that do not match any rule. Nil if the ingress has no default
+	// backend.
+	DefaultBackend *IngressBackend `json:"defaultBackend"`
+
+	// Rules of the ingress flattened to one rule per host and path.
+	Rules []IngressRule `json:"rules"`
 
-	// Status is the current state of the Ingress.
-	Status extensions.IngressStatus `json:"status"`
+	// TLS configuration of the ingress.
+	TLS []IngressTLS `json:"tls"`
+}
+
+// IngressRule routes requests for the host and path to the backend.
+type IngressRule struct {
+	// Host of the requests. Empty if the rule matches all hosts.
+	Host string `json:"host"`
+
+	// Path of the requests. Empty if the rule matches all paths.
+	Path string `json:"path"`
+
+	// Backend the requests are routed to.
+	Backend IngressBackend `json:"backend"`
+}
+
+// IngressBackend is a service port requests are routed to, resolved to the service it refers to.
+type IngressBackend struct {
+	// Name of the service.
+	ServiceName string `json:"serviceName"`
+
+	// Port of the service as given in the ingress, either its number or name.
+	ServicePort string `json:"servicePort"`
+
+	// Number of the service port. Zero if the port could not be resolved.
+	Port int32 `json:"port"`
+
+	// Status of the backend. One of: Resolved, ServiceMissing, PortMissing, Unknown.
+	Status string `json:"status"`
+
+	// Health of endpoints of the service. Empty if the service is missing or its endpoints could
+	// not be read.
+	EndpointsHealth string `json:"endpointsHealth"`
+
+	// Reason why the service or its endpoints could not be read.
+	Error string `json:"error,omitempty"`
+}
+
+// IngressTLS is a TLS configuration of a list of hosts.
+type IngressTLS struct {
+	// Hosts included in the certificate.
+	Hosts []string `json:"hosts"`
+
+	// Name of the secret with the certificate.
+	SecretName string `json:"secretName"`
+
+	// Time after which the certificate is no longer valid. Nil if it could not be read.
+	CertificateExpiry *unversioned.Time `json:"certificateExpiry"`
+
+	// Reason why the certificate could not be read, e.g., the secret does not exist or can not be
+	// accessed.
+	Error string `json:"error,omitempty"`
 }
 
 // GetIngressDetail returns returns detailed information about a ingress
@@ -46,14 +125,157 @@ func GetIngressDetail(client client.Interface, namespace, name string) (*Ingress
 		return nil, err
 	}
 
-	return getIngressDetail(rawIngress), nil
+	detail := getIngressDetail(rawIngress)
+	resolveBackends(client, namespace, detail)
+	for i := range detail.TLS {
+		readCertificateExpiry(client, namespace, &detail.TLS[i])
+	}
+	return detail, nil
 }
 
 func getIngressDetail(rawIngress *extensions.Ingress) *IngressDetail {
-	return &IngressDetail{
+	detail := &IngressDetail{
 		ObjectMeta: common.NewObjectMeta(rawIngress.ObjectMeta),
 		TypeMeta:   common.NewTypeMeta(common.ResourceKindIngress),
-		Spec:       rawIngress.Spec,
-		Status:     rawIngress.Status,
+		Endpoints:  getEndpoints(rawIngress),
+		Rules:      make([]IngressRule, 0),
+		TLS:        make([]IngressTLS, 0),
+	}
+
+	if backend := rawIngress.Spec.Backend; backend != nil {
+		detail.DefaultBackend = toIngressBackend(backend)
+	}
+	for _, rule := range rawIngress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			detail.Rules = append(detail.Rules, IngressRule{
+				Host:    rule.Host,
+				Path:    path.Path,
+				Backend: *toIngressBackend(&path.Backend),
+			})
+		}
 	}
+	for _, tls := range rawIngress.Spec.TLS {
+		detail.TLS = append(detail.TLS, IngressTLS{Hosts: tls.Hosts, SecretName: tls.SecretName})
+	}
+
+	return detail
+}
+
+func toIngressBackend(backend *extensions.IngressBackend) *IngressBackend {
+	return &IngressBackend{
+		ServiceName: backend.ServiceName,
+		ServicePort: backend.ServicePort.String(),
+	}
+}
+
+// resolveBackends resolves backends of the ingress detail to ports of their services and fills
+// health of the service endpoints. Every service is read once, even if it backs many rules.
+// Services and endpoints that can not be read are reported in the backends, so that the rest of
+// the detail is still shown.
+func resolveBackends(client client.Interface, namespace string, detail *IngressDetail) {
+	backends := make([]*IngressBackend, 0)
+	if detail.DefaultBackend != nil {
+		backends = append(backends, detail.DefaultBackend)
+	}
+	for i := range detail.Rules {
+		backends = append(backends, &detail.Rules[i].Backend)
+	}
+
+	services := make(map[string]*api.Service)
+	serviceErrors := make(map[string]error)
+	endpointsHealth := make(map[string]string)
+	endpointsErrors := make(map[string]error)
+	for _, backend := range backends {
+		svc, ok := services[backend.ServiceName]
+		if !ok {
+			var err error
+			svc, err = client.Core().Services(namespace).Get(backend.ServiceName)
+			if k8serrors.IsNotFound(err) {
+				svc = nil
+			} else if err != nil {
+				svc = nil
+				serviceErrors[backend.ServiceName] = err
+			} else {
+				subsets, err := service.GetServiceEndpoints(client, svc)
+				if err != nil {
+					endpointsErrors[backend.ServiceName] = err
+				} else {
+					endpointsHealth[backend.ServiceName] = service.GetEndpointsHealth(subsets)
+				}
+			}
+			services[backend.ServiceName] = svc
+		}
+
+		if err := serviceErrors[backend.ServiceName]; err != nil {
+			backend.Status = BackendUnknown
+			backend.Error = fmt.Sprintf("Could not read service %s: %s", backend.ServiceName, err)
+			continue
+		}
+		if svc == nil {
+			backend.Status = BackendServiceMissing
+			continue
+		}
+		if err := endpointsErrors[backend.ServiceName]; err != nil {
+			backend.Error = fmt.Sprintf("Could not read endpoints of service %s: %s",
+				backend.ServiceName, err)
+		}
+		backend.EndpointsHealth = endpointsHealth[backend.ServiceName]
+		backend.Port = findServicePort(svc, backend.ServicePort)
+		if backend.Port == 0 {
+			backend.Status = BackendPortMissing
+		} else {
+			backend.Status = BackendResolved
+		}
+	}
+}
+
+// findServicePort returns number of the service port with the given number or name. Returns zero
+// if the service has no such port. Port names always contain a letter, so they can not be mistaken
+// for numbers.
+func findServicePort(service *api.Service, port string) int32 {
+	number, err := strconv.Atoi(port)
+	for _, servicePort := range service.Spec.Ports {
+		if err == nil && servicePort.Port == int32(number) || err != nil && servicePort.Name == port {
+			return servicePort.Port
+		}
+	}
+	return 0
+}
+
+// readCertificateExpiry fills expiry date of the TLS certificate from its secret. Problems with
+// the secret or the certificate, including errors reading the secret, are reported in the TLS
+// configuration, as they break the ingress and not the request.
+func readCertificateExpiry(client client.Interface, namespace string, tls *IngressTLS) {
+	if tls.SecretName == "" {
+		return
+	}
+
+	secret, err := client.Core().Secrets(namespace).Get(tls.SecretName)
+	if k8serrors.IsNotFound(err) {
+		tls.Error = fmt.Sprintf("Secret %s does not exist", tls.SecretName)
+		return
+	}
+	if err != nil {
+		tls.Error = fmt.Sprintf("Could not read secret %s: %s", tls.SecretName, err)
+		return
+	}
+
+	block, _ := pem.Decode(secret.Data[api.TLSCertKey])
+	if block == nil {
+		tls.Error = fmt.Sprintf("Secret %s has no PEM encoded certificate under %s key",
+			tls.SecretName, api.TLSCertKey)
+		return
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		tls.Error = fmt.Sprintf("Could not parse certificate of secret %s: %s", tls.SecretName,
+			err)
+		return
+	}
+
+	expiry := unversioned.NewTime(certificate.NotAfter)
+	tls.CertificateExpiry = &expiry
 }
@@ -20,8 +20,6 @@ import (
 	"k8s.io/kubernetes/pkg/api"
 	"k8s.io/kubernetes/pkg/apis/extensions"
 	client "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
-	"k8s.io/kubernetes/pkg/fields"
-	"k8s.io/kubernetes/pkg/labels"
 )
 
 // Ingress - a single ingress returned to the frontend.
@@ -42,12 +40,9 @@ type IngressList struct {
 }
 
 // GetIngressList - return all ingresses in the given namespace.
-func GetIngressList(client client.Interface, namespace *common.NamespaceQuery,
+func GetIngressList(client client.Interface, namespace *common.NamespaceQuery, listOptions api.ListOptions,
 	dsQuery *dataselect.DataSelectQuery) (*IngressList, error) {
-	ingressList, err := client.Extensions().Ingresses(namespace.ToRequestParam()).List(api.ListOptions{
-		LabelSelector: labels.Everything(),
-		FieldSelector: fields.Everything(),
-	})
+	ingressList, err := client.Extensions().Ingresses(namespace.ToRequestParam()).List(listOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -90,11 +85,12 @@ func getEndpoints(ingress *extensions.Ingress) []common.Endpoint {
 // NewIngressList - creates a new instance of IngressList struct based on K8s Ingress array.
 func NewIngressList(ingresses []extensions.Ingress, dsQuery *dataselect.DataSelectQuery) *IngressList {
 	newIngressList := &IngressList{
-		ListMeta: common.ListMeta{TotalItems: len(ingresses)},
-		Items:    make([]Ingress, 0),
+		Items: make([]Ingress, 0),
 	}
 
-	ingresses = fromCells(dataselect.GenericDataSelect(toCells(ingresses), dsQuery))
+	cells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(ingresses), dsQuery)
+	ingresses = fromCells(cells)
+	newIngressList.ListMeta = common.ListMeta{TotalItems: filteredTotal}
 
 	for _, ingress := range ingresses {
 		newIngressList.Items = append(newIngressList.Items, *NewIngress(&ingress))
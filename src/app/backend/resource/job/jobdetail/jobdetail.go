@@ -52,7 +52,7 @@ type JobDetail struct {
 }
 
 // GetJobDetail gets job details.
-func GetJobDetail(client k8sClient.Interface, heapsterClient client.HeapsterClient,
+func GetJobDetail(client k8sClient.Interface, metricsProvider client.MetricsProvider,
 	namespace, name string) (*JobDetail, error) {
 
 	// TODO(floreks): Use channels.
@@ -61,7 +61,7 @@ func GetJobDetail(client k8sClient.Interface, heapsterClient client.HeapsterClie
 		return nil, err
 	}
 
-	podList, err := GetJobPods(client, heapsterClient, dataselect.DefaultDataSelectWithMetrics, namespace, name)
+	podList, err := GetJobPods(client, metricsProvider, dataselect.DefaultDataSelectWithMetrics, namespace, name)
 	if err != nil {
 		return nil, err
 	}
@@ -76,11 +76,11 @@ func GetJobDetail(client k8sClient.Interface, heapsterClient client.HeapsterClie
 		return nil, err
 	}
 
-	job := getJobDetail(jobData, heapsterClient, *eventList, *podList, *podInfo)
+	job := getJobDetail(jobData, metricsProvider, *eventList, *podList, *podInfo)
 	return &job, nil
 }
 
-func getJobDetail(job *batch.Job, heapsterClient client.HeapsterClient,
+func getJobDetail(job *batch.Job, metricsProvider client.MetricsProvider,
 	eventList common.EventList, podList pod.PodList, podInfo common.PodInfo) JobDetail {
 	return JobDetail{
 		ObjectMeta:      common.NewObjectMeta(job.ObjectMeta),
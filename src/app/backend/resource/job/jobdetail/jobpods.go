@@ -30,7 +30,7 @@ import (
 )
 
 // GetJobPods return list of pods targeting job.
-func GetJobPods(client k8sClient.Interface, heapsterClient client.HeapsterClient,
+func GetJobPods(client k8sClient.Interface, metricsProvider client.MetricsProvider,
 	dsQuery *dataselect.DataSelectQuery, namespace string, jobName string) (*pod.PodList, error) {
 	log.Printf("Getting replication controller %s pods in namespace %s", jobName, namespace)
 
@@ -39,7 +39,7 @@ func GetJobPods(client k8sClient.Interface, heapsterClient client.HeapsterClient
 		return nil, err
 	}
 
-	podList := pod.CreatePodList(pods, []api.Event{}, dsQuery, heapsterClient)
+	podList := pod.CreatePodList(pods, []api.Event{}, dsQuery, metricsProvider)
 	return &podList, nil
 }
 
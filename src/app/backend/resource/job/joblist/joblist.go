@@ -21,7 +21,7 @@ import (
 	"github.com/kubernetes/dashboard/src/app/backend/resource/event"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/job"
 
-	heapster "github.com/kubernetes/dashboard/src/app/backend/client"
+	kdClient "github.com/kubernetes/dashboard/src/app/backend/client"
 	"k8s.io/kubernetes/pkg/api"
 	k8serrors "k8s.io/kubernetes/pkg/api/errors"
 	"k8s.io/kubernetes/pkg/apis/batch"
@@ -54,22 +54,22 @@ type Job struct {
 }
 
 // GetJobList returns a list of all Jobs in the cluster.
-func GetJobList(client client.Interface, nsQuery *common.NamespaceQuery,
-	dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) (*JobList, error) {
+func GetJobList(client client.Interface, nsQuery *common.NamespaceQuery, listOptions api.ListOptions,
+	dsQuery *dataselect.DataSelectQuery, metricsProvider *kdClient.MetricsProvider) (*JobList, error) {
 	log.Print("Getting list of all jobs in the cluster")
 
 	channels := &common.ResourceChannels{
-		JobList:   common.GetJobListChannel(client, nsQuery, 1),
+		JobList:   common.GetJobListChannelWithOptions(client, nsQuery, listOptions, 1),
 		PodList:   common.GetPodListChannel(client, nsQuery, 1),
 		EventList: common.GetEventListChannel(client, nsQuery, 1),
 	}
 
-	return GetJobListFromChannels(channels, dsQuery, heapsterClient)
+	return GetJobListFromChannels(channels, dsQuery, metricsProvider)
 }
 
 // GetJobList returns a list of all Jobs in the cluster
 // reading required resource list once from the channels.
-func GetJobListFromChannels(channels *common.ResourceChannels, dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) (
+func GetJobListFromChannels(channels *common.ResourceChannels, dsQuery *dataselect.DataSelectQuery, metricsProvider *kdClient.MetricsProvider) (
 	*JobList, error) {
 
 	jobs := <-channels.JobList.List
@@ -96,25 +96,25 @@ func GetJobListFromChannels(channels *common.ResourceChannels, dsQuery *datasele
 		return nil, err
 	}
 
-	return CreateJobList(jobs.Items, pods.Items, events.Items, dsQuery, heapsterClient), nil
+	return CreateJobList(jobs.Items, pods.Items, events.Items, dsQuery, metricsProvider), nil
 }
 
 // CreateJobList returns a list of all Job model objects in the cluster, based on all
 // Kubernetes Job API objects.
 func CreateJobList(jobs []batch.Job, pods []api.Pod, events []api.Event,
-	dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) *JobList {
+	dsQuery *dataselect.DataSelectQuery, metricsProvider *kdClient.MetricsProvider) *JobList {
 
 	jobList := &JobList{
-		Jobs:     make([]Job, 0),
-		ListMeta: common.ListMeta{TotalItems: len(jobs)},
+		Jobs: make([]Job, 0),
 	}
 
 	cachedResources := &dataselect.CachedResources{
 		Pods: pods,
 	}
-	replicationControllerCells, metricPromises := dataselect.GenericDataSelectWithMetrics(
-		job.ToCells(jobs), dsQuery, cachedResources, heapsterClient)
+	replicationControllerCells, metricPromises, filteredTotal := dataselect.GenericDataSelectWithFilterAndMetrics(
+		job.ToCells(jobs), dsQuery, cachedResources, metricsProvider)
 	jobs = job.FromCells(replicationControllerCells)
+	jobList.ListMeta = common.ListMeta{TotalItems: filteredTotal}
 
 	for _, job := range jobs {
 		var completions int32
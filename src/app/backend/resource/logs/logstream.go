@@ -0,0 +1,138 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"strings"
+	"time"
+)
+
+// LogLine is a single log line pushed to clients that follow a log, together with the
+// reference that identifies it.
+type LogLine struct {
+	// Reference of this line. It can be used as a ReferenceLogLineId of a LogViewSelector.
+	Id LogLineId `json:"id"`
+
+	// Content of the line, including its timestamp.
+	Content string `json:"content"`
+}
+
+// LogStreamFilter assigns ids to lines of a followed log as they arrive and drops all lines up to
+// and including the reference line, so that a client can resume exactly after the last line it
+// has seen. Lines are identified by positive line numbers, which stay valid when more lines with
+// the same timestamp are written later.
+type LogStreamFilter struct {
+	// Line after which lines should be passed through. Nil means that all lines are passed.
+	reference *LogLineId
+
+	// Timestamp of the last consumed line and its index among lines with that timestamp.
+	lastTimestamp LogTimestamp
+	lineNum       int
+
+	// Lines with the reference timestamp held back until it is known which of them come after
+	// the reference line. Used only for negative reference line numbers.
+	pending []LogLine
+}
+
+// NewLogStreamFilter creates a filter that passes lines following the given reference line. When
+// reference is nil or points to the newest or oldest line, every line is passed.
+func NewLogStreamFilter(reference *LogLineId) *LogStreamFilter {
+	if reference != nil && (reference.LogTimestamp == "" ||
+		reference.LogTimestamp == NewestTimestamp || reference.LogTimestamp == OldestTimestamp) {
+		reference = nil
+	}
+	return &LogStreamFilter{reference: reference}
+}
+
+// Next consumes a raw log line and returns lines that are ready to be sent, in order.
+func (self *LogStreamFilter) Next(rawLine string) []LogLine {
+	if rawLine == "" {
+		return nil
+	}
+
+	timestamp := lineTimestamp(rawLine)
+	if timestamp == self.lastTimestamp {
+		self.lineNum++
+	} else {
+		self.lastTimestamp = timestamp
+		self.lineNum = 1
+	}
+	line := LogLine{
+		Id:      LogLineId{LogTimestamp: timestamp, LineNum: self.lineNum},
+		Content: rawLine,
+	}
+
+	if self.reference == nil {
+		return []LogLine{line}
+	}
+
+	switch compareLogTimestamps(timestamp, self.reference.LogTimestamp) {
+	case -1:
+		return nil
+	case 0:
+		if self.reference.LineNum >= 0 {
+			if self.lineNum <= self.reference.LineNum {
+				return nil
+			}
+			return []LogLine{line}
+		}
+		// Reference counts from the end of the lines sharing its timestamp, so all of them have to
+		// be seen before it is known which ones are new.
+		self.pending = append(self.pending, line)
+		return nil
+	default:
+		result := self.Flush()
+		self.reference = nil
+		return append(result, line)
+	}
+}
+
+// Flush returns lines that are still held back by the filter.
+func (self *LogStreamFilter) Flush() []LogLine {
+	if len(self.pending) == 0 {
+		return nil
+	}
+	skip := len(self.pending) + self.reference.LineNum + 1
+	if skip < 0 {
+		skip = 0
+	}
+	result := self.pending[skip:]
+	self.pending = nil
+	return result
+}
+
+// lineTimestamp returns timestamp of the given raw log line.
+func lineTimestamp(line string) LogTimestamp {
+	if idx := strings.Index(line, " "); idx >= 0 {
+		return LogTimestamp(line[0:idx])
+	}
+	return LogTimestamp(line)
+}
+
+// compareLogTimestamps compares two log timestamps as times. When any of them can not be parsed,
+// they are compared as strings.
+func compareLogTimestamps(a, b LogTimestamp) int {
+	aTime, err1 := time.Parse(time.RFC3339Nano, string(a))
+	bTime, err2 := time.Parse(time.RFC3339Nano, string(b))
+	if err1 != nil || err2 != nil {
+		return strings.Compare(string(a), string(b))
+	}
+	if aTime.Before(bTime) {
+		return -1
+	} else if aTime.After(bTime) {
+		return 1
+	}
+	return 0
+}
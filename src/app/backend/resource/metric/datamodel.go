@@ -0,0 +1,46 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"github.com/kubernetes/dashboard/src/app/backend/client"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+)
+
+// NativeResources is a set of resource types that metrics providers can download metrics for.
+// Metrics of all other resource types have to be derived from them, see DerivedResources.
+var NativeResources = map[common.ResourceKind]string{
+	common.ResourceKindPod:       client.PodMetricResource,
+	common.ResourceKindNode:      client.NodeMetricResource,
+	common.ResourceKindNamespace: client.NamespaceMetricResource,
+}
+
+// DataPointsFromMetricSamples converts all the samples returned by metrics provider to our format.
+func DataPointsFromMetricSamples(samples client.MetricSamples) DataPoints {
+	dp := DataPoints{}
+	for _, sample := range samples {
+		converted := DataPoint{
+			X: sample.Timestamp.Unix(),
+			Y: int64(sample.Value),
+		}
+
+		if converted.Y < 0 {
+			converted.Y = 0
+		}
+
+		dp = append(dp, converted)
+	}
+	return dp
+}
@@ -16,12 +16,9 @@ package metric
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/kubernetes/dashboard/src/app/backend/client"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
-
-	heapster "k8s.io/heapster/metrics/api/v1/types"
 )
 
 type MetricPromises []MetricPromise
@@ -119,18 +116,18 @@ func (self Label) AddMetricLabel(other Label) Label {
 	return self
 }
 
-// DownloadMetric downloads requested metric for each HeapsterSelector present in HeapsterSelectors and returns
-// the result as MetricPromises - one promise for each HeapsterSelector. If HeapsterSelector consists of many native resources
+// DownloadMetric downloads requested metric for each MetricSelector present in MetricSelectors and returns
+// the result as MetricPromises - one promise for each MetricSelector. If MetricSelector consists of many native resources
 // (eg. for example deployments can consist of hundreds of pods) then the sum for all its native resources is calculated.
-// HeapsterSelectors are compressed before download process so that the smallest number of heapster requests is used.
-func (self HeapsterSelectors) DownloadMetric(client client.HeapsterClient, metricName string) MetricPromises {
-	// Downloads metric in the fastest possible way by first compressing HeapsterSelectors and later unpacking the result to separate boxes.
+// MetricSelectors are compressed before download process so that the smallest number of metrics provider requests is used.
+func (self MetricSelectors) DownloadMetric(provider client.MetricsProvider, metricName string) MetricPromises {
+	// Downloads metric in the fastest possible way by first compressing MetricSelectors and later unpacking the result to separate boxes.
 	compressedSelectors, reverseMapping := self.compress()
 
 	// collect all the required data (as promises)
 	unassignedResourcePromisesList := make([]MetricPromises, len(compressedSelectors))
 	for selectorId, compressedSelector := range compressedSelectors {
-		unassignedResourcePromisesList[selectorId] = compressedSelector.downloadMetricForEachTargetResource(client, metricName)
+		unassignedResourcePromisesList[selectorId] = compressedSelector.downloadMetricForEachTargetResource(provider, metricName)
 
 	}
 	// prepare final result
@@ -143,7 +140,7 @@ func (self HeapsterSelectors) DownloadMetric(client client.HeapsterClient, metri
 			// now unpack the resources and push errors in case of error.
 			unassignedResources, err := unassignedResourcePromises.GetMetrics()
 			if err != nil {
-				for _, originalMappingIndex := range reverseMapping[selector.Path] {
+				for _, originalMappingIndex := range reverseMapping[selector.compressionKey()] {
 					result[originalMappingIndex].Error <- err
 					result[originalMappingIndex].Metric <- nil
 				}
@@ -155,7 +152,7 @@ func (self HeapsterSelectors) DownloadMetric(client client.HeapsterClient, metri
 			}
 
 			// now, if everything went ok, unpack the metrics into original selectors
-			for _, originalMappingIndex := range reverseMapping[selector.Path] {
+			for _, originalMappingIndex := range reverseMapping[selector.compressionKey()] {
 				// find out what resources this selector needs
 				requestedResources := []Metric{}
 				for _, requestedResourceName := range self[originalMappingIndex].Resources {
@@ -173,16 +170,16 @@ func (self HeapsterSelectors) DownloadMetric(client client.HeapsterClient, metri
 	return result
 }
 
-// DownloadAndAggregate downloads and aggregates requested metrics for all resources present in HeapsterSelectors.
-// Each item in HeapsterSelectors is treated as a separate resource and aggregation reflects that - ie. first we calculate,
-// metrics for every heapster selector separately and afterwards we aggregate the data.
-// So for example, we have 2 HeapsterSelectors each one consisting of many pods. If aggregation MIN is specified, then
-// first for each HeapsterSelector the sum of metrics of all its pods is calculated and afterwards the min is taken.
-// This function downloads the data using smallest possible number of requests to Heapster and returns the result as MetricPromises.
-func (self HeapsterSelectors) DownloadAndAggregate(client client.HeapsterClient, metricNames []string, aggregations AggregationNames) MetricPromises {
+// DownloadAndAggregate downloads and aggregates requested metrics for all resources present in MetricSelectors.
+// Each item in MetricSelectors is treated as a separate resource and aggregation reflects that - ie. first we calculate,
+// metrics for every metric selector separately and afterwards we aggregate the data.
+// So for example, we have 2 MetricSelectors each one consisting of many pods. If aggregation MIN is specified, then
+// first for each MetricSelector the sum of metrics of all its pods is calculated and afterwards the min is taken.
+// This function downloads the data using smallest possible number of requests to metrics provider and returns the result as MetricPromises.
+func (self MetricSelectors) DownloadAndAggregate(provider client.MetricsProvider, metricNames []string, aggregations AggregationNames) MetricPromises {
 	result := MetricPromises{}
 	for _, metricName := range metricNames {
-		collectedMetrics := self.DownloadMetric(client, metricName)
+		collectedMetrics := self.DownloadMetric(provider, metricName)
 		result = append(result, aggregateMetricPromises(collectedMetrics, metricName, aggregations, nil)...)
 	}
 	return result
@@ -203,33 +200,36 @@ func removeDuplicates(list []string) []string {
 
 }
 
-// compress compresses list of HeapsterSelectors to equivalent, shorter one in order to perform smaller number of requests.
-// For example if we have 2 HeapsterSelectors, first downloading data for pods A, B and second one downloading data for pods B,C.
-// compress will compress this to just one HeapsterSelector downloading data for A,B,C. Reverse mapping returned provides
+// compress compresses list of MetricSelectors to equivalent, shorter one in order to perform smaller number of requests.
+// For example if we have 2 MetricSelectors, first downloading data for pods A, B and second one downloading data for pods B,C.
+// compress will compress this to just one MetricSelector downloading data for A,B,C. Reverse mapping returned provides
 // a mapping between indices from new compressed list to the list of children indices from original list.
-func (self HeapsterSelectors) compress() (HeapsterSelectors, map[string][]int) {
+func (self MetricSelectors) compress() (MetricSelectors, map[string][]int) {
 	reverseMapping := map[string][]int{}
 	resourceTypeMap := map[string]common.ResourceKind{}
+	namespaceMap := map[string]string{}
 	resourceMap := map[string][]string{}
 	labelMap := map[string]Label{}
 	for i, selector := range self {
-		entry := selector.Path
-		resources, doesEntryExist := resourceMap[selector.Path]
+		entry := selector.compressionKey()
+		resources, doesEntryExist := resourceMap[entry]
 		// compress resources
 		resourceMap[entry] = append(resources, selector.Resources...)
 		// compress labels
 		if !doesEntryExist {
-			resourceTypeMap[entry] = selector.TargetResourceType // this will be the same for all entries
+			// these will be the same for all entries
+			resourceTypeMap[entry] = selector.TargetResourceType
+			namespaceMap[entry] = selector.Namespace
 			labelMap[entry] = Label{}
 		}
 		labelMap[entry].AddMetricLabel(selector.Label)
 		reverseMapping[entry] = append(reverseMapping[entry], i)
 	}
-	// create new compressed HeapsterSelectors.
-	compressed := HeapsterSelectors{}
+	// create new compressed MetricSelectors.
+	compressed := MetricSelectors{}
 	for entry, resourceType := range resourceTypeMap {
-		newSelector := HeapsterSelector{
-			Path:               entry,
+		newSelector := MetricSelector{
+			Namespace:          namespaceMap[entry],
 			Resources:          removeDuplicates(resourceMap[entry]), // remove duplicate resources so that they are not downloaded twice.
 			Label:              labelMap[entry],
 			TargetResourceType: resourceType,
@@ -240,27 +240,22 @@ func (self HeapsterSelectors) compress() (HeapsterSelectors, map[string][]int) {
 
 }
 
-// NewHeapsterSelectorFromNativeResource returns new heapster selector for native resources specified in arguments.
+// NewMetricSelectorFromNativeResource returns new metric selector for native resources specified in arguments.
 // returns error if requested resource is not native or is not supported.
-func NewHeapsterSelectorFromNativeResource(resourceType common.ResourceKind, namespace string, resourceNames []string) (HeapsterSelector, error) {
-	// Here we have 2 possibilities because this module allows downloading Nodes and Pods from heapster
-	if resourceType == common.ResourceKindPod {
-		return HeapsterSelector{
-			TargetResourceType: common.ResourceKindPod,
-			Path:               `namespaces/` + namespace + `/pod-list/`,
-			Resources:          resourceNames,
-			Label:              Label{resourceType: resourceNames},
-		}, nil
-	} else if resourceType == common.ResourceKindNode {
-		return HeapsterSelector{
-			TargetResourceType: common.ResourceKindNode,
-			Path:               `nodes/`,
-			Resources:          resourceNames,
-			Label:              Label{resourceType: resourceNames},
-		}, nil
-	} else {
-		return HeapsterSelector{}, fmt.Errorf(`Resource "%s" is not a native heapster resource type or is not supported`, resourceType)
+func NewMetricSelectorFromNativeResource(resourceType common.ResourceKind, namespace string, resourceNames []string) (MetricSelector, error) {
+	if _, isNative := NativeResources[resourceType]; !isNative {
+		return MetricSelector{}, fmt.Errorf(`Resource "%s" is not a native metric resource type or is not supported`, resourceType)
+	}
+	if resourceType != common.ResourceKindPod {
+		// Only pods are namespaced from the metrics provider point of view.
+		namespace = ""
 	}
+	return MetricSelector{
+		TargetResourceType: resourceType,
+		Namespace:          namespace,
+		Resources:          resourceNames,
+		Label:              Label{resourceType: resourceNames},
+	}, nil
 }
 
 // aggregateMetricPromises takes a list of metric promises, aggregates the data as instructed and returns a new list of promises.
@@ -288,88 +283,55 @@ func aggregateMetricPromises(metricPromises MetricPromises, metricName string, a
 	return result
 }
 
-type HeapsterSelectors []HeapsterSelector
+type MetricSelectors []MetricSelector
 
-type HeapsterSelector struct {
+// MetricSelector selects native resources of the same type (and namespace in case of pods) that metrics should be
+// downloaded for.
+type MetricSelector struct {
 	TargetResourceType common.ResourceKind
-	Path               string
+	Namespace          string
 	Resources          []string
 	Label
 }
 
-// DownloadMetric downloads one metric for this drill from heapster and returns it as a DataPromise
-// Note, if you want to download data for multiple selectors make sure to pack them into HeapsterSelectors object.
-// HeapsterSelectors uses smart download process in order to perform smallest number of heapster requests.
-func (self HeapsterSelector) DownloadMetric(client client.HeapsterClient, metricName string) MetricPromise {
-	return aggregateMetricPromises(self.downloadMetricForEachTargetResource(client, metricName), metricName, OnlySumAggregation, self.Label)[0]
+// compressionKey returns a key that is the same for all selectors that can be downloaded with one query.
+func (self MetricSelector) compressionKey() string {
+	return string(self.TargetResourceType) + "/" + self.Namespace
 }
 
-// downloadMetricForEachTargetResource downloads requested metric for each resource present in HeapsterSelector
-// and returns the result as a list of promises - one promise for each resource. Order of promises returned is the same as order in self.Resources.
-func (self HeapsterSelector) downloadMetricForEachTargetResource(client client.HeapsterClient, metricName string) MetricPromises {
-	var notAggregatedMetrics MetricPromises
-	if HeapsterAllInOneDownloadConfig[self.TargetResourceType] {
-		notAggregatedMetrics = self.allInOneDownload(client, metricName)
-	} else {
-		notAggregatedMetrics = MetricPromises{}
-		for i := range self.Resources {
-			notAggregatedMetrics = append(notAggregatedMetrics, self.ithResourceDownload(client, metricName, i))
-		}
-	}
-	return notAggregatedMetrics
+// DownloadMetric downloads one metric for this drill from metrics provider and returns it as a DataPromise
+// Note, if you want to download data for multiple selectors make sure to pack them into MetricSelectors object.
+// MetricSelectors uses smart download process in order to perform smallest number of metrics provider requests.
+func (self MetricSelector) DownloadMetric(provider client.MetricsProvider, metricName string) MetricPromise {
+	return aggregateMetricPromises(self.downloadMetricForEachTargetResource(provider, metricName), metricName, OnlySumAggregation, self.Label)[0]
 }
 
-// ithResourceDownload downloads metric for ith resource in self.Resources. Use only in case all in 1 download is not supported
-// for this resource type.
-func (self HeapsterSelector) ithResourceDownload(client client.HeapsterClient, metricName string, i int) MetricPromise {
-	result := NewMetricPromise()
-	go func() {
-		rawResult := heapster.MetricResult{}
-		err := HeapsterUnmarshalType(client, self.Path+self.Resources[i]+"/metrics/"+metricName, &rawResult)
-		if err != nil {
-			result.Metric <- nil
-			result.Error <- err
-			return
-		}
-		dataPoints := DataPointsFromMetricJSONFormat(rawResult)
-
-		result.Metric <- &Metric{
-			DataPoints: dataPoints,
-			MetricName: metricName,
-			Label: Label{
-				self.TargetResourceType: []string{self.Resources[i]},
-			},
-		}
-		result.Error <- nil
-		return
-	}()
-	return result
-}
-
-// allInOneDownload downloads metrics for all resources present in self.Resources in one request.
-// returns a list of metric promises - one promise for each resource. Order of self.Resources is preserved.
-func (self HeapsterSelector) allInOneDownload(client client.HeapsterClient, metricName string) MetricPromises {
+// downloadMetricForEachTargetResource downloads requested metric for each resource present in MetricSelector
+// and returns the result as a list of promises - one promise for each resource. Order of promises returned is the same as order in self.Resources.
+func (self MetricSelector) downloadMetricForEachTargetResource(provider client.MetricsProvider, metricName string) MetricPromises {
 	result := NewMetricPromises(len(self.Resources))
 	go func() {
 		if len(self.Resources) == 0 {
 			return
 		}
-		rawResults := heapster.MetricResultList{}
-		err := HeapsterUnmarshalType(client, self.Path+strings.Join(self.Resources, ",")+"/metrics/"+metricName, &rawResults)
+		samples, err := provider.DownloadMetric(client.MetricQuery{
+			MetricName:    metricName,
+			ResourceKind:  NativeResources[self.TargetResourceType],
+			Namespace:     self.Namespace,
+			ResourceNames: self.Resources,
+		})
 		if err != nil {
 			result.PutMetrics(nil, err)
 			return
 		}
-		if len(result) != len(rawResults.Items) {
-			result.PutMetrics(nil, fmt.Errorf(`Received invalid number of resources from heapster. Expected %d received %d`, len(result), len(rawResults.Items)))
+		if len(result) != len(samples) {
+			result.PutMetrics(nil, fmt.Errorf(`Received invalid number of resources from metrics provider. Expected %d received %d`, len(result), len(samples)))
 			return
 		}
 
-		for i, rawResult := range rawResults.Items {
-			dataPoints := DataPointsFromMetricJSONFormat(rawResult)
-
+		for i, resourceSamples := range samples {
 			result[i].Metric <- &Metric{
-				DataPoints: dataPoints,
+				DataPoints: DataPointsFromMetricSamples(resourceSamples),
 				MetricName: metricName,
 				Label: Label{
 					self.TargetResourceType: []string{self.Resources[i]},
@@ -377,8 +339,6 @@ func (self HeapsterSelector) allInOneDownload(client client.HeapsterClient, metr
 			}
 			result[i].Error <- nil
 		}
-		return
-
 	}()
 	return result
 }
@@ -7,9 +7,9 @@ import (
 	"k8s.io/kubernetes/pkg/api/unversioned"
 )
 
-// DerivedResources is a map from a derived resource(a resource that is not supported by heapster)
-// to native resource (supported by heapster) to which derived resource should be converted.
-// For example, deployment is not available in heapster so it has to be converted to its pods before downloading any data.
+// DerivedResources is a map from a derived resource(a resource that is not supported by metrics providers)
+// to native resource (supported by metrics providers) to which derived resource should be converted.
+// For example, deployment is not available in metrics providers so it has to be converted to its pods before downloading any data.
 // Hence deployments map to pods.
 var DerivedResources = map[common.ResourceKind]common.ResourceKind{
 	common.ResourceKindDeployment:            common.ResourceKindPod,
@@ -21,7 +21,7 @@ var DerivedResources = map[common.ResourceKind]common.ResourceKind{
 }
 
 // ResourceSelector is a structure used to quickly and uniquely identify given resource.
-// This struct can be later used for heapster data download etc.
+// This struct can be later used for metric data download etc.
 type ResourceSelector struct {
 	// Namespace of this resource.
 	Namespace string
@@ -35,23 +35,23 @@ type ResourceSelector struct {
 	LabelSelector *unversioned.LabelSelector
 }
 
-// GetHeapsterSelector calculates and returns HeapsterSelector that can be used to download metrics for this resource.
-func (self *ResourceSelector) GetHeapsterSelector(cachedPods []api.Pod) (HeapsterSelector, error) {
+// GetMetricSelector calculates and returns MetricSelector that can be used to download metrics for this resource.
+func (self *ResourceSelector) GetMetricSelector(cachedPods []api.Pod) (MetricSelector, error) {
 	summingResource, isDerivedResource := DerivedResources[self.ResourceType]
 	if !isDerivedResource {
-		return NewHeapsterSelectorFromNativeResource(self.ResourceType, self.Namespace, []string{self.ResourceName})
+		return NewMetricSelectorFromNativeResource(self.ResourceType, self.Namespace, []string{self.ResourceName})
 	}
 	// We are dealing with derived resource. Convert derived resource to its native resources.
 	// For example, convert deployment to the list of pod names that belong to this deployment
 	if summingResource == common.ResourceKindPod {
 		myPods, err := self.getMyPodsFromCache(cachedPods)
 		if err != nil {
-			return HeapsterSelector{}, err
+			return MetricSelector{}, err
 		}
-		return NewHeapsterSelectorFromNativeResource(common.ResourceKindPod, self.Namespace, podListToNameList(myPods))
+		return NewMetricSelectorFromNativeResource(common.ResourceKindPod, self.Namespace, podListToNameList(myPods))
 	} else {
 		// currently can only convert derived resource to pods. You can change it by implementing other methods
-		return HeapsterSelector{}, fmt.Errorf(`Internal Error: Requested summing resource is not supported. Requested "%s"`, summingResource)
+		return MetricSelector{}, fmt.Errorf(`Internal Error: Requested summing resource is not supported. Requested "%s"`, summingResource)
 	}
 }
 
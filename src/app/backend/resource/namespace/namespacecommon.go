@@ -20,6 +20,7 @@ import (
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"k8s.io/kubernetes/pkg/api"
 	client "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
+	"k8s.io/kubernetes/pkg/runtime"
 )
 
 // NamespaceSpec is a specification of namespace to create.
@@ -54,6 +55,8 @@ func (self NamespaceCell) GetProperty(name dataselect.PropertyName) dataselect.C
 		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
 	case dataselect.NamespaceProperty:
 		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.LabelsProperty:
+		return dataselect.StdComparableLabels(self.ObjectMeta.Labels)
 	default:
 		// if name is not supported then just return a constant dummy value, sort will have no effect.
 		return nil
@@ -75,3 +78,8 @@ func fromCells(cells []dataselect.DataCell) []api.Namespace {
 	}
 	return std
 }
+
+func (self NamespaceCell) GetObject() runtime.Object {
+	namespace := api.Namespace(self)
+	return &namespace
+}
@@ -46,10 +46,13 @@ type NamespaceDetail struct {
 
 	// ResourceLimits is list of limit ranges associated to the namespace
 	ResourceLimits []limitrange.LimitRangeItem `json:"resourceLimits"`
+
+	// Usage relates resources requested by pods of the namespace to its quotas and limit ranges.
+	Usage *NamespaceUsage `json:"usage"`
 }
 
 // GetNamespaceDetail gets namespace details.
-func GetNamespaceDetail(client k8sClient.Interface, heapsterClient client.HeapsterClient, name string) (
+func GetNamespaceDetail(client k8sClient.Interface, metricsProvider client.MetricsProvider, name string) (
 	*NamespaceDetail, error) {
 	log.Printf("Getting details of %s namespace", name)
 
@@ -73,7 +76,13 @@ func GetNamespaceDetail(client k8sClient.Interface, heapsterClient client.Heapst
 		return nil, err
 	}
 
+	usage, err := GetNamespaceUsage(client, metricsProvider, namespace.Name)
+	if err != nil {
+		return nil, err
+	}
+
 	namespaceDetails := toNamespaceDetail(*namespace, events, resourceQuotaList, resourceLimits)
+	namespaceDetails.Usage = usage
 
 	return &namespaceDetails, nil
 }
@@ -21,8 +21,6 @@ import (
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"k8s.io/kubernetes/pkg/api"
 	client "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
-	"k8s.io/kubernetes/pkg/fields"
-	"k8s.io/kubernetes/pkg/labels"
 )
 
 // NamespaceList contains a list of namespaces in the cluster.
@@ -57,14 +55,11 @@ func GetNamespaceListFromChannels(channels *common.ResourceChannels, dsQuery *da
 }
 
 // GetNamespaceList returns a list of all namespaces in the cluster.
-func GetNamespaceList(client *client.Clientset, dsQuery *dataselect.DataSelectQuery) (*NamespaceList,
-	error) {
+func GetNamespaceList(client *client.Clientset, listOptions api.ListOptions,
+	dsQuery *dataselect.DataSelectQuery) (*NamespaceList, error) {
 	log.Printf("Getting namespace list")
 
-	namespaces, err := client.Namespaces().List(api.ListOptions{
-		LabelSelector: labels.Everything(),
-		FieldSelector: fields.Everything(),
-	})
+	namespaces, err := client.Namespaces().List(listOptions)
 
 	if err != nil {
 		return nil, err
@@ -76,10 +71,11 @@ func GetNamespaceList(client *client.Clientset, dsQuery *dataselect.DataSelectQu
 func toNamespaceList(namespaces []api.Namespace, dsQuery *dataselect.DataSelectQuery) *NamespaceList {
 	namespaceList := &NamespaceList{
 		Namespaces: make([]Namespace, 0),
-		ListMeta:   common.ListMeta{TotalItems: len(namespaces)},
 	}
 
-	namespaces = fromCells(dataselect.GenericDataSelect(toCells(namespaces), dsQuery))
+	cells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(namespaces), dsQuery)
+	namespaces = fromCells(cells)
+	namespaceList.ListMeta = common.ListMeta{TotalItems: filteredTotal}
 
 	for _, namespace := range namespaces {
 		namespaceList.Namespaces = append(namespaceList.Namespaces, toNamespace(namespace))
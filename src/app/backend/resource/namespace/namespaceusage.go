@@ -0,0 +1,401 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"log"
+	"sort"
+
+	"github.com/kubernetes/dashboard/src/app/backend/client"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/resource"
+	k8sClient "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
+)
+
+// NamespaceUsage relates resources requested by pods of a namespace to its resource quotas and
+// limit ranges.
+type NamespaceUsage struct {
+	// Sums of resource requests of all running pods.
+	Requests map[api.ResourceName]string `json:"requests"`
+
+	// Sums of resource limits of all running pods.
+	Limits map[api.ResourceName]string `json:"limits"`
+
+	// Current CPU usage of all pods in millicores. Nil if metrics are not available.
+	CPUUsage *uint64 `json:"cpuUsage"`
+
+	// Current memory usage of all pods in bytes. Nil if metrics are not available.
+	MemoryUsage *uint64 `json:"memoryUsage"`
+
+	// Usage of every resource quota of the namespace.
+	Quotas []QuotaUsage `json:"quotas"`
+
+	// Pods that lack requests or limits demanded by a quota or a limit range. Such pods would
+	// be rejected if they were created again, e.g., by a deploy.
+	PodsWithoutRequests []PodWithoutRequests `json:"podsWithoutRequests"`
+}
+
+// QuotaUsage is a usage of a resource quota by the pods it applies to.
+type QuotaUsage struct {
+	// Name of the resource quota.
+	Name string `json:"name"`
+
+	// Scopes of the quota, which select the pods it applies to.
+	Scopes []api.ResourceQuotaScope `json:"scopes,omitempty"`
+
+	// Usage of every resource the quota limits, sorted by resource name.
+	Resources []QuotaResourceUsage `json:"resources"`
+}
+
+// QuotaResourceUsage is a usage of a single resource limited by a quota.
+type QuotaResourceUsage struct {
+	// Name of the resource, e.g., requests.cpu or pods.
+	Name api.ResourceName `json:"name"`
+
+	// Hard limit of the resource.
+	Hard string `json:"hard"`
+
+	// Used amount of the resource. Compute resources and pods are summed from the pods of the
+	// namespace, other resources are taken from the quota status.
+	Used string `json:"used"`
+
+	// Used amount as a percentage of the hard limit. Can be over 100 if the quota has been
+	// lowered after the resources were created.
+	Percentage float64 `json:"percentage"`
+}
+
+// PodWithoutRequests is a pod that lacks requests or limits demanded by a quota or a limit range.
+type PodWithoutRequests struct {
+	// Name of the pod.
+	Name string `json:"name"`
+
+	// Missing resources, e.g., requests.cpu or limits.memory.
+	MissingResources []api.ResourceName `json:"missingResources"`
+}
+
+// GetNamespaceUsage sums requests and limits of running pods of the namespace and compares them
+// to its resource quotas. Current usage is included if the metrics provider can serve it.
+func GetNamespaceUsage(client k8sClient.Interface, metricsProvider client.MetricsProvider,
+	name string) (*NamespaceUsage, error) {
+
+	log.Printf("Getting resource usage of %s namespace", name)
+
+	podList, err := client.Core().Pods(name).List(listEverything)
+	if err != nil {
+		return nil, err
+	}
+	quotaList, err := client.Core().ResourceQuotas(name).List(listEverything)
+	if err != nil {
+		return nil, err
+	}
+	limitRangeList, err := client.Core().LimitRanges(name).List(listEverything)
+	if err != nil {
+		return nil, err
+	}
+
+	pods := make([]api.Pod, 0)
+	for _, pod := range podList.Items {
+		if pod.Status.Phase != api.PodSucceeded && pod.Status.Phase != api.PodFailed {
+			pods = append(pods, pod)
+		}
+	}
+
+	usage, err := toNamespaceUsage(pods, quotaList.Items, limitRangeList.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	metricsChannel := common.GetPodListMetricsChannel(metricsProvider, pods, 1)
+	if err := <-metricsChannel.Error; err != nil {
+		log.Printf("Skipping namespace usage metrics because of error: %s", err)
+	} else {
+		usage.CPUUsage, usage.MemoryUsage = sumPodMetrics(<-metricsChannel.MetricsByPod)
+	}
+
+	return usage, nil
+}
+
+func toNamespaceUsage(pods []api.Pod, quotas []api.ResourceQuota,
+	limitRanges []api.LimitRange) (*NamespaceUsage, error) {
+
+	requests, limits, err := sumRequestsAndLimits(pods)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &NamespaceUsage{
+		Requests:            toStrings(requests),
+		Limits:              toStrings(limits),
+		Quotas:              make([]QuotaUsage, 0),
+		PodsWithoutRequests: make([]PodWithoutRequests, 0),
+	}
+
+	for _, quota := range quotas {
+		quotaUsage, err := toQuotaUsage(quota, pods)
+		if err != nil {
+			return nil, err
+		}
+		usage.Quotas = append(usage.Quotas, *quotaUsage)
+	}
+
+	demanded := GetDemandedResources(quotas, limitRanges)
+	for _, pod := range pods {
+		missing := GetMissingResources(pod, demanded)
+		if len(missing) > 0 {
+			usage.PodsWithoutRequests = append(usage.PodsWithoutRequests,
+				PodWithoutRequests{Name: pod.Name, MissingResources: missing})
+		}
+	}
+
+	return usage, nil
+}
+
+func toQuotaUsage(quota api.ResourceQuota, pods []api.Pod) (*QuotaUsage, error) {
+	scopedPods := make([]api.Pod, 0)
+	for _, pod := range pods {
+		if MatchesQuotaScopes(pod, quota.Spec.Scopes) {
+			scopedPods = append(scopedPods, pod)
+		}
+	}
+	requests, limits, err := sumRequestsAndLimits(scopedPods)
+	if err != nil {
+		return nil, err
+	}
+
+	quotaUsage := &QuotaUsage{
+		Name:      quota.Name,
+		Scopes:    quota.Spec.Scopes,
+		Resources: make([]QuotaResourceUsage, 0),
+	}
+	for name, hard := range quota.Spec.Hard {
+		var used resource.Quantity
+		switch name {
+		case api.ResourceCPU, api.ResourceRequestsCPU:
+			used = requests[api.ResourceCPU]
+		case api.ResourceMemory, api.ResourceRequestsMemory:
+			used = requests[api.ResourceMemory]
+		case api.ResourceLimitsCPU:
+			used = limits[api.ResourceCPU]
+		case api.ResourceLimitsMemory:
+			used = limits[api.ResourceMemory]
+		case api.ResourcePods:
+			used = *resource.NewQuantity(int64(len(scopedPods)), resource.DecimalSI)
+		default:
+			used = quota.Status.Used[name]
+		}
+
+		var percentage float64
+		if hard.MilliValue() > 0 {
+			percentage = float64(used.MilliValue()) * 100 / float64(hard.MilliValue())
+		}
+		quotaUsage.Resources = append(quotaUsage.Resources, QuotaResourceUsage{
+			Name:       name,
+			Hard:       hard.String(),
+			Used:       used.String(),
+			Percentage: percentage,
+		})
+	}
+	sort.Sort(quotaResourceUsages(quotaUsage.Resources))
+
+	return quotaUsage, nil
+}
+
+// MatchesQuotaScopes returns true if the quota with the given scopes applies to the pod.
+func MatchesQuotaScopes(pod api.Pod, scopes []api.ResourceQuotaScope) bool {
+	terminating := pod.Spec.ActiveDeadlineSeconds != nil
+	bestEffort := isBestEffort(pod)
+	for _, scope := range scopes {
+		if scope == api.ResourceQuotaScopeTerminating && !terminating ||
+			scope == api.ResourceQuotaScopeNotTerminating && terminating ||
+			scope == api.ResourceQuotaScopeBestEffort && !bestEffort ||
+			scope == api.ResourceQuotaScopeNotBestEffort && bestEffort {
+			return false
+		}
+	}
+	return true
+}
+
+// isBestEffort returns true if no container of the pod has requests or limits.
+func isBestEffort(pod api.Pod) bool {
+	containers := make([]api.Container, 0)
+	containers = append(containers, pod.Spec.InitContainers...)
+	for _, container := range append(containers, pod.Spec.Containers...) {
+		if len(container.Resources.Requests) > 0 || len(container.Resources.Limits) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// GetDemandedResources returns resources every container has to request or limit, as quotas and
+// limit ranges of the namespace would reject containers without them. The names are of the form
+// requests.cpu or limits.memory. Resources defaulted by a
+// limit range are not demanded, as the defaults are set when the container is created.
+func GetDemandedResources(quotas []api.ResourceQuota,
+	limitRanges []api.LimitRange) map[api.ResourceName]bool {
+
+	defaulted := make(map[api.ResourceName]bool)
+	demanded := make(map[api.ResourceName]bool)
+	for _, limitRange := range limitRanges {
+		for _, item := range limitRange.Spec.Limits {
+			if item.Type != api.LimitTypeContainer {
+				continue
+			}
+			for _, name := range []api.ResourceName{api.ResourceCPU, api.ResourceMemory} {
+				_, hasDefaultRequest := item.DefaultRequest[name]
+				_, hasDefault := item.Default[name]
+				if hasDefaultRequest || hasDefault {
+					defaulted[requestsOf(name)] = true
+				}
+				if hasDefault {
+					defaulted[limitsOf(name)] = true
+				}
+				if _, ok := item.Min[name]; ok {
+					demanded[requestsOf(name)] = true
+				}
+				if _, ok := item.Max[name]; ok {
+					demanded[limitsOf(name)] = true
+				}
+			}
+		}
+	}
+
+	for _, quota := range quotas {
+		for name := range quota.Spec.Hard {
+			switch name {
+			case api.ResourceCPU, api.ResourceRequestsCPU:
+				demanded[api.ResourceRequestsCPU] = true
+			case api.ResourceMemory, api.ResourceRequestsMemory:
+				demanded[api.ResourceRequestsMemory] = true
+			case api.ResourceLimitsCPU, api.ResourceLimitsMemory:
+				demanded[name] = true
+			}
+		}
+	}
+
+	for name := range defaulted {
+		delete(demanded, name)
+	}
+	return demanded
+}
+
+// GetMissingResources returns demanded resources that some container of the pod does not request
+// or limit, sorted by name.
+func GetMissingResources(pod api.Pod, demanded map[api.ResourceName]bool) []api.ResourceName {
+	missing := make([]api.ResourceName, 0)
+	for _, name := range []api.ResourceName{api.ResourceCPU, api.ResourceMemory} {
+		for _, container := range pod.Spec.Containers {
+			if _, ok := container.Resources.Requests[name]; !ok && demanded[requestsOf(name)] {
+				missing = appendResourceName(missing, requestsOf(name))
+			}
+			if _, ok := container.Resources.Limits[name]; !ok && demanded[limitsOf(name)] {
+				missing = appendResourceName(missing, limitsOf(name))
+			}
+		}
+	}
+	sort.Sort(resourceNames(missing))
+	return missing
+}
+
+func sumRequestsAndLimits(pods []api.Pod) (api.ResourceList, api.ResourceList, error) {
+	requests, limits := api.ResourceList{}, api.ResourceList{}
+	for _, pod := range pods {
+		podRequests, podLimits, err := api.PodRequestsAndLimits(&pod)
+		if err != nil {
+			return nil, nil, err
+		}
+		addResources(requests, podRequests)
+		addResources(limits, podLimits)
+	}
+	return requests, limits, nil
+}
+
+func addResources(total, resources api.ResourceList) {
+	for name, value := range resources {
+		if sum, ok := total[name]; ok {
+			sum.Add(value)
+			total[name] = sum
+		} else {
+			total[name] = *value.Copy()
+		}
+	}
+}
+
+// sumPodMetrics returns sums of current CPU and memory usage of the pods. A sum is nil if no pod
+// has the metric.
+func sumPodMetrics(metrics *common.MetricsByPod) (*uint64, *uint64) {
+	var cpuUsage, memoryUsage *uint64
+	if metrics == nil {
+		return nil, nil
+	}
+	for _, metricsByName := range metrics.MetricsMap {
+		for _, podMetrics := range metricsByName {
+			cpuUsage = addMetric(cpuUsage, podMetrics.CPUUsage)
+			memoryUsage = addMetric(memoryUsage, podMetrics.MemoryUsage)
+		}
+	}
+	return cpuUsage, memoryUsage
+}
+
+func addMetric(sum, value *uint64) *uint64 {
+	if value == nil {
+		return sum
+	}
+	result := *value
+	if sum != nil {
+		result += *sum
+	}
+	return &result
+}
+
+func toStrings(resources api.ResourceList) map[api.ResourceName]string {
+	result := make(map[api.ResourceName]string)
+	for name, value := range resources {
+		result[name] = value.String()
+	}
+	return result
+}
+
+func requestsOf(name api.ResourceName) api.ResourceName {
+	return api.ResourceName("requests." + string(name))
+}
+
+func limitsOf(name api.ResourceName) api.ResourceName {
+	return api.ResourceName("limits." + string(name))
+}
+
+func appendResourceName(names []api.ResourceName, name api.ResourceName) []api.ResourceName {
+	for _, existing := range names {
+		if existing == name {
+			return names
+		}
+	}
+	return append(names, name)
+}
+
+type resourceNames []api.ResourceName
+
+func (names resourceNames) Len() int           { return len(names) }
+func (names resourceNames) Swap(i, j int)      { names[i], names[j] = names[j], names[i] }
+func (names resourceNames) Less(i, j int) bool { return names[i] < names[j] }
+
+type quotaResourceUsages []QuotaResourceUsage
+
+func (usages quotaResourceUsages) Len() int      { return len(usages) }
+func (usages quotaResourceUsages) Swap(i, j int) { usages[i], usages[j] = usages[j], usages[i] }
+func (usages quotaResourceUsages) Less(i, j int) bool {
+	return usages[i].Name < usages[j].Name
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/metric"
 	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/runtime"
 )
 
 //getContainerImages returns container image strings from the given node.
@@ -44,6 +45,8 @@ func (self NodeCell) GetProperty(name dataselect.PropertyName) dataselect.Compar
 		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
 	case dataselect.NamespaceProperty:
 		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.LabelsProperty:
+		return dataselect.StdComparableLabels(self.ObjectMeta.Labels)
 	default:
 		// if name is not supported then just return a constant dummy value, sort will have no effect.
 		return nil
@@ -88,3 +91,8 @@ func getNodeConditions(pod api.Node) []common.Condition {
 	}
 	return conditions
 }
+
+func (self NodeCell) GetObject() runtime.Object {
+	node := api.Node(self)
+	return &node
+}
@@ -114,7 +114,7 @@ type NodeDetail struct {
 }
 
 // GetNodeDetail gets node details.
-func GetNodeDetail(client k8sClient.Interface, heapsterClient client.HeapsterClient, name string) (*NodeDetail, error) {
+func GetNodeDetail(client k8sClient.Interface, metricsProvider client.MetricsProvider, name string) (*NodeDetail, error) {
 	log.Printf("Getting details of %s node", name)
 
 	node, err := client.Core().Nodes().Get(name)
@@ -126,14 +126,14 @@ func GetNodeDetail(client k8sClient.Interface, heapsterClient client.HeapsterCli
 	// dataselect.StdMetricsDataSelect with data select provided in the request.
 	_, metricPromises := dataselect.GenericDataSelectWithMetrics(toCells([]api.Node{*node}),
 		dataselect.StdMetricsDataSelect,
-		dataselect.NoResourceCache, &heapsterClient)
+		dataselect.NoResourceCache, &metricsProvider)
 
 	pods, err := getNodePods(client, *node)
 	if err != nil {
 		return nil, err
 	}
 
-	podList, err := GetNodePods(client, heapsterClient, dataselect.DefaultDataSelect, name)
+	podList, err := GetNodePods(client, metricsProvider, dataselect.DefaultDataSelect, name)
 
 	eventList, err := event.GetNodeEvents(client, dataselect.DefaultDataSelect, node.Name)
 	if err != nil {
@@ -207,7 +207,7 @@ func getNodeAllocatedResources(node api.Node, podList *api.PodList) (NodeAllocat
 	}, nil
 }
 
-func GetNodePods(client k8sClient.Interface, heapsterClient client.HeapsterClient, dsQuery *dataselect.DataSelectQuery, name string) (*pod.PodList, error) {
+func GetNodePods(client k8sClient.Interface, metricsProvider client.MetricsProvider, dsQuery *dataselect.DataSelectQuery, name string) (*pod.PodList, error) {
 	node, err := client.Core().Nodes().Get(name)
 	if err != nil {
 		return nil, err
@@ -218,7 +218,7 @@ func GetNodePods(client k8sClient.Interface, heapsterClient client.HeapsterClien
 		return nil, err
 	}
 
-	podList := pod.CreatePodList(pods.Items, []api.Event{}, dsQuery, heapsterClient)
+	podList := pod.CreatePodList(pods.Items, []api.Event{}, dsQuery, metricsProvider)
 	return &podList, nil
 }
 
@@ -0,0 +1,327 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"k8s.io/kubernetes/pkg/api"
+	k8serrors "k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/apis/policy"
+	k8sClient "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
+	"k8s.io/kubernetes/pkg/client/retry"
+	kubetypes "k8s.io/kubernetes/pkg/kubelet/types"
+	"k8s.io/kubernetes/pkg/util/wait"
+)
+
+// Statuses of pods during a drain.
+const (
+	// The pod has been evicted. It terminates within its grace period.
+	PodEvicted = "Evicted"
+
+	// The pod has been evicted and is gone from the node.
+	PodDeleted = "Deleted"
+
+	// The pod is left on the node, e.g., because it is a mirror pod or a DaemonSet pod.
+	PodIgnored = "Ignored"
+
+	// The pod prevents the drain, unless the drain is forced, ignores DaemonSets or deletes local
+	// data. No pods are evicted when any pod is blocking.
+	PodBlocking = "Blocking"
+
+	// The pod has not been evicted, because another pod prevents the drain.
+	PodNotEvicted = "NotEvicted"
+
+	// The eviction failed, e.g., because it would violate a pod disruption budget.
+	PodEvictionFailed = "Failed"
+)
+
+// How often the pods are checked while waiting for them to be deleted.
+const drainPollInterval = time.Second
+
+// DrainSpec is a specification of a node drain. It follows the options of kubectl drain.
+type DrainSpec struct {
+	// Grace period of the evicted pods. The grace period of each pod is used if nil.
+	GracePeriodSeconds *int64 `json:"gracePeriodSeconds"`
+
+	// Evict pods not managed by a replication controller, replica set, job, daemon set or
+	// stateful set. Such pods are not recreated on other nodes.
+	Force bool `json:"force"`
+
+	// Leave pods managed by daemon sets on the node instead of failing the drain. Daemon set pods
+	// can not be evicted, as the daemon set controller would recreate them right away.
+	IgnoreDaemonSets bool `json:"ignoreDaemonSets"`
+
+	// Evict pods with local storage (emptyDir volumes), whose data is lost.
+	DeleteLocalData bool `json:"deleteLocalData"`
+
+	// How long to wait for the evicted pods to be deleted. Evicted pods are not awaited if zero.
+	TimeoutSeconds int64 `json:"timeoutSeconds"`
+}
+
+// DrainResult is a result of a node drain.
+type DrainResult struct {
+	// Name of the drained node.
+	NodeName string `json:"nodeName"`
+
+	// Whether all pods, except the ignored ones, have been evicted.
+	Complete bool `json:"complete"`
+
+	// Statuses of the pods on the node.
+	Pods []DrainPodStatus `json:"pods"`
+}
+
+// DrainPodStatus is a status of a pod during a drain.
+type DrainPodStatus struct {
+	// Name of the pod.
+	Name string `json:"name"`
+
+	// Namespace of the pod.
+	Namespace string `json:"namespace"`
+
+	// Status of the pod. One of: Evicted, Deleted, Ignored, Blocking, NotEvicted, Failed.
+	Status string `json:"status"`
+
+	// Human readable reason of the status, e.g., why the pod has not been evicted.
+	Reason string `json:"reason,omitempty"`
+}
+
+// CordonNode marks the node as unschedulable, so that no new pods are scheduled to it.
+func CordonNode(client k8sClient.Interface, name string) (*Node, error) {
+	log.Printf("Cordoning %s node", name)
+	return setNodeUnschedulable(client, name, true)
+}
+
+// UncordonNode marks the node as schedulable again.
+func UncordonNode(client k8sClient.Interface, name string) (*Node, error) {
+	log.Printf("Uncordoning %s node", name)
+	return setNodeUnschedulable(client, name, false)
+}
+
+// setNodeUnschedulable updates the unschedulable flag of the node. The node is read again and the
+// update retried when the node has been modified in the meantime, e.g., by the kubelet.
+func setNodeUnschedulable(client k8sClient.Interface, name string,
+	unschedulable bool) (*Node, error) {
+
+	var node *api.Node
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var err error
+		node, err = client.Core().Nodes().Get(name)
+		if err != nil || node.Spec.Unschedulable == unschedulable {
+			return err
+		}
+		node.Spec.Unschedulable = unschedulable
+		node, err = client.Core().Nodes().Update(node)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := toNode(*node)
+	return &result, nil
+}
+
+// DrainNode cordons the node and evicts its pods. Mirror pods are always left on the node. Pods
+// managed by daemon sets, pods with local storage and pods not managed by any controller block
+// the drain unless the spec allows them. The progress function, if given, is called with a status
+// of every pod as soon as it is known.
+func DrainNode(client k8sClient.Interface, name string, spec *DrainSpec,
+	progress func(DrainPodStatus)) (*DrainResult, error) {
+
+	log.Printf("Draining %s node", name)
+	if progress == nil {
+		progress = func(DrainPodStatus) {}
+	}
+
+	if _, err := CordonNode(client, name); err != nil {
+		return nil, err
+	}
+	pods, err := getNodePods(client, api.Node{ObjectMeta: api.ObjectMeta{Name: name}})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DrainResult{NodeName: name, Complete: true, Pods: make([]DrainPodStatus, 0)}
+	toEvict := make([]api.Pod, 0)
+	for _, pod := range pods.Items {
+		status, reason := getDrainStatus(pod, spec)
+		if status == PodBlocking {
+			result.Complete = false
+		}
+		if status == "" {
+			toEvict = append(toEvict, pod)
+			continue
+		}
+		result.Pods = append(result.Pods, reportDrainStatus(pod, status, reason, progress))
+	}
+
+	if !result.Complete {
+		for _, pod := range toEvict {
+			result.Pods = append(result.Pods, reportDrainStatus(pod, PodNotEvicted,
+				"Other pods prevent the drain", progress))
+		}
+		return result, nil
+	}
+
+	evicted := make([]api.Pod, 0)
+	for _, pod := range toEvict {
+		if err := evictPod(client, pod, spec.GracePeriodSeconds); err != nil {
+			result.Complete = false
+			result.Pods = append(result.Pods, reportDrainStatus(pod, PodEvictionFailed,
+				err.Error(), progress))
+			continue
+		}
+		reportDrainStatus(pod, PodEvicted, "", progress)
+		evicted = append(evicted, pod)
+	}
+
+	if spec.TimeoutSeconds > 0 {
+		deleted, err := waitForPodsDeleted(client, evicted,
+			time.Duration(spec.TimeoutSeconds)*time.Second, progress)
+		if err != nil {
+			return nil, err
+		}
+		for _, pod := range evicted {
+			if deleted[string(pod.UID)] {
+				result.Pods = append(result.Pods, toDrainPodStatus(pod, PodDeleted, ""))
+			} else {
+				result.Pods = append(result.Pods, toDrainPodStatus(pod, PodEvicted,
+					"Not deleted within the timeout"))
+			}
+		}
+	} else {
+		for _, pod := range evicted {
+			result.Pods = append(result.Pods, toDrainPodStatus(pod, PodEvicted, ""))
+		}
+	}
+
+	return result, nil
+}
+
+// getDrainStatus returns status of the pod before it is evicted and its reason. The status is
+// empty if the pod should be evicted.
+func getDrainStatus(pod api.Pod, spec *DrainSpec) (string, string) {
+	if _, ok := pod.Annotations[kubetypes.ConfigMirrorAnnotationKey]; ok {
+		return PodIgnored, "Mirror pod of a static pod"
+	}
+
+	controllerKind := getControllerKind(pod)
+	if controllerKind == "DaemonSet" {
+		if spec.IgnoreDaemonSets {
+			return PodIgnored, "Managed by a daemon set"
+		}
+		return PodBlocking, "Managed by a daemon set"
+	}
+	if controllerKind == "" && !spec.Force {
+		return PodBlocking, "Not managed by any controller"
+	}
+	if hasLocalStorage(pod) && !spec.DeleteLocalData {
+		return PodBlocking, "Has local storage"
+	}
+	return "", ""
+}
+
+// getControllerKind returns kind of the controller that manages the pod, or an empty string if the
+// pod is not managed by any controller.
+func getControllerKind(pod api.Pod) string {
+	for _, reference := range pod.OwnerReferences {
+		if reference.Controller != nil && *reference.Controller {
+			return reference.Kind
+		}
+	}
+
+	createdBy, ok := pod.Annotations[api.CreatedByAnnotation]
+	if !ok {
+		return ""
+	}
+	reference := &api.SerializedReference{}
+	if err := json.Unmarshal([]byte(createdBy), reference); err != nil {
+		return ""
+	}
+	return reference.Reference.Kind
+}
+
+func hasLocalStorage(pod api.Pod) bool {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.EmptyDir != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// evictPod evicts the pod through the eviction API, which respects pod disruption budgets. A pod
+// that is already gone counts as evicted.
+func evictPod(client k8sClient.Interface, pod api.Pod, gracePeriodSeconds *int64) error {
+	err := client.Policy().Evictions(pod.Namespace).Evict(&policy.Eviction{
+		ObjectMeta:    api.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+		DeleteOptions: &api.DeleteOptions{GracePeriodSeconds: gracePeriodSeconds},
+	})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if k8serrors.IsTooManyRequests(err) {
+		return errors.New("Eviction would violate a pod disruption budget")
+	}
+	return err
+}
+
+// waitForPodsDeleted waits until the pods are gone or the timeout passes, and returns UIDs of the
+// deleted pods. A pod with the same name but another UID is a new pod, so the old one is gone.
+func waitForPodsDeleted(client k8sClient.Interface, pods []api.Pod, timeout time.Duration,
+	progress func(DrainPodStatus)) (map[string]bool, error) {
+
+	deleted := make(map[string]bool)
+	err := wait.PollImmediate(drainPollInterval, timeout, func() (bool, error) {
+		for _, pod := range pods {
+			if deleted[string(pod.UID)] {
+				continue
+			}
+			current, err := client.Core().Pods(pod.Namespace).Get(pod.Name)
+			if err != nil && !k8serrors.IsNotFound(err) {
+				return false, err
+			}
+			if err != nil || current.UID != pod.UID {
+				deleted[string(pod.UID)] = true
+				reportDrainStatus(pod, PodDeleted, "", progress)
+			}
+		}
+		return len(deleted) == len(pods), nil
+	})
+	if err != nil && err != wait.ErrWaitTimeout {
+		return nil, err
+	}
+	return deleted, nil
+}
+
+func reportDrainStatus(pod api.Pod, status, reason string,
+	progress func(DrainPodStatus)) DrainPodStatus {
+
+	podStatus := toDrainPodStatus(pod, status, reason)
+	progress(podStatus)
+	return podStatus
+}
+
+func toDrainPodStatus(pod api.Pod, status, reason string) DrainPodStatus {
+	return DrainPodStatus{
+		Name:      pod.Name,
+		Namespace: pod.Namespace,
+		Status:    status,
+		Reason:    reason,
+	}
+}
@@ -17,15 +17,13 @@ package node
 import (
 	"log"
 
-	heapster "github.com/kubernetes/dashboard/src/app/backend/client"
+	kdClient "github.com/kubernetes/dashboard/src/app/backend/client"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"k8s.io/kubernetes/pkg/api"
 	client "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
 
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/metric"
-	"k8s.io/kubernetes/pkg/fields"
-	"k8s.io/kubernetes/pkg/labels"
 )
 
 // NodeList contains a list of nodes in the cluster.
@@ -45,11 +43,14 @@ type Node struct {
 
 	// Ready Status of the node
 	Ready api.ConditionStatus `json:"ready"`
+
+	// Unschedulable is true if the node is cordoned and no new pods are scheduled to it.
+	Unschedulable bool `json:"unschedulable"`
 }
 
 // GetNodeListFromChannels returns a list of all namespaces in the cluster.
 func GetNodeListFromChannels(channels *common.ResourceChannels, dsQuery *dataselect.DataSelectQuery,
-	heapsterClient *heapster.HeapsterClient) (*NodeList, error) {
+	metricsProvider *kdClient.MetricsProvider) (*NodeList, error) {
 	log.Print("Getting node list")
 
 	namespaces := <-channels.NodeList.List
@@ -57,39 +58,34 @@ func GetNodeListFromChannels(channels *common.ResourceChannels, dsQuery *datasel
 		return nil, err
 	}
 
-	return toNodeList(namespaces.Items, dsQuery, heapsterClient), nil
+	return toNodeList(namespaces.Items, dsQuery, metricsProvider), nil
 }
 
 // GetNodeList returns a list of all Nodes in the cluster.
-func GetNodeList(client client.Interface, dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) (*NodeList, error) {
+func GetNodeList(client client.Interface, listOptions api.ListOptions, dsQuery *dataselect.DataSelectQuery, metricsProvider *kdClient.MetricsProvider) (*NodeList, error) {
 	log.Print("Getting list of all nodes in the cluster")
 
-	nodes, err := client.Core().Nodes().List(api.ListOptions{
-		LabelSelector: labels.Everything(),
-		FieldSelector: fields.Everything(),
-	})
-
-	if err != nil {
-		return nil, err
+	channels := &common.ResourceChannels{
+		NodeList: common.GetNodeListChannelWithOptions(client, listOptions, 1),
 	}
 
-	return toNodeList(nodes.Items, dsQuery, heapsterClient), nil
+	return GetNodeListFromChannels(channels, dsQuery, metricsProvider)
 }
 
-func toNodeList(nodes []api.Node, dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) *NodeList {
+func toNodeList(nodes []api.Node, dsQuery *dataselect.DataSelectQuery, metricsProvider *kdClient.MetricsProvider) *NodeList {
 	nodeList := &NodeList{
-		Nodes:    make([]Node, 0),
-		ListMeta: common.ListMeta{TotalItems: len(nodes)},
+		Nodes: make([]Node, 0),
 	}
 
-	replicationControllerCells, metricPromises := dataselect.GenericDataSelectWithMetrics(toCells(nodes), dsQuery, dataselect.NoResourceCache, heapsterClient)
+	replicationControllerCells, metricPromises, filteredTotal := dataselect.GenericDataSelectWithFilterAndMetrics(toCells(nodes), dsQuery, dataselect.NoResourceCache, metricsProvider)
 	nodes = fromCells(replicationControllerCells)
+	nodeList.ListMeta = common.ListMeta{TotalItems: filteredTotal}
 
 	for _, node := range nodes {
 		nodeList.Nodes = append(nodeList.Nodes, toNode(node))
 	}
 
-	// this may be slow because heapster does not support all in one download for nodes.
+	// this may be slow because some metrics providers do not support all in one download for nodes.
 	cumulativeMetrics, err := metricPromises.GetMetrics()
 	nodeList.CumulativeMetrics = cumulativeMetrics
 	if err != nil {
@@ -101,9 +97,10 @@ func toNodeList(nodes []api.Node, dsQuery *dataselect.DataSelectQuery, heapsterC
 
 func toNode(node api.Node) Node {
 	return Node{
-		ObjectMeta: common.NewObjectMeta(node.ObjectMeta),
-		TypeMeta:   common.NewTypeMeta(common.ResourceKindNode),
-		Ready:      getNodeConditionStatus(node, api.NodeReady),
+		ObjectMeta:    common.NewObjectMeta(node.ObjectMeta),
+		TypeMeta:      common.NewTypeMeta(common.ResourceKindNode),
+		Ready:         getNodeConditionStatus(node, api.NodeReady),
+		Unschedulable: node.Spec.Unschedulable,
 	}
 }
 
@@ -45,10 +45,11 @@ type PersistentVolume struct {
 }
 
 // GetPersistentVolumeList returns a list of all Persistent Volumes in the cluster.
-func GetPersistentVolumeList(client *client.Clientset, dsQuery *dataselect.DataSelectQuery) (*PersistentVolumeList, error) {
+func GetPersistentVolumeList(client *client.Clientset, listOptions api.ListOptions,
+	dsQuery *dataselect.DataSelectQuery) (*PersistentVolumeList, error) {
 	log.Print("Getting list persistent volumes")
 	channels := &common.ResourceChannels{
-		PersistentVolumeList: common.GetPersistentVolumeListChannel(client, 1),
+		PersistentVolumeList: common.GetPersistentVolumeListChannelWithOptions(client, listOptions, 1),
 	}
 
 	return GetPersistentVolumeListFromChannels(channels, dsQuery)
@@ -71,11 +72,12 @@ func GetPersistentVolumeListFromChannels(channels *common.ResourceChannels, dsQu
 
 func getPersistentVolumeList(persistentVolumes []api.PersistentVolume, dsQuery *dataselect.DataSelectQuery) *PersistentVolumeList {
 	result := &PersistentVolumeList{
-		Items:    make([]PersistentVolume, 0),
-		ListMeta: common.ListMeta{TotalItems: len(persistentVolumes)},
+		Items: make([]PersistentVolume, 0),
 	}
 
-	persistentVolumes = fromCells(dataselect.GenericDataSelect(toCells(persistentVolumes), dsQuery))
+	cells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(persistentVolumes), dsQuery)
+	persistentVolumes = fromCells(cells)
+	result.ListMeta = common.ListMeta{TotalItems: filteredTotal}
 
 	for _, item := range persistentVolumes {
 
@@ -17,6 +17,7 @@ package persistentvolumeclaim
 import (
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/runtime"
 )
 
 // The code below allows to perform complex data section on []api.PersistentVolumeClaim
@@ -31,6 +32,8 @@ func (self PersistentVolumeClaimCell) GetProperty(name dataselect.PropertyName)
 		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
 	case dataselect.NamespaceProperty:
 		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.LabelsProperty:
+		return dataselect.StdComparableLabels(self.ObjectMeta.Labels)
 	default:
 		// if name is not supported then just return a constant dummy value, sort will have no effect.
 		return nil
@@ -52,3 +55,8 @@ func fromCells(cells []dataselect.DataCell) []api.PersistentVolumeClaim {
 	}
 	return std
 }
+
+func (self PersistentVolumeClaimCell) GetObject() runtime.Object {
+	persistentVolumeClaim := api.PersistentVolumeClaim(self)
+	return &persistentVolumeClaim
+}
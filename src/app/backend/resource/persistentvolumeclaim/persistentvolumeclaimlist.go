@@ -45,12 +45,12 @@ type PersistentVolumeClaim struct {
 }
 
 // GetPersistentVolumeClaimList returns a list of all Persistent Volume Claims in the cluster.
-func GetPersistentVolumeClaimList(client *client.Clientset, nsQuery *common.NamespaceQuery,
+func GetPersistentVolumeClaimList(client *client.Clientset, nsQuery *common.NamespaceQuery, listOptions api.ListOptions,
 	dsQuery *dataselect.DataSelectQuery) (*PersistentVolumeClaimList, error) {
 
 	log.Printf("Getting list persistent volumes claims")
 	channels := &common.ResourceChannels{
-		PersistentVolumeClaimList: common.GetPersistentVolumeClaimListChannel(client, nsQuery, 1),
+		PersistentVolumeClaimList: common.GetPersistentVolumeClaimListChannelWithOptions(client, nsQuery, listOptions, 1),
 	}
 
 	return GetPersistentVolumeClaimListFromChannels(channels, nsQuery, dsQuery)
@@ -74,11 +74,12 @@ func GetPersistentVolumeClaimListFromChannels(channels *common.ResourceChannels,
 func getPersistentVolumeClaimList(persistentVolumeClaims []api.PersistentVolumeClaim, dsQuery *dataselect.DataSelectQuery) *PersistentVolumeClaimList {
 
 	result := &PersistentVolumeClaimList{
-		Items:    make([]PersistentVolumeClaim, 0),
-		ListMeta: common.ListMeta{TotalItems: len(persistentVolumeClaims)},
+		Items: make([]PersistentVolumeClaim, 0),
 	}
 
-	persistentVolumeClaims = fromCells(dataselect.GenericDataSelect(toCells(persistentVolumeClaims), dsQuery))
+	cells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(persistentVolumeClaims), dsQuery)
+	persistentVolumeClaims = fromCells(cells)
+	result.ListMeta = common.ListMeta{TotalItems: filteredTotal}
 
 	for _, item := range persistentVolumeClaims {
 		result.Items = append(result.Items,
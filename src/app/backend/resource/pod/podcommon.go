@@ -19,6 +19,7 @@ import (
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/metric"
 	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/runtime"
 )
 
 // Gets restart count of given pod (total number of its containers restarts).
@@ -113,6 +114,8 @@ func (self PodCell) GetProperty(name dataselect.PropertyName) dataselect.Compara
 		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
 	case dataselect.NamespaceProperty:
 		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.LabelsProperty:
+		return dataselect.StdComparableLabels(self.ObjectMeta.Labels)
 	case dataselect.StatusProperty:
 		return dataselect.StdComparableString(self.Status.Phase)
 	default:
@@ -159,3 +162,8 @@ func getPodConditions(pod api.Pod) []common.Condition {
 	}
 	return conditions
 }
+
+func (self PodCell) GetObject() runtime.Object {
+	pod := api.Pod(self)
+	return &pod
+}
@@ -118,14 +118,14 @@ type EnvVar struct {
 
 // GetPodDetail returns the details (PodDetail) of a named Pod from a particular
 // namespace.
-func GetPodDetail(client k8sClient.Interface, heapsterClient client.HeapsterClient,
+func GetPodDetail(client k8sClient.Interface, metricsProvider client.MetricsProvider,
 	namespace, name string) (*PodDetail, error) {
 
 	log.Printf("Getting details of %s pod in %s namespace", name, namespace)
 
 	channels := &common.ResourceChannels{
 		ConfigMapList: common.GetConfigMapListChannel(client, common.NewSameNamespaceQuery(namespace), 1),
-		PodMetrics:    common.GetPodMetricsChannel(heapsterClient, name, namespace),
+		PodMetrics:    common.GetPodMetricsChannel(metricsProvider, name, namespace),
 	}
 
 	pod, err := client.Core().Pods(namespace).Get(name)
@@ -139,7 +139,7 @@ func GetPodDetail(client k8sClient.Interface, heapsterClient client.HeapsterClie
 	}
 	creatorAnnotation, found := pod.ObjectMeta.Annotations[api.CreatedByAnnotation]
 	if found {
-		creatorRef, err := getPodCreator(client, creatorAnnotation, common.NewSameNamespaceQuery(namespace), heapsterClient)
+		creatorRef, err := getPodCreator(client, creatorAnnotation, common.NewSameNamespaceQuery(namespace), metricsProvider)
 		if err != nil {
 			return nil, err
 		}
@@ -148,7 +148,7 @@ func GetPodDetail(client k8sClient.Interface, heapsterClient client.HeapsterClie
 
 	// Download metrics
 	_, metricPromises := dataselect.GenericDataSelectWithMetrics(toCells([]api.Pod{*pod}),
-		dataselect.StdMetricsDataSelect, dataselect.NoResourceCache, &heapsterClient)
+		dataselect.StdMetricsDataSelect, dataselect.NoResourceCache, &metricsProvider)
 	metrics, _ := metricPromises.GetMetrics()
 
 	if err = <-channels.ConfigMapList.Error; err != nil {
@@ -160,7 +160,7 @@ func GetPodDetail(client k8sClient.Interface, heapsterClient client.HeapsterClie
 	return &podDetail, nil
 }
 
-func getPodCreator(client k8sClient.Interface, creatorAnnotation string, nsQuery *common.NamespaceQuery, heapsterClient client.HeapsterClient) (*Controller, error) {
+func getPodCreator(client k8sClient.Interface, creatorAnnotation string, nsQuery *common.NamespaceQuery, metricsProvider client.MetricsProvider) (*Controller, error) {
 	var serializedReference api.SerializedReference
 	err := json.Unmarshal([]byte(creatorAnnotation), &serializedReference)
 	if err != nil {
@@ -181,22 +181,22 @@ func getPodCreator(client k8sClient.Interface, creatorAnnotation string, nsQuery
 		return nil, err
 	}
 	reference := serializedReference.Reference
-	return toPodController(client, reference, pods.Items, events.Items, heapsterClient)
+	return toPodController(client, reference, pods.Items, events.Items, metricsProvider)
 }
 
-func toPodController(client k8sClient.Interface, reference api.ObjectReference, pods []api.Pod, events []api.Event, heapsterClient client.HeapsterClient) (*Controller, error) {
+func toPodController(client k8sClient.Interface, reference api.ObjectReference, pods []api.Pod, events []api.Event, metricsProvider client.MetricsProvider) (*Controller, error) {
 	kind := reference.Kind
 	switch kind {
 	case "Job":
-		return toJobPodController(client, reference, pods, events, heapsterClient)
+		return toJobPodController(client, reference, pods, events, metricsProvider)
 	case "ReplicaSet":
-		return toReplicaSetPodController(client, reference, pods, events, heapsterClient)
+		return toReplicaSetPodController(client, reference, pods, events, metricsProvider)
 	case "ReplicationController":
-		return toReplicationControllerPodController(client, reference, pods, events, heapsterClient)
+		return toReplicationControllerPodController(client, reference, pods, events, metricsProvider)
 	case "DaemonSet":
-		return toDaemonSetPodController(client, reference, pods, events, heapsterClient)
+		return toDaemonSetPodController(client, reference, pods, events, metricsProvider)
 	case "StatefulSet":
-		return toStatefulSetPodController(client, reference, pods, events, heapsterClient)
+		return toStatefulSetPodController(client, reference, pods, events, metricsProvider)
 	default:
 	}
 	// Will be moved into the default case once all cases are implemented
@@ -205,67 +205,67 @@ func toPodController(client k8sClient.Interface, reference api.ObjectReference,
 	}, nil
 }
 
-func toJobPodController(client k8sClient.Interface, reference api.ObjectReference, pods []api.Pod, events []api.Event, heapsterClient client.HeapsterClient) (*Controller, error) {
+func toJobPodController(client k8sClient.Interface, reference api.ObjectReference, pods []api.Pod, events []api.Event, metricsProvider client.MetricsProvider) (*Controller, error) {
 	job, err := client.Batch().Jobs(reference.Namespace).Get(reference.Name)
 	if err != nil {
 		return nil, err
 	}
 	jobs := []batch.Job{*job}
-	jobList := joblist.CreateJobList(jobs, pods, events, dataselect.StdMetricsDataSelect, &heapsterClient)
+	jobList := joblist.CreateJobList(jobs, pods, events, dataselect.StdMetricsDataSelect, &metricsProvider)
 	return &Controller{
 		Kind:    "Job",
 		JobList: jobList,
 	}, nil
 }
 
-func toReplicaSetPodController(client k8sClient.Interface, reference api.ObjectReference, pods []api.Pod, events []api.Event, heapsterClient client.HeapsterClient) (*Controller, error) {
+func toReplicaSetPodController(client k8sClient.Interface, reference api.ObjectReference, pods []api.Pod, events []api.Event, metricsProvider client.MetricsProvider) (*Controller, error) {
 	rs, err := client.Extensions().ReplicaSets(reference.Namespace).Get(reference.Name)
 	if err != nil {
 		return nil, err
 	}
 	replicaSets := []extensions.ReplicaSet{*rs}
-	replicaSetList := replicasetlist.CreateReplicaSetList(replicaSets, pods, events, dataselect.StdMetricsDataSelect, &heapsterClient)
+	replicaSetList := replicasetlist.CreateReplicaSetList(replicaSets, pods, events, dataselect.StdMetricsDataSelect, &metricsProvider)
 	return &Controller{
 		Kind:           "ReplicaSet",
 		ReplicaSetList: replicaSetList,
 	}, nil
 }
 
-func toReplicationControllerPodController(client k8sClient.Interface, reference api.ObjectReference, pods []api.Pod, events []api.Event, heapsterClient client.HeapsterClient) (*Controller, error) {
+func toReplicationControllerPodController(client k8sClient.Interface, reference api.ObjectReference, pods []api.Pod, events []api.Event, metricsProvider client.MetricsProvider) (*Controller, error) {
 	rc, err := client.Core().ReplicationControllers(reference.Namespace).Get(reference.Name)
 	if err != nil {
 		return nil, err
 	}
 	rcs := []api.ReplicationController{*rc}
-	replicationControllerList := replicationcontrollerlist.CreateReplicationControllerList(rcs, dataselect.StdMetricsDataSelect, pods, events, &heapsterClient)
+	replicationControllerList := replicationcontrollerlist.CreateReplicationControllerList(rcs, dataselect.StdMetricsDataSelect, pods, events, &metricsProvider)
 	return &Controller{
 		Kind: "ReplicationController",
 		ReplicationControllerList: replicationControllerList,
 	}, nil
 }
 
-func toDaemonSetPodController(client k8sClient.Interface, reference api.ObjectReference, pods []api.Pod, events []api.Event, heapsterClient client.HeapsterClient) (*Controller, error) {
+func toDaemonSetPodController(client k8sClient.Interface, reference api.ObjectReference, pods []api.Pod, events []api.Event, metricsProvider client.MetricsProvider) (*Controller, error) {
 	daemonset, err := client.Extensions().DaemonSets(reference.Namespace).Get(reference.Name)
 	if err != nil {
 		return nil, err
 	}
 	daemonsets := []extensions.DaemonSet{*daemonset}
 
-	daemonSetList := daemonsetlist.CreateDaemonSetList(daemonsets, pods, events, dataselect.StdMetricsDataSelect, &heapsterClient)
+	daemonSetList := daemonsetlist.CreateDaemonSetList(daemonsets, pods, events, dataselect.StdMetricsDataSelect, &metricsProvider)
 	return &Controller{
 		Kind:          "DaemonSet",
 		DaemonSetList: daemonSetList,
 	}, nil
 }
 
-func toStatefulSetPodController(client k8sClient.Interface, reference api.ObjectReference, pods []api.Pod, events []api.Event, heapsterClient client.HeapsterClient) (*Controller, error) {
+func toStatefulSetPodController(client k8sClient.Interface, reference api.ObjectReference, pods []api.Pod, events []api.Event, metricsProvider client.MetricsProvider) (*Controller, error) {
 	statefulset, err := client.Apps().StatefulSets(reference.Namespace).Get(reference.Name)
 	if err != nil {
 		return nil, err
 	}
 	statefulsets := []apps.StatefulSet{*statefulset}
 
-	statefulSetList := statefulsetlist.CreateStatefulSetList(statefulsets, pods, events, dataselect.StdMetricsDataSelect, &heapsterClient)
+	statefulSetList := statefulsetlist.CreateStatefulSetList(statefulsets, pods, events, dataselect.StdMetricsDataSelect, &metricsProvider)
 	return &Controller{
 		Kind:            "StatefulSet",
 		StatefulSetList: statefulSetList,
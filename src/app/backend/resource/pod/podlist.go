@@ -64,22 +64,22 @@ type Pod struct {
 }
 
 // GetPodList returns a list of all Pods in the cluster.
-func GetPodList(client k8sClient.Interface, heapsterClient client.HeapsterClient,
-	nsQuery *common.NamespaceQuery, dsQuery *dataselect.DataSelectQuery) (*PodList, error) {
+func GetPodList(client k8sClient.Interface, metricsProvider client.MetricsProvider,
+	nsQuery *common.NamespaceQuery, listOptions api.ListOptions, dsQuery *dataselect.DataSelectQuery) (*PodList, error) {
 	log.Print("Getting list of all pods in the cluster")
 
 	channels := &common.ResourceChannels{
-		PodList:   common.GetPodListChannelWithOptions(client, nsQuery, api.ListOptions{}, 1),
+		PodList:   common.GetPodListChannelWithOptions(client, nsQuery, listOptions, 1),
 		EventList: common.GetEventListChannel(client, nsQuery, 1),
 	}
 
-	return GetPodListFromChannels(channels, dsQuery, heapsterClient)
+	return GetPodListFromChannels(channels, dsQuery, metricsProvider)
 }
 
 // GetPodList returns a list of all Pods in the cluster
 // reading required resource list once from the channels.
 func GetPodListFromChannels(channels *common.ResourceChannels, dsQuery *dataselect.DataSelectQuery,
-	heapsterClient client.HeapsterClient) (*PodList, error) {
+	metricsProvider client.MetricsProvider) (*PodList, error) {
 
 	pods := <-channels.PodList.List
 	if err := <-channels.PodList.Error; err != nil {
@@ -91,19 +91,19 @@ func GetPodListFromChannels(channels *common.ResourceChannels, dsQuery *datasele
 		return nil, err
 	}
 
-	podList := CreatePodList(pods.Items, eventList.Items, dsQuery, heapsterClient)
+	podList := CreatePodList(pods.Items, eventList.Items, dsQuery, metricsProvider)
 	return &podList, nil
 }
 
 func CreatePodList(pods []api.Pod, events []api.Event, dsQuery *dataselect.DataSelectQuery,
-	heapsterClient client.HeapsterClient) PodList {
+	metricsProvider client.MetricsProvider) PodList {
 
 	channels := &common.ResourceChannels{
-		PodMetrics: common.GetPodListMetricsChannel(heapsterClient, pods, 1),
+		PodMetrics: common.GetPodListMetricsChannel(metricsProvider, pods, 1),
 	}
 
 	if err := <-channels.PodMetrics.Error; err != nil {
-		log.Printf("Skipping Heapster metrics because of error: %s\n", err)
+		log.Printf("Skipping pod metrics because of error: %s\n", err)
 	}
 	metrics := <-channels.PodMetrics.MetricsByPod
 
@@ -114,7 +114,7 @@ func CreatePodList(pods []api.Pod, events []api.Event, dsQuery *dataselect.DataS
 	cache := &dataselect.CachedResources{Pods: pods}
 
 	podCells, cumulativeMetricsPromises, filteredTotal := dataselect.GenericDataSelectWithFilterAndMetrics(toCells(pods), dsQuery,
-		cache, &heapsterClient)
+		cache, &metricsProvider)
 	pods = fromCells(podCells)
 	podList.ListMeta = common.ListMeta{TotalItems: filteredTotal}
 
@@ -20,6 +20,7 @@ import (
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/metric"
 	"k8s.io/kubernetes/pkg/apis/extensions"
+	"k8s.io/kubernetes/pkg/runtime"
 )
 
 // ReplicaSet is a presentation layer view of Kubernetes Replica Set resource. This means
@@ -58,6 +59,8 @@ func (self ReplicaSetCell) GetProperty(name dataselect.PropertyName) dataselect.
 		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
 	case dataselect.NamespaceProperty:
 		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.LabelsProperty:
+		return dataselect.StdComparableLabels(self.ObjectMeta.Labels)
 	default:
 		// if name is not supported then just return a constant dummy value, sort will have no effect.
 		return nil
@@ -88,3 +91,8 @@ func FromCells(cells []dataselect.DataCell) []extensions.ReplicaSet {
 	}
 	return std
 }
+
+func (self ReplicaSetCell) GetObject() runtime.Object {
+	replicaSet := extensions.ReplicaSet(self)
+	return &replicaSet
+}
@@ -59,7 +59,7 @@ type ReplicaSetDetail struct {
 }
 
 // GetReplicaSetDetail gets replica set details.
-func GetReplicaSetDetail(client k8sClient.Interface, heapsterClient client.HeapsterClient,
+func GetReplicaSetDetail(client k8sClient.Interface, metricsProvider client.MetricsProvider,
 	namespace, name string) (*ReplicaSetDetail, error) {
 	log.Printf("Getting details of %s service in %s namespace", name, namespace)
 
@@ -74,7 +74,7 @@ func GetReplicaSetDetail(client k8sClient.Interface, heapsterClient client.Heaps
 		return nil, err
 	}
 
-	podList, err := GetReplicaSetPods(client, heapsterClient, dataselect.DefaultDataSelectWithMetrics, name, namespace)
+	podList, err := GetReplicaSetPods(client, metricsProvider, dataselect.DefaultDataSelectWithMetrics, name, namespace)
 	if err != nil {
 		return nil, err
 	}
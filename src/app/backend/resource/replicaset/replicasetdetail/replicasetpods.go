@@ -30,7 +30,7 @@ import (
 )
 
 // GetReplicaSetPods return list of pods targeting replica set.
-func GetReplicaSetPods(client k8sClient.Interface, heapsterClient client.HeapsterClient,
+func GetReplicaSetPods(client k8sClient.Interface, metricsProvider client.MetricsProvider,
 	dsQuery *dataselect.DataSelectQuery, petSetName, namespace string) (*pod.PodList, error) {
 	log.Printf("Getting replication controller %s pods in namespace %s", petSetName, namespace)
 
@@ -39,7 +39,7 @@ func GetReplicaSetPods(client k8sClient.Interface, heapsterClient client.Heapste
 		return nil, err
 	}
 
-	podList := pod.CreatePodList(pods, []api.Event{}, dsQuery, heapsterClient)
+	podList := pod.CreatePodList(pods, []api.Event{}, dsQuery, metricsProvider)
 	return &podList, nil
 }
 
@@ -20,7 +20,7 @@ import (
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/event"
 
-	heapster "github.com/kubernetes/dashboard/src/app/backend/client"
+	kdClient "github.com/kubernetes/dashboard/src/app/backend/client"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/metric"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/replicaset"
@@ -40,23 +40,23 @@ type ReplicaSetList struct {
 }
 
 // GetReplicaSetList returns a list of all Replica Sets in the cluster.
-func GetReplicaSetList(client client.Interface, nsQuery *common.NamespaceQuery,
-	dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) (*ReplicaSetList, error) {
+func GetReplicaSetList(client client.Interface, nsQuery *common.NamespaceQuery, listOptions api.ListOptions,
+	dsQuery *dataselect.DataSelectQuery, metricsProvider *kdClient.MetricsProvider) (*ReplicaSetList, error) {
 	log.Print("Getting list of all replica sets in the cluster")
 
 	channels := &common.ResourceChannels{
-		ReplicaSetList: common.GetReplicaSetListChannel(client, nsQuery, 1),
+		ReplicaSetList: common.GetReplicaSetListChannelWithOptions(client, nsQuery, listOptions, 1),
 		PodList:        common.GetPodListChannel(client, nsQuery, 1),
 		EventList:      common.GetEventListChannel(client, nsQuery, 1),
 	}
 
-	return GetReplicaSetListFromChannels(channels, dsQuery, heapsterClient)
+	return GetReplicaSetListFromChannels(channels, dsQuery, metricsProvider)
 }
 
 // GetReplicaSetList returns a list of all Replica Sets in the cluster
 // reading required resource list once from the channels.
 func GetReplicaSetListFromChannels(channels *common.ResourceChannels,
-	dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) (*ReplicaSetList, error) {
+	dsQuery *dataselect.DataSelectQuery, metricsProvider *kdClient.MetricsProvider) (*ReplicaSetList, error) {
 
 	replicaSets := <-channels.ReplicaSetList.List
 	if err := <-channels.ReplicaSetList.Error; err != nil {
@@ -81,24 +81,24 @@ func GetReplicaSetListFromChannels(channels *common.ResourceChannels,
 	if err := <-channels.EventList.Error; err != nil {
 		return nil, err
 	}
-	return CreateReplicaSetList(replicaSets.Items, pods.Items, events.Items, dsQuery, heapsterClient), nil
+	return CreateReplicaSetList(replicaSets.Items, pods.Items, events.Items, dsQuery, metricsProvider), nil
 }
 
 // CreateReplicaSetList creates paginated list of Replica Set model
 // objects based on Kubernetes Replica Set objects array and related resources arrays.
 func CreateReplicaSetList(replicaSets []extensions.ReplicaSet, pods []api.Pod,
-	events []api.Event, dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) *ReplicaSetList {
+	events []api.Event, dsQuery *dataselect.DataSelectQuery, metricsProvider *kdClient.MetricsProvider) *ReplicaSetList {
 
 	replicaSetList := &ReplicaSetList{
 		ReplicaSets: make([]replicaset.ReplicaSet, 0),
-		ListMeta:    common.ListMeta{TotalItems: len(replicaSets)},
 	}
 
 	cachedResources := &dataselect.CachedResources{
 		Pods: pods,
 	}
-	replicationControllerCells, metricPromises := dataselect.GenericDataSelectWithMetrics(replicaset.ToCells(replicaSets), dsQuery, cachedResources, heapsterClient)
+	replicationControllerCells, metricPromises, filteredTotal := dataselect.GenericDataSelectWithFilterAndMetrics(replicaset.ToCells(replicaSets), dsQuery, cachedResources, metricsProvider)
 	replicaSets = replicaset.FromCells(replicationControllerCells)
+	replicaSetList.ListMeta = common.ListMeta{TotalItems: filteredTotal}
 
 	for _, replicaSet := range replicaSets {
 		matchingPods := common.FilterNamespacedPodsBySelector(pods, replicaSet.ObjectMeta.Namespace,
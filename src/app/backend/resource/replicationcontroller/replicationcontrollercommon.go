@@ -24,6 +24,7 @@ import (
 	client "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
 	"k8s.io/kubernetes/pkg/fields"
 	"k8s.io/kubernetes/pkg/labels"
+	"k8s.io/kubernetes/pkg/runtime"
 )
 
 // ReplicationController (aka. Replication Controller) plus zero or more Kubernetes services that
@@ -108,6 +109,8 @@ func (self ReplicationControllerCell) GetProperty(name dataselect.PropertyName)
 		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
 	case dataselect.NamespaceProperty:
 		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.LabelsProperty:
+		return dataselect.StdComparableLabels(self.ObjectMeta.Labels)
 	default:
 		// if name is not supported then just return a constant dummy value, sort will have no effect.
 		return nil
@@ -137,3 +140,8 @@ func FromCells(cells []dataselect.DataCell) []api.ReplicationController {
 	}
 	return std
 }
+
+func (self ReplicationControllerCell) GetObject() runtime.Object {
+	replicationController := api.ReplicationController(self)
+	return &replicationController
+}
@@ -66,7 +66,7 @@ type ReplicationControllerSpec struct {
 
 // GetReplicationControllerDetail returns detailed information about the given replication
 // controller in the given namespace.
-func GetReplicationControllerDetail(client k8sClient.Interface, heapsterClient client.HeapsterClient,
+func GetReplicationControllerDetail(client k8sClient.Interface, metricsProvider client.MetricsProvider,
 	namespace, name string) (*ReplicationControllerDetail, error) {
 	log.Printf("Getting details of %s replication controller in %s namespace", name, namespace)
 
@@ -80,7 +80,7 @@ func GetReplicationControllerDetail(client k8sClient.Interface, heapsterClient c
 		return nil, err
 	}
 
-	podList, err := GetReplicationControllerPods(client, heapsterClient, dataselect.DefaultDataSelectWithMetrics,
+	podList, err := GetReplicationControllerPods(client, metricsProvider, dataselect.DefaultDataSelectWithMetrics,
 		name, namespace)
 	if err != nil {
 		return nil, err
@@ -29,7 +29,7 @@ import (
 
 // GetReplicationControllerPods return list of pods targeting replication controller associated
 // to given name.
-func GetReplicationControllerPods(client k8sClient.Interface, heapsterClient client.HeapsterClient,
+func GetReplicationControllerPods(client k8sClient.Interface, metricsProvider client.MetricsProvider,
 	dsQuery *dataselect.DataSelectQuery, rcName, namespace string) (*pod.PodList, error) {
 	log.Printf("Getting replication controller %s pods in namespace %s", rcName, namespace)
 
@@ -38,7 +38,7 @@ func GetReplicationControllerPods(client k8sClient.Interface, heapsterClient cli
 		return nil, err
 	}
 
-	podList := pod.CreatePodList(pods, []api.Event{}, dsQuery, heapsterClient)
+	podList := pod.CreatePodList(pods, []api.Event{}, dsQuery, metricsProvider)
 	return &podList, nil
 }
 
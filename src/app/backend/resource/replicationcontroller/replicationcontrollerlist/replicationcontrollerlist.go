@@ -17,7 +17,7 @@ package replicationcontrollerlist
 import (
 	"log"
 
-	heapster "github.com/kubernetes/dashboard/src/app/backend/client"
+	kdClient "github.com/kubernetes/dashboard/src/app/backend/client"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/event"
@@ -37,23 +37,23 @@ type ReplicationControllerList struct {
 }
 
 // GetReplicationControllerList returns a list of all Replication Controllers in the cluster.
-func GetReplicationControllerList(client *client.Clientset, nsQuery *common.NamespaceQuery,
-	dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) (*ReplicationControllerList, error) {
+func GetReplicationControllerList(client *client.Clientset, nsQuery *common.NamespaceQuery, listOptions api.ListOptions,
+	dsQuery *dataselect.DataSelectQuery, metricsProvider *kdClient.MetricsProvider) (*ReplicationControllerList, error) {
 	log.Print("Getting list of all replication controllers in the cluster")
 
 	channels := &common.ResourceChannels{
-		ReplicationControllerList: common.GetReplicationControllerListChannel(client, nsQuery, 1),
+		ReplicationControllerList: common.GetReplicationControllerListChannelWithOptions(client, nsQuery, listOptions, 1),
 		PodList:                   common.GetPodListChannel(client, nsQuery, 1),
 		EventList:                 common.GetEventListChannel(client, nsQuery, 1),
 	}
 
-	return GetReplicationControllerListFromChannels(channels, dsQuery, heapsterClient)
+	return GetReplicationControllerListFromChannels(channels, dsQuery, metricsProvider)
 }
 
 // GetReplicationControllerListFromChannels returns a list of all Replication Controllers in the cluster
 // reading required resource list once from the channels.
 func GetReplicationControllerListFromChannels(channels *common.ResourceChannels,
-	dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) (*ReplicationControllerList, error) {
+	dsQuery *dataselect.DataSelectQuery, metricsProvider *kdClient.MetricsProvider) (*ReplicationControllerList, error) {
 
 	rcList := <-channels.ReplicationControllerList.List
 	if err := <-channels.ReplicationControllerList.Error; err != nil {
@@ -70,23 +70,23 @@ func GetReplicationControllerListFromChannels(channels *common.ResourceChannels,
 		return nil, err
 	}
 
-	return CreateReplicationControllerList(rcList.Items, dsQuery, podList.Items, eventList.Items, heapsterClient), nil
+	return CreateReplicationControllerList(rcList.Items, dsQuery, podList.Items, eventList.Items, metricsProvider), nil
 }
 
 // CreateReplicationControllerList creates paginated list of Replication Controller model
 // objects based on Kubernetes Replication Controller objects array and related resources arrays.
 func CreateReplicationControllerList(replicationControllers []api.ReplicationController,
-	dsQuery *dataselect.DataSelectQuery, pods []api.Pod, events []api.Event, heapsterClient *heapster.HeapsterClient) *ReplicationControllerList {
+	dsQuery *dataselect.DataSelectQuery, pods []api.Pod, events []api.Event, metricsProvider *kdClient.MetricsProvider) *ReplicationControllerList {
 
 	rcList := &ReplicationControllerList{
 		ReplicationControllers: make([]replicationcontroller.ReplicationController, 0),
-		ListMeta:               common.ListMeta{TotalItems: len(replicationControllers)},
 	}
 	cachedResources := &dataselect.CachedResources{
 		Pods: pods,
 	}
-	replicationControllerCells, metricPromises := dataselect.GenericDataSelectWithMetrics(replicationcontroller.ToCells(replicationControllers), dsQuery, cachedResources, heapsterClient)
+	replicationControllerCells, metricPromises, filteredTotal := dataselect.GenericDataSelectWithFilterAndMetrics(replicationcontroller.ToCells(replicationControllers), dsQuery, cachedResources, metricsProvider)
 	replicationControllers = replicationcontroller.FromCells(replicationControllerCells)
+	rcList.ListMeta = common.ListMeta{TotalItems: filteredTotal}
 
 	for _, rc := range replicationControllers {
 		matchingPods := common.FilterNamespacedPodsBySelector(pods, rc.ObjectMeta.Namespace,
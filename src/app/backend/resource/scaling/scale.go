@@ -0,0 +1,218 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scaling
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	k8serrors "k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/runtime"
+	"k8s.io/kubernetes/pkg/util/validation/field"
+)
+
+// ReplicaCounts is the number of replicas of a scalable resource.
+type ReplicaCounts struct {
+	// Kind of the resource.
+	Kind string `json:"kind"`
+
+	// Number of replicas that the resource should have. For jobs it is the parallelism.
+	DesiredReplicas int32 `json:"desiredReplicas"`
+
+	// Number of replicas that the resource currently has. For jobs it is the number of active
+	// pods.
+	ActualReplicas int32 `json:"actualReplicas"`
+}
+
+// ScaleSpec is a specification of the desired number of replicas of a scalable resource.
+type ScaleSpec struct {
+	// Desired number of replicas. For jobs it is the parallelism.
+	Replicas int32 `json:"replicas"`
+}
+
+// ResourceVerber gets and puts resources of any kind, e.g., common.ResourceVerber.
+type ResourceVerber interface {
+	Get(kind string, namespace string, name string) (runtime.Object, error)
+	Put(kind string, namespace string, name string, object *runtime.Unknown) error
+}
+
+// scaleFields are paths of the fields that hold the desired and actual number of replicas of a
+// resource.
+type scaleFields struct {
+	desired []string
+	actual  []string
+}
+
+// scalableKinds maps kinds of resources that can be scaled to their scale fields.
+var scalableKinds = map[string]scaleFields{
+	common.ResourceKindDeployment: {
+		desired: []string{"spec", "replicas"},
+		actual:  []string{"status", "replicas"},
+	},
+	common.ResourceKindReplicaSet: {
+		desired: []string{"spec", "replicas"},
+		actual:  []string{"status", "replicas"},
+	},
+	common.ResourceKindReplicationController: {
+		desired: []string{"spec", "replicas"},
+		actual:  []string{"status", "replicas"},
+	},
+	common.ResourceKindStatefulSet: {
+		desired: []string{"spec", "replicas"},
+		actual:  []string{"status", "replicas"},
+	},
+	common.ResourceKindJob: {
+		desired: []string{"spec", "parallelism"},
+		actual:  []string{"status", "active"},
+	},
+}
+
+// GetReplicaCounts returns the number of replicas of the resource of the given kind in the given
+// namespace with the given name.
+func GetReplicaCounts(verber ResourceVerber, kind, namespace, name string) (*ReplicaCounts, error) {
+	fields, ok := scalableKinds[kind]
+	if !ok {
+		return nil, k8serrors.NewBadRequest(fmt.Sprintf("Resources of kind %s can not be scaled",
+			kind))
+	}
+
+	object, err := getObject(verber, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return toReplicaCounts(kind, fields, object)
+}
+
+// ScaleResource sets the desired number of replicas of the resource of the given kind in the
+// given namespace with the given name. The update fails when the resource changes in the meantime.
+func ScaleResource(verber ResourceVerber, kind, namespace, name string,
+	spec *ScaleSpec) (*ReplicaCounts, error) {
+
+	log.Printf("Scaling %s %s in %s namespace to %d replicas", kind, name, namespace,
+		spec.Replicas)
+
+	fields, ok := scalableKinds[kind]
+	if !ok {
+		return nil, k8serrors.NewBadRequest(fmt.Sprintf("Resources of kind %s can not be scaled",
+			kind))
+	}
+	if spec.Replicas < 0 {
+		return nil, k8serrors.NewInvalid(unversioned.GroupKind{Kind: kind}, name,
+			field.ErrorList{field.Invalid(field.NewPath("replicas"), spec.Replicas,
+				"must be greater than or equal to 0")})
+	}
+
+	object, err := getObject(verber, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	replicas := json.Number(strconv.Itoa(int(spec.Replicas)))
+	if err := setField(object, fields.desired, replicas); err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	if err := verber.Put(kind, namespace, name, &runtime.Unknown{Raw: raw}); err != nil {
+		return nil, err
+	}
+
+	return toReplicaCounts(kind, fields, object)
+}
+
+// getObject gets generic JSON representation of the resource. Numbers are kept as json.Number, so
+// that the resource can be put back without loss of precision.
+func getObject(verber ResourceVerber, kind, namespace, name string) (map[string]interface{},
+	error) {
+
+	result, err := verber.Get(kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	unknown, ok := result.(*runtime.Unknown)
+	if !ok {
+		return nil, fmt.Errorf("Received unexpected object of type %T", result)
+	}
+
+	object := make(map[string]interface{})
+	decoder := json.NewDecoder(bytes.NewReader(unknown.Raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&object); err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+func toReplicaCounts(kind string, fields scaleFields,
+	object map[string]interface{}) (*ReplicaCounts, error) {
+
+	desired, err := getCount(object, fields.desired)
+	if err != nil {
+		return nil, err
+	}
+	actual, err := getCount(object, fields.actual)
+	if err != nil {
+		return nil, err
+	}
+	return &ReplicaCounts{Kind: kind, DesiredReplicas: desired, ActualReplicas: actual}, nil
+}
+
+// getCount returns the number at the given path of the object. Missing numbers are zero.
+func getCount(object map[string]interface{}, path []string) (int32, error) {
+	var value interface{} = object
+	for _, key := range path {
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return 0, nil
+		}
+		value = fields[key]
+	}
+	if value == nil {
+		return 0, nil
+	}
+
+	number, ok := value.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("Expected number at %v, got %v", path, value)
+	}
+	count, err := strconv.ParseInt(string(number), 10, 32)
+	return int32(count), err
+}
+
+// setField sets the value at the given path of the object, creating missing parents.
+func setField(object map[string]interface{}, path []string, value interface{}) error {
+	fields := object
+	for _, key := range path[:len(path)-1] {
+		child, ok := fields[key]
+		if !ok || child == nil {
+			child = make(map[string]interface{})
+			fields[key] = child
+		}
+		childFields, ok := child.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("Expected object at %s, got %v", key, child)
+		}
+		fields = childFields
+	}
+	fields[path[len(path)-1]] = value
+	return nil
+}
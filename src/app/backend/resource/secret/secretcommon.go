@@ -17,6 +17,7 @@ package secret
 import (
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/runtime"
 )
 
 // The code below allows to perform complex data section on []api.Secret
@@ -31,6 +32,8 @@ func (self SecretCell) GetProperty(name dataselect.PropertyName) dataselect.Comp
 		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
 	case dataselect.NamespaceProperty:
 		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.LabelsProperty:
+		return dataselect.StdComparableLabels(self.ObjectMeta.Labels)
 	default:
 		// if name is not supported then just return a constant dummy value, sort will have no effect.
 		return nil
@@ -52,3 +55,8 @@ func fromCells(cells []dataselect.DataCell) []api.Secret {
 	}
 	return std
 }
+
+func (self SecretCell) GetObject() runtime.Object {
+	secret := api.Secret(self)
+	return &secret
+}
@@ -15,10 +15,14 @@
 package secret
 
 import (
+	"encoding/base64"
 	"log"
+	"sort"
+	"unicode/utf8"
 
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"k8s.io/kubernetes/pkg/api"
+	k8serrors "k8s.io/kubernetes/pkg/api/errors"
 	client "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
 )
 
@@ -28,16 +32,35 @@ type SecretDetail struct {
 	ObjectMeta common.ObjectMeta `json:"objectMeta"`
 	TypeMeta   common.TypeMeta   `json:"typeMeta"`
 
-	// Data contains the secret data.  Each key must be a valid DNS_SUBDOMAIN
-	// or leading dot followed by valid DNS_SUBDOMAIN.
-	// The serialized form of the secret data is a base64 encoded string,
-	// representing the arbitrary (possibly non-string) data value here.
-	Data map[string][]byte `json:"data"`
+	// Keys of the secret data, sorted by name. Values are not included, so that they are not
+	// exposed until they are explicitly revealed.
+	Keys []SecretKey `json:"keys"`
 
 	// Used to facilitate programmatic handling of secret data.
 	Type api.SecretType `json:"type"`
 }
 
+// SecretKey is a key of the secret data.
+type SecretKey struct {
+	// Name of the key.
+	Name string `json:"name"`
+
+	// Size of the value in bytes.
+	Size int `json:"size"`
+}
+
+// SecretValue is a revealed value of a key of the secret data.
+type SecretValue struct {
+	// Name of the key.
+	Key string `json:"key"`
+
+	// Decoded value. Base64 encoded if it is not valid UTF-8 text, e.g., a binary file.
+	Value string `json:"value"`
+
+	// Whether the value is Base64 encoded.
+	Base64 bool `json:"base64"`
+}
+
 // GetSecretDetail returns returns detailed information about a secret
 func GetSecretDetail(client *client.Clientset, namespace, name string) (*SecretDetail, error) {
 	log.Printf("Getting details of %s secret in %s namespace", name, namespace)
@@ -51,11 +74,46 @@ func GetSecretDetail(client *client.Clientset, namespace, name string) (*SecretD
 	return getSecretDetail(rawSecret), nil
 }
 
+// RevealSecretValue returns value of the key of the secret data. Returns a not found error when the
+// secret has no such key.
+func RevealSecretValue(client client.Interface, namespace, name, key string) (*SecretValue,
+	error) {
+
+	log.Printf("Revealing value of %s key of %s secret in %s namespace", key, name, namespace)
+
+	rawSecret, err := client.Core().Secrets(namespace).Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := rawSecret.Data[key]
+	if !ok {
+		return nil, k8serrors.NewNotFound(api.Resource("secrets/key"), key)
+	}
+	if utf8.Valid(value) {
+		return &SecretValue{Key: key, Value: string(value)}, nil
+	}
+	return &SecretValue{Key: key, Value: base64.StdEncoding.EncodeToString(value), Base64: true},
+		nil
+}
+
 func getSecretDetail(rawSecret *api.Secret) *SecretDetail {
+	keys := make([]SecretKey, 0, len(rawSecret.Data))
+	for name, value := range rawSecret.Data {
+		keys = append(keys, SecretKey{Name: name, Size: len(value)})
+	}
+	sort.Sort(secretKeysByName(keys))
+
 	return &SecretDetail{
 		ObjectMeta: common.NewObjectMeta(rawSecret.ObjectMeta),
 		TypeMeta:   common.NewTypeMeta(common.ResourceKindSecret),
-		Data:       rawSecret.Data,
+		Keys:       keys,
 		Type:       rawSecret.Type,
 	}
 }
+
+type secretKeysByName []SecretKey
+
+func (keys secretKeysByName) Len() int           { return len(keys) }
+func (keys secretKeysByName) Swap(i, j int)      { keys[i], keys[j] = keys[j], keys[i] }
+func (keys secretKeysByName) Less(i, j int) bool { return keys[i].Name < keys[j].Name }
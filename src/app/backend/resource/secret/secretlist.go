@@ -15,12 +15,12 @@
 package secret
 
 import (
+	"errors"
+
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"k8s.io/kubernetes/pkg/api"
 	client "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
-	"k8s.io/kubernetes/pkg/fields"
-	"k8s.io/kubernetes/pkg/labels"
 )
 
 // SecretSpec - common interface for the specification of different secrets.
@@ -29,6 +29,8 @@ type SecretSpec interface {
 	GetType() api.SecretType
 	GetNamespace() string
 	GetData() map[string][]byte
+	// Validate returns an error if the secret can not be created from the spec.
+	Validate() error
 }
 
 // ImagePullSecretSpec - specification of an image pull secret implements SecretSpec
@@ -59,6 +61,14 @@ func (spec *ImagePullSecretSpec) GetData() map[string][]byte {
 	return map[string][]byte{api.DockerConfigKey: spec.Data}
 }
 
+// Validate - check that the ImagePullSecret has the .dockercfg value
+func (spec *ImagePullSecretSpec) Validate() error {
+	if len(spec.Data) == 0 {
+		return errors.New("Value of .dockercfg has to be given")
+	}
+	return nil
+}
+
 // Secret - a single secret returned to the frontend.
 type Secret struct {
 	common.ObjectMeta `json:"objectMeta"`
@@ -74,12 +84,9 @@ type SecretList struct {
 }
 
 // GetSecretList - return all secrets in the given namespace.
-func GetSecretList(client *client.Clientset, namespace *common.NamespaceQuery,
+func GetSecretList(client *client.Clientset, namespace *common.NamespaceQuery, listOptions api.ListOptions,
 	dsQuery *dataselect.DataSelectQuery) (*SecretList, error) {
-	secretList, err := client.Secrets(namespace.ToRequestParam()).List(api.ListOptions{
-		LabelSelector: labels.Everything(),
-		FieldSelector: fields.Everything(),
-	})
+	secretList, err := client.Secrets(namespace.ToRequestParam()).List(listOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -103,6 +110,9 @@ func GetSecretListFromChannels(channels *common.ResourceChannels, dsQuery *datas
 
 // CreateSecret - create a single secret using the cluster API client
 func CreateSecret(client *client.Clientset, spec SecretSpec) (*Secret, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
 	namespace := spec.GetNamespace()
 	secret := &api.Secret{
 		ObjectMeta: api.ObjectMeta{
@@ -125,11 +135,12 @@ func NewSecret(secret *api.Secret) *Secret {
 // NewSecret - creates a new instance of SecretList struct based on K8s Secrets array.
 func NewSecretList(secrets []api.Secret, dsQuery *dataselect.DataSelectQuery) *SecretList {
 	newSecretList := &SecretList{
-		ListMeta: common.ListMeta{TotalItems: len(secrets)},
-		Secrets:  make([]Secret, 0),
+		Secrets: make([]Secret, 0),
 	}
 
-	secrets = fromCells(dataselect.GenericDataSelect(toCells(secrets), dsQuery))
+	cells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(secrets), dsQuery)
+	secrets = fromCells(cells)
+	newSecretList.ListMeta = common.ListMeta{TotalItems: filteredTotal}
 
 	for _, secret := range secrets {
 		newSecretList.Secrets = append(newSecretList.Secrets, *NewSecret(&secret))
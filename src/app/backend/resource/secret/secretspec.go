@@ -0,0 +1,191 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"k8s.io/kubernetes/pkg/api"
+)
+
+// OpaqueSecretSpec - specification of a secret with arbitrary data implements SecretSpec
+type OpaqueSecretSpec struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	// Values of the secret, e.g., contents of uploaded files. They must be Base64 encoded.
+	Data map[string][]byte `json:"data"`
+	// Plain text values of the secret. They take precedence over values with the same key in Data.
+	StringData map[string]string `json:"stringData"`
+}
+
+// GetName - return the name of the OpaqueSecret
+func (spec *OpaqueSecretSpec) GetName() string {
+	return spec.Name
+}
+
+// GetType - return the type of the OpaqueSecret, which is always api.SecretTypeOpaque
+func (spec *OpaqueSecretSpec) GetType() api.SecretType {
+	return api.SecretTypeOpaque
+}
+
+// GetNamespace - return the namespace of the OpaqueSecret
+func (spec *OpaqueSecretSpec) GetNamespace() string {
+	return spec.Namespace
+}
+
+// GetData - return the data the secret carries, merged from binary and plain text values
+func (spec *OpaqueSecretSpec) GetData() map[string][]byte {
+	data := make(map[string][]byte)
+	for key, value := range spec.Data {
+		data[key] = value
+	}
+	for key, value := range spec.StringData {
+		data[key] = []byte(value)
+	}
+	return data
+}
+
+// Validate - check that the OpaqueSecret has at least one value
+func (spec *OpaqueSecretSpec) Validate() error {
+	if len(spec.Data) == 0 && len(spec.StringData) == 0 {
+		return errors.New("Secret has to have at least one value")
+	}
+	return nil
+}
+
+// TLSSecretSpec - specification of a TLS secret implements SecretSpec
+type TLSSecretSpec struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	// PEM encoded certificate, optionally followed by intermediate certificates.
+	Certificate string `json:"certificate"`
+	// PEM encoded private key matching the certificate.
+	PrivateKey string `json:"privateKey"`
+}
+
+// GetName - return the name of the TLSSecret
+func (spec *TLSSecretSpec) GetName() string {
+	return spec.Name
+}
+
+// GetType - return the type of the TLSSecret, which is always api.SecretTypeTLS
+func (spec *TLSSecretSpec) GetType() api.SecretType {
+	return api.SecretTypeTLS
+}
+
+// GetNamespace - return the namespace of the TLSSecret
+func (spec *TLSSecretSpec) GetNamespace() string {
+	return spec.Namespace
+}
+
+// GetData - return the data the secret carries, the certificate and the private key
+func (spec *TLSSecretSpec) GetData() map[string][]byte {
+	return map[string][]byte{
+		api.TLSCertKey:       []byte(spec.Certificate),
+		api.TLSPrivateKeyKey: []byte(spec.PrivateKey),
+	}
+}
+
+// Validate - check that the certificate and the private key are PEM encoded and match each other
+func (spec *TLSSecretSpec) Validate() error {
+	if block, _ := pem.Decode([]byte(spec.Certificate)); block == nil {
+		return errors.New("Certificate is not PEM encoded")
+	}
+	if block, _ := pem.Decode([]byte(spec.PrivateKey)); block == nil {
+		return errors.New("Private key is not PEM encoded")
+	}
+	if _, err := tls.X509KeyPair([]byte(spec.Certificate), []byte(spec.PrivateKey)); err != nil {
+		return fmt.Errorf("Invalid certificate or private key: %s", err)
+	}
+	return nil
+}
+
+// BasicAuthSecretSpec - specification of a basic authentication secret implements SecretSpec
+type BasicAuthSecretSpec struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Username  string `json:"username"`
+	Password  string `json:"password"`
+}
+
+// GetName - return the name of the BasicAuthSecret
+func (spec *BasicAuthSecretSpec) GetName() string {
+	return spec.Name
+}
+
+// GetType - return the type of the BasicAuthSecret, which is always api.SecretTypeBasicAuth
+func (spec *BasicAuthSecretSpec) GetType() api.SecretType {
+	return api.SecretTypeBasicAuth
+}
+
+// GetNamespace - return the namespace of the BasicAuthSecret
+func (spec *BasicAuthSecretSpec) GetNamespace() string {
+	return spec.Namespace
+}
+
+// GetData - return the data the secret carries, the username and the password
+func (spec *BasicAuthSecretSpec) GetData() map[string][]byte {
+	return map[string][]byte{
+		api.BasicAuthUsernameKey: []byte(spec.Username),
+		api.BasicAuthPasswordKey: []byte(spec.Password),
+	}
+}
+
+// Validate - check that the BasicAuthSecret has a username or a password
+func (spec *BasicAuthSecretSpec) Validate() error {
+	if spec.Username == "" && spec.Password == "" {
+		return errors.New("Username or password has to be given")
+	}
+	return nil
+}
+
+// SSHAuthSecretSpec - specification of an SSH authentication secret implements SecretSpec
+type SSHAuthSecretSpec struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	// PEM encoded SSH private key.
+	PrivateKey string `json:"privateKey"`
+}
+
+// GetName - return the name of the SSHAuthSecret
+func (spec *SSHAuthSecretSpec) GetName() string {
+	return spec.Name
+}
+
+// GetType - return the type of the SSHAuthSecret, which is always api.SecretTypeSSHAuth
+func (spec *SSHAuthSecretSpec) GetType() api.SecretType {
+	return api.SecretTypeSSHAuth
+}
+
+// GetNamespace - return the namespace of the SSHAuthSecret
+func (spec *SSHAuthSecretSpec) GetNamespace() string {
+	return spec.Namespace
+}
+
+// GetData - return the data the secret carries, it is a single key-value pair
+func (spec *SSHAuthSecretSpec) GetData() map[string][]byte {
+	return map[string][]byte{api.SSHAuthPrivateKey: []byte(spec.PrivateKey)}
+}
+
+// Validate - check that the private key is PEM encoded
+func (spec *SSHAuthSecretSpec) Validate() error {
+	if block, _ := pem.Decode([]byte(spec.PrivateKey)); block == nil {
+		return errors.New("Private key is not PEM encoded")
+	}
+	return nil
+}
@@ -16,6 +16,7 @@ package service
 
 import (
 	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/runtime"
 
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
@@ -52,10 +53,11 @@ func ToServiceDetail(service *api.Service) ServiceDetail {
 func CreateServiceList(services []api.Service, dsQuery *dataselect.DataSelectQuery) *ServiceList {
 	serviceList := &ServiceList{
 		Services: make([]Service, 0),
-		ListMeta: common.ListMeta{TotalItems: len(services)},
 	}
 
-	services = fromCells(dataselect.GenericDataSelect(toCells(services), dsQuery))
+	cells, filteredTotal := dataselect.GenericDataSelectWithFilter(toCells(services), dsQuery)
+	services = fromCells(cells)
+	serviceList.ListMeta = common.ListMeta{TotalItems: filteredTotal}
 
 	for _, service := range services {
 		serviceList.Services = append(serviceList.Services, ToService(&service))
@@ -76,6 +78,8 @@ func (self ServiceCell) GetProperty(name dataselect.PropertyName) dataselect.Com
 		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
 	case dataselect.NamespaceProperty:
 		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.LabelsProperty:
+		return dataselect.StdComparableLabels(self.ObjectMeta.Labels)
 	default:
 		// if name is not supported then just return a constant dummy value, sort will have no effect.
 		return nil
@@ -97,3 +101,8 @@ func fromCells(cells []dataselect.DataCell) []api.Service {
 	}
 	return std
 }
+
+func (self ServiceCell) GetObject() runtime.Object {
+	service := api.Service(self)
+	return &service
+}
@@ -53,10 +53,17 @@ type ServiceDetail struct {
 
 	// PodList represents list of pods targeted by same label selector as this service.
 	PodList pod.PodList `json:"podList"`
+
+	// EndpointSubsets are addresses the service proxies requests to, grouped by their ports.
+	EndpointSubsets []EndpointSubset `json:"endpointSubsets"`
+
+	// EndpointsHealth tells whether the service has ready endpoints. One of: NoEndpoints,
+	// PartiallyReady, Ready.
+	EndpointsHealth string `json:"endpointsHealth"`
 }
 
 // GetServiceDetail gets service details.
-func GetServiceDetail(client k8sClient.Interface, heapsterClient client.HeapsterClient,
+func GetServiceDetail(client k8sClient.Interface, metricsProvider client.MetricsProvider,
 	namespace, name string, dsQuery *dataselect.DataSelectQuery) (*ServiceDetail, error) {
 
 	log.Printf("Getting details of %s service in %s namespace", name, namespace)
@@ -67,19 +74,26 @@ func GetServiceDetail(client k8sClient.Interface, heapsterClient client.Heapster
 		return nil, err
 	}
 
-	podList, err := GetServicePods(client, heapsterClient, namespace, name, dsQuery)
+	endpointSubsets, err := GetServiceEndpoints(client, serviceData)
+	if err != nil {
+		return nil, err
+	}
+
+	podList, err := GetServicePods(client, metricsProvider, namespace, name, dsQuery)
 	if err != nil {
 		return nil, err
 	}
 
 	service := ToServiceDetail(serviceData)
 	service.PodList = *podList
+	service.EndpointSubsets = endpointSubsets
+	service.EndpointsHealth = GetEndpointsHealth(endpointSubsets)
 
 	return &service, nil
 }
 
 // GetServicePods gets list of pods targeted by given label selector in given namespace.
-func GetServicePods(client k8sClient.Interface, heapsterClient client.HeapsterClient, namespace,
+func GetServicePods(client k8sClient.Interface, metricsProvider client.MetricsProvider, namespace,
 	name string, dsQuery *dataselect.DataSelectQuery) (*pod.PodList, error) {
 
 	service, err := client.Core().Services(namespace).Get(name)
@@ -103,6 +117,6 @@ func GetServicePods(client k8sClient.Interface, heapsterClient client.HeapsterCl
 		return nil, err
 	}
 
-	podList := pod.CreatePodList(apiPodList.Items, []api.Event{}, dsQuery, heapsterClient)
+	podList := pod.CreatePodList(apiPodList.Items, []api.Event{}, dsQuery, metricsProvider)
 	return &podList, nil
 }
@@ -0,0 +1,164 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"k8s.io/kubernetes/pkg/api"
+	k8serrors "k8s.io/kubernetes/pkg/api/errors"
+	k8sClient "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
+)
+
+// Health of service endpoints, derived from readiness of their addresses.
+const (
+	// No address is ready, so requests to the service fail.
+	NoEndpoints = "NoEndpoints"
+
+	// Some of the addresses are ready.
+	EndpointsPartiallyReady = "PartiallyReady"
+
+	// All addresses are ready.
+	EndpointsReady = "Ready"
+)
+
+// EndpointSubset is a group of addresses of the service that share the same ports.
+type EndpointSubset struct {
+	// Addresses that are ready to serve requests. The service proxies requests only to them.
+	ReadyAddresses []EndpointAddress `json:"readyAddresses"`
+
+	// Addresses that are not ready yet, e.g., their pods are starting or failing readiness probes.
+	NotReadyAddresses []EndpointAddress `json:"notReadyAddresses"`
+
+	// Ports exposed by the addresses of the subset.
+	Ports []EndpointPort `json:"ports"`
+}
+
+// EndpointAddress is a single address the service proxies requests to.
+type EndpointAddress struct {
+	// IP address of the endpoint.
+	IP string `json:"ip"`
+
+	// Hostname of the endpoint, used by DNS of headless services.
+	Hostname string `json:"hostname,omitempty"`
+
+	// Name of the node hosting the endpoint.
+	NodeName string `json:"nodeName,omitempty"`
+
+	// Name of the pod behind the endpoint. Empty if the endpoint does not target a pod.
+	Pod string `json:"pod,omitempty"`
+}
+
+// EndpointPort is a mapping of a service port to a port of endpoint addresses.
+type EndpointPort struct {
+	// Name of the port. Empty if the service has only one port.
+	Name string `json:"name"`
+
+	// Port of the service. Zero when the endpoint port does not belong to any service port.
+	ServicePort int32 `json:"servicePort"`
+
+	// Port of endpoint addresses requests are forwarded to.
+	TargetPort int32 `json:"targetPort"`
+
+	// Protocol of the port.
+	Protocol api.Protocol `json:"protocol"`
+}
+
+// GetServiceEndpoints returns endpoint subsets of the given service. Services without the
+// endpoints object, e.g., the ones that were just created, have no endpoint subsets.
+func GetServiceEndpoints(client k8sClient.Interface, service *api.Service) ([]EndpointSubset,
+	error) {
+
+	endpoints, err := client.Core().Endpoints(service.Namespace).Get(service.Name)
+	if k8serrors.IsNotFound(err) {
+		return make([]EndpointSubset, 0), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ToEndpointSubsets(endpoints.Subsets, service.Spec.Ports), nil
+}
+
+// ToEndpointSubsets converts kubernetes endpoint subsets to endpoint subsets of the service with
+// the given ports.
+func ToEndpointSubsets(subsets []api.EndpointSubset,
+	servicePorts []api.ServicePort) []EndpointSubset {
+
+	result := make([]EndpointSubset, 0, len(subsets))
+	for _, subset := range subsets {
+		result = append(result, EndpointSubset{
+			ReadyAddresses:    toEndpointAddresses(subset.Addresses),
+			NotReadyAddresses: toEndpointAddresses(subset.NotReadyAddresses),
+			Ports:             toEndpointPorts(subset.Ports, servicePorts),
+		})
+	}
+	return result
+}
+
+// GetEndpointsHealth returns health of the endpoint subsets. It is one of: NoEndpoints,
+// PartiallyReady, Ready.
+func GetEndpointsHealth(subsets []EndpointSubset) string {
+	ready, notReady := 0, 0
+	for _, subset := range subsets {
+		ready += len(subset.ReadyAddresses)
+		notReady += len(subset.NotReadyAddresses)
+	}
+
+	switch {
+	case ready == 0:
+		return NoEndpoints
+	case notReady > 0:
+		return EndpointsPartiallyReady
+	default:
+		return EndpointsReady
+	}
+}
+
+func toEndpointAddresses(addresses []api.EndpointAddress) []EndpointAddress {
+	result := make([]EndpointAddress, 0, len(addresses))
+	for _, address := range addresses {
+		endpointAddress := EndpointAddress{
+			IP:       address.IP,
+			Hostname: address.Hostname,
+		}
+		if address.NodeName != nil {
+			endpointAddress.NodeName = *address.NodeName
+		}
+		if address.TargetRef != nil && address.TargetRef.Kind == "Pod" {
+			endpointAddress.Pod = address.TargetRef.Name
+		}
+		result = append(result, endpointAddress)
+	}
+	return result
+}
+
+// toEndpointPorts maps endpoint ports to service ports. The endpoints controller names endpoint
+// ports after the service ports they belong to.
+func toEndpointPorts(ports []api.EndpointPort, servicePorts []api.ServicePort) []EndpointPort {
+	result := make([]EndpointPort, 0, len(ports))
+	for _, port := range ports {
+		endpointPort := EndpointPort{
+			Name:       port.Name,
+			TargetPort: port.Port,
+			Protocol:   port.Protocol,
+		}
+		for _, servicePort := range servicePorts {
+			if servicePort.Name == port.Name && servicePort.Protocol == port.Protocol {
+				endpointPort.ServicePort = servicePort.Port
+				break
+			}
+		}
+		result = append(result, endpointPort)
+	}
+	return result
+}
@@ -57,12 +57,12 @@ type ServiceList struct {
 }
 
 // GetServiceList returns a list of all services in the cluster.
-func GetServiceList(client client.Interface, nsQuery *common.NamespaceQuery,
+func GetServiceList(client client.Interface, nsQuery *common.NamespaceQuery, listOptions api.ListOptions,
 	dsQuery *dataselect.DataSelectQuery) (*ServiceList, error) {
 	log.Printf("Getting list of all services in the cluster")
 
 	channels := &common.ResourceChannels{
-		ServiceList: common.GetServiceListChannel(client, nsQuery, 1),
+		ServiceList: common.GetServiceListChannelWithOptions(client, nsQuery, listOptions, 1),
 	}
 
 	return GetServiceListFromChannels(channels, dsQuery)
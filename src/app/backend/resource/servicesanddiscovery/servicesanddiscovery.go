@@ -21,6 +21,7 @@ import (
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/ingress"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/service"
+	"k8s.io/kubernetes/pkg/api"
 	k8sClient "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
 )
 
@@ -31,14 +32,15 @@ type ServicesAndDiscovery struct {
 	IngressList ingress.IngressList `json:"ingressList"`
 }
 
-// GetServicesAndDiscovery returns a list of all servicesAndDiscovery resources in the cluster.
-func GetServicesAndDiscovery(client *k8sClient.Clientset, nsQuery *common.NamespaceQuery) (
-	*ServicesAndDiscovery, error) {
+// GetServicesAndDiscovery returns a list of all servicesAndDiscovery resources in the cluster, that
+// match given list options.
+func GetServicesAndDiscovery(client *k8sClient.Clientset, nsQuery *common.NamespaceQuery,
+	listOptions api.ListOptions) (*ServicesAndDiscovery, error) {
 
 	log.Print("Getting servicesAndDiscovery category")
 	channels := &common.ResourceChannels{
-		ServiceList: common.GetServiceListChannel(client, nsQuery, 1),
-		IngressList: common.GetIngressListChannel(client, nsQuery, 1),
+		ServiceList: common.GetServiceListChannelWithOptions(client, nsQuery, listOptions, 1),
+		IngressList: common.GetIngressListChannelWithOptions(client, nsQuery, listOptions, 1),
 	}
 
 	return GetServicesAndDiscoveryFromChannels(channels)
@@ -19,6 +19,7 @@ import (
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/metric"
 	"k8s.io/kubernetes/pkg/apis/apps"
+	"k8s.io/kubernetes/pkg/runtime"
 )
 
 // The code below allows to perform complex data section on []apps.StatefulSet
@@ -33,6 +34,8 @@ func (self StatefulSetCell) GetProperty(name dataselect.PropertyName) dataselect
 		return dataselect.StdComparableTime(self.ObjectMeta.CreationTimestamp.Time)
 	case dataselect.NamespaceProperty:
 		return dataselect.StdComparableString(self.ObjectMeta.Namespace)
+	case dataselect.LabelsProperty:
+		return dataselect.StdComparableLabels(self.ObjectMeta.Labels)
 	default:
 		// if name is not supported then just return a constant dummy value, sort will have no effect.
 		return nil
@@ -63,3 +66,8 @@ func FromCells(cells []dataselect.DataCell) []apps.StatefulSet {
 	}
 	return std
 }
+
+func (self StatefulSetCell) GetObject() runtime.Object {
+	statefulSet := apps.StatefulSet(self)
+	return &statefulSet
+}
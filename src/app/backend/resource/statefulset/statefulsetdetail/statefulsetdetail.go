@@ -47,7 +47,7 @@ type StatefulSetDetail struct {
 }
 
 // GetStatefulSetDetail gets pet set details.
-func GetStatefulSetDetail(client *k8sClient.Clientset, heapsterClient client.HeapsterClient,
+func GetStatefulSetDetail(client *k8sClient.Clientset, metricsProvider client.MetricsProvider,
 	namespace, name string) (*StatefulSetDetail, error) {
 
 	log.Printf("Getting details of %s service in %s namespace", name, namespace)
@@ -58,7 +58,7 @@ func GetStatefulSetDetail(client *k8sClient.Clientset, heapsterClient client.Hea
 		return nil, err
 	}
 
-	podList, err := GetStatefulSetPods(client, heapsterClient, dataselect.DefaultDataSelectWithMetrics, name, namespace)
+	podList, err := GetStatefulSetPods(client, metricsProvider, dataselect.DefaultDataSelectWithMetrics, name, namespace)
 	if err != nil {
 		return nil, err
 	}
@@ -73,11 +73,11 @@ func GetStatefulSetDetail(client *k8sClient.Clientset, heapsterClient client.Hea
 		return nil, err
 	}
 
-	statefulSet := getStatefulSetDetail(statefulSetData, heapsterClient, *events, *podList, *podInfo)
+	statefulSet := getStatefulSetDetail(statefulSetData, metricsProvider, *events, *podList, *podInfo)
 	return &statefulSet, nil
 }
 
-func getStatefulSetDetail(statefulSet *apps.StatefulSet, heapsterClient client.HeapsterClient,
+func getStatefulSetDetail(statefulSet *apps.StatefulSet, metricsProvider client.MetricsProvider,
 	eventList common.EventList, podList pod.PodList, podInfo common.PodInfo) StatefulSetDetail {
 
 	return StatefulSetDetail{
@@ -28,7 +28,7 @@ import (
 )
 
 // GetStatefulSetPods return list of pods targeting pet set.
-func GetStatefulSetPods(client *k8sClient.Clientset, heapsterClient client.HeapsterClient,
+func GetStatefulSetPods(client *k8sClient.Clientset, metricsProvider client.MetricsProvider,
 	dsQuery *dataselect.DataSelectQuery, statefulSetName, namespace string) (*pod.PodList, error) {
 	log.Printf("Getting replication controller %s pods in namespace %s", statefulSetName, namespace)
 
@@ -37,7 +37,7 @@ func GetStatefulSetPods(client *k8sClient.Clientset, heapsterClient client.Heaps
 		return nil, err
 	}
 
-	podList := pod.CreatePodList(pods, []api.Event{}, dsQuery, heapsterClient)
+	podList := pod.CreatePodList(pods, []api.Event{}, dsQuery, metricsProvider)
 	return &podList, nil
 }
 
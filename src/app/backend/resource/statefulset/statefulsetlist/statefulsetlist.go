@@ -20,7 +20,7 @@ import (
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/event"
 
-	heapster "github.com/kubernetes/dashboard/src/app/backend/client"
+	kdClient "github.com/kubernetes/dashboard/src/app/backend/client"
 	"k8s.io/kubernetes/pkg/api"
 	k8serrors "k8s.io/kubernetes/pkg/api/errors"
 	"k8s.io/kubernetes/pkg/apis/apps"
@@ -55,22 +55,22 @@ type StatefulSet struct {
 }
 
 // GetStatefulSetList returns a list of all Pet Sets in the cluster.
-func GetStatefulSetList(client *client.Clientset, nsQuery *common.NamespaceQuery,
-	dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) (*StatefulSetList, error) {
+func GetStatefulSetList(client *client.Clientset, nsQuery *common.NamespaceQuery, listOptions api.ListOptions,
+	dsQuery *dataselect.DataSelectQuery, metricsProvider *kdClient.MetricsProvider) (*StatefulSetList, error) {
 	log.Print("Getting list of all pet sets in the cluster")
 
 	channels := &common.ResourceChannels{
-		StatefulSetList: common.GetStatefulSetListChannel(client, nsQuery, 1),
+		StatefulSetList: common.GetStatefulSetListChannelWithOptions(client, nsQuery, listOptions, 1),
 		PodList:         common.GetPodListChannel(client, nsQuery, 1),
 		EventList:       common.GetEventListChannel(client, nsQuery, 1),
 	}
 
-	return GetStatefulSetListFromChannels(channels, dsQuery, heapsterClient)
+	return GetStatefulSetListFromChannels(channels, dsQuery, metricsProvider)
 }
 
 // GetStatefulSetListFromChannels returns a list of all Pet Sets in the cluster
 // reading required resource list once from the channels.
-func GetStatefulSetListFromChannels(channels *common.ResourceChannels, dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) (
+func GetStatefulSetListFromChannels(channels *common.ResourceChannels, dsQuery *dataselect.DataSelectQuery, metricsProvider *kdClient.MetricsProvider) (
 	*StatefulSetList, error) {
 
 	statefulSets := <-channels.StatefulSetList.List
@@ -97,24 +97,24 @@ func GetStatefulSetListFromChannels(channels *common.ResourceChannels, dsQuery *
 		return nil, err
 	}
 
-	return CreateStatefulSetList(statefulSets.Items, pods.Items, events.Items, dsQuery, heapsterClient), nil
+	return CreateStatefulSetList(statefulSets.Items, pods.Items, events.Items, dsQuery, metricsProvider), nil
 }
 
 // CreateStatefulSetList creates paginated list of Pet Set model
 // objects based on Kubernetes Pet Set objects array and related resources arrays.
 func CreateStatefulSetList(statefulSets []apps.StatefulSet, pods []api.Pod, events []api.Event,
-	dsQuery *dataselect.DataSelectQuery, heapsterClient *heapster.HeapsterClient) *StatefulSetList {
+	dsQuery *dataselect.DataSelectQuery, metricsProvider *kdClient.MetricsProvider) *StatefulSetList {
 
 	statefulSetList := &StatefulSetList{
 		StatefulSets: make([]StatefulSet, 0),
-		ListMeta:     common.ListMeta{TotalItems: len(statefulSets)},
 	}
 
 	cachedResources := &dataselect.CachedResources{
 		Pods: pods,
 	}
-	replicationControllerCells, metricPromises := dataselect.GenericDataSelectWithMetrics(statefulset.ToCells(statefulSets), dsQuery, cachedResources, heapsterClient)
+	replicationControllerCells, metricPromises, filteredTotal := dataselect.GenericDataSelectWithFilterAndMetrics(statefulset.ToCells(statefulSets), dsQuery, cachedResources, metricsProvider)
 	statefulSets = statefulset.FromCells(replicationControllerCells)
+	statefulSetList.ListMeta = common.ListMeta{TotalItems: filteredTotal}
 
 	for _, statefulSet := range statefulSets {
 		matchingPods := common.FilterNamespacedPodsBySelector(pods, statefulSet.ObjectMeta.Namespace,
@@ -27,6 +27,7 @@ import (
 	"github.com/kubernetes/dashboard/src/app/backend/resource/replicaset/replicasetlist"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/replicationcontroller/replicationcontrollerlist"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/statefulset/statefulsetlist"
+	"k8s.io/kubernetes/pkg/api"
 	k8sClient "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
 )
 
@@ -47,30 +48,43 @@ type Workloads struct {
 	StatefulSetList statefulsetlist.StatefulSetList `json:"statefulSetList"`
 }
 
-// GetWorkloads returns a list of all workloads in the cluster.
-func GetWorkloads(client *k8sClient.Clientset, heapsterClient client.HeapsterClient,
-	nsQuery *common.NamespaceQuery, metricQuery *dataselect.MetricQuery) (*Workloads, error) {
+// GetWorkloads returns a list of all workloads in the cluster, that match given list options. The
+// options select listed objects only, pod statuses of workloads take all their pods into account.
+func GetWorkloads(client *k8sClient.Clientset, metricsProvider client.MetricsProvider,
+	nsQuery *common.NamespaceQuery, listOptions api.ListOptions, metricQuery *dataselect.MetricQuery) (
+	*Workloads, error) {
 
 	log.Print("Getting lists of all workloads")
 	channels := &common.ResourceChannels{
-		ReplicationControllerList: common.GetReplicationControllerListChannel(client, nsQuery, 1),
-		ReplicaSetList:            common.GetReplicaSetListChannel(client, nsQuery, 1),
-		JobList:                   common.GetJobListChannel(client, nsQuery, 1),
-		DaemonSetList:             common.GetDaemonSetListChannel(client, nsQuery, 1),
-		DeploymentList:            common.GetDeploymentListChannel(client, nsQuery, 1),
-		StatefulSetList:           common.GetStatefulSetListChannel(client, nsQuery, 1),
+		ReplicationControllerList: common.GetReplicationControllerListChannelWithOptions(client, nsQuery, listOptions, 1),
+		ReplicaSetList:            common.GetReplicaSetListChannelWithOptions(client, nsQuery, listOptions, 1),
+		JobList:                   common.GetJobListChannelWithOptions(client, nsQuery, listOptions, 1),
+		DaemonSetList:             common.GetDaemonSetListChannelWithOptions(client, nsQuery, listOptions, 1),
+		DeploymentList:            common.GetDeploymentListChannelWithOptions(client, nsQuery, listOptions, 1),
+		StatefulSetList:           common.GetStatefulSetListChannelWithOptions(client, nsQuery, listOptions, 1),
 		ServiceList:               common.GetServiceListChannel(client, nsQuery, 1),
-		PodList:                   common.GetPodListChannel(client, nsQuery, 7),
+		PodList:                   common.GetPodListChannel(client, nsQuery, 6),
 		EventList:                 common.GetEventListChannel(client, nsQuery, 7),
 	}
+	podChannels := &common.ResourceChannels{
+		PodList:   common.GetPodListChannelWithOptions(client, nsQuery, listOptions, 1),
+		EventList: channels.EventList,
+	}
 
-	return GetWorkloadsFromChannels(channels, heapsterClient, metricQuery)
+	return getWorkloadsFromChannels(channels, podChannels, metricsProvider, metricQuery)
 }
 
 // GetWorkloadsFromChannels returns a list of all workloads in the cluster, from the
 // channel sources.
 func GetWorkloadsFromChannels(channels *common.ResourceChannels,
-	heapsterClient client.HeapsterClient, metricQuery *dataselect.MetricQuery) (*Workloads, error) {
+	metricsProvider client.MetricsProvider, metricQuery *dataselect.MetricQuery) (*Workloads, error) {
+	return getWorkloadsFromChannels(channels, channels, metricsProvider, metricQuery)
+}
+
+// getWorkloadsFromChannels is GetWorkloadsFromChannels, that lists pods from podChannels. Pod
+// lists of channels are read only to get pod statuses of other workloads.
+func getWorkloadsFromChannels(channels *common.ResourceChannels, podChannels *common.ResourceChannels,
+	metricsProvider client.MetricsProvider, metricQuery *dataselect.MetricQuery) (*Workloads, error) {
 
 	rsChan := make(chan *replicasetlist.ReplicaSetList)
 	jobChan := make(chan *joblist.JobList)
@@ -107,9 +121,9 @@ func GetWorkloadsFromChannels(channels *common.ResourceChannels,
 	}()
 
 	go func() {
-		podList, err := pod.GetPodListFromChannels(channels,
+		podList, err := pod.GetPodListFromChannels(podChannels,
 			dataselect.NewDataSelectQuery(dataselect.DefaultPagination, dataselect.NoSort, dataselect.NoFilter, metricQuery),
-			heapsterClient)
+			metricsProvider)
 		errChan <- err
 		podChan <- podList
 	}()
@@ -0,0 +1,285 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/deployment"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/namespace"
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/resource"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/apis/extensions"
+	client "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
+	"k8s.io/kubernetes/pkg/util/validation/field"
+)
+
+// AppDeploymentValidity describes validity of the whole application deployment spec.
+type AppDeploymentValidity struct {
+	// True when the application can be deployed.
+	Valid bool `json:"valid"`
+
+	// Errors of the spec fields that do not pass validation.
+	Errors []FieldError `json:"errors"`
+}
+
+// FieldError is a validation error of a single field of the application deployment spec.
+type FieldError struct {
+	// Machine-readable type of the error, e.g., FieldValueDuplicate.
+	Type unversioned.CauseType `json:"type"`
+
+	// JSON path of the field, e.g., portMappings[0].port.
+	Field string `json:"field"`
+
+	// Human-readable description of the error.
+	Message string `json:"message"`
+}
+
+// ValidateAppDeployment validates the given application deployment spec before it is deployed.
+// Besides the checks done by deploy itself, it checks the spec against the target namespace:
+// whether objects with the same name as the ones deploy would create already exist, whether the
+// quotas of the namespace have enough room left and whether the referenced image pull secret,
+// config maps, secrets and claims exist. When error is returned, validity could not be
+// determined.
+func ValidateAppDeployment(spec *deployment.AppDeploymentSpec,
+	client client.Interface) (*AppDeploymentValidity, error) {
+	log.Printf("Validating deployment of %s application in %s namespace", spec.Name,
+		spec.Namespace)
+
+	allErrs := deployment.ValidateAppDeploymentSpec(spec)
+
+	deploymentObj, service, err := deployment.GetAppObjects(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	errs, err := validateNameCollisions(spec, service != nil, client)
+	if err != nil {
+		return nil, err
+	}
+	allErrs = append(allErrs, errs...)
+
+	errs, err = validateQuotas(spec, deploymentObj, service, client)
+	if err != nil {
+		return nil, err
+	}
+	allErrs = append(allErrs, errs...)
+
+	errs, err = validateReferences(spec, client)
+	if err != nil {
+		return nil, err
+	}
+	allErrs = append(allErrs, errs...)
+
+	validity := &AppDeploymentValidity{
+		Valid:  len(allErrs) == 0,
+		Errors: make([]FieldError, 0),
+	}
+	for _, err := range allErrs {
+		validity.Errors = append(validity.Errors, FieldError{
+			Type:    unversioned.CauseType(err.Type),
+			Field:   err.Field,
+			Message: err.ErrorBody(),
+		})
+	}
+
+	log.Printf("Validation result for deployment of %s application in %s namespace is %t",
+		spec.Name, spec.Namespace, validity.Valid)
+
+	return validity, nil
+}
+
+// Checks that no object with the name of the application exists for any kind that deploy would
+// create. The service is only created when the application has port mappings.
+func validateNameCollisions(spec *deployment.AppDeploymentSpec, hasService bool,
+	client client.Interface) (field.ErrorList, error) {
+	allErrs := field.ErrorList{}
+	if len(spec.Name) == 0 {
+		return allErrs, nil
+	}
+	namePath := field.NewPath("name")
+
+	_, err := client.Extensions().Deployments(spec.Namespace).Get(spec.Name)
+	if err == nil {
+		allErrs = append(allErrs, field.Invalid(namePath, spec.Name,
+			"a deployment with this name already exists"))
+	} else if !isNotFoundError(err) {
+		return nil, err
+	}
+
+	if hasService {
+		_, err = client.Core().Services(spec.Namespace).Get(spec.Name)
+		if err == nil {
+			allErrs = append(allErrs, field.Invalid(namePath, spec.Name,
+				"a service with this name already exists"))
+		} else if !isNotFoundError(err) {
+			return nil, err
+		}
+	}
+
+	return allErrs, nil
+}
+
+// Checks that the quotas of the namespace have room for the pods and the service of the
+// application. The room is taken from the usage that the quota controller has recorded. Also
+// checks that the application requests and limits every resource that quotas and limit ranges
+// demand from containers, as pods without them are rejected.
+func validateQuotas(spec *deployment.AppDeploymentSpec, deploymentObj *extensions.Deployment,
+	service *api.Service, client client.Interface) (field.ErrorList, error) {
+	allErrs := field.ErrorList{}
+
+	quotas, err := client.Core().ResourceQuotas(spec.Namespace).List(api.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	limitRanges, err := client.Core().LimitRanges(spec.Namespace).List(api.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	pod := api.Pod{Spec: deploymentObj.Spec.Template.Spec}
+	requests, limits, err := api.PodRequestsAndLimits(&pod)
+	if err != nil {
+		return nil, err
+	}
+	requested := map[api.ResourceName]resource.Quantity{
+		api.ResourcePods:           *resource.NewQuantity(int64(spec.Replicas), resource.DecimalSI),
+		api.ResourceCPU:            multiply(requests[api.ResourceCPU], spec.Replicas),
+		api.ResourceRequestsCPU:    multiply(requests[api.ResourceCPU], spec.Replicas),
+		api.ResourceMemory:         multiply(requests[api.ResourceMemory], spec.Replicas),
+		api.ResourceRequestsMemory: multiply(requests[api.ResourceMemory], spec.Replicas),
+		api.ResourceLimitsCPU:      multiply(limits[api.ResourceCPU], spec.Replicas),
+		api.ResourceLimitsMemory:   multiply(limits[api.ResourceMemory], spec.Replicas),
+	}
+	if service != nil {
+		requested[api.ResourceServices] = *resource.NewQuantity(1, resource.DecimalSI)
+		if service.Spec.Type == api.ServiceTypeLoadBalancer {
+			requested[api.ResourceServicesLoadBalancers] =
+				*resource.NewQuantity(1, resource.DecimalSI)
+		}
+	}
+
+	scopedQuotas := make([]api.ResourceQuota, 0)
+	for _, quota := range quotas.Items {
+		if !namespace.MatchesQuotaScopes(pod, quota.Spec.Scopes) {
+			continue
+		}
+		scopedQuotas = append(scopedQuotas, quota)
+		for _, name := range quotaResourceNames {
+			hard, ok := quota.Spec.Hard[name]
+			request, isRequested := requested[name]
+			if !ok || !isRequested || request.IsZero() {
+				continue
+			}
+			used := quota.Status.Used[name]
+			total := used.Copy()
+			total.Add(request)
+			if total.Cmp(hard) > 0 {
+				allErrs = append(allErrs, field.Forbidden(quotaFieldPaths[name], fmt.Sprintf(
+					"exceeds quota %s, requested: %s=%s, used: %s=%s, limited: %s=%s",
+					quota.Name, name, request.String(), name, used.String(), name,
+					hard.String())))
+			}
+		}
+	}
+
+	demanded := namespace.GetDemandedResources(scopedQuotas, limitRanges.Items)
+	for _, name := range namespace.GetMissingResources(pod, demanded) {
+		allErrs = append(allErrs, field.Required(quotaFieldPaths[name], fmt.Sprintf(
+			"%s must be set, as quotas or limit ranges of the namespace require it", name)))
+	}
+
+	return allErrs, nil
+}
+
+// Resources of quotas that deploy can exceed, in the order in which they are checked.
+var quotaResourceNames = []api.ResourceName{
+	api.ResourcePods,
+	api.ResourceCPU,
+	api.ResourceRequestsCPU,
+	api.ResourceMemory,
+	api.ResourceRequestsMemory,
+	api.ResourceLimitsCPU,
+	api.ResourceLimitsMemory,
+	api.ResourceServices,
+	api.ResourceServicesLoadBalancers,
+}
+
+// Paths of the spec fields that make deploy request the quota resources.
+var quotaFieldPaths = map[api.ResourceName]*field.Path{
+	api.ResourcePods:                  field.NewPath("replicas"),
+	api.ResourceCPU:                   field.NewPath("cpuRequirement"),
+	api.ResourceRequestsCPU:           field.NewPath("cpuRequirement"),
+	api.ResourceMemory:                field.NewPath("memoryRequirement"),
+	api.ResourceRequestsMemory:        field.NewPath("memoryRequirement"),
+	api.ResourceLimitsCPU:             field.NewPath("cpuLimit"),
+	api.ResourceLimitsMemory:          field.NewPath("memoryLimit"),
+	api.ResourceServices:              field.NewPath("portMappings"),
+	api.ResourceServicesLoadBalancers: field.NewPath("isExternal"),
+}
+
+// Returns the given quantity multiplied by the given number of replicas.
+func multiply(quantity resource.Quantity, replicas int32) resource.Quantity {
+	return *resource.NewMilliQuantity(quantity.MilliValue()*int64(replicas), quantity.Format)
+}
+
+// Checks that the image pull secret and the sources of the volumes of the application exist in
+// the namespace.
+func validateReferences(spec *deployment.AppDeploymentSpec,
+	client client.Interface) (field.ErrorList, error) {
+	allErrs := field.ErrorList{}
+
+	if spec.ImagePullSecret != nil {
+		fldPath := field.NewPath("imagePullSecret")
+		secret, err := client.Core().Secrets(spec.Namespace).Get(*spec.ImagePullSecret)
+		if isNotFoundError(err) {
+			allErrs = append(allErrs, field.NotFound(fldPath, *spec.ImagePullSecret))
+		} else if err != nil {
+			return nil, err
+		} else if secret.Type != api.SecretTypeDockercfg &&
+			secret.Type != api.SecretTypeDockerConfigJson {
+			allErrs = append(allErrs, field.Invalid(fldPath, *spec.ImagePullSecret,
+				fmt.Sprintf("must be a secret of type %s or %s, but is %s",
+					api.SecretTypeDockercfg, api.SecretTypeDockerConfigJson, secret.Type)))
+		}
+	}
+
+	for i, volume := range spec.Volumes {
+		if len(volume.SourceName) == 0 {
+			continue
+		}
+		var err error
+		switch volume.Type {
+		case deployment.ConfigMapVolume:
+			_, err = client.Core().ConfigMaps(spec.Namespace).Get(volume.SourceName)
+		case deployment.SecretVolume:
+			_, err = client.Core().Secrets(spec.Namespace).Get(volume.SourceName)
+		case deployment.PersistentVolumeClaimVolume:
+			_, err = client.Core().PersistentVolumeClaims(spec.Namespace).Get(volume.SourceName)
+		default:
+			continue
+		}
+		if isNotFoundError(err) {
+			allErrs = append(allErrs, field.NotFound(
+				field.NewPath("volumes").Index(i).Child("sourceName"), volume.SourceName))
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	return allErrs, nil
+}
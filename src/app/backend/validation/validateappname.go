@@ -43,6 +43,7 @@ func ValidateAppName(spec *AppNameValiditySpec, client client.Interface) (*AppNa
 
 	isValidRc := false
 	isValidService := false
+	isValidDeployment := false
 
 	_, err := client.Core().ReplicationControllers(spec.Namespace).Get(spec.Name)
 	if err != nil {
@@ -62,7 +63,16 @@ func ValidateAppName(spec *AppNameValiditySpec, client client.Interface) (*AppNa
 		}
 	}
 
-	isValid := isValidRc && isValidService
+	_, err = client.Extensions().Deployments(spec.Namespace).Get(spec.Name)
+	if err != nil {
+		if isNotFoundError(err) {
+			isValidDeployment = true
+		} else {
+			return nil, err
+		}
+	}
+
+	isValid := isValidRc && isValidService && isValidDeployment
 
 	log.Printf("Validation result for %s application name in %s namespace is %t", spec.Name,
 		spec.Namespace, isValid)
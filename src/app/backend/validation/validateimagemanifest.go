@@ -0,0 +1,124 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"fmt"
+	"log"
+
+	distreference "github.com/docker/distribution/reference"
+	kdClient "github.com/kubernetes/dashboard/src/app/backend/client"
+	k8serrors "k8s.io/kubernetes/pkg/api/errors"
+	client "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
+)
+
+// ImageLookupSpec is a specification of a request that looks up an image in its registry.
+type ImageLookupSpec struct {
+	// Reference of the image.
+	Reference string `json:"reference"`
+
+	// Namespace of the image pull secret.
+	Namespace string `json:"namespace"`
+
+	// Name of the image pull secret with credentials of the registry. Anonymous requests are
+	// made when it is not set.
+	ImagePullSecret *string `json:"imagePullSecret"`
+}
+
+// ImageManifestValidity describes whether the registry has a manifest of the image.
+type ImageManifestValidity struct {
+	// True when the registry has a manifest of the image.
+	Valid bool `json:"valid"`
+
+	// Digest of the manifest, when it exists.
+	Digest string `json:"digest"`
+
+	// Error reason when the manifest does not exist or can not be accessed.
+	Reason string `json:"reason"`
+}
+
+// ImageTags is a list of tags of an image repository.
+type ImageTags struct {
+	// Sorted tags of the repository.
+	Tags []string `json:"tags"`
+}
+
+// ValidateImageManifest validates image reference by looking up its manifest in the registry.
+// When error is returned, validity could not be determined.
+func ValidateImageManifest(spec *ImageLookupSpec, client client.Interface,
+	registryClient kdClient.RegistryClient) (*ImageManifestValidity, error) {
+	log.Printf("Validating manifest of %s image", spec.Reference)
+
+	named, err := distreference.ParseNamed(spec.Reference)
+	if err != nil {
+		return &ImageManifestValidity{Valid: false, Reason: err.Error()}, nil
+	}
+
+	credentials, err := getRegistryCredentials(spec, named, client)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := registryClient.GetManifest(spec.Reference, credentials)
+	if err == kdClient.ErrRegistryUnauthorized {
+		return &ImageManifestValidity{Valid: false, Reason: err.Error()}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if !manifest.Exists {
+		reason := fmt.Sprintf("manifest of %s was not found in the registry", spec.Reference)
+		return &ImageManifestValidity{Valid: false, Reason: reason}, nil
+	}
+	return &ImageManifestValidity{Valid: true, Digest: manifest.Digest}, nil
+}
+
+// GetImageTags returns tags of the repository of the image from its registry.
+func GetImageTags(spec *ImageLookupSpec, client client.Interface,
+	registryClient kdClient.RegistryClient) (*ImageTags, error) {
+	log.Printf("Getting tags of %s image", spec.Reference)
+
+	named, err := distreference.ParseNamed(spec.Reference)
+	if err != nil {
+		return nil, k8serrors.NewBadRequest(err.Error())
+	}
+
+	credentials, err := getRegistryCredentials(spec, named, client)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := registryClient.ListTags(spec.Reference, credentials)
+	if err != nil {
+		return nil, err
+	}
+	return &ImageTags{Tags: tags}, nil
+}
+
+// Returns credentials of the registry of the image from the image pull secret of the spec, or nil
+// when the spec has no image pull secret.
+func getRegistryCredentials(spec *ImageLookupSpec, named distreference.Named,
+	client client.Interface) (*kdClient.RegistryCredentials, error) {
+	if spec.ImagePullSecret == nil {
+		return nil, nil
+	}
+
+	secret, err := client.Core().Secrets(spec.Namespace).Get(*spec.ImagePullSecret)
+	if err != nil {
+		return nil, err
+	}
+	host, _ := kdClient.SplitImageName(named.Name())
+	return kdClient.GetRegistryCredentials(secret, host)
+}
@@ -41,6 +41,26 @@ backendApi.PaginationQuery;
  */
 backendApi.ListMeta;
 
+/**
+ * @typedef {{
+ *   type: string,
+ *   message: string,
+ *   field: (string|undefined)
+ * }}
+ */
+backendApi.APIErrorCause;
+
+/**
+ * @typedef {{
+ *   code: number,
+ *   reason: string,
+ *   message: string,
+ *   causes: (!Array<!backendApi.APIErrorCause>|undefined),
+ *   retryAfterSeconds: (number|undefined)
+ * }}
+ */
+backendApi.APIError;
+
 /**
  * @typedef {{
  *   port: (number|null),
@@ -81,10 +101,81 @@ backendApi.Label;
  *   memoryRequirement: ?string,
  *   cpuRequirement: ?number,
  *   runAsPrivileged: boolean,
+ *   memoryLimit: (?string|undefined),
+ *   cpuLimit: (?number|undefined),
+ *   livenessProbe: (?backendApi.ProbeSpec|undefined),
+ *   readinessProbe: (?backendApi.ProbeSpec|undefined),
+ *   volumes: (!Array<!backendApi.VolumeSpec>|undefined),
+ *   volumeMounts: (!Array<!backendApi.VolumeMountSpec>|undefined),
+ *   initContainers: (!Array<!backendApi.ContainerSpec>|undefined),
+ *   sidecars: (!Array<!backendApi.ContainerSpec>|undefined),
+ *   nodeSelector: (!Array<!backendApi.Label>|undefined),
+ *   tolerations: (!Array<!backendApi.Toleration>|undefined),
  * }}
  */
 backendApi.AppDeploymentSpec;
 
+/**
+ * @typedef {{
+ *   type: string,
+ *   path: string,
+ *   port: number,
+ *   command: !Array<string>,
+ *   initialDelaySeconds: number,
+ *   periodSeconds: number,
+ *   timeoutSeconds: number,
+ *   failureThreshold: number
+ * }}
+ */
+backendApi.ProbeSpec;
+
+/**
+ * @typedef {{
+ *   name: string,
+ *   type: string,
+ *   sourceName: string,
+ *   readOnly: boolean
+ * }}
+ */
+backendApi.VolumeSpec;
+
+/**
+ * @typedef {{
+ *   name: string,
+ *   mountPath: string,
+ *   readOnly: boolean
+ * }}
+ */
+backendApi.VolumeMountSpec;
+
+/**
+ * @typedef {{
+ *   name: string,
+ *   image: string,
+ *   command: !Array<string>,
+ *   args: !Array<string>,
+ *   variables: !Array<!backendApi.EnvironmentVariable>,
+ *   memoryRequirement: ?string,
+ *   cpuRequirement: ?number,
+ *   memoryLimit: ?string,
+ *   cpuLimit: ?number,
+ *   livenessProbe: ?backendApi.ProbeSpec,
+ *   readinessProbe: ?backendApi.ProbeSpec,
+ *   volumeMounts: !Array<!backendApi.VolumeMountSpec>
+ * }}
+ */
+backendApi.ContainerSpec;
+
+/**
+ * @typedef {{
+ *   key: string,
+ *   operator: string,
+ *   value: string,
+ *   effect: string
+ * }}
+ */
+backendApi.Toleration;
+
 /**
  * @typedef {{
  *   name: string,
@@ -94,6 +185,51 @@ backendApi.AppDeploymentSpec;
  */
 backendApi.AppDeploymentFromFileSpec;
 
+/**
+ * @typedef {{
+ *   path: string,
+ *   operation: string,
+ *   live: (?|undefined),
+ *   submitted: (?|undefined)
+ * }}
+ */
+backendApi.FieldDiff;
+
+/**
+ * @typedef {{
+ *   kind: string,
+ *   name: string,
+ *   namespace: string,
+ *   action: string,
+ *   diff: ?Array<!backendApi.FieldDiff>,
+ *   error: string
+ * }}
+ */
+backendApi.DeployedObjectStatus;
+
+/**
+ * @typedef {{
+ *   name: string,
+ *   namespace: string,
+ *   error: string,
+ *   rolledBack: boolean,
+ *   objects: !Array<!backendApi.DeployedObjectStatus>
+ * }}
+ */
+backendApi.AppDeploymentResponse;
+
+/**
+ * @typedef {{
+ *   name: string,
+ *   content: string,
+ *   error: string,
+ *   dryRun: boolean,
+ *   rolledBack: boolean,
+ *   objects: !Array<!backendApi.DeployedObjectStatus>
+ * }}
+ */
+backendApi.AppDeploymentFromFileResponse;
+
 /**
  * @typedef {{
  *   events: !Array<!backendApi.Event>,
@@ -319,6 +455,7 @@ backendApi.ConfigMap;
  *   objectMeta: !backendApi.ObjectMeta,
  *   typeMeta: !backendApi.TypeMeta,
  *   data: !Object<string, string>,
+ *   resourceVersion: string
  * }}
  */
 backendApi.ConfigMapDetail;
@@ -923,6 +1060,48 @@ backendApi.AppNameValiditySpec;
  */
 backendApi.AppNameValidity;
 
+/**
+ * @typedef {{
+ *   type: string,
+ *   field: string,
+ *   message: string
+ * }}
+ */
+backendApi.FieldError;
+
+/**
+ * @typedef {{
+ *   valid: boolean,
+ *   errors: !Array<!backendApi.FieldError>
+ * }}
+ */
+backendApi.AppDeploymentValidity;
+
+/**
+ * @typedef {{
+ *   reference: string,
+ *   namespace: string,
+ *   imagePullSecret: ?string
+ * }}
+ */
+backendApi.ImageLookupSpec;
+
+/**
+ * @typedef {{
+ *   valid: boolean,
+ *   digest: string,
+ *   reason: string
+ * }}
+ */
+backendApi.ImageManifestValidity;
+
+/**
+ * @typedef {{
+ *   tags: !Array<string>
+ * }}
+ */
+backendApi.ImageTags;
+
 /**
  * @typedef {{
  *   reference: string
@@ -994,19 +1173,77 @@ backendApi.NamespaceList;
  *   eventList: !backendApi.EventList,
  *   resourceLimits: Array<!backendApi.LimitRange>,
  *   resourceQuotaList: !backendApi.ResourceQuotaDetailList,
+ *   usage: !backendApi.NamespaceUsage
  * }}
  */
 backendApi.NamespaceDetail;
 
+/**
+ * @typedef {{
+ *   requests: !Object<string, string>,
+ *   limits: !Object<string, string>,
+ *   cpuUsage: ?number,
+ *   memoryUsage: ?number,
+ *   quotas: !Array<!backendApi.QuotaUsage>,
+ *   podsWithoutRequests: !Array<!backendApi.PodWithoutRequests>
+ * }}
+ */
+backendApi.NamespaceUsage;
+
+/**
+ * @typedef {{
+ *   name: string,
+ *   scopes: (!Array<string>|undefined),
+ *   resources: !Array<!backendApi.QuotaResourceUsage>
+ * }}
+ */
+backendApi.QuotaUsage;
+
+/**
+ * @typedef {{
+ *   name: string,
+ *   hard: string,
+ *   used: string,
+ *   percentage: number
+ * }}
+ */
+backendApi.QuotaResourceUsage;
+
+/**
+ * @typedef {{
+ *   name: string,
+ *   missingResources: !Array<string>
+ * }}
+ */
+backendApi.PodWithoutRequests;
+
 /**
  * @typedef {{
  *   objectMeta: !backendApi.ObjectMeta,
  *   typeMeta: !backendApi.TypeMeta,
- *   data: !Object<string, string>,
+ *   keys: !Array<!backendApi.SecretKey>,
+ *   type: string
  * }}
  */
 backendApi.SecretDetail;
 
+/**
+ * @typedef {{
+ *   name: string,
+ *   size: number
+ * }}
+ */
+backendApi.SecretKey;
+
+/**
+ * @typedef {{
+ *   key: string,
+ *   value: string,
+ *   base64: boolean
+ * }}
+ */
+backendApi.SecretValue;
+
 /**
  * @typedef {{
  *   objectMeta: !backendApi.ObjectMeta,
@@ -1117,6 +1354,46 @@ backendApi.NodeDetail;
  */
 backendApi.NodeList;
 
+/**
+ * @typedef {{
+ *   objectMeta: !backendApi.ObjectMeta,
+ *   typeMeta: !backendApi.TypeMeta,
+ *   ready: string,
+ *   unschedulable: boolean
+ * }}
+ */
+backendApi.Node;
+
+/**
+ * @typedef {{
+ *   gracePeriodSeconds: ?number,
+ *   force: boolean,
+ *   ignoreDaemonSets: boolean,
+ *   deleteLocalData: boolean,
+ *   timeoutSeconds: number
+ * }}
+ */
+backendApi.DrainSpec;
+
+/**
+ * @typedef {{
+ *   name: string,
+ *   namespace: string,
+ *   status: string,
+ *   reason: (string|undefined)
+ * }}
+ */
+backendApi.DrainPodStatus;
+
+/**
+ * @typedef {{
+ *   nodeName: string,
+ *   complete: boolean,
+ *   pods: !Array<!backendApi.DrainPodStatus>
+ * }}
+ */
+backendApi.DrainResult;
+
 /**
  * @typedef {{
  *   objectMeta: !backendApi.ObjectMeta,
@@ -12,6 +12,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+import {getErrorMessage} from 'common/errorhandling/apierror';
+
 /**
  * @final
  */
@@ -121,7 +123,8 @@ export class ResourceCardListPaginationController {
           this.resourceCardListCtrl.setPending(false);
         },
         (err) => {
-          this.errorDialog_.open(this.i18n.MSG_RESOURCE_CARD_LIST_PAGINATION_ERROR, err.data);
+          this.errorDialog_.open(
+              this.i18n.MSG_RESOURCE_CARD_LIST_PAGINATION_ERROR, getErrorMessage(err));
           this.resourceCardListCtrl.setPending(false);
         });
   }
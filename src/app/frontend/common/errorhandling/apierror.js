@@ -0,0 +1,30 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/**
+ * Returns human-readable message of the given error response. Backend API errors are
+ * backendApi.APIError objects, other responses may carry plain text.
+ *
+ * @param {?angular.$http.Response} err
+ * @return {string}
+ */
+export function getErrorMessage(err) {
+  if (!err || !err.data) {
+    return '';
+  }
+  if (angular.isString(err.data)) {
+    return err.data;
+  }
+  return /** @type {!backendApi.APIError} */ (err.data).message || '';
+}
@@ -12,6 +12,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+import {getErrorMessage} from 'common/errorhandling/apierror';
+
 import showDeleteDialog from './deleteresource_dialog';
 import showEditDialog from './editresource_dialog';
 
@@ -91,7 +93,8 @@ export class VerberService {
       this.mdDialog_.show(this.mdDialog_.alert()
                               .ok('Ok')
                               .title(err.statusText || 'Internal server error')
-                              .textContent(err.data || 'Could not delete the resource'));
+                              .textContent(getErrorMessage(err) ||
+                                           'Could not delete the resource'));
     }
   }
 
@@ -106,7 +109,8 @@ export class VerberService {
       this.mdDialog_.show(this.mdDialog_.alert()
                               .ok('Ok')
                               .title(err.statusText || 'Internal server error')
-                              .textContent(err.data || 'Could not edit the resource'));
+                              .textContent(getErrorMessage(err) ||
+                                           'Could not edit the resource'));
     }
   }
 }
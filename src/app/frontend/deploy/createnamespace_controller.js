@@ -12,6 +12,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+import {getErrorMessage} from 'common/errorhandling/apierror';
+
 /**
  * Namespace creation dialog controller.
  *
@@ -106,7 +108,7 @@ export default class NamespaceDialogController {
         },
         (err) => {
           this.mdDialog_.hide();
-          this.errorDialog_.open('Error creating namespace', err.data);
+          this.errorDialog_.open('Error creating namespace', getErrorMessage(err));
           this.log_.info('Error creating namespace:', err);
         });
   }
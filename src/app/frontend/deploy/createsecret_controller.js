@@ -12,6 +12,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+import {getErrorMessage} from 'common/errorhandling/apierror';
+
 /**
  * Secret creation dialog controller.
  *
@@ -114,7 +116,7 @@ export default class CreateSecretController {
         },
         (err) => {
           this.mdDialog_.hide();
-          this.errorDialog_.open('Error creating secret', err.data);
+          this.errorDialog_.open('Error creating secret', getErrorMessage(err));
           this.log_.info('Error creating secret:', err);
         });
   }
@@ -12,6 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+import {getErrorMessage} from 'common/errorhandling/apierror';
 import {stateName as workloads} from 'workloads/workloads_state';
 
 import showDeployAnywayDialog from './deployanyway_dialog';
@@ -105,11 +106,18 @@ export default class DeployFromFileController {
           },
           (err) => {
             defer.reject(err);  // Progress ends
-            if (this.hasValidationError_(err.data)) {
-              this.handleDeployAnywayDialog_(err.data);
+            // Deployments that have been rolled back are answered with status of every object.
+            if (err.data && err.data.rolledBack) {
+              this.log_.error('Deployment has been rolled back:', err.data);
+              this.errorDialog_.open(this.i18n.MSG_DEPLOY_ROLLED_BACK_ERROR, err.data.error);
+              return;
+            }
+            let message = getErrorMessage(err);
+            if (this.hasValidationError_(message)) {
+              this.handleDeployAnywayDialog_(message);
             } else {
               this.log_.error('Error deploying application:', err);
-              this.errorDialog_.open(this.i18n.MSG_DEPLOY_DIALOG_ERROR, err.data);
+              this.errorDialog_.open(this.i18n.MSG_DEPLOY_DIALOG_ERROR, message);
             }
           });
       defer.promise.finally(() => {
@@ -178,4 +186,8 @@ const i18n = {
 
   /** @export {string} @desc Text shown on failed deploy in error dialog. */
   MSG_DEPLOY_DIALOG_ERROR: goog.getMsg('Deploying file has failed'),
+
+  /** @export {string} @desc Text shown in error dialog when an object from the file could not be
+     deployed, so that the objects created before it have been deleted again. */
+  MSG_DEPLOY_ROLLED_BACK_ERROR: goog.getMsg('Deploying file has failed and has been rolled back'),
 };
@@ -43,11 +43,12 @@ export default class DeployFromSettingsController {
    * @param {!./../chrome/chrome_state.StateParams} $stateParams
    * @param {!./../common/history/history_service.HistoryService} kdHistoryService
    * @param {!./../common/namespace/namespace_service.NamespaceService} kdNamespaceService
+   * @param {!./../common/errorhandling/errordialog_service.ErrorDialog} errorDialog
    * @ngInject
    */
   constructor(
       namespaces, protocols, $log, $state, $resource, $q, $mdDialog, $stateParams, kdHistoryService,
-      kdNamespaceService) {
+      kdNamespaceService, errorDialog) {
     /**
      * Initialized from the template.
      * @export {!angular.FormController}
@@ -176,6 +177,9 @@ export default class DeployFromSettingsController {
     /** @private {!md.$dialog} */
     this.mdDialog_ = $mdDialog;
 
+    /** @private {!./../common/errorhandling/errordialog_service.ErrorDialog} */
+    this.errorDialog_ = errorDialog;
+
     /** @private {!./../common/history/history_service.HistoryService} */
     this.kdHistoryService_ = kdHistoryService;
 
@@ -240,14 +244,24 @@ export default class DeployFromSettingsController {
       this.isDeployInProgress_ = true;
       resource.save(
           appDeploymentSpec,
-          (savedConfig) => {
-            defer.resolve(savedConfig);  // Progress ends
-            this.log_.info('Successfully deployed application: ', savedConfig);
+          (response) => {
+            defer.resolve(response);  // Progress ends
+            if (response.error.length > 0) {
+              this.log_.error('Error deploying application:', response);
+              this.errorDialog_.open(this.i18n.MSG_DEPLOY_SETTINGS_PARTIAL_ERROR, response.error);
+              return;
+            }
+            this.log_.info('Successfully deployed application: ', response);
             this.state_.go(workloads);
           },
           (err) => {
             defer.reject(err);  // Progress ends
             this.log_.error('Error deploying application:', err);
+            // Deployments that have been rolled back are answered with status of every object.
+            if (err.data && err.data.rolledBack) {
+              this.errorDialog_.open(
+                  this.i18n.MSG_DEPLOY_SETTINGS_ROLLED_BACK_ERROR, err.data.error);
+            }
           });
       defer.promise.finally(() => {
         this.isDeployInProgress_ = false;
@@ -430,6 +444,14 @@ export default class DeployFromSettingsController {
 }
 
 const i18n = {
+  /** @export {string} @desc Title of the error dialog shown when a part of the application could
+     not be deployed, so that the already created objects have been deleted again. */
+  MSG_DEPLOY_SETTINGS_ROLLED_BACK_ERROR:
+      goog.getMsg('Deploying application has failed and has been rolled back'),
+
+  /** @export {string} @desc Title of the error dialog shown when a part of the application could
+     not be deployed and the already created objects could not be deleted again. */
+  MSG_DEPLOY_SETTINGS_PARTIAL_ERROR: goog.getMsg('Application has been partly deployed'),
 
   /** @export {string} @desc Appears when the typed in app name on the deploy from settings page
      exceeds the maximal allowed length. */
@@ -12,6 +12,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+import {getErrorMessage} from 'common/errorhandling/apierror';
+
 /** The name of this directive. */
 export const validImageReferenceValidationKey = 'validImageReference';
 
@@ -71,7 +73,7 @@ function validate(reference, scope, resource, q) {
         }
       },
       (err) => {
-        scope[invalidImageErrorMessage] = err.data;
+        scope[invalidImageErrorMessage] = getErrorMessage(err);
         deferred.reject();
       });
 
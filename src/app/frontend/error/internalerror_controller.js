@@ -12,6 +12,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+import {getErrorMessage} from 'common/errorhandling/apierror';
+
 /**
  * @final
  */
@@ -52,8 +54,9 @@ export class InternalErrorController {
      * @return {string}
      */
   getErrorData() {
-    if (this.error && this.error.data && this.error.data.length > 0) {
-      return this.error.data;
+    let message = getErrorMessage(this.error);
+    if (message.length > 0) {
+      return message;
     }
     return this.i18n.MSG_NO_ERROR_DATA;
   }
@@ -18,23 +18,46 @@
 export class SecretDetailController {
   /**
    * @param {!backendApi.SecretDetail} secretDetail
-   * @param {!angular.$window} $window
+   * @param {!angular.$resource} $resource
    * @ngInject
    */
-  constructor(secretDetail, $window) {
+  constructor(secretDetail, $resource) {
     /** @export {!backendApi.SecretDetail} */
     this.secretDetail = secretDetail;
 
-    /** @private {!angular.$window} */
-    this.window_ = $window;
+    /**
+     * Revealed values of the secret keys. Values are fetched only when they are shown.
+     * @export {!Object<string, string>}
+     */
+    this.values = {};
+
+    /** @export {!Object<string, boolean>} */
+    this.revealed = {};
+
+    /** @private {!angular.$resource} */
+    this.resource_ = $resource;
   }
 
   /**
-   * @param {string} valueB64
-   * @return {string}
+   * Shows or hides value of the secret key. Fetches the value when it is shown for the first time.
+   *
+   * @param {string} key
    * @export
    */
-  formatDataValue(valueB64) {
-    return this.window_.atob(valueB64);
+  toggleValue(key) {
+    if (this.values.hasOwnProperty(key)) {
+      this.revealed[key] = !this.revealed[key];
+      return;
+    }
+
+    let namespace = this.secretDetail.objectMeta.namespace;
+    let name = this.secretDetail.objectMeta.name;
+    /** @type {!angular.Resource<!backendApi.SecretValue>} */
+    let resource = this.resource_(
+        `api/v1/secret/${namespace}/${name}/key/${encodeURIComponent(key)}`);
+    resource.get().$promise.then((value) => {
+      this.values[key] = value.value;
+      this.revealed[key] = true;
+    });
   }
 }
@@ -0,0 +1,177 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cert
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/kubernetes/pkg/api"
+	k8serrors "k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset/fake"
+	"k8s.io/kubernetes/pkg/client/testing/core"
+	"k8s.io/kubernetes/pkg/runtime"
+)
+
+func TestGenerateSelfSignedCertificate(t *testing.T) {
+	certPEM, keyPEM, err := GenerateSelfSignedCertificate([]string{"localhost", "127.0.0.1"},
+		time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateSelfSignedCertificate() returned unexpected error: %v", err)
+	}
+	if _, err := tls.X509KeyPair(certPEM, keyPEM); err != nil {
+		t.Fatalf("Generated certificate and key do not match: %v", err)
+	}
+
+	block, _ := pem.Decode(certPEM)
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(certificate.DNSNames, []string{"localhost"}) ||
+		len(certificate.IPAddresses) != 1 || certificate.IPAddresses[0].String() != "127.0.0.1" {
+		t.Errorf("Unexpected hosts of certificate: %v, %v", certificate.DNSNames,
+			certificate.IPAddresses)
+	}
+	if certificate.NotAfter.After(time.Now().Add(time.Hour)) {
+		t.Errorf("Certificate valid until %v, expected at most an hour", certificate.NotAfter)
+	}
+}
+
+func TestGetOrCreateCertificateSecret(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset()
+	certPEM, keyPEM, err := GetOrCreateCertificateSecret(fakeClient, "kube-system", "certs",
+		[]string{"localhost"})
+	if err != nil {
+		t.Fatalf("GetOrCreateCertificateSecret() returned unexpected error: %v", err)
+	}
+	secret, err := fakeClient.Core().Secrets("kube-system").Get("certs")
+	if err != nil {
+		t.Fatalf("Expected certificate secret to be created: %v", err)
+	}
+	if secret.Type != api.SecretTypeTLS ||
+		!reflect.DeepEqual(secret.Data[api.TLSCertKey], certPEM) ||
+		!reflect.DeepEqual(secret.Data[api.TLSPrivateKeyKey], keyPEM) {
+		t.Errorf("Unexpected certificate secret: %#v", secret)
+	}
+
+	// Valid certificate is reused.
+	reusedCertPEM, _, err := GetOrCreateCertificateSecret(fakeClient, "kube-system", "certs",
+		[]string{"localhost"})
+	if err != nil || !reflect.DeepEqual(reusedCertPEM, certPEM) {
+		t.Errorf("Expected certificate to be reused, got error %v", err)
+	}
+
+	// Certificate that expires soon is regenerated.
+	expiringCertPEM, expiringKeyPEM, err := GenerateSelfSignedCertificate(
+		[]string{"localhost"}, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret.Data = map[string][]byte{api.TLSCertKey: expiringCertPEM,
+		api.TLSPrivateKeyKey: expiringKeyPEM}
+	fakeClient = fake.NewSimpleClientset(secret)
+	renewedCertPEM, _, err := GetOrCreateCertificateSecret(fakeClient, "kube-system", "certs",
+		[]string{"localhost"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reflect.DeepEqual(renewedCertPEM, expiringCertPEM) {
+		t.Error("Expected expiring certificate to be regenerated")
+	}
+	secret, _ = fakeClient.Core().Secrets("kube-system").Get("certs")
+	if !reflect.DeepEqual(secret.Data[api.TLSCertKey], renewedCertPEM) {
+		t.Error("Expected certificate secret to be updated with regenerated certificate")
+	}
+}
+
+func TestGetOrCreateCertificateSecretCreatedConcurrently(t *testing.T) {
+	storedCertPEM, storedKeyPEM, err := GenerateSelfSignedCertificate([]string{"localhost"},
+		time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeClient := fake.NewSimpleClientset(&api.Secret{
+		ObjectMeta: api.ObjectMeta{Name: "certs", Namespace: "kube-system"},
+		Type:       api.SecretTypeTLS,
+		Data: map[string][]byte{api.TLSCertKey: storedCertPEM,
+			api.TLSPrivateKeyKey: storedKeyPEM},
+	})
+	// Secret does not exist yet on the first get, but another replica creates it before this
+	// one does.
+	notFound := true
+	fakeClient.PrependReactor("get", "secrets",
+		func(action core.Action) (bool, runtime.Object, error) {
+			if notFound {
+				notFound = false
+				return true, nil, k8serrors.NewNotFound(api.Resource("secrets"), "certs")
+			}
+			return false, nil, nil
+		})
+	fakeClient.PrependReactor("create", "secrets",
+		func(action core.Action) (bool, runtime.Object, error) {
+			return true, nil, k8serrors.NewAlreadyExists(api.Resource("secrets"), "certs")
+		})
+
+	certPEM, keyPEM, err := GetOrCreateCertificateSecret(fakeClient, "kube-system", "certs",
+		[]string{"localhost"})
+	if err != nil {
+		t.Fatalf("GetOrCreateCertificateSecret() returned unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(certPEM, storedCertPEM) || !reflect.DeepEqual(keyPEM, storedKeyPEM) {
+		t.Error("Expected certificate stored by another replica to be used")
+	}
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	certPEM, keyPEM, err := GenerateSelfSignedCertificate([]string{"localhost"}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := NewTLSConfig(certPEM, keyPEM, "")
+	if err != nil {
+		t.Fatalf("NewTLSConfig() returned unexpected error: %v", err)
+	}
+	if config.ClientAuth != tls.NoClientCert || len(config.Certificates) != 1 {
+		t.Errorf("Unexpected TLS config without client CA: %#v", config)
+	}
+
+	caFile, err := ioutil.TempFile("", "client-ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(caFile.Name())
+	caFile.Write(certPEM)
+	caFile.Close()
+
+	config, err = NewTLSConfig(certPEM, keyPEM, caFile.Name())
+	if err != nil {
+		t.Fatalf("NewTLSConfig() returned unexpected error: %v", err)
+	}
+	if config.ClientAuth != tls.RequireAndVerifyClientCert || config.ClientCAs == nil {
+		t.Errorf("Expected TLS config to require client certificates, got %#v", config)
+	}
+
+	if _, err := NewTLSConfig(certPEM, keyPEM, os.DevNull); err == nil {
+		t.Error("Expected error for client CA file without certificates")
+	}
+}
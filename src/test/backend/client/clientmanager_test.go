@@ -0,0 +1,193 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"net"
+	"net/http"
+	"reflect"
+	"testing"
+
+	client "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
+	"k8s.io/kubernetes/pkg/client/unversioned/clientcmd"
+	clientcmdapi "k8s.io/kubernetes/pkg/client/unversioned/clientcmd/api"
+)
+
+type recordingRoundTripper struct {
+	request *http.Request
+}
+
+func (rt *recordingRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	rt.request = request
+	return &http.Response{}, nil
+}
+
+func newTestClientManager(t *testing.T, trustedProxies []string,
+	enablePrivilegedFallback bool) (*ClientManager, *client.Clientset) {
+
+	clientConfig := clientcmd.NewDefaultClientConfig(clientcmdapi.Config{},
+		&clientcmd.ConfigOverrides{
+			ClusterInfo: clientcmdapi.Cluster{Server: "https://localhost:8443",
+				InsecureSkipTLSVerify: true},
+			AuthInfo: clientcmdapi.AuthInfo{Token: "dashboard-token"},
+		})
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	privilegedClient, err := client.NewForConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	networks := []*net.IPNet{}
+	for _, cidr := range trustedProxies {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		networks = append(networks, network)
+	}
+
+	clientManager, err := NewClientManager(clientConfig, privilegedClient, networks,
+		enablePrivilegedFallback)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return clientManager, privilegedClient
+}
+
+func newTestRequest(remoteAddr string, header http.Header) *http.Request {
+	request, _ := http.NewRequest("GET", "/api/v1/pod", nil)
+	request.RemoteAddr = remoteAddr
+	for key, values := range header {
+		request.Header[key] = values
+	}
+	return request
+}
+
+func TestClientForRequestWithBearerToken(t *testing.T) {
+	clientManager, privilegedClient := newTestClientManager(t, nil, true)
+	request := newTestRequest("10.0.0.1:1234",
+		http.Header{"Authorization": []string{"Bearer user-token"}})
+
+	k8sClient, clientConfig, err := clientManager.ClientForRequest(request)
+	if err != nil {
+		t.Fatalf("ClientForRequest() returned unexpected error: %v", err)
+	}
+	if k8sClient == privilegedClient {
+		t.Error("ClientForRequest() returned privileged client for request with bearer token")
+	}
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.BearerToken != "user-token" || config.Host != "https://localhost:8443" {
+		t.Errorf("Expected config with user token and apiserver host, got %#v", config)
+	}
+}
+
+func TestClientForRequestWithImpersonation(t *testing.T) {
+	clientManager, _ := newTestClientManager(t, []string{"10.0.0.0/8"}, false)
+	request := newTestRequest("10.1.2.3:1234", http.Header{
+		"Impersonate-User":  []string{"jane"},
+		"Impersonate-Group": []string{"developers", "admins"},
+	})
+
+	_, clientConfig, err := clientManager.ClientForRequest(request)
+	if err != nil {
+		t.Fatalf("ClientForRequest() returned unexpected error: %v", err)
+	}
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.BearerToken != "dashboard-token" {
+		t.Errorf("Expected impersonating config with dashboard token, got %s", config.BearerToken)
+	}
+	if config.WrapTransport == nil {
+		t.Fatal("Expected impersonating config to wrap transport")
+	}
+
+	recorder := &recordingRoundTripper{}
+	apiRequest, _ := http.NewRequest("GET", "https://localhost:8443/api/v1/pods", nil)
+	config.WrapTransport(recorder).RoundTrip(apiRequest)
+	if actual := recorder.request.Header.Get("Impersonate-User"); actual != "jane" {
+		t.Errorf("Impersonate-User header == %s, expected jane", actual)
+	}
+	expectedGroups := []string{"developers", "admins"}
+	if actual := recorder.request.Header["Impersonate-Group"]; !reflect.DeepEqual(actual,
+		expectedGroups) {
+		t.Errorf("Impersonate-Group headers == %v, expected %v", actual, expectedGroups)
+	}
+	if len(apiRequest.Header) != 0 {
+		t.Errorf("Expected original request to be unmodified, got headers %v", apiRequest.Header)
+	}
+}
+
+func TestClientForRequestErrors(t *testing.T) {
+	cases := []struct {
+		info                     string
+		remoteAddr               string
+		header                   http.Header
+		enablePrivilegedFallback bool
+		expected                 error
+	}{
+		{
+			"impersonation from untrusted proxy",
+			"192.168.0.1:1234",
+			http.Header{"Impersonate-User": []string{"jane"}},
+			true,
+			ErrUntrustedImpersonation,
+		},
+		{
+			"basic authorization",
+			"10.0.0.1:1234",
+			http.Header{"Authorization": []string{"Basic amFuZTpzZWNyZXQ="}},
+			true,
+			ErrInvalidAuthorization,
+		},
+		{
+			"no credentials without privileged fallback",
+			"10.0.0.1:1234",
+			http.Header{},
+			false,
+			ErrMissingCredentials,
+		},
+	}
+
+	for _, c := range cases {
+		clientManager, _ := newTestClientManager(t, []string{"10.0.0.0/8"},
+			c.enablePrivilegedFallback)
+		_, _, err := clientManager.ClientForRequest(newTestRequest(c.remoteAddr, c.header))
+		if err != c.expected {
+			t.Errorf("Test Case: %s. ClientForRequest() returned error %v, expected %v", c.info,
+				err, c.expected)
+		}
+	}
+}
+
+func TestClientForRequestWithPrivilegedFallback(t *testing.T) {
+	clientManager, privilegedClient := newTestClientManager(t, nil, true)
+
+	k8sClient, _, err := clientManager.ClientForRequest(newTestRequest("10.0.0.1:1234",
+		http.Header{}))
+	if err != nil {
+		t.Fatalf("ClientForRequest() returned unexpected error: %v", err)
+	}
+	if k8sClient != privilegedClient {
+		t.Error("Expected privileged client for request without credentials")
+	}
+}
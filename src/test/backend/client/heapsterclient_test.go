@@ -0,0 +1,161 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	heapster "k8s.io/heapster/metrics/api/v1/types"
+)
+
+type FakeHeapsterClient struct {
+	// Responses by request path.
+	responses map[string]interface{}
+	mutex     *sync.Mutex
+	paths     *[]string
+}
+
+type FakeHeapsterRequest struct {
+	response interface{}
+}
+
+func (c FakeHeapsterClient) Get(path string) RequestInterface {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	*c.paths = append(*c.paths, path)
+	return FakeHeapsterRequest{response: c.responses[path]}
+}
+
+func (r FakeHeapsterRequest) DoRaw() ([]byte, error) {
+	if r.response == nil {
+		return nil, fmt.Errorf("Not found")
+	}
+	return json.Marshal(r.response)
+}
+
+func newFakeHeapsterClient(responses map[string]interface{}) FakeHeapsterClient {
+	return FakeHeapsterClient{responses: responses, mutex: &sync.Mutex{}, paths: &[]string{}}
+}
+
+func TestHeapsterDownloadMetric(t *testing.T) {
+	timestamp := time.Unix(1000, 0).UTC()
+	point := func(value uint64) heapster.MetricPoint {
+		return heapster.MetricPoint{Timestamp: timestamp, Value: value}
+	}
+	sample := func(value uint64) MetricSample {
+		return MetricSample{Timestamp: timestamp, Value: value}
+	}
+	responses := map[string]interface{}{
+		"/model/namespaces/default/pod-list/a,b/metrics/cpu/usage_rate": heapster.MetricResultList{
+			Items: []heapster.MetricResult{
+				{Metrics: []heapster.MetricPoint{point(1), point(2)}},
+				{Metrics: []heapster.MetricPoint{}},
+			},
+		},
+		"/model/namespaces/default/pod-list/a/metrics/cpu/usage_rate": heapster.MetricResultList{},
+		"/model/nodes/n1/metrics/memory/usage": heapster.MetricResult{
+			Metrics: []heapster.MetricPoint{point(10)},
+		},
+		"/model/nodes/n2/metrics/memory/usage": heapster.MetricResult{
+			Metrics: []heapster.MetricPoint{point(20)},
+		},
+		"/model/namespaces/kube-system/metrics/memory/usage": heapster.MetricResult{
+			Metrics: []heapster.MetricPoint{point(30)},
+		},
+	}
+
+	cases := []struct {
+		info          string
+		query         MetricQuery
+		expected      []MetricSamples
+		expectedPaths []string
+		expectedErr   bool
+	}{
+		{
+			"pods are downloaded in one request",
+			MetricQuery{CPUUsageMetric, PodMetricResource, "default", []string{"a", "b"}},
+			[]MetricSamples{{sample(1), sample(2)}, {}},
+			[]string{"/model/namespaces/default/pod-list/a,b/metrics/cpu/usage_rate"},
+			false,
+		},
+		{
+			"invalid number of pods",
+			MetricQuery{CPUUsageMetric, PodMetricResource, "default", []string{"a"}},
+			nil,
+			[]string{"/model/namespaces/default/pod-list/a/metrics/cpu/usage_rate"},
+			true,
+		},
+		{
+			"nodes are downloaded one by one",
+			MetricQuery{MemoryUsageMetric, NodeMetricResource, "", []string{"n1", "n2"}},
+			[]MetricSamples{{sample(10)}, {sample(20)}},
+			[]string{"/model/nodes/n1/metrics/memory/usage", "/model/nodes/n2/metrics/memory/usage"},
+			false,
+		},
+		{
+			"namespaces",
+			MetricQuery{MemoryUsageMetric, NamespaceMetricResource, "", []string{"kube-system"}},
+			[]MetricSamples{{sample(30)}},
+			[]string{"/model/namespaces/kube-system/metrics/memory/usage"},
+			false,
+		},
+		{
+			"failed request",
+			MetricQuery{MemoryUsageMetric, NodeMetricResource, "", []string{"n1", "n3"}},
+			nil,
+			[]string{"/model/nodes/n1/metrics/memory/usage", "/model/nodes/n3/metrics/memory/usage"},
+			true,
+		},
+		{
+			"no resources",
+			MetricQuery{MemoryUsageMetric, NodeMetricResource, "", []string{}},
+			[]MetricSamples{},
+			[]string{},
+			false,
+		},
+		{
+			"unsupported resource",
+			MetricQuery{MemoryUsageMetric, "deployment", "default", []string{"d"}},
+			nil,
+			[]string{},
+			true,
+		},
+	}
+
+	for _, c := range cases {
+		heapsterClient := newFakeHeapsterClient(responses)
+		actual, err := NewHeapsterMetricsProvider(heapsterClient).DownloadMetric(c.query)
+		if (err != nil) != c.expectedErr {
+			t.Errorf("Test Case: %s. DownloadMetric(%#v) returned error %v, expected error: %t",
+				c.info, c.query, err, c.expectedErr)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Test Case: %s. DownloadMetric(%#v) == %#v, expected %#v", c.info, c.query,
+				actual, c.expected)
+		}
+		paths := *heapsterClient.paths
+		sort.Strings(paths)
+		if !reflect.DeepEqual(paths, c.expectedPaths) {
+			t.Errorf("Test Case: %s. DownloadMetric(%#v) requested %v, expected %v", c.info,
+				c.query, paths, c.expectedPaths)
+		}
+	}
+}
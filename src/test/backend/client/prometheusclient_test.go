@@ -0,0 +1,139 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type FakePrometheusClient struct {
+	response string
+	queries  *[]string
+}
+
+type FakePrometheusRequest struct {
+	response string
+}
+
+func (c FakePrometheusClient) QueryRange(query string, start, end time.Time,
+	step time.Duration) RequestInterface {
+
+	*c.queries = append(*c.queries, query)
+	return FakePrometheusRequest{response: c.response}
+}
+
+func (r FakePrometheusRequest) DoRaw() ([]byte, error) {
+	return []byte(r.response), nil
+}
+
+func TestCreatePromQLQuery(t *testing.T) {
+	cases := []struct {
+		query       MetricQuery
+		expected    string
+		expectedErr bool
+	}{
+		{
+			MetricQuery{CPUUsageMetric, PodMetricResource, "default", []string{"a", "b.c"}},
+			`sum(rate(container_cpu_usage_seconds_total{namespace="default",` +
+				`pod_name=~"a|b\\.c",container_name!~"POD|"}[2m])) by (pod_name) * 1000`,
+			false,
+		},
+		{
+			MetricQuery{MemoryUsageMetric, NodeMetricResource, "", []string{"n1"}},
+			`sum(container_memory_usage_bytes{instance=~"n1",id="/"}) by (instance)`,
+			false,
+		},
+		{
+			MetricQuery{MemoryUsageMetric, NamespaceMetricResource, "", []string{"a", "b"}},
+			`sum(container_memory_usage_bytes{namespace=~"a|b",container_name!~"POD|"}) ` +
+				`by (namespace)`,
+			false,
+		},
+		{MetricQuery{"network/tx", PodMetricResource, "default", []string{"a"}}, "", true},
+		{MetricQuery{CPUUsageMetric, "deployment", "default", []string{"a"}}, "", true},
+	}
+
+	for _, c := range cases {
+		actual, err := CreatePromQLQuery(c.query)
+		if (err != nil) != c.expectedErr {
+			t.Errorf("CreatePromQLQuery(%#v) returned error %v, expected error: %t", c.query, err,
+				c.expectedErr)
+		}
+		if actual != c.expected {
+			t.Errorf("CreatePromQLQuery(%#v) == %s, expected %s", c.query, actual, c.expected)
+		}
+	}
+}
+
+func TestPrometheusDownloadMetric(t *testing.T) {
+	query := MetricQuery{MemoryUsageMetric, PodMetricResource, "default", []string{"a", "b", "c"}}
+	cases := []struct {
+		info        string
+		response    string
+		expected    []MetricSamples
+		expectedErr bool
+	}{
+		{
+			"samples are ordered by resource names",
+			`{"status":"success","data":{"resultType":"matrix","result":[` +
+				`{"metric":{"pod_name":"c"},"values":[[1000,"3"],[1060,"-1"]]},` +
+				`{"metric":{"pod_name":"a"},"values":[[1000,"1.5"]]}]}}`,
+			[]MetricSamples{
+				{{Timestamp: time.Unix(1000, 0), Value: 1}},
+				{},
+				{{Timestamp: time.Unix(1000, 0), Value: 3}, {Timestamp: time.Unix(1060, 0)}},
+			},
+			false,
+		},
+		{
+			"failed query",
+			`{"status":"error","errorType":"bad_data","error":"parse error"}`,
+			nil,
+			true,
+		},
+		{
+			"unexpected result type",
+			`{"status":"success","data":{"resultType":"vector","result":[]}}`,
+			nil,
+			true,
+		},
+		{
+			"invalid sample",
+			`{"status":"success","data":{"resultType":"matrix","result":[` +
+				`{"metric":{"pod_name":"a"},"values":[[1000,"x"]]}]}}`,
+			nil,
+			true,
+		},
+	}
+
+	for _, c := range cases {
+		prometheusClient := FakePrometheusClient{response: c.response, queries: &[]string{}}
+		actual, err := NewPrometheusMetricsProvider(prometheusClient).DownloadMetric(query)
+		if (err != nil) != c.expectedErr {
+			t.Errorf("Test Case: %s. DownloadMetric() returned error %v, expected error: %t",
+				c.info, err, c.expectedErr)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Test Case: %s. DownloadMetric() == %#v, expected %#v", c.info, actual,
+				c.expected)
+		}
+		if len(*prometheusClient.queries) != 1 {
+			t.Errorf("Test Case: %s. DownloadMetric() made %d queries, expected 1", c.info,
+				len(*prometheusClient.queries))
+		}
+	}
+}
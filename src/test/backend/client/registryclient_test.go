@@ -0,0 +1,272 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/kubernetes/pkg/api"
+	k8serrors "k8s.io/kubernetes/pkg/api/errors"
+)
+
+const fakeRegistryDigest = "sha256:" +
+	"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"
+
+// newFakeRegistry starts a registry that serves foo/bar repository with tags 1.0 and 1.1, in
+// pages of one tag, and fails to serve manifest of broken tag. Requests have to carry a bearer
+// token, that the token endpoint issues to user with password secret.
+func newFakeRegistry() *httptest.Server {
+	mux := http.NewServeMux()
+	server := httptest.NewTLSServer(mux)
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "user" || password != "secret" ||
+			r.URL.Query().Get("scope") != "repository:foo/bar:pull" ||
+			r.URL.Query().Get("service") != "fake" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"token": "fake-token"}`)
+	})
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer fake-token" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(
+				`Bearer realm="%s/token",service="fake",scope="repository:foo/bar:pull"`,
+				server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/foo/bar/manifests/1.0":
+			if !strings.Contains(r.Header.Get("Accept"),
+				"application/vnd.docker.distribution.manifest.v2+json") {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Docker-Content-Digest", fakeRegistryDigest)
+		case "/v2/foo/bar/manifests/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "/v2/foo/bar/tags/list":
+			if r.URL.Query().Get("last") == "" {
+				w.Header().Set("Link", `</v2/foo/bar/tags/list?n=1&last=1.1>; rel="next"`)
+				fmt.Fprint(w, `{"name": "foo/bar", "tags": ["1.1"]}`)
+			} else {
+				fmt.Fprint(w, `{"name": "foo/bar", "tags": ["1.0"]}`)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	return server
+}
+
+// newFakeRegistryHTTPClient returns HTTP client that trusts the certificate of the fake registry.
+func newFakeRegistryHTTPClient(t *testing.T, server *httptest.Server) *http.Client {
+	certificate, err := x509.ParseCertificate(server.TLS.Certificates[0].Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(certificate)
+	return &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{RootCAs: rootCAs},
+	}}
+}
+
+func TestRegistryClientGetManifest(t *testing.T) {
+	server := newFakeRegistry()
+	defer server.Close()
+	registryClient := NewRegistryClient(newFakeRegistryHTTPClient(t, server))
+	host := strings.TrimPrefix(server.URL, "https://")
+	credentials := &RegistryCredentials{Username: "user", Password: "secret"}
+
+	cases := []struct {
+		reference   string
+		credentials *RegistryCredentials
+		expected    *ImageManifest
+		expectedErr error
+	}{
+		{
+			host + "/foo/bar:1.0",
+			credentials,
+			&ImageManifest{Exists: true, Digest: fakeRegistryDigest},
+			nil,
+		},
+		{
+			host + "/foo/bar:1.2",
+			credentials,
+			&ImageManifest{Exists: false},
+			nil,
+		},
+		{
+			host + "/foo/bar:1.0",
+			nil,
+			nil,
+			ErrRegistryUnauthorized,
+		},
+		{
+			host + "/foo/bar:1.0",
+			&RegistryCredentials{Username: "user", Password: "wrong"},
+			nil,
+			ErrRegistryUnauthorized,
+		},
+	}
+
+	for _, c := range cases {
+		actual, err := registryClient.GetManifest(c.reference, c.credentials)
+		if err != c.expectedErr {
+			t.Errorf("GetManifest(%s, %#v) returned error %v, expected %v", c.reference,
+				c.credentials, err, c.expectedErr)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetManifest(%s, %#v) == %#v, expected %#v", c.reference, c.credentials,
+				actual, c.expected)
+		}
+	}
+}
+
+func TestRegistryClientListTags(t *testing.T) {
+	server := newFakeRegistry()
+	defer server.Close()
+	registryClient := NewRegistryClient(newFakeRegistryHTTPClient(t, server))
+	reference := strings.TrimPrefix(server.URL, "https://") + "/foo/bar:1.0"
+
+	actual, err := registryClient.ListTags(reference,
+		&RegistryCredentials{Username: "user", Password: "secret"})
+	if err != nil {
+		t.Fatalf("ListTags(%s) returned error: %s", reference, err)
+	}
+	expected := []string{"1.0", "1.1"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("ListTags(%s) == %#v, expected %#v", reference, actual, expected)
+	}
+}
+
+func TestRegistryClientErrors(t *testing.T) {
+	server := newFakeRegistry()
+	registryClient := NewRegistryClient(newFakeRegistryHTTPClient(t, server))
+	host := strings.TrimPrefix(server.URL, "https://")
+	credentials := &RegistryCredentials{Username: "user", Password: "secret"}
+
+	// Registry responds with unexpected status.
+	_, err := registryClient.GetManifest(host+"/foo/bar:broken", credentials)
+	if _, ok := err.(*RegistryError); !ok {
+		t.Errorf("GetManifest() of broken tag returned error %#v, expected registry error", err)
+	}
+
+	// Registry can not be reached.
+	server.Close()
+	_, err = registryClient.GetManifest(host+"/foo/bar:1.0", credentials)
+	if registryErr, ok := err.(*RegistryError); !ok || registryErr.Host != host {
+		t.Errorf("GetManifest() of unreachable registry returned error %#v, expected registry "+
+			"error of %s", err, host)
+	}
+}
+
+func TestSplitImageName(t *testing.T) {
+	cases := []struct {
+		name, expectedHost, expectedRepository string
+	}{
+		{"nginx", DefaultRegistryHost, "library/nginx"},
+		{"user/app", DefaultRegistryHost, "user/app"},
+		{"docker.io/user/app", DefaultRegistryHost, "user/app"},
+		{"gcr.io/google_containers/pause", "gcr.io", "google_containers/pause"},
+		{"localhost/app", "localhost", "app"},
+		{"registry:5000/team/app", "registry:5000", "team/app"},
+	}
+
+	for _, c := range cases {
+		host, repository := SplitImageName(c.name)
+		if host != c.expectedHost || repository != c.expectedRepository {
+			t.Errorf("SplitImageName(%s) == %s, %s, expected %s, %s", c.name, host, repository,
+				c.expectedHost, c.expectedRepository)
+		}
+	}
+}
+
+func TestGetRegistryCredentials(t *testing.T) {
+	cases := []struct {
+		secret      *api.Secret
+		host        string
+		expected    *RegistryCredentials
+		expectedErr bool
+	}{
+		{
+			&api.Secret{
+				Type: api.SecretTypeDockerConfigJson,
+				Data: map[string][]byte{api.DockerConfigJsonKey: []byte(
+					`{"auths": {"https://index.docker.io/v1/": {"auth": "dXNlcjpzZWNyZXQ="}}}`)},
+			},
+			DefaultRegistryHost,
+			&RegistryCredentials{Username: "user", Password: "secret"},
+			false,
+		},
+		{
+			&api.Secret{
+				Type: api.SecretTypeDockercfg,
+				Data: map[string][]byte{api.DockerConfigKey: []byte(
+					`{"registry:5000": {"username": "user", "password": "secret"}}`)},
+			},
+			"registry:5000",
+			&RegistryCredentials{Username: "user", Password: "secret"},
+			false,
+		},
+		{
+			&api.Secret{
+				Type: api.SecretTypeDockercfg,
+				Data: map[string][]byte{api.DockerConfigKey: []byte(
+					`{"registry:5000": {"username": "user", "password": "secret"}}`)},
+			},
+			"gcr.io",
+			nil,
+			false,
+		},
+		{
+			&api.Secret{Type: api.SecretTypeOpaque},
+			"gcr.io",
+			nil,
+			true,
+		},
+		{
+			&api.Secret{
+				Type: api.SecretTypeDockerConfigJson,
+				Data: map[string][]byte{api.DockerConfigJsonKey: []byte("{")},
+			},
+			"gcr.io",
+			nil,
+			true,
+		},
+	}
+
+	for _, c := range cases {
+		actual, err := GetRegistryCredentials(c.secret, c.host)
+		if (err != nil) != c.expectedErr || (err != nil && !k8serrors.IsBadRequest(err)) {
+			t.Errorf("GetRegistryCredentials(%#v, %s) returned error %v, expected bad request "+
+				"error: %t", c.secret, c.host, err, c.expectedErr)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetRegistryCredentials(%#v, %s) == %#v, expected %#v", c.secret, c.host,
+				actual, c.expected)
+		}
+	}
+}
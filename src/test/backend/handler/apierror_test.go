@@ -0,0 +1,129 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	restful "github.com/emicklei/go-restful"
+	"k8s.io/kubernetes/pkg/api"
+	k8serrors "k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/util/validation/field"
+)
+
+func TestToAPIError(t *testing.T) {
+	notFound := k8serrors.NewNotFound(api.Resource("pods"), "foo")
+	forbidden := k8serrors.NewForbidden(api.Resource("secrets"), "bar", errors.New("denied"))
+	invalid := k8serrors.NewInvalid(api.Kind("Pod"), "foo", field.ErrorList{
+		field.Required(field.NewPath("spec", "containers"), ""),
+	})
+	timeout := k8serrors.NewServerTimeout(api.Resource("pods"), "list", 5)
+	unknown := k8serrors.NewGenericServerResponse(422, "post", api.Resource("pods"), "foo", "",
+		0, false)
+
+	cases := []struct {
+		err      error
+		expected *APIError
+	}{
+		{
+			notFound,
+			&APIError{Code: 404, Reason: unversioned.StatusReasonNotFound,
+				Message: notFound.Error()},
+		},
+		{
+			forbidden,
+			&APIError{Code: 403, Reason: unversioned.StatusReasonForbidden,
+				Message: forbidden.Error()},
+		},
+		{
+			invalid,
+			&APIError{Code: 422, Reason: unversioned.StatusReasonInvalid,
+				Message: invalid.Error(), Causes: []APIErrorCause{{
+					Type:    unversioned.CauseTypeFieldValueRequired,
+					Message: "Required value",
+					Field:   "spec.containers",
+				}}},
+		},
+		{
+			timeout,
+			&APIError{Code: 500, Reason: unversioned.StatusReasonServerTimeout,
+				Message: timeout.Error(), RetryAfterSeconds: 5},
+		},
+		{
+			unknown,
+			&APIError{Code: 422, Reason: unversioned.StatusReasonInvalid,
+				Message: unknown.Error()},
+		},
+		{
+			errors.New("something is broken"),
+			&APIError{Code: 500, Reason: unversioned.StatusReasonInternalError,
+				Message: "something is broken"},
+		},
+	}
+
+	for _, c := range cases {
+		if actual := toAPIError(c.err); !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("toAPIError(%#v) == \n%#v\nexpected \n%#v", c.err, actual, c.expected)
+		}
+	}
+}
+
+func TestHandleError(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	handleError(restful.NewResponse(recorder),
+		k8serrors.NewTimeoutError("too slow", 10))
+
+	if recorder.Code != 504 {
+		t.Errorf("handleError() wrote status code %d, expected 504", recorder.Code)
+	}
+	if actual := recorder.HeaderMap.Get("Retry-After"); actual != "10" {
+		t.Errorf("handleError() wrote Retry-After header %q, expected 10", actual)
+	}
+	if actual := recorder.HeaderMap.Get("Content-Type"); actual != restful.MIME_JSON {
+		t.Errorf("handleError() wrote Content-Type header %q, expected %s", actual,
+			restful.MIME_JSON)
+	}
+
+	actual := new(APIError)
+	if err := json.Unmarshal(recorder.Body.Bytes(), actual); err != nil {
+		t.Fatalf("handleError() wrote invalid JSON: %s", err)
+	}
+	expected := &APIError{Code: 504, Reason: unversioned.StatusReasonTimeout,
+		Message: "Timeout: too slow", RetryAfterSeconds: 10}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("handleError() wrote \n%#v\nexpected \n%#v", actual, expected)
+	}
+}
+
+func TestHandleBadRequestError(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	handleBadRequestError(restful.NewResponse(recorder), errors.New("invalid tailLines"))
+
+	actual := new(APIError)
+	if err := json.Unmarshal(recorder.Body.Bytes(), actual); err != nil {
+		t.Fatalf("handleBadRequestError() wrote invalid JSON: %s", err)
+	}
+	expected := &APIError{Code: 400, Reason: unversioned.StatusReasonBadRequest,
+		Message: "invalid tailLines"}
+	if recorder.Code != 400 || !reflect.DeepEqual(actual, expected) {
+		t.Errorf("handleBadRequestError() wrote %d \n%#v\nexpected 400 \n%#v", recorder.Code,
+			actual, expected)
+	}
+}
@@ -0,0 +1,343 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	restful "github.com/emicklei/go-restful"
+	"github.com/kubernetes/dashboard/src/app/backend/client"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/deployment"
+	"k8s.io/kubernetes/pkg/api"
+	k8serrors "k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	clientK8s "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
+	"k8s.io/kubernetes/pkg/client/restclient"
+)
+
+func TestParseListOptions(t *testing.T) {
+	cases := []struct {
+		query                 string
+		expectedLabelSelector string
+		expectedFieldSelector string
+		expectedErr           bool
+	}{
+		{"", "", "", false},
+		{"?labelSelector=team%3Dfoo,app%3Dbar", "app=bar,team=foo", "", false},
+		{"?labelSelector=team+in+(foo,bar)&fieldSelector=metadata.name%3Dbaz",
+			"team in (bar,foo)", "metadata.name=baz", false},
+		{"?labelSelector=team%3D%3D%3D", "", "", true},
+		{"?fieldSelector=metadata.name", "", "", true},
+	}
+
+	for _, c := range cases {
+		httpRequest, _ := http.NewRequest("GET", "/api/v1/pod"+c.query, nil)
+		options, err := parseListOptions(restful.NewRequest(httpRequest))
+		if (err != nil) != c.expectedErr {
+			t.Errorf("parseListOptions(%s) returned error %v, expected error: %t", c.query, err,
+				c.expectedErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if actual := options.LabelSelector.String(); actual != c.expectedLabelSelector {
+			t.Errorf("parseListOptions(%s) returned label selector %s, expected %s", c.query,
+				actual, c.expectedLabelSelector)
+		}
+		if actual := options.FieldSelector.String(); actual != c.expectedFieldSelector {
+			t.Errorf("parseListOptions(%s) returned field selector %s, expected %s", c.query,
+				actual, c.expectedFieldSelector)
+		}
+	}
+}
+
+func TestParseAggregateListOptions(t *testing.T) {
+	cases := []struct {
+		query       string
+		expectedErr bool
+	}{
+		{"", false},
+		{"?labelSelector=app%3Dfoo,tier%3Dweb", false},
+		{"?fieldSelector=metadata.name%3Dfoo,metadata.namespace!%3Dbar", false},
+		{"?fieldSelector=status.phase%3DRunning", true},
+		{"?fieldSelector=metadata.name%3Dfoo,spec.nodeName%3Dbar", true},
+	}
+
+	for _, c := range cases {
+		httpRequest, _ := http.NewRequest("GET", "/api/v1/workload"+c.query, nil)
+		_, err := parseAggregateListOptions(restful.NewRequest(httpRequest))
+		if (err != nil) != c.expectedErr {
+			t.Errorf("parseAggregateListOptions(%s) returned error %v, expected error: %t",
+				c.query, err, c.expectedErr)
+		}
+	}
+}
+
+func TestParseFilterPathParameter(t *testing.T) {
+	query := "?filterby=" + url.QueryEscape(`labels="app=nginx,env in (prod,qa)",name~=web`)
+	httpRequest, _ := http.NewRequest("GET", "/api/v1/pod"+query, nil)
+	expected := &dataselect.FilterQuery{FilterByList: []dataselect.FilterBy{
+		{Conditions: []dataselect.FilterCondition{{
+			Property: dataselect.LabelsProperty,
+			Operator: dataselect.EqualOperator,
+			Value:    "app=nginx,env in (prod,qa)",
+		}}},
+		{Conditions: []dataselect.FilterCondition{{
+			Property: dataselect.NameProperty,
+			Operator: dataselect.ContainsOperator,
+			Value:    "web",
+		}}},
+	}}
+
+	actual := parseFilterPathParameter(restful.NewRequest(httpRequest))
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("parseFilterPathParameter(%s) == %#v, expected %#v", query, actual, expected)
+	}
+}
+
+func TestParseExportFormat(t *testing.T) {
+	cases := []struct {
+		query    string
+		accept   string
+		expected string
+	}{
+		{"", "", ""},
+		{"", "application/json, text/plain, */*", ""},
+		{"?format=csv", "", "csv"},
+		{"?format=YAML", "application/json", "yaml"},
+		{"", "text/csv", "csv"},
+		{"", "application/yaml", "yaml"},
+		{"?format=json", "text/csv", ""},
+	}
+
+	for _, c := range cases {
+		httpRequest, _ := http.NewRequest("GET", "/api/v1/pod"+c.query, nil)
+		httpRequest.Header.Set("Accept", c.accept)
+		request := restful.NewRequest(httpRequest)
+		if actual := parseExportFormat(request); actual != c.expected {
+			t.Errorf("parseExportFormat(%s, %s) == %s, expected %s", c.query, c.accept, actual,
+				c.expected)
+		}
+		dataSelect := parseDataSelectPathParameter(request)
+		if (dataSelect.Collector != nil) != (c.expected != "") {
+			t.Errorf("parseDataSelectPathParameter(%s, %s) returned collector %v", c.query,
+				c.accept, dataSelect.Collector)
+		}
+	}
+}
+
+func TestParsePodLogOptions(t *testing.T) {
+	ten := int64(10)
+	zero := int64(0)
+	sinceTime := unversioned.NewTime(time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC))
+	cases := []struct {
+		query       string
+		expected    *api.PodLogOptions
+		expectedErr bool
+	}{
+		{"", &api.PodLogOptions{}, false},
+		{"?previous=true&sinceSeconds=10&tailLines=0",
+			&api.PodLogOptions{Previous: true, SinceSeconds: &ten, TailLines: &zero}, false},
+		{"?sinceTime=2017-01-02T03:04:05Z&limitBytes=10",
+			&api.PodLogOptions{SinceTime: &sinceTime, LimitBytes: &ten}, false},
+		{"?previous=maybe", nil, true},
+		{"?tailLines=-1", nil, true},
+		{"?limitBytes=0", nil, true},
+		{"?sinceTime=yesterday", nil, true},
+		{"?sinceSeconds=10&sinceTime=2017-01-02T03:04:05Z", nil, true},
+	}
+
+	for _, c := range cases {
+		httpRequest, _ := http.NewRequest("GET", "/api/v1/pod/ns/pod/log"+c.query, nil)
+		actual, err := parsePodLogOptions(restful.NewRequest(httpRequest))
+		if (err != nil) != c.expectedErr {
+			t.Errorf("parsePodLogOptions(%s) returned error %v, expected error: %t", c.query, err,
+				c.expectedErr)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("parsePodLogOptions(%s) == %#v, expected %#v", c.query, actual, c.expected)
+		}
+	}
+}
+
+// newFakeDeployServer starts an apiserver that creates and deletes deployments in foo-namespace
+// and answers creation of services with the given status code.
+func newFakeDeployServer(serviceStatus int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "POST" &&
+			r.URL.Path == "/apis/extensions/v1beta1/namespaces/foo-namespace/deployments":
+			body, _ := ioutil.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+			w.Write(body)
+		case r.Method == "DELETE" &&
+			r.URL.Path == "/apis/extensions/v1beta1/namespaces/foo-namespace/deployments/foo-name":
+			fmt.Fprint(w, `{"kind": "Status", "apiVersion": "v1", "status": "Success"}`)
+		case r.Method == "POST" && r.URL.Path == "/api/v1/namespaces/foo-namespace/services" &&
+			serviceStatus == http.StatusCreated:
+			body, _ := ioutil.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+			w.Write(body)
+		case r.Method == "POST" && r.URL.Path == "/api/v1/namespaces/foo-namespace/services":
+			w.WriteHeader(serviceStatus)
+			fmt.Fprintf(w, `{"kind": "Status", "apiVersion": "v1", "status": "Failure", `+
+				`"reason": "Forbidden", "message": "services is forbidden", "code": %d}`,
+				serviceStatus)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestHandleDeploy(t *testing.T) {
+	cases := []struct {
+		info               string
+		serviceStatus      int
+		expectedStatus     int
+		expectedRolledBack bool
+	}{
+		{"deployed application", http.StatusCreated, http.StatusCreated, false},
+		{"rolled back application", http.StatusForbidden, k8serrors.StatusUnprocessableEntity,
+			true},
+	}
+
+	for _, c := range cases {
+		server := newFakeDeployServer(c.serviceStatus)
+		k8sClient, err := clientK8s.NewForConfig(&restclient.Config{Host: server.URL})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		httpRequest, _ := http.NewRequest("POST", "/api/v1/appdeployment", strings.NewReader(
+			`{"name": "foo-name", "namespace": "foo-namespace", "containerImage": "nginx", `+
+				`"replicas": 1, "portMappings": [{"port": 80, "targetPort": 8080, `+
+				`"protocol": "TCP"}]}`))
+		httpRequest.Header.Set("Content-Type", restful.MIME_JSON)
+		request := restful.NewRequest(httpRequest)
+		request.SetAttribute(clientAttribute, k8sClient)
+		recorder := httptest.NewRecorder()
+		response := restful.NewResponse(recorder)
+		response.SetRequestAccepts(restful.MIME_JSON)
+
+		new(APIHandler).handleDeploy(request, response)
+		server.Close()
+
+		if recorder.Code != c.expectedStatus {
+			t.Errorf("Test Case: %s. handleDeploy() answered with status %d, expected %d: %s",
+				c.info, recorder.Code, c.expectedStatus, recorder.Body)
+			continue
+		}
+		result := &deployment.AppDeploymentResponse{}
+		if err := json.Unmarshal(recorder.Body.Bytes(), result); err != nil {
+			t.Errorf("Test Case: %s. handleDeploy() answered with invalid body: %s", c.info,
+				err)
+			continue
+		}
+		if result.RolledBack != c.expectedRolledBack || len(result.Objects) != 2 {
+			t.Errorf("Test Case: %s. handleDeploy() answered with %#v, expected status of "+
+				"both objects and rolled back: %t", c.info, result, c.expectedRolledBack)
+		}
+	}
+}
+
+func TestWriteDeployResponse(t *testing.T) {
+	cases := []struct {
+		rolledBack     bool
+		expectedStatus int
+	}{
+		{false, http.StatusCreated},
+		{true, k8serrors.StatusUnprocessableEntity},
+	}
+
+	for _, c := range cases {
+		recorder := httptest.NewRecorder()
+		result := &deployment.AppDeploymentFromFileResponse{Name: "foo.yaml",
+			RolledBack: c.rolledBack}
+		response := restful.NewResponse(recorder)
+		response.SetRequestAccepts(restful.MIME_JSON)
+		writeDeployResponse(response, c.rolledBack, result)
+
+		if recorder.Code != c.expectedStatus {
+			t.Errorf("writeDeployResponse() with rolled back: %t answered with status %d, "+
+				"expected %d", c.rolledBack, recorder.Code, c.expectedStatus)
+		}
+		if !strings.Contains(recorder.Body.String(), `"name": "foo.yaml"`) {
+			t.Errorf("writeDeployResponse() answered without the result: %s", recorder.Body)
+		}
+	}
+}
+
+func TestHandleMalformedEntity(t *testing.T) {
+	apiHandler := &APIHandler{}
+	handlers := map[string]restful.RouteFunction{
+		"deploy":             apiHandler.handleDeploy,
+		"deploy from file":   apiHandler.handleDeployFromFile,
+		"name validity":      apiHandler.handleNameValidity,
+		"image reference":    apiHandler.handleImageReferenceValidity,
+		"protocol validity":  apiHandler.handleProtocolValidity,
+		"update replicas":    apiHandler.handleUpdateReplicasCount,
+		"create namespace":   apiHandler.handleCreateNamespace,
+		"create config map":  apiHandler.handleCreateConfigMap,
+		"set config map key": apiHandler.handleSetConfigMapKey,
+	}
+
+	for name, handler := range handlers {
+		httpRequest, _ := http.NewRequest("POST", "/api/v1/foo", strings.NewReader("{not json"))
+		httpRequest.Header.Set("Content-Type", restful.MIME_JSON)
+		recorder := httptest.NewRecorder()
+		handler(restful.NewRequest(httpRequest), restful.NewResponse(recorder))
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("Handler of %s answered malformed entity with status %d, expected %d", name,
+				recorder.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestHandleRegistryError(t *testing.T) {
+	cases := []struct {
+		err          error
+		expectedCode int
+	}{
+		{client.ErrRegistryUnauthorized, http.StatusForbidden},
+		{&client.RegistryError{Host: "gcr.io", Err: errors.New("connection refused")},
+			http.StatusBadGateway},
+		{k8serrors.NewBadRequest("secret foo is not an image pull secret"),
+			http.StatusBadRequest},
+		{errors.New("something is broken"), http.StatusInternalServerError},
+	}
+
+	for _, c := range cases {
+		recorder := httptest.NewRecorder()
+		handleRegistryError(restful.NewResponse(recorder), c.err)
+		if recorder.Code != c.expectedCode {
+			t.Errorf("handleRegistryError(%#v) answered with status %d, expected %d", c.err,
+				recorder.Code, c.expectedCode)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/node"
+	"golang.org/x/net/websocket"
+	"k8s.io/kubernetes/pkg/api"
+	k8serrors "k8s.io/kubernetes/pkg/api/errors"
+)
+
+func TestCheckWebSocketOrigin(t *testing.T) {
+	cases := []struct {
+		info          string
+		host          string
+		origin        string
+		forwardedHost string
+		expectError   bool
+	}{
+		{"accept clients without origin", "dashboard:9090", "", "", false},
+		{"accept same origin", "dashboard:9090", "http://dashboard:9090", "", false},
+		{"accept forwarded host", "10.0.0.1:9090", "https://proxy:8443", "proxy:8443", false},
+		{"reject other origins", "dashboard:9090", "http://evil.example.com", "", true},
+		{"reject invalid origin", "dashboard:9090", "::", "", true},
+	}
+
+	for _, c := range cases {
+		request := &http.Request{Host: c.host, Header: http.Header{}}
+		if c.origin != "" {
+			request.Header.Set("Origin", c.origin)
+		}
+		if c.forwardedHost != "" {
+			request.Header.Set("X-Forwarded-Host", c.forwardedHost)
+		}
+		config := &websocket.Config{Version: websocket.ProtocolVersionHybi13}
+
+		err := checkWebSocketOrigin(config, request)
+		if (err != nil) != c.expectError {
+			t.Errorf("Test Case: %s. checkWebSocketOrigin() returned %v, expected error: %t",
+				c.info, err, c.expectError)
+		}
+	}
+}
+
+func TestSendDrainResult(t *testing.T) {
+	cases := []struct {
+		result       *node.DrainResult
+		err          error
+		expectedCode int32
+	}{
+		{&node.DrainResult{NodeName: "node-1", Complete: true}, nil, 0},
+		{nil, k8serrors.NewNotFound(api.Resource("nodes"), "node-1"), http.StatusNotFound},
+	}
+
+	for _, c := range cases {
+		server := httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+			sendDrainResult(ws, c.result, c.err)
+		}))
+		ws, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http"), "", server.URL)
+		if err != nil {
+			server.Close()
+			t.Fatal(err)
+		}
+
+		message := struct {
+			NodeName string `json:"nodeName"`
+			Code     int32  `json:"code"`
+		}{}
+		err = websocket.JSON.Receive(ws, &message)
+		ws.Close()
+		server.Close()
+		if err != nil {
+			t.Errorf("sendDrainResult(%v) sent no message: %v", c.err, err)
+			continue
+		}
+		if message.Code != c.expectedCode {
+			t.Errorf("sendDrainResult(%v) sent error code %d, expected %d", c.err,
+				message.Code, c.expectedCode)
+		}
+		if c.result != nil && message.NodeName != c.result.NodeName {
+			t.Errorf("sendDrainResult() sent result of node %q, expected %q", message.NodeName,
+				c.result.NodeName)
+		}
+	}
+}
@@ -18,46 +18,31 @@ import (
 	"reflect"
 	"testing"
 
-	heapster "k8s.io/heapster/metrics/api/v1/types"
+	"github.com/kubernetes/dashboard/src/app/backend/client"
 )
 
-func TestCreateMetricPath(t *testing.T) {
+func TestCreatePodMetricQuery(t *testing.T) {
 	cases := []struct {
 		namespace  string
 		podNames   []string
 		metricName string
-		expected   string
+		expected   client.MetricQuery
 	}{
-		{"", make([]string, 0), "", "/model/namespaces//pod-list//metrics/"},
-		{"default", []string{"a", "b"}, "cpu-usage",
-			"/model/namespaces/default/pod-list/a,b/metrics/cpu-usage"},
+		{"", make([]string, 0), "", client.MetricQuery{ResourceKind: client.PodMetricResource,
+			ResourceNames: make([]string, 0)}},
+		{"default", []string{"a", "b"}, "cpu-usage", client.MetricQuery{MetricName: "cpu-usage",
+			ResourceKind: client.PodMetricResource, Namespace: "default",
+			ResourceNames: []string{"a", "b"}}},
 	}
 	for _, c := range cases {
-		actual := createMetricPath(c.namespace, c.podNames, c.metricName)
+		actual := createPodMetricQuery(c.namespace, c.podNames, c.metricName)
 		if !reflect.DeepEqual(actual, c.expected) {
-			t.Errorf("getReplicationControllerPods(%#v, %#v, %#v) == %#v, expected %#v",
+			t.Errorf("createPodMetricQuery(%#v, %#v, %#v) == %#v, expected %#v",
 				c.namespace, c.podNames, c.metricName, actual, c.expected)
 		}
 	}
 }
 
-func TestUnmarshalMetrics(t *testing.T) {
-	cases := []struct {
-		rawData  []byte
-		expected []heapster.MetricResult
-	}{
-		{make([]byte, 0), []heapster.MetricResult{}},
-	}
-	for _, c := range cases {
-		actual, _ := unmarshalMetrics(c.rawData)
-
-		if !reflect.DeepEqual(actual, c.expected) {
-			t.Errorf("unmarshalMetrics(%#v) == %#v, expected %#v",
-				c.rawData, actual, c.expected)
-		}
-	}
-}
-
 func TestCreateResponse(t *testing.T) {
 	var cpuUsage1 uint64 = 1
 	var cpuUsage2 uint64 = 2
@@ -65,43 +50,43 @@ func TestCreateResponse(t *testing.T) {
 	var memoryUsage uint64 = 6131712
 	var memoryUsage2 uint64 = 6131713
 	cases := []struct {
-		cpuMetrics []heapster.MetricResult
-		memMetrics []heapster.MetricResult
+		cpuMetrics []client.MetricSamples
+		memMetrics []client.MetricSamples
 		podNames   []string
 		expected   map[string]PodMetrics
 	}{
-		{make([]heapster.MetricResult, 0), make([]heapster.MetricResult, 0), make([]string, 0),
+		{make([]client.MetricSamples, 0), make([]client.MetricSamples, 0), make([]string, 0),
 			map[string]PodMetrics{}},
-		{[]heapster.MetricResult{
-			{Metrics: []heapster.MetricPoint{
+		{[]client.MetricSamples{
+			{
 				{Value: 0},
-			}},
+			},
 		},
-			[]heapster.MetricResult{
-				{Metrics: []heapster.MetricPoint{
+			[]client.MetricSamples{
+				{
 					{Value: 6131712},
-				}},
+				},
 			},
 			[]string{"a", "b"},
 			map[string]PodMetrics{},
 		},
-		{[]heapster.MetricResult{
-			{Metrics: []heapster.MetricPoint{
+		{[]client.MetricSamples{
+			{
 				{Value: cpuUsage1},
-			}},
-			{Metrics: []heapster.MetricPoint{
+			},
+			{
 				{Value: cpuUsage2},
 				{Value: cpuUsage3},
-			}},
+			},
 		},
-			[]heapster.MetricResult{
-				{Metrics: []heapster.MetricPoint{
+			[]client.MetricSamples{
+				{
 					{Value: memoryUsage},
 					{Value: memoryUsage2},
-				}},
-				{Metrics: []heapster.MetricPoint{
+				},
+				{
 					{Value: memoryUsage},
-				}},
+				},
 			},
 			[]string{"a", "b"},
 			map[string]PodMetrics{
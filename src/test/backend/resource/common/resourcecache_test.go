@@ -0,0 +1,164 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset/fake"
+	"k8s.io/kubernetes/pkg/fields"
+	"k8s.io/kubernetes/pkg/labels"
+)
+
+func getPodNames(list *api.PodList) []string {
+	names := []string{}
+	for _, pod := range list.Items {
+		names = append(names, pod.Name)
+	}
+	return names
+}
+
+func newCachedPod(namespace, name string, podLabels map[string]string) *api.Pod {
+	return &api.Pod{ObjectMeta: api.ObjectMeta{Namespace: namespace, Name: name, Labels: podLabels}}
+}
+
+func isResourceCacheSynced(status ResourceCacheStatus) bool {
+	for _, resource := range status.Resources {
+		if !resource.Synced {
+			return false
+		}
+	}
+	return true
+}
+
+func TestResourceCache(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(
+		newCachedPod("b", "p3", map[string]string{"app": "foo"}),
+		newCachedPod("a", "p2", map[string]string{"app": "bar"}),
+		newCachedPod("a", "p1", map[string]string{"app": "foo"}),
+	)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	resourceCache := NewResourceCache(fakeClient)
+	resourceCache.Run(stopCh)
+	SetResourceCache(resourceCache)
+	defer SetResourceCache(nil)
+	for i := 0; !isResourceCacheSynced(GetResourceCacheStatus()); i++ {
+		if i == 100 {
+			t.Fatal("Resource cache did not sync")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cases := []struct {
+		info          string
+		nsQuery       *NamespaceQuery
+		options       api.ListOptions
+		expected      []string
+		expectedCalls int
+	}{
+		{"all namespaces", NewNamespaceQuery(nil), ListEverything, []string{"p1", "p2", "p3"}, 0},
+		{"single namespace", NewSameNamespaceQuery("a"), ListEverything, []string{"p1", "p2"}, 0},
+		{
+			"label selector",
+			NewNamespaceQuery(nil),
+			api.ListOptions{
+				LabelSelector: labels.SelectorFromSet(labels.Set{"app": "foo"}),
+				FieldSelector: fields.Everything(),
+			},
+			[]string{"p1", "p3"},
+			0,
+		},
+		{
+			"field selector is sent to apiserver",
+			NewNamespaceQuery(nil),
+			api.ListOptions{
+				LabelSelector: labels.Everything(),
+				FieldSelector: fields.OneTermEqualSelector("metadata.name", "p1"),
+			},
+			// Fake client ignores field selectors.
+			[]string{"p3", "p2", "p1"},
+			1,
+		},
+	}
+
+	for _, c := range cases {
+		fakeClient.ClearActions()
+		channel := GetPodListChannelWithOptions(fakeClient, c.nsQuery, c.options, 1)
+		list := <-channel.List
+		if err := <-channel.Error; err != nil {
+			t.Errorf("Test Case: %s. Unexpected error: %v", c.info, err)
+		}
+		if actual := getPodNames(list); !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Test Case: %s. Got pods %v, expected %v", c.info, actual, c.expected)
+		}
+		if actual := len(fakeClient.Actions()); actual != c.expectedCalls {
+			t.Errorf("Test Case: %s. Made %d calls to apiserver, expected %d", c.info, actual,
+				c.expectedCalls)
+		}
+	}
+
+	// Lists are copies of cached objects.
+	channel := GetPodListChannel(fakeClient, NewNamespaceQuery(nil), 1)
+	list := <-channel.List
+	<-channel.Error
+	list.Items[0].Labels["app"] = "changed"
+	channel = GetPodListChannel(fakeClient, NewNamespaceQuery(nil), 1)
+	list = <-channel.List
+	<-channel.Error
+	if actual := list.Items[0].Labels["app"]; actual != "foo" {
+		t.Errorf("Change of a listed pod modified the cache, got label app=%s", actual)
+	}
+
+	// Node lists are served from the cache.
+	fakeClient.ClearActions()
+	nodeChannel := GetNodeListChannel(fakeClient, 1)
+	<-nodeChannel.List
+	<-nodeChannel.Error
+	if actual := len(fakeClient.Actions()); actual != 0 {
+		t.Errorf("Made %d calls to apiserver to list nodes, expected 0", actual)
+	}
+
+	// Requests of other clients are sent directly to the apiserver.
+	otherClient := fake.NewSimpleClientset()
+	channel = GetPodListChannel(otherClient, NewNamespaceQuery(nil), 1)
+	<-channel.List
+	<-channel.Error
+	if actual := len(otherClient.Actions()); actual != 1 {
+		t.Errorf("Made %d calls with other client, expected 1", actual)
+	}
+
+	status := GetResourceCacheStatus()
+	if !status.Enabled || len(status.Resources) != 11 {
+		t.Errorf("Expected enabled cache of 11 resources, got %#v", status)
+	}
+	for _, resource := range status.Resources {
+		if resource.SyncedAt == nil || resource.LastUpdate == nil {
+			t.Errorf("Expected synced cache of %s, got %#v", resource.Kind, resource)
+		}
+	}
+}
+
+func TestResourceCacheDisabled(t *testing.T) {
+	status := GetResourceCacheStatus()
+	expected := ResourceCacheStatus{Enabled: false, Resources: make([]CachedResourceStatus, 0)}
+	if !reflect.DeepEqual(status, expected) {
+		t.Errorf("GetResourceCacheStatus() == %#v, expected %#v", status, expected)
+	}
+}
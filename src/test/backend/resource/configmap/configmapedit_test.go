@@ -0,0 +1,182 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configmap
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/kubernetes/pkg/api"
+	k8serrors "k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset/fake"
+)
+
+func TestCreateConfigMap(t *testing.T) {
+	cases := []struct {
+		info          string
+		spec          *ConfigMapSpec
+		expectedData  map[string]string
+		expectedError bool
+	}{
+		{
+			"values and files",
+			&ConfigMapSpec{
+				Name: "foo", Namespace: "bar",
+				Data:  map[string]string{"log.level": "debug"},
+				Files: map[string][]byte{"app.properties": []byte("a=b\n")},
+			},
+			map[string]string{"log.level": "debug", "app.properties": "a=b\n"},
+			false,
+		},
+		{
+			"binary file",
+			&ConfigMapSpec{Name: "foo", Namespace: "bar",
+				Files: map[string][]byte{"app.bin": {0xff, 0xfe}}},
+			nil,
+			true,
+		},
+		{
+			"invalid key",
+			&ConfigMapSpec{Name: "foo", Namespace: "bar",
+				Data: map[string]string{"a/b": "c"}},
+			nil,
+			true,
+		},
+		{
+			"key given twice",
+			&ConfigMapSpec{Name: "foo", Namespace: "bar",
+				Data:  map[string]string{"a": "b"},
+				Files: map[string][]byte{"a": []byte("c")}},
+			nil,
+			true,
+		},
+	}
+
+	for _, c := range cases {
+		actual, err := CreateConfigMap(fake.NewSimpleClientset(), c.spec)
+		if (err != nil) != c.expectedError {
+			t.Errorf("Test Case: %s. Got error %v, expected error: %t", c.info, err,
+				c.expectedError)
+			continue
+		}
+		if err != nil {
+			if !k8serrors.IsBadRequest(err) {
+				t.Errorf("Test Case: %s. Expected bad request error, got %v", c.info, err)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(actual.Data, c.expectedData) {
+			t.Errorf("Test Case: %s. Got data %v, expected %v", c.info, actual.Data,
+				c.expectedData)
+		}
+	}
+}
+
+func TestSetAndDeleteConfigMapKey(t *testing.T) {
+	configMap := &api.ConfigMap{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "bar", ResourceVersion: "5"},
+		Data:       map[string]string{"a": "1"},
+	}
+
+	cases := []struct {
+		info          string
+		operation     func(client *fake.Clientset) (*ConfigMapDetail, error)
+		expectedData  map[string]string
+		expectedError func(err error) bool
+	}{
+		{
+			"add key",
+			func(client *fake.Clientset) (*ConfigMapDetail, error) {
+				return SetConfigMapKey(client, "bar", "foo", "b",
+					&ConfigMapKeySpec{Value: []byte("2"), ResourceVersion: "5"})
+			},
+			map[string]string{"a": "1", "b": "2"},
+			nil,
+		},
+		{
+			"update key",
+			func(client *fake.Clientset) (*ConfigMapDetail, error) {
+				return SetConfigMapKey(client, "bar", "foo", "a",
+					&ConfigMapKeySpec{Value: []byte("3"), ResourceVersion: "5"})
+			},
+			map[string]string{"a": "3"},
+			nil,
+		},
+		{
+			"stale resource version",
+			func(client *fake.Clientset) (*ConfigMapDetail, error) {
+				return SetConfigMapKey(client, "bar", "foo", "a",
+					&ConfigMapKeySpec{Value: []byte("3"), ResourceVersion: "4"})
+			},
+			nil,
+			k8serrors.IsConflict,
+		},
+		{
+			"missing resource version",
+			func(client *fake.Clientset) (*ConfigMapDetail, error) {
+				return SetConfigMapKey(client, "bar", "foo", "a", &ConfigMapKeySpec{Value: []byte("3")})
+			},
+			nil,
+			k8serrors.IsBadRequest,
+		},
+		{
+			"binary value",
+			func(client *fake.Clientset) (*ConfigMapDetail, error) {
+				return SetConfigMapKey(client, "bar", "foo", "a",
+					&ConfigMapKeySpec{Value: []byte{0xff, 0xfe, 0x00}, ResourceVersion: "5"})
+			},
+			nil,
+			k8serrors.IsBadRequest,
+		},
+		{
+			"delete key",
+			func(client *fake.Clientset) (*ConfigMapDetail, error) {
+				return DeleteConfigMapKey(client, "bar", "foo", "a", "5")
+			},
+			map[string]string{},
+			nil,
+		},
+		{
+			"delete missing key",
+			func(client *fake.Clientset) (*ConfigMapDetail, error) {
+				return DeleteConfigMapKey(client, "bar", "foo", "b", "5")
+			},
+			nil,
+			k8serrors.IsNotFound,
+		},
+	}
+
+	for _, c := range cases {
+		copied, err := api.Scheme.Copy(configMap)
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual, err := c.operation(fake.NewSimpleClientset(copied))
+		if c.expectedError != nil {
+			if err == nil || !c.expectedError(err) {
+				t.Errorf("Test Case: %s. Got unexpected error %v", c.info, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test Case: %s. Got unexpected error %v", c.info, err)
+			continue
+		}
+		if !reflect.DeepEqual(actual.Data, c.expectedData) {
+			t.Errorf("Test Case: %s. Got data %v, expected %v", c.info, actual.Data,
+				c.expectedData)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/logs"
+	"k8s.io/kubernetes/pkg/api"
+)
+
+func TestReadLogStream(t *testing.T) {
+	cases := []struct {
+		info      string
+		rawLogs   string
+		reference *logs.LogLineId
+		expected  []logs.LogLine
+	}{
+		{
+			"pass all lines when there is no reference",
+			"1 log1\n2 log2\n2 log3\n",
+			nil,
+			[]logs.LogLine{
+				{Id: logs.LogLineId{LogTimestamp: "1", LineNum: 1}, Content: "1 log1"},
+				{Id: logs.LogLineId{LogTimestamp: "2", LineNum: 1}, Content: "2 log2"},
+				{Id: logs.LogLineId{LogTimestamp: "2", LineNum: 2}, Content: "2 log3"},
+			},
+		},
+		{
+			"pass all lines when reference points to the newest line",
+			"1 log1\n2 log2",
+			&logs.NewestLogLineId,
+			[]logs.LogLine{
+				{Id: logs.LogLineId{LogTimestamp: "1", LineNum: 1}, Content: "1 log1"},
+				{Id: logs.LogLineId{LogTimestamp: "2", LineNum: 1}, Content: "2 log2"},
+			},
+		},
+		{
+			"skip lines up to the reference line",
+			"1 log1\n2 log2\n3 log3\n4 log4",
+			&logs.LogLineId{LogTimestamp: "2", LineNum: 1},
+			[]logs.LogLine{
+				{Id: logs.LogLineId{LogTimestamp: "3", LineNum: 1}, Content: "3 log3"},
+				{Id: logs.LogLineId{LogTimestamp: "4", LineNum: 1}, Content: "4 log4"},
+			},
+		},
+		{
+			"resume after a positive reference among duplicated timestamps",
+			"1 log1\n2 log2\n2 log3\n2 log4\n3 log5",
+			&logs.LogLineId{LogTimestamp: "2", LineNum: 2},
+			[]logs.LogLine{
+				{Id: logs.LogLineId{LogTimestamp: "2", LineNum: 3}, Content: "2 log4"},
+				{Id: logs.LogLineId{LogTimestamp: "3", LineNum: 1}, Content: "3 log5"},
+			},
+		},
+		{
+			"resume after a negative reference among duplicated timestamps",
+			"1 log1\n2 log2\n2 log3\n2 log4\n3 log5",
+			&logs.LogLineId{LogTimestamp: "2", LineNum: -2},
+			[]logs.LogLine{
+				{Id: logs.LogLineId{LogTimestamp: "2", LineNum: 3}, Content: "2 log4"},
+				{Id: logs.LogLineId{LogTimestamp: "3", LineNum: 1}, Content: "3 log5"},
+			},
+		},
+		{
+			"flush held back lines when the stream ends",
+			"2 log2\n2 log3\n2 log4",
+			&logs.LogLineId{LogTimestamp: "2", LineNum: -3},
+			[]logs.LogLine{
+				{Id: logs.LogLineId{LogTimestamp: "2", LineNum: 2}, Content: "2 log3"},
+				{Id: logs.LogLineId{LogTimestamp: "2", LineNum: 3}, Content: "2 log4"},
+			},
+		},
+		{
+			"compare RFC3339 timestamps as times",
+			"2016-10-01T10:00:00Z log1\n2016-10-01T10:00:00.5Z log2\n2016-10-01T10:00:01Z log3",
+			&logs.LogLineId{LogTimestamp: "2016-10-01T10:00:00Z", LineNum: 1},
+			[]logs.LogLine{
+				{
+					Id:      logs.LogLineId{LogTimestamp: "2016-10-01T10:00:00.5Z", LineNum: 1},
+					Content: "2016-10-01T10:00:00.5Z log2",
+				},
+				{
+					Id:      logs.LogLineId{LogTimestamp: "2016-10-01T10:00:01Z", LineNum: 1},
+					Content: "2016-10-01T10:00:01Z log3",
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		var actual []logs.LogLine
+		err := ReadLogStream(strings.NewReader(c.rawLogs), c.reference, func(line logs.LogLine) error {
+			actual = append(actual, line)
+			return nil
+		})
+		if err != nil {
+			t.Errorf("Test Case: %s. Unexpected error: %s", c.info, err)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Test Case: %s.\nReceived: %#v \nExpected: %#v\n\n", c.info, actual, c.expected)
+		}
+	}
+}
+
+func TestReadLogStreamStopsOnError(t *testing.T) {
+	expectedErr := errors.New("connection closed")
+	calls := 0
+	err := ReadLogStream(strings.NewReader("1 log1\n2 log2\n3 log3"), nil, func(line logs.LogLine) error {
+		calls++
+		return expectedErr
+	})
+
+	if err != expectedErr {
+		t.Errorf("ReadLogStream() returned %v, expected %v", err, expectedErr)
+	}
+	if calls != 1 {
+		t.Errorf("ReadLogStream() called lineFn %d times, expected 1", calls)
+	}
+}
+
+func TestSetLogStreamStart(t *testing.T) {
+	cases := []struct {
+		info              string
+		reference         *logs.LogLineId
+		expectedReference *logs.LogLineId
+		expectTail        bool
+		expectSince       bool
+	}{
+		{"stream new lines only without reference", nil, nil, true, false},
+		{"stream new lines only for the newest line", &logs.NewestLogLineId, nil, true, false},
+		{"stream whole log for the oldest line", &logs.OldestLogLineId, nil, false, false},
+		{
+			"resume from reference timestamp",
+			&logs.LogLineId{LogTimestamp: "2016-10-01T10:00:00.5Z", LineNum: 1},
+			&logs.LogLineId{LogTimestamp: "2016-10-01T10:00:00.5Z", LineNum: 1},
+			false,
+			true,
+		},
+	}
+
+	for _, c := range cases {
+		logOptions := &api.PodLogOptions{}
+		actual := setLogStreamStart(logOptions, c.reference)
+		if !reflect.DeepEqual(actual, c.expectedReference) {
+			t.Errorf("Test Case: %s. Returned reference %#v, expected %#v", c.info, actual,
+				c.expectedReference)
+		}
+		if (logOptions.TailLines != nil) != c.expectTail {
+			t.Errorf("Test Case: %s. TailLines set: %t, expected %t", c.info,
+				logOptions.TailLines != nil, c.expectTail)
+		}
+		if (logOptions.SinceTime != nil) != c.expectSince {
+			t.Errorf("Test Case: %s. SinceTime set: %t, expected %t", c.info,
+				logOptions.SinceTime != nil, c.expectSince)
+		}
+	}
+}
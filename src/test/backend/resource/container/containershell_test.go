@@ -0,0 +1,199 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+
+	"k8s.io/kubernetes/pkg/client/restclient"
+)
+
+type frame struct {
+	channel byte
+	data    string
+}
+
+type fakeChannelStream struct {
+	protocol string
+	toRead   chan frame
+	mutex    sync.Mutex
+	written  []frame
+}
+
+func (self *fakeChannelStream) ReadFrame() (byte, []byte, error) {
+	f, ok := <-self.toRead
+	if !ok {
+		return 0, nil, io.EOF
+	}
+	return f.channel, []byte(f.data), nil
+}
+
+func (self *fakeChannelStream) WriteFrame(channel byte, data []byte) error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	self.written = append(self.written, frame{channel, string(data)})
+	return nil
+}
+
+func (self *fakeChannelStream) Protocol() string {
+	return self.protocol
+}
+
+type fakeTerminalSession struct {
+	toRead  chan *TerminalMessage
+	written []*TerminalMessage
+}
+
+func (self *fakeTerminalSession) Read() (*TerminalMessage, error) {
+	message, ok := <-self.toRead
+	if !ok {
+		return nil, io.EOF
+	}
+	return message, nil
+}
+
+func (self *fakeTerminalSession) Write(message *TerminalMessage) error {
+	self.written = append(self.written, message)
+	return nil
+}
+
+func TestProxyTerminalForwardsOutput(t *testing.T) {
+	stream := &fakeChannelStream{protocol: channelProtocolV4, toRead: make(chan frame, 3)}
+	stream.toRead <- frame{stdoutChannel, "hello\n"}
+	stream.toRead <- frame{stderrChannel, "oops\n"}
+	stream.toRead <- frame{errorChannel, `{"status":"Success"}`}
+	session := &fakeTerminalSession{toRead: make(chan *TerminalMessage)}
+
+	if err := proxyTerminal(session, stream); err != nil {
+		t.Fatalf("proxyTerminal() returned unexpected error: %s", err)
+	}
+
+	expected := []*TerminalMessage{
+		{Op: TerminalStdout, Data: "hello\n"},
+		{Op: TerminalStderr, Data: "oops\n"},
+		{Op: TerminalExit, Data: "0"},
+	}
+	if !reflect.DeepEqual(session.written, expected) {
+		t.Errorf("proxyTerminal() sent %#v, expected %#v", session.written, expected)
+	}
+}
+
+func TestProxyTerminalForwardsInput(t *testing.T) {
+	cases := []struct {
+		protocol string
+		expected []frame
+	}{
+		{
+			channelProtocolV4,
+			[]frame{
+				{stdinChannel, "ls\n"},
+				{resizeChannel, `{"Width":80,"Height":24}`},
+			},
+		},
+		{
+			channelProtocol,
+			[]frame{
+				{stdinChannel, "ls\n"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		stream := &fakeChannelStream{protocol: c.protocol, toRead: make(chan frame)}
+		session := &fakeTerminalSession{toRead: make(chan *TerminalMessage, 3)}
+		session.toRead <- &TerminalMessage{Op: TerminalStdin, Data: "ls\n"}
+		session.toRead <- &TerminalMessage{Op: TerminalResize, Cols: 80, Rows: 24}
+		session.toRead <- &TerminalMessage{Op: "unknown"}
+		close(session.toRead)
+
+		if err := proxyTerminal(session, stream); err != nil {
+			t.Fatalf("proxyTerminal() returned unexpected error: %s", err)
+		}
+
+		if !reflect.DeepEqual(stream.written, c.expected) {
+			t.Errorf("proxyTerminal() with protocol %s wrote %#v, expected %#v", c.protocol,
+				stream.written, c.expected)
+		}
+	}
+}
+
+func TestGetExitMessage(t *testing.T) {
+	cases := []struct {
+		protocol string
+		data     string
+		expected *TerminalMessage
+	}{
+		{channelProtocolV4, "", nil},
+		{channelProtocolV4, `{"status":"Success"}`, &TerminalMessage{Op: TerminalExit, Data: "0"}},
+		{
+			channelProtocolV4,
+			`{"status":"Failure","message":"command terminated with non-zero exit code",` +
+				`"reason":"NonZeroExitCode","details":{"causes":[{"reason":"ExitCode","message":"127"}]}}`,
+			&TerminalMessage{Op: TerminalExit, Data: "127"},
+		},
+		{
+			channelProtocolV4,
+			`{"status":"Failure","message":"container not found"}`,
+			&TerminalMessage{Op: TerminalError, Data: "container not found"},
+		},
+		{channelProtocol, "exec failed", &TerminalMessage{Op: TerminalError, Data: "exec failed"}},
+	}
+
+	for _, c := range cases {
+		actual := getExitMessage(c.protocol, []byte(c.data))
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("getExitMessage(%s, %s) == %#v, expected %#v", c.protocol, c.data, actual,
+				c.expected)
+		}
+	}
+}
+
+func TestGetAuthHeaders(t *testing.T) {
+	cases := []struct {
+		config   *restclient.Config
+		header   string
+		expected string
+	}{
+		{
+			&restclient.Config{Host: "https://localhost", BearerToken: "token"},
+			"Authorization",
+			"Bearer token",
+		},
+		{
+			&restclient.Config{Host: "https://localhost", Username: "admin", Password: "admin"},
+			"Authorization",
+			"Basic YWRtaW46YWRtaW4=",
+		},
+		{
+			&restclient.Config{Host: "https://localhost", Impersonate: "jane"},
+			"Impersonate-User",
+			"jane",
+		},
+	}
+
+	for _, c := range cases {
+		header, err := getAuthHeaders(c.config)
+		if err != nil {
+			t.Fatalf("getAuthHeaders() returned unexpected error: %s", err)
+		}
+		if actual := header.Get(c.header); actual != c.expected {
+			t.Errorf("getAuthHeaders() set %s header to %s, expected %s", c.header, actual,
+				c.expected)
+		}
+	}
+}
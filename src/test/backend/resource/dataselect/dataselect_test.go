@@ -222,3 +222,19 @@ func TestPagination(t *testing.T) {
 	}
 
 }
+
+func TestCollect(t *testing.T) {
+	dsQuery := NewDataSelectQuery(NewPaginationQuery(2, 1), NewSortQuery([]string{"a", "creationTimestamp"}),
+		NoFilter, NoMetrics)
+	dsQuery.Collector = new(DataCellCollector)
+
+	selected, _ := GenericDataSelectWithFilter(getDataCellList(), dsQuery)
+
+	if !reflect.DeepEqual(dsQuery.Collector.Cells, selected) {
+		t.Errorf("Collector received %v, expected selected cells %v",
+			fromCells(dsQuery.Collector.Cells), fromCells(selected))
+	}
+	if actual := getOrder(fromCells(dsQuery.Collector.Cells)); !reflect.DeepEqual(actual, []int{3, 4}) {
+		t.Errorf("Collector received cells in order %v, expected [3 4]", actual)
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataselect
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type FilterTestDataCell struct {
+	Name    string
+	Replica int
+	Created time.Time
+	Labels  map[string]string
+}
+
+func (self FilterTestDataCell) GetProperty(name PropertyName) ComparableValue {
+	switch name {
+	case NameProperty:
+		return StdComparableString(self.Name)
+	case StatusProperty:
+		return StdComparableInt(self.Replica)
+	case CreationTimestampProperty:
+		return StdComparableTime(self.Created)
+	case LabelsProperty:
+		return StdComparableLabels(self.Labels)
+	default:
+		return nil
+	}
+}
+
+func getFilterDataCellList() []DataCell {
+	now := time.Now()
+	return []DataCell{
+		FilterTestDataCell{"nginx", 1, now.Add(-time.Minute), map[string]string{"app": "nginx", "tier": "web"}},
+		FilterTestDataCell{"nginx-canary", 2, now.Add(-2 * time.Hour), map[string]string{"app": "nginx"}},
+		FilterTestDataCell{"web", 3, now.Add(-48 * time.Hour), map[string]string{"tier": "web"}},
+		FilterTestDataCell{"redis", 4, now.Add(-time.Hour), nil},
+	}
+}
+
+func TestNewFilterQuery(t *testing.T) {
+	cases := []struct {
+		info     string
+		raw      []string
+		expected *FilterQuery
+	}{
+		{"nil list", nil, NoFilter},
+		{"empty list", []string{""}, NoFilter},
+		{
+			"legacy property and value pairs",
+			[]string{"name", "nginx", "namespace", "default"},
+			&FilterQuery{FilterByList: []FilterBy{
+				{Conditions: []FilterCondition{{NameProperty, EqualOperator, "nginx"}}},
+				{Conditions: []FilterCondition{{NamespaceProperty, EqualOperator, "default"}}},
+			}},
+		},
+		{"legacy property without value", []string{"name"}, NoFilter},
+		{
+			"operators",
+			[]string{"name~=ngi", "name!=web", "status>=2", "labels=app=nginx"},
+			&FilterQuery{FilterByList: []FilterBy{
+				{Conditions: []FilterCondition{{NameProperty, ContainsOperator, "ngi"}}},
+				{Conditions: []FilterCondition{{NameProperty, NotEqualOperator, "web"}}},
+				{Conditions: []FilterCondition{{StatusProperty, GreaterOrEqualOperator, "2"}}},
+				{Conditions: []FilterCondition{{LabelsProperty, EqualOperator, "app=nginx"}}},
+			}},
+		},
+		{
+			"alternative conditions",
+			[]string{"name^=nginx|name==web"},
+			&FilterQuery{FilterByList: []FilterBy{
+				{Conditions: []FilterCondition{
+					{NameProperty, PrefixOperator, "nginx"},
+					{NameProperty, DoubleEqualOperator, "web"},
+				}},
+			}},
+		},
+		{"condition without operator in a group", []string{"name^=nginx|web"}, NoFilter},
+		{
+			"quoted values",
+			[]string{`labels="app=nginx,tier in (web|api)"|name="a\"b"`},
+			&FilterQuery{FilterByList: []FilterBy{
+				{Conditions: []FilterCondition{
+					{LabelsProperty, EqualOperator, "app=nginx,tier in (web|api)"},
+					{NameProperty, EqualOperator, `a"b`},
+				}},
+			}},
+		},
+		{"malformed quoted value", []string{`name="nginx`}, NoFilter},
+	}
+
+	for _, c := range cases {
+		actual := NewFilterQuery(c.raw)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Test Case: %s. NewFilterQuery(%#v) == %#v, expected %#v", c.info, c.raw,
+				actual, c.expected)
+		}
+	}
+}
+
+func TestSplitFilterByList(t *testing.T) {
+	cases := []struct {
+		raw      string
+		expected []string
+	}{
+		{"", []string{""}},
+		{"name,nginx", []string{"name", "nginx"}},
+		{`labels="app=a,tier=b",name~=x`, []string{`labels="app=a,tier=b"`, "name~=x"}},
+		{`labels="env in (a,b)"|name="x\",y",status>1`,
+			[]string{`labels="env in (a,b)"|name="x\",y"`, "status>1"}},
+	}
+
+	for _, c := range cases {
+		actual := SplitFilterByList(c.raw)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("SplitFilterByList(%s) == %#v, expected %#v", c.raw, actual, c.expected)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	cases := []struct {
+		info     string
+		raw      []string
+		expected []string
+	}{
+		{"no filter", nil, []string{"nginx", "nginx-canary", "web", "redis"}},
+		{"legacy equality", []string{"name", "web"}, []string{"web"}},
+		{"contains", []string{"name~=ngin"}, []string{"nginx", "nginx-canary"}},
+		{"prefix", []string{"name^=nginx-"}, []string{"nginx-canary"}},
+		{"not equal", []string{"name!=nginx"}, []string{"nginx-canary", "web", "redis"}},
+		{"integer comparison", []string{"status>2"}, []string{"web", "redis"}},
+		{"integer with invalid value", []string{"status>two"}, []string{}},
+		{"created within last 90 minutes", []string{"creationTimestamp>90m"}, []string{"nginx", "redis"}},
+		{"created before last day", []string{"creationTimestamp<=24h"}, []string{"web"}},
+		{"label selector", []string{"labels=app=nginx"}, []string{"nginx", "nginx-canary"}},
+		{"set based label selector", []string{"labels=tier in (web)"}, []string{"nginx", "web"}},
+		{"all label selectors", []string{"labels=app=nginx", "labels=tier=web"}, []string{"nginx"}},
+		{"negated label selector", []string{"labels!=app"}, []string{"web", "redis"}},
+		{"alternative conditions", []string{"name==web|name==redis"}, []string{"web", "redis"}},
+		{
+			"all groups have to match",
+			[]string{"name^=nginx|name==web", "status<3"},
+			[]string{"nginx", "nginx-canary"},
+		},
+		{"unknown property", []string{"foo==bar"}, []string{}},
+	}
+
+	for _, c := range cases {
+		selector := DataSelector{
+			GenericDataList: getFilterDataCellList(),
+			DataSelectQuery: &DataSelectQuery{FilterQuery: NewFilterQuery(c.raw)},
+		}
+		actual := []string{}
+		for _, cell := range selector.Filter().GenericDataList {
+			actual = append(actual, cell.(FilterTestDataCell).Name)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Test Case: %s. Filter(%#v) selected %v, expected %v", c.info, c.raw, actual,
+				c.expected)
+		}
+	}
+}
+
+func TestGenericDataSelectWithFilter(t *testing.T) {
+	dsQuery := NewDataSelectQuery(NewPaginationQuery(1, 0), NoSort,
+		NewFilterQuery([]string{"name~=nginx"}), NoMetrics)
+
+	selected, filteredTotal := GenericDataSelectWithFilter(getFilterDataCellList(), dsQuery)
+
+	if filteredTotal != 2 {
+		t.Errorf("GenericDataSelectWithFilter() returned total %d, expected 2", filteredTotal)
+	}
+	if len(selected) != 1 || selected[0].(FilterTestDataCell).Name != "nginx" {
+		t.Errorf("GenericDataSelectWithFilter() selected %v, expected first page with nginx",
+			selected)
+	}
+}
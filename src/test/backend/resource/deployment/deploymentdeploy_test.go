@@ -15,15 +15,28 @@
 package deployment
 
 import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
 	"reflect"
 	"regexp"
+	"strconv"
+	"strings"
 	"testing"
 
 	"k8s.io/kubernetes/pkg/api"
+	k8serrors "k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/api/meta"
+	"k8s.io/kubernetes/pkg/api/testapi"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/api/v1"
 	"k8s.io/kubernetes/pkg/apis/extensions"
 	"k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset/fake"
+	restfake "k8s.io/kubernetes/pkg/client/restclient/fake"
 	"k8s.io/kubernetes/pkg/client/testing/core"
 	kubectlResource "k8s.io/kubernetes/pkg/kubectl/resource"
+	"k8s.io/kubernetes/pkg/runtime"
 
 	"k8s.io/kubernetes/pkg/api/resource"
 )
@@ -33,6 +46,7 @@ func TestDeployApp(t *testing.T) {
 	spec := &AppDeploymentSpec{
 		Namespace:       namespace,
 		Name:            "foo-name",
+		ContainerImage:  "foo-image",
 		RunAsPrivileged: true,
 	}
 
@@ -51,7 +65,8 @@ func TestDeployApp(t *testing.T) {
 				},
 				Spec: api.PodSpec{
 					Containers: []api.Container{{
-						Name: "foo-name",
+						Name:  "foo-name",
+						Image: "foo-image",
 						SecurityContext: &api.SecurityContext{
 							Privileged: &spec.RunAsPrivileged,
 						},
@@ -84,12 +99,220 @@ func TestDeployApp(t *testing.T) {
 	}
 }
 
+func TestDeployAppRollsBackOnFailure(t *testing.T) {
+	spec := &AppDeploymentSpec{
+		Namespace:      "foo-namespace",
+		Name:           "foo-name",
+		ContainerImage: "foo-image",
+		PortMappings:   []PortMapping{{Port: 80, TargetPort: 8080, Protocol: api.ProtocolTCP}},
+	}
+	testClient := fake.NewSimpleClientset()
+	testClient.PrependReactor("create", "deployments",
+		func(action core.Action) (bool, runtime.Object, error) {
+			return true, action.(core.CreateAction).GetObject(), nil
+		})
+	testClient.PrependReactor("delete", "deployments",
+		func(action core.Action) (bool, runtime.Object, error) {
+			return true, nil, nil
+		})
+	testClient.PrependReactor("create", "services",
+		func(action core.Action) (bool, runtime.Object, error) {
+			return true, nil, k8serrors.NewForbidden(api.Resource("services"), "foo-name",
+				errors.New("denied"))
+		})
+
+	result, err := DeployApp(spec, testClient)
+	if err != nil {
+		t.Fatalf("DeployApp() returned error: %s", err)
+	}
+	if !result.RolledBack {
+		t.Errorf("DeployApp() did not roll back")
+	}
+	actions := []string{}
+	for _, status := range result.Objects {
+		actions = append(actions, status.Kind+" "+status.Action)
+	}
+	expected := []string{"Deployment " + ObjectRolledBack, "Service " + ObjectFailed}
+	if !reflect.DeepEqual(actions, expected) {
+		t.Errorf("DeployApp() returned objects %#v, expected %#v", actions, expected)
+	}
+	lastAction := testClient.Actions()[len(testClient.Actions())-1]
+	if !lastAction.Matches("delete", "deployments") {
+		t.Errorf("DeployApp() did not delete the deployment, last action: %#v", lastAction)
+	}
+}
+
+func TestDeployAppFailsOnFirstObject(t *testing.T) {
+	spec := &AppDeploymentSpec{Namespace: "foo-namespace", Name: "foo-name",
+		ContainerImage: "foo-image"}
+	testClient := fake.NewSimpleClientset()
+	testClient.PrependReactor("create", "deployments",
+		func(action core.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("denied")
+		})
+
+	if _, err := DeployApp(spec, testClient); err == nil {
+		t.Errorf("DeployApp() returned no error")
+	}
+}
+
+func TestDeployTransactionRollBack(t *testing.T) {
+	deleted := []string{}
+	deleteFn := func(name string, err error) func() error {
+		return func() error {
+			deleted = append(deleted, name)
+			return err
+		}
+	}
+	transaction := new(deployTransaction)
+	transaction.add(DeployedObjectStatus{Name: "a", Action: ObjectCreated}, deleteFn("a", nil))
+	transaction.add(DeployedObjectStatus{Name: "b", Action: ObjectUpdated}, deleteFn("b", nil))
+	transaction.add(DeployedObjectStatus{Name: "c", Action: ObjectCreated},
+		deleteFn("c", errors.New("denied")))
+	transaction.add(DeployedObjectStatus{Name: "d", Action: ObjectCreated},
+		deleteFn("d", k8serrors.NewNotFound(api.Resource("services"), "d")))
+	transaction.add(DeployedObjectStatus{Name: "e", Action: ObjectFailed, Error: "invalid"},
+		deleteFn("e", nil))
+
+	if !transaction.failed() {
+		t.Errorf("failed() == false, expected true")
+	}
+	if !transaction.rollBack() {
+		t.Errorf("rollBack() == false, expected true")
+	}
+
+	expected := []DeployedObjectStatus{
+		{Name: "a", Action: ObjectRolledBack},
+		{Name: "b", Action: ObjectUpdated},
+		{Name: "c", Action: ObjectCreated, Error: "Rollback failed: denied"},
+		{Name: "d", Action: ObjectRolledBack},
+		{Name: "e", Action: ObjectFailed, Error: "invalid"},
+	}
+	if !reflect.DeepEqual(transaction.objects, expected) {
+		t.Errorf("rollBack() left objects \n%#v\nexpected \n%#v", transaction.objects, expected)
+	}
+	if !reflect.DeepEqual(deleted, []string{"d", "c", "a"}) {
+		t.Errorf("rollBack() deleted %#v, expected %#v", deleted, []string{"d", "c", "a"})
+	}
+	expectedErrors := []string{"Rollback failed: denied", "invalid"}
+	if actual := transaction.errors(); !reflect.DeepEqual(actual, expectedErrors) {
+		t.Errorf("errors() == %#v, expected %#v", actual, expectedErrors)
+	}
+	if !transaction.deployedAny() {
+		t.Errorf("deployedAny() == false, expected true for updated object")
+	}
+
+	transaction = new(deployTransaction)
+	transaction.add(DeployedObjectStatus{Name: "a", Action: ObjectCreated}, deleteFn("a", nil))
+	transaction.add(DeployedObjectStatus{Name: "b", Action: ObjectFailed, Error: "invalid"},
+		deleteFn("b", nil))
+	transaction.rollBack()
+	if transaction.deployedAny() {
+		t.Errorf("deployedAny() == true, expected false when every object is rolled back")
+	}
+}
+
+func TestGetPodTemplate(t *testing.T) {
+	cpu := resource.MustParse("100m")
+	memory := resource.MustParse("64Mi")
+	spec := &AppDeploymentSpec{
+		Name:           "foo-name",
+		ContainerImage: "foo-image",
+		CpuLimit:       &cpu,
+		LivenessProbe:  &ProbeSpec{Type: HTTPProbe, Path: "/healthz", Port: 8080},
+		ReadinessProbe: &ProbeSpec{Type: ExecProbe, Command: []string{"true"}},
+		Volumes: []VolumeSpec{
+			{Name: "config", Type: ConfigMapVolume, SourceName: "foo-config"},
+			{Name: "data", Type: PersistentVolumeClaimVolume, SourceName: "foo-claim",
+				ReadOnly: true},
+		},
+		VolumeMounts: []VolumeMountSpec{{Name: "config", MountPath: "/etc/foo"}},
+		InitContainers: []ContainerSpec{{Name: "init", Image: "busybox",
+			Command: []string{"sh", "-c", "true"}}},
+		Sidecars: []ContainerSpec{{Name: "proxy", Image: "nginx", MemoryLimit: &memory,
+			VolumeMounts: []VolumeMountSpec{{Name: "data", MountPath: "/data"}}}},
+		NodeSelector: []Label{{Key: "disk", Value: "ssd"}},
+		Tolerations:  []api.Toleration{{Key: "dedicated", Value: "foo"}},
+	}
+	objectMeta := api.ObjectMeta{Name: "foo-name", Annotations: map[string]string{"a": "b"}}
+
+	template, err := getPodTemplate(spec, objectMeta)
+	if err != nil {
+		t.Fatalf("getPodTemplate() returned error: %s", err)
+	}
+
+	podSpec := template.Spec
+	if len(podSpec.Containers) != 2 || podSpec.Containers[1].Name != "proxy" {
+		t.Fatalf("getPodTemplate() returned containers %#v, expected foo-name and proxy",
+			podSpec.Containers)
+	}
+	container := podSpec.Containers[0]
+	if limit := container.Resources.Limits[api.ResourceCPU]; limit.Cmp(cpu) != 0 {
+		t.Errorf("Expected CPU limit %s but got %s", cpu.String(), limit.String())
+	}
+	if container.LivenessProbe == nil || container.LivenessProbe.HTTPGet == nil ||
+		container.LivenessProbe.HTTPGet.Port.IntValue() != 8080 {
+		t.Errorf("Expected HTTP liveness probe on port 8080 but got %#v",
+			container.LivenessProbe)
+	}
+	if container.ReadinessProbe == nil || container.ReadinessProbe.Exec == nil {
+		t.Errorf("Expected exec readiness probe but got %#v", container.ReadinessProbe)
+	}
+	expectedMounts := []api.VolumeMount{{Name: "config", MountPath: "/etc/foo"}}
+	if !reflect.DeepEqual(container.VolumeMounts, expectedMounts) {
+		t.Errorf("Expected volume mounts %#v but got %#v", expectedMounts, container.VolumeMounts)
+	}
+	expectedVolumes := []api.Volume{
+		{Name: "config", VolumeSource: api.VolumeSource{ConfigMap: &api.ConfigMapVolumeSource{
+			LocalObjectReference: api.LocalObjectReference{Name: "foo-config"}}}},
+		{Name: "data", VolumeSource: api.VolumeSource{
+			PersistentVolumeClaim: &api.PersistentVolumeClaimVolumeSource{
+				ClaimName: "foo-claim", ReadOnly: true}}},
+	}
+	if !reflect.DeepEqual(podSpec.Volumes, expectedVolumes) {
+		t.Errorf("Expected volumes %#v but got %#v", expectedVolumes, podSpec.Volumes)
+	}
+	if len(podSpec.InitContainers) != 1 || podSpec.InitContainers[0].Image != "busybox" {
+		t.Errorf("Expected busybox init container but got %#v", podSpec.InitContainers)
+	}
+	if !reflect.DeepEqual(podSpec.NodeSelector, map[string]string{"disk": "ssd"}) {
+		t.Errorf("Expected node selector disk=ssd but got %#v", podSpec.NodeSelector)
+	}
+
+	tolerations, err := api.GetTolerationsFromPodAnnotations(template.Annotations)
+	if err != nil || !reflect.DeepEqual(tolerations, spec.Tolerations) {
+		t.Errorf("Expected tolerations %#v but got %#v, error: %v", spec.Tolerations,
+			tolerations, err)
+	}
+	if template.Annotations["a"] != "b" {
+		t.Errorf("Expected annotations of the template to be kept but got %#v",
+			template.Annotations)
+	}
+	if _, ok := objectMeta.Annotations[api.TolerationsAnnotationKey]; ok {
+		t.Errorf("Expected tolerations not to be set on other objects")
+	}
+}
+
+func TestDeployAppWithInvalidSpec(t *testing.T) {
+	spec := &AppDeploymentSpec{Namespace: "foo-namespace", Name: "Foo"}
+	testClient := fake.NewSimpleClientset()
+
+	_, err := DeployApp(spec, testClient)
+	if !k8serrors.IsInvalid(err) {
+		t.Errorf("Expected invalid error but got %#v", err)
+	}
+	if len(testClient.Actions()) != 0 {
+		t.Errorf("Expected no actions but got %#v", testClient.Actions())
+	}
+}
+
 func TestDeployAppContainerCommands(t *testing.T) {
 	command := "foo-command"
 	commandArgs := "foo-command-args"
 	spec := &AppDeploymentSpec{
 		Namespace:            "foo-namespace",
 		Name:                 "foo-name",
+		ContainerImage:       "foo-image",
 		ContainerCommand:     &command,
 		ContainerCommandArgs: &commandArgs,
 	}
@@ -113,9 +336,10 @@ func TestDeployAppContainerCommands(t *testing.T) {
 
 func TestDeployShouldPopulateEnvVars(t *testing.T) {
 	spec := &AppDeploymentSpec{
-		Namespace: "foo-namespace",
-		Name:      "foo-name",
-		Variables: []EnvironmentVariable{{"foo", "bar"}},
+		Namespace:      "foo-namespace",
+		Name:           "foo-name",
+		ContainerImage: "foo-image",
+		Variables:      []EnvironmentVariable{{"foo", "bar"}},
 	}
 	testClient := fake.NewSimpleClientset()
 
@@ -150,6 +374,7 @@ func TestDeployWithResourceRequirements(t *testing.T) {
 	spec := &AppDeploymentSpec{
 		Namespace:         "foo-namespace",
 		Name:              "foo-name",
+		ContainerImage:    "foo-image",
 		CpuRequirement:    &cpuRequirement,
 		MemoryRequirement: &memoryRequirement,
 	}
@@ -195,12 +420,13 @@ func TestDeployAppFromFileWithValidContent(t *testing.T) {
 	}
 	fakeCreateObjectFromInfo := func(info *kubectlResource.Info) (bool, error) { return true, nil }
 
-	isDeployed, err := DeployAppFromFile(spec, fakeCreateObjectFromInfo, nil)
+	result, err := DeployAppFromFile(spec, fakeCreateObjectFromInfo, fakePatchObjectFromInfo,
+		fakeDeleteObjectFromInfo, nil, nil)
 	if err != nil {
 		t.Errorf("Expected err to be %#v but got %#v", nil, err)
 	}
-	if !isDeployed {
-		t.Errorf("Expected return value to have %#v but got %#v", true, isDeployed)
+	if len(result.Objects) != 1 || result.Objects[0].Action != ObjectCreated {
+		t.Errorf("Expected one created object but got %#v", result.Objects)
 	}
 }
 
@@ -212,11 +438,330 @@ func TestDeployAppFromFileWithInvalidContent(t *testing.T) {
 	// return is set to true to check if the validation prior to this function really works
 	fakeCreateObjectFromInfo := func(info *kubectlResource.Info) (bool, error) { return true, nil }
 
-	isDeployed, err := DeployAppFromFile(spec, fakeCreateObjectFromInfo, nil)
+	result, err := DeployAppFromFile(spec, fakeCreateObjectFromInfo, fakePatchObjectFromInfo,
+		fakeDeleteObjectFromInfo, nil, nil)
 	if err == nil {
 		t.Errorf("Expected return value to have an error but got %#v", nil)
 	}
-	if isDeployed {
-		t.Errorf("Expected return value to have %#v but got %#v", false, isDeployed)
+	if result != nil && len(result.Objects) != 0 {
+		t.Errorf("Expected no deployed objects but got %#v", result.Objects)
+	}
+}
+
+func TestDeployAppFromFileWithUnknownMode(t *testing.T) {
+	spec := &AppDeploymentFromFileSpec{
+		Name:    "foo-name",
+		Content: "{}",
+		Mode:    "foo-mode",
+	}
+	fakeCreateObjectFromInfo := func(info *kubectlResource.Info) (bool, error) { return true, nil }
+
+	_, err := DeployAppFromFile(spec, fakeCreateObjectFromInfo, fakePatchObjectFromInfo,
+		fakeDeleteObjectFromInfo, nil, nil)
+	if err == nil {
+		t.Error("Expected error for unknown deployment mode")
+	}
+}
+
+func fakeGetObjectFromInfo(object runtime.Object, err error) getObjectFromInfo {
+	return func(info *kubectlResource.Info) (runtime.Object, error) {
+		return object, err
+	}
+}
+
+func fakePatchObjectFromInfo(info *kubectlResource.Info, patch *ApplyPatch) error {
+	return nil
+}
+
+func fakeDeleteObjectFromInfo(info *kubectlResource.Info) error { return nil }
+
+func TestDeployObjectFromInfo(t *testing.T) {
+	info := &kubectlResource.Info{
+		Mapping: &meta.RESTMapping{
+			GroupVersionKind: unversioned.GroupVersionKind{Version: "v1", Kind: "Service"},
+			MetadataAccessor: meta.NewAccessor(),
+		},
+		Namespace: "foo-namespace",
+		Name:      "foo-name",
+		Object: &api.Service{
+			ObjectMeta: api.ObjectMeta{Name: "foo-name", Namespace: "foo-namespace"},
+			Spec:       api.ServiceSpec{Type: api.ServiceTypeLoadBalancer},
+		},
+		VersionedObject: &v1.Service{
+			ObjectMeta: v1.ObjectMeta{Name: "foo-name", Namespace: "foo-namespace"},
+			Spec:       v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+		},
+	}
+	notFound := k8serrors.NewNotFound(api.Resource("services"), "foo-name")
+	liveService := &runtime.Unknown{Raw: []byte(`{"kind": "Service", "metadata": {` +
+		`"name": "foo-name", "namespace": "foo-namespace", "resourceVersion": "7"}, ` +
+		`"spec": {"type": "ClusterIP", "clusterIP": "10.0.0.1"}, "status": {}}`)}
+	upToDateService := &runtime.Unknown{Raw: []byte(`{"kind": "Service", "metadata": {` +
+		`"name": "foo-name", "namespace": "foo-namespace"}, "spec": {"type": "LoadBalancer"}}`)}
+	typeDiff := []FieldDiff{{
+		Path:      "spec.type",
+		Operation: FieldReplaced,
+		Live:      "ClusterIP",
+		Submitted: "LoadBalancer",
+	}}
+
+	cases := []struct {
+		info            string
+		spec            *AppDeploymentFromFileSpec
+		getFn           getObjectFromInfo
+		expectedAction  string
+		expectedDiff    []FieldDiff
+		expectedCreated bool
+		expectedUpdated bool
+	}{
+		{
+			"create",
+			&AppDeploymentFromFileSpec{},
+			fakeGetObjectFromInfo(nil, nil),
+			ObjectCreated, nil, true, false,
+		},
+		{
+			"create dry run of existing object",
+			&AppDeploymentFromFileSpec{DryRun: true},
+			fakeGetObjectFromInfo(liveService, nil),
+			ObjectFailed, nil, false, false,
+		},
+		{
+			"apply of new object",
+			&AppDeploymentFromFileSpec{Mode: ApplyDeploymentMode},
+			fakeGetObjectFromInfo(nil, notFound),
+			ObjectCreated, nil, true, false,
+		},
+		{
+			"apply of changed object",
+			&AppDeploymentFromFileSpec{Mode: ApplyDeploymentMode},
+			fakeGetObjectFromInfo(liveService, nil),
+			ObjectUpdated, typeDiff, false, true,
+		},
+		{
+			"apply dry run of changed object",
+			&AppDeploymentFromFileSpec{Mode: ApplyDeploymentMode, DryRun: true},
+			fakeGetObjectFromInfo(liveService, nil),
+			ObjectUpdated, typeDiff, false, false,
+		},
+		{
+			"apply of unchanged object",
+			&AppDeploymentFromFileSpec{Mode: ApplyDeploymentMode},
+			fakeGetObjectFromInfo(upToDateService, nil),
+			ObjectUnchanged, []FieldDiff{}, false, false,
+		},
+	}
+
+	for _, c := range cases {
+		created, updated := false, false
+		createFn := func(info *kubectlResource.Info) (bool, error) {
+			created = true
+			return true, nil
+		}
+		patchFn := func(info *kubectlResource.Info, patch *ApplyPatch) error {
+			updated = true
+			return nil
+		}
+
+		status := deployObjectFromInfo(c.spec, info, createFn, patchFn, c.getFn)
+		if status.Action != c.expectedAction {
+			t.Errorf("Test Case: %s. Action == %s, expected %s. Error: %s", c.info, status.Action,
+				c.expectedAction, status.Error)
+		}
+		if !reflect.DeepEqual(status.Diff, c.expectedDiff) {
+			t.Errorf("Test Case: %s. Diff == %#v, expected %#v", c.info, status.Diff,
+				c.expectedDiff)
+		}
+		if created != c.expectedCreated || updated != c.expectedUpdated {
+			t.Errorf("Test Case: %s. Created: %t, updated: %t, expected %t, %t", c.info, created,
+				updated, c.expectedCreated, c.expectedUpdated)
+		}
+	}
+}
+
+func TestGetApplyPatch(t *testing.T) {
+	// The tier label has been applied before and is no longer in the file, the controller label
+	// and the cluster IP are set by the apiserver and controllers.
+	lastApplied := `{"metadata": {"labels": {"app": "foo", "tier": "web"}}}`
+	live := &runtime.Unknown{Raw: []byte(`{"kind": "Service", "apiVersion": "v1", ` +
+		`"metadata": {"name": "foo-name", "namespace": "foo-namespace", ` +
+		`"labels": {"app": "foo", "tier": "web", "controller": "bar"}, ` +
+		`"annotations": {"kubectl.kubernetes.io/last-applied-configuration": ` +
+		strconv.Quote(lastApplied) + `}}, ` +
+		`"spec": {"type": "ClusterIP", "clusterIP": "10.0.0.1"}}`)}
+	labels := map[string]string{"app": "foo"}
+	info := &kubectlResource.Info{
+		Mapping: &meta.RESTMapping{
+			GroupVersionKind: unversioned.GroupVersionKind{Version: "v1", Kind: "Service"},
+			MetadataAccessor: meta.NewAccessor(),
+		},
+		Namespace: "foo-namespace",
+		Name:      "foo-name",
+		VersionedObject: &v1.Service{
+			ObjectMeta: v1.ObjectMeta{Name: "foo-name", Namespace: "foo-namespace",
+				Labels: labels},
+			Spec: v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+		},
+	}
+
+	patch, patched, err := getApplyPatch(info, live)
+	if err != nil {
+		t.Fatalf("getApplyPatch() returned unexpected error: %v", err)
+	}
+	if patch.Type != api.StrategicMergePatchType {
+		t.Errorf("getApplyPatch() returned patch of type %s, expected %s", patch.Type,
+			api.StrategicMergePatchType)
+	}
+	patchObject := struct {
+		Metadata struct {
+			Labels map[string]interface{} `json:"labels"`
+		} `json:"metadata"`
+		Spec map[string]interface{} `json:"spec"`
+	}{}
+	if err := json.Unmarshal(patch.Data, &patchObject); err != nil {
+		t.Fatal(err)
+	}
+	expectedLabels := map[string]interface{}{"tier": nil}
+	if !reflect.DeepEqual(patchObject.Metadata.Labels, expectedLabels) {
+		t.Errorf("getApplyPatch() patches labels with %#v, expected %#v",
+			patchObject.Metadata.Labels, expectedLabels)
+	}
+	if _, ok := patchObject.Spec["clusterIP"]; ok {
+		t.Errorf("getApplyPatch() patches cluster IP: %s", patch.Data)
+	}
+
+	diff, err := GetObjectDiff(live, &runtime.Unknown{Raw: patched})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedDiff := []FieldDiff{
+		{Path: "metadata.labels.tier", Operation: FieldRemoved, Live: "web"},
+		{Path: "spec.type", Operation: FieldReplaced, Live: "ClusterIP",
+			Submitted: "LoadBalancer"},
+	}
+	if !reflect.DeepEqual(diff, expectedDiff) {
+		t.Errorf("Diff of patched object == %#v, expected %#v", diff, expectedDiff)
+	}
+}
+
+func TestGetApplyPatchOfUnknownKind(t *testing.T) {
+	lastApplied := `{"spec": {"size": 1, "mode": "fast"}}`
+	live := &runtime.Unknown{Raw: []byte(`{"kind": "Foo", "apiVersion": "example.com/v1", ` +
+		`"metadata": {"name": "foo-name", "annotations": ` +
+		`{"kubectl.kubernetes.io/last-applied-configuration": ` + strconv.Quote(lastApplied) +
+		`}}, "spec": {"size": 1, "mode": "fast", "owner": "bar"}}`)}
+	info := &kubectlResource.Info{
+		Mapping: &meta.RESTMapping{
+			GroupVersionKind: unversioned.GroupVersionKind{Group: "example.com",
+				Version: "v1", Kind: "Foo"},
+			MetadataAccessor: meta.NewAccessor(),
+		},
+		Name: "foo-name",
+		VersionedObject: &runtime.Unstructured{Object: map[string]interface{}{
+			"kind":       "Foo",
+			"apiVersion": "example.com/v1",
+			"metadata":   map[string]interface{}{"name": "foo-name"},
+			"spec":       map[string]interface{}{"size": 2},
+		}},
+	}
+
+	patch, patched, err := getApplyPatch(info, live)
+	if err != nil {
+		t.Fatalf("getApplyPatch() returned unexpected error: %v", err)
+	}
+	if patch.Type != api.MergePatchType {
+		t.Errorf("getApplyPatch() returned patch of type %s, expected %s", patch.Type,
+			api.MergePatchType)
+	}
+
+	diff, err := GetObjectDiff(live, &runtime.Unknown{Raw: patched})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedDiff := []FieldDiff{
+		{Path: "spec.mode", Operation: FieldRemoved, Live: "fast"},
+		{Path: "spec.size", Operation: FieldReplaced, Live: float64(1), Submitted: float64(2)},
+	}
+	if !reflect.DeepEqual(diff, expectedDiff) {
+		t.Errorf("Diff of patched object == %#v, expected %#v", diff, expectedDiff)
+	}
+}
+
+func TestPatchObjectFromInfoFn(t *testing.T) {
+	client := &restfake.RESTClient{
+		NegotiatedSerializer: testapi.Default.NegotiatedSerializer(),
+		Resp: &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{runtime.ContentTypeJSON}},
+			Body:       ioutil.NopCloser(strings.NewReader(`{}`)),
+		},
+	}
+	info := &kubectlResource.Info{
+		Client: client,
+		Mapping: &meta.RESTMapping{
+			Resource:         "services",
+			GroupVersionKind: unversioned.GroupVersionKind{Version: "v1", Kind: "Service"},
+			Scope:            meta.RESTScopeNamespace,
+		},
+		Namespace: "foo-namespace",
+		Name:      "foo-name",
+	}
+
+	err := PatchObjectFromInfoFn(info, &ApplyPatch{Type: api.StrategicMergePatchType,
+		Data: []byte(`{"spec": {"type": "LoadBalancer"}}`)})
+	if err != nil {
+		t.Fatalf("PatchObjectFromInfoFn() returned unexpected error: %v", err)
+	}
+	if client.Req.Method != "PATCH" ||
+		client.Req.URL.Path != "/namespaces/foo-namespace/services/foo-name" {
+		t.Errorf("Unexpected patch request: %s %s", client.Req.Method, client.Req.URL.Path)
+	}
+}
+
+func TestGetObjectFromInfoFn(t *testing.T) {
+	// Service accounts are not known to the resource verber, but every kind with a REST mapping
+	// can be got.
+	client := &restfake.RESTClient{
+		NegotiatedSerializer: testapi.Default.NegotiatedSerializer(),
+		Resp: &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{runtime.ContentTypeJSON}},
+			Body: ioutil.NopCloser(strings.NewReader(`{"kind": "ServiceAccount", ` +
+				`"apiVersion": "v1", "metadata": {"name": "foo-name", ` +
+				`"namespace": "foo-namespace", "resourceVersion": "3"}, ` +
+				`"secrets": [{"name": "foo-token"}]}`)),
+		},
+	}
+	info := &kubectlResource.Info{
+		Client: client,
+		Mapping: &meta.RESTMapping{
+			Resource:         "serviceaccounts",
+			GroupVersionKind: unversioned.GroupVersionKind{Version: "v1", Kind: "ServiceAccount"},
+			Scope:            meta.RESTScopeNamespace,
+		},
+		Namespace: "foo-namespace",
+		Name:      "foo-name",
+		VersionedObject: &v1.ServiceAccount{
+			ObjectMeta: v1.ObjectMeta{Name: "foo-name", Namespace: "foo-namespace"},
+		},
+	}
+
+	// Secrets are added to the live service account by the token controller. They are not in the
+	// file, but apply keeps them.
+	patched := false
+	patchFn := func(info *kubectlResource.Info, patch *ApplyPatch) error {
+		patched = true
+		return nil
+	}
+	status := deployObjectFromInfo(&AppDeploymentFromFileSpec{Mode: ApplyDeploymentMode}, info,
+		nil, patchFn, GetObjectFromInfoFn)
+
+	if status.Action != ObjectUnchanged || patched {
+		t.Errorf("Action == %s, patched: %t, expected %s without patch. Error: %s",
+			status.Action, patched, ObjectUnchanged, status.Error)
+	}
+	expectedPath := "/namespaces/foo-namespace/serviceaccounts/foo-name"
+	if client.Req == nil || client.Req.URL.Path != expectedPath {
+		t.Errorf("Live object was requested from %v, expected %s", client.Req, expectedPath)
 	}
 }
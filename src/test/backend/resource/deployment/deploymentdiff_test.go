@@ -0,0 +1,99 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/kubernetes/pkg/runtime"
+)
+
+func TestGetObjectDiff(t *testing.T) {
+	live := &runtime.Unknown{Raw: []byte(`{
+		"metadata": {"name": "foo", "uid": "123", "labels": {"app": "foo"}},
+		"spec": {
+			"replicas": 1,
+			"containers": [{"name": "foo", "image": "foo:1"}, {"name": "bar", "image": "bar:1"}],
+			"paused": false
+		},
+		"status": {"replicas": 1}
+	}`)}
+	submitted := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":              "foo",
+			"creationTimestamp": nil,
+			"labels":            map[string]interface{}{"app": "foo", "tier": "web"},
+		},
+		"spec": map[string]interface{}{
+			"replicas": 3,
+			"containers": []interface{}{
+				map[string]interface{}{"name": "foo", "image": "foo:2"},
+			},
+			"paused": false,
+		},
+		"status": map[string]interface{}{"replicas": 3},
+	}
+
+	expected := []FieldDiff{
+		{Path: "metadata.labels.tier", Operation: FieldAdded, Submitted: "web"},
+		{Path: "spec.containers[0].image", Operation: FieldReplaced, Live: "foo:1",
+			Submitted: "foo:2"},
+		{Path: "spec.containers[1]", Operation: FieldRemoved,
+			Live: map[string]interface{}{"name": "bar", "image": "bar:1"}},
+		{Path: "spec.replicas", Operation: FieldReplaced, Live: float64(1), Submitted: float64(3)},
+	}
+
+	actual, err := GetObjectDiff(live, submitted)
+	if err != nil {
+		t.Fatalf("GetObjectDiff() returned unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GetObjectDiff() == %#v, expected %#v", actual, expected)
+	}
+}
+
+func TestGetObjectDiffWithRemovedLabel(t *testing.T) {
+	live := &runtime.Unknown{Raw: []byte(`{
+		"metadata": {
+			"name": "foo",
+			"labels": {"app": "foo", "tier": "web"},
+			"annotations": {"kubectl.kubernetes.io/last-applied-configuration": "{}"}
+		},
+		"spec": {"selector": {"app": "foo", "tier": "web"}}
+	}`)}
+	submitted := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":   "foo",
+			"labels": map[string]interface{}{"app": "foo"},
+		},
+		"spec": map[string]interface{}{
+			"selector": map[string]interface{}{"app": "foo"},
+		},
+	}
+
+	expected := []FieldDiff{
+		{Path: "metadata.labels.tier", Operation: FieldRemoved, Live: "web"},
+		{Path: "spec.selector.tier", Operation: FieldRemoved, Live: "web"},
+	}
+
+	actual, err := GetObjectDiff(live, submitted)
+	if err != nil {
+		t.Fatalf("GetObjectDiff() returned unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GetObjectDiff() == %#v, expected %#v", actual, expected)
+	}
+}
@@ -0,0 +1,271 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"k8s.io/kubernetes/pkg/api"
+	k8serrors "k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/apis/extensions"
+	"k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset/fake"
+	"k8s.io/kubernetes/pkg/client/testing/core"
+	"k8s.io/kubernetes/pkg/controller/deployment/util"
+	"k8s.io/kubernetes/pkg/runtime"
+)
+
+func createRolloutDeployment() *extensions.Deployment {
+	deployment := createDeployment("dp-1", "ns-1", "pod-1", map[string]string{"foo": "bar"},
+		map[string]string{"foo": "bar"})
+	deployment.Annotations = map[string]string{util.RevisionAnnotation: "3"}
+	deployment.Spec.Template.Spec.Containers = []api.Container{
+		{Name: "app", Image: "app:1"},
+		{Name: "sidecar", Image: "sidecar:1"},
+	}
+	return deployment
+}
+
+func getUpdatedDeployment(t *testing.T, fakeClient *fake.Clientset) *extensions.Deployment {
+	for _, action := range fakeClient.Actions() {
+		if update, ok := action.(core.UpdateAction); ok {
+			return update.GetObject().(*extensions.Deployment)
+		}
+	}
+	t.Fatalf("Expected deployment to be updated, got actions %#v", fakeClient.Actions())
+	return nil
+}
+
+func TestPauseAndResumeDeployment(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(createRolloutDeployment())
+
+	status, err := PauseDeployment(fakeClient, "ns-1", "dp-1")
+	if err != nil {
+		t.Fatalf("PauseDeployment() returned unexpected error: %v", err)
+	}
+	if !status.Paused || status.Complete || status.Message != "Rollout is paused" {
+		t.Errorf("Expected paused rollout, got %#v", status)
+	}
+	if status.Revision != 3 {
+		t.Errorf("Expected revision 3, got %d", status.Revision)
+	}
+
+	status, err = ResumeDeployment(fakeClient, "ns-1", "dp-1")
+	if err != nil {
+		t.Fatalf("ResumeDeployment() returned unexpected error: %v", err)
+	}
+	if status.Paused {
+		t.Errorf("Expected resumed rollout, got %#v", status)
+	}
+}
+
+func TestUpdateDeploymentRetriesOnConflict(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(createRolloutDeployment())
+	conflicts := 0
+	fakeClient.PrependReactor("update", "deployments",
+		func(action core.Action) (bool, runtime.Object, error) {
+			if conflicts < 2 {
+				conflicts++
+				return true, nil, k8serrors.NewConflict(extensions.Resource("deployments"),
+					"dp-1", errors.New("the object has been modified"))
+			}
+			return false, nil, nil
+		})
+
+	status, err := PauseDeployment(fakeClient, "ns-1", "dp-1")
+	if err != nil {
+		t.Fatalf("PauseDeployment() returned unexpected error: %v", err)
+	}
+	if !status.Paused {
+		t.Errorf("Expected paused rollout, got %#v", status)
+	}
+
+	gets := 0
+	for _, action := range fakeClient.Actions() {
+		if action.GetVerb() == "get" {
+			gets++
+		}
+	}
+	if gets != 3 {
+		t.Errorf("Expected deployment to be got 3 times, got %d times", gets)
+	}
+}
+
+func TestRestartDeployment(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(createRolloutDeployment())
+
+	if _, err := RestartDeployment(fakeClient, "ns-1", "dp-1"); err != nil {
+		t.Fatalf("RestartDeployment() returned unexpected error: %v", err)
+	}
+	deployment := getUpdatedDeployment(t, fakeClient)
+	if _, ok := deployment.Spec.Template.Annotations[RestartedAtAnnotation]; !ok {
+		t.Errorf("Expected pod template to have %s annotation, got %#v", RestartedAtAnnotation,
+			deployment.Spec.Template.Annotations)
+	}
+}
+
+func TestSetDeploymentImage(t *testing.T) {
+	cases := []struct {
+		info           string
+		spec           *ContainerImageSpec
+		expectedImages []string
+		expectedError  bool
+	}{
+		{
+			"existing container",
+			&ContainerImageSpec{Container: "sidecar", Image: "sidecar:2"},
+			[]string{"app:1", "sidecar:2"},
+			false,
+		},
+		{
+			"missing container",
+			&ContainerImageSpec{Container: "foo", Image: "foo:2"},
+			nil,
+			true,
+		},
+		{
+			"empty image",
+			&ContainerImageSpec{Container: "app"},
+			nil,
+			true,
+		},
+	}
+
+	for _, c := range cases {
+		fakeClient := fake.NewSimpleClientset(createRolloutDeployment())
+		status, err := SetDeploymentImage(fakeClient, "ns-1", "dp-1", c.spec)
+		if (err != nil) != c.expectedError {
+			t.Errorf("Test Case: %s. Got error %v, expected error: %t", c.info, err,
+				c.expectedError)
+			continue
+		}
+		if err != nil && !k8serrors.IsBadRequest(err) {
+			t.Errorf("Test Case: %s. Got error %v, expected bad request", c.info, err)
+		}
+		if err == nil && !reflect.DeepEqual(status.ContainerImages, c.expectedImages) {
+			t.Errorf("Test Case: %s. Got images %v, expected %v", c.info, status.ContainerImages,
+				c.expectedImages)
+		}
+	}
+}
+
+func TestRollbackDeployment(t *testing.T) {
+	deployment := createRolloutDeployment()
+	oldTemplate := util.GetNewReplicaSetTemplate(deployment)
+	oldTemplate.Spec.Containers = []api.Container{{Name: "app", Image: "app:0"}}
+	oldReplicaSet := createReplicaSet("rs-1", "ns-1", map[string]string{"foo": "bar"},
+		oldTemplate)
+	oldReplicaSet.Annotations = map[string]string{util.RevisionAnnotation: "2"}
+	pausedDeployment := createRolloutDeployment()
+	pausedDeployment.Spec.Paused = true
+
+	cases := []struct {
+		info             string
+		deployment       *extensions.Deployment
+		revision         int64
+		expectedRollback bool
+		expectedErr      func(err error) bool
+	}{
+		{"revision of old replica set", deployment, 2, true, nil},
+		{"unknown revision", deployment, 1, false, k8serrors.IsBadRequest},
+		{"paused deployment", pausedDeployment, 2, false, k8serrors.IsConflict},
+	}
+
+	for _, c := range cases {
+		fakeClient := fake.NewSimpleClientset(c.deployment,
+			&extensions.ReplicaSetList{Items: []extensions.ReplicaSet{oldReplicaSet}})
+		// Default reactor can not handle rollback objects, which have no metadata.
+		fakeClient.PrependReactor("create", "deployments",
+			func(action core.Action) (bool, runtime.Object, error) {
+				return action.GetSubresource() == "rollback", nil, nil
+			})
+
+		_, err := RollbackDeployment(fakeClient, "ns-1", "dp-1", &RollbackSpec{Revision: c.revision})
+		if (err == nil) != c.expectedRollback {
+			t.Errorf("Test Case: %s. Got error %v, expected rollback: %t", c.info, err,
+				c.expectedRollback)
+		}
+		if err != nil && c.expectedErr != nil && !c.expectedErr(err) {
+			t.Errorf("Test Case: %s. Got error of unexpected type: %v", c.info, err)
+		}
+
+		rolledBack := false
+		for _, action := range fakeClient.Actions() {
+			if action.GetSubresource() == "rollback" {
+				rollback := action.(core.CreateAction).GetObject().(*extensions.DeploymentRollback)
+				rolledBack = rollback.RollbackTo.Revision == c.revision
+			}
+		}
+		if rolledBack != c.expectedRollback {
+			t.Errorf("Test Case: %s. Rolled back: %t, expected %t", c.info, rolledBack,
+				c.expectedRollback)
+		}
+	}
+}
+
+func TestToRolloutStatus(t *testing.T) {
+	cases := []struct {
+		info             string
+		status           extensions.DeploymentStatus
+		expectedComplete bool
+		expectedMessage  string
+	}{
+		{
+			"not observed",
+			extensions.DeploymentStatus{ObservedGeneration: 1},
+			false,
+			"Waiting for rollout to start",
+		},
+		{
+			"updating",
+			extensions.DeploymentStatus{ObservedGeneration: 2, Replicas: 4, UpdatedReplicas: 1},
+			false,
+			"1 of 4 replicas have been updated",
+		},
+		{
+			"terminating old replicas",
+			extensions.DeploymentStatus{ObservedGeneration: 2, Replicas: 5, UpdatedReplicas: 4},
+			false,
+			"1 old replicas are pending termination",
+		},
+		{
+			"waiting for availability",
+			extensions.DeploymentStatus{ObservedGeneration: 2, Replicas: 4, UpdatedReplicas: 4,
+				AvailableReplicas: 3},
+			false,
+			"3 of 4 updated replicas are available",
+		},
+		{
+			"complete",
+			extensions.DeploymentStatus{ObservedGeneration: 2, Replicas: 4, UpdatedReplicas: 4,
+				AvailableReplicas: 4},
+			true,
+			"Rollout is complete",
+		},
+	}
+
+	for _, c := range cases {
+		deployment := createRolloutDeployment()
+		deployment.Generation = 2
+		deployment.Status = c.status
+
+		status := ToRolloutStatus(deployment)
+		if status.Complete != c.expectedComplete || status.Message != c.expectedMessage {
+			t.Errorf("Test Case: %s. Got %t, %q, expected %t, %q", c.info, status.Complete,
+				status.Message, c.expectedComplete, c.expectedMessage)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/resource"
+)
+
+func newValidationTestSpec() *AppDeploymentSpec {
+	return &AppDeploymentSpec{
+		Name:           "foo-name",
+		ContainerImage: "foo-image",
+		Volumes:        []VolumeSpec{{Name: "config", Type: SecretVolume, SourceName: "foo"}},
+		VolumeMounts:   []VolumeMountSpec{{Name: "config", MountPath: "/etc/foo"}},
+	}
+}
+
+func TestValidateAppDeploymentSpec(t *testing.T) {
+	cpu := resource.MustParse("200m")
+	lowCpu := resource.MustParse("100m")
+	cases := []struct {
+		info     string
+		modify   func(spec *AppDeploymentSpec)
+		expected []string
+	}{
+		{
+			"valid spec",
+			func(spec *AppDeploymentSpec) {},
+			[]string{},
+		},
+		{
+			"invalid name and missing image",
+			func(spec *AppDeploymentSpec) {
+				spec.Name = "Foo"
+				spec.ContainerImage = ""
+			},
+			[]string{"name", "containerImage"},
+		},
+		{
+			"limit lower than requirement",
+			func(spec *AppDeploymentSpec) {
+				spec.CpuRequirement = &cpu
+				spec.CpuLimit = &lowCpu
+			},
+			[]string{"cpuLimit"},
+		},
+		{
+			"invalid probes",
+			func(spec *AppDeploymentSpec) {
+				spec.LivenessProbe = &ProbeSpec{Type: HTTPProbe, PeriodSeconds: -1}
+				spec.ReadinessProbe = &ProbeSpec{Type: "grpc"}
+			},
+			[]string{"livenessProbe.port", "livenessProbe.periodSeconds", "readinessProbe.type"},
+		},
+		{
+			"invalid volumes and mounts",
+			func(spec *AppDeploymentSpec) {
+				spec.Volumes = append(spec.Volumes, VolumeSpec{Name: "config", Type: "hostPath"})
+				spec.VolumeMounts = append(spec.VolumeMounts,
+					VolumeMountSpec{Name: "data", MountPath: "/etc/foo"},
+					VolumeMountSpec{Name: "config", MountPath: "relative"})
+			},
+			[]string{"volumes[1].name", "volumes[1].type", "volumes[1].sourceName",
+				"volumeMounts[1].name", "volumeMounts[1].mountPath", "volumeMounts[2].mountPath"},
+		},
+		{
+			"invalid init containers and sidecars",
+			func(spec *AppDeploymentSpec) {
+				spec.Sidecars = []ContainerSpec{{Name: "foo-name", Image: "nginx"}}
+				spec.InitContainers = []ContainerSpec{{
+					Name:           "init",
+					ReadinessProbe: &ProbeSpec{Type: TCPProbe, Port: 80},
+					VolumeMounts:   []VolumeMountSpec{{Name: "config", MountPath: "/init"}},
+				}}
+			},
+			[]string{"sidecars[0].name", "initContainers[0].image",
+				"initContainers[0].readinessProbe"},
+		},
+		{
+			"invalid node selector and tolerations",
+			func(spec *AppDeploymentSpec) {
+				spec.NodeSelector = []Label{{Key: "disk type", Value: "ssd"}}
+				spec.Tolerations = []api.Toleration{
+					{Operator: api.TolerationOpExists},
+					{Key: "dedicated", Operator: api.TolerationOpExists, Value: "foo"},
+					{Key: "dedicated", Operator: "In", Effect: "NoExecute"},
+				}
+			},
+			[]string{"nodeSelector[0].key", "tolerations[1].value", "tolerations[2].operator",
+				"tolerations[2].effect"},
+		},
+		{
+			"invalid port mappings and variables",
+			func(spec *AppDeploymentSpec) {
+				spec.PortMappings = []PortMapping{{Port: 0, TargetPort: 80, Protocol: "SCTP"}}
+				spec.Variables = []EnvironmentVariable{{Name: "FOO-BAR", Value: "baz"}}
+			},
+			[]string{"portMappings[0].port", "portMappings[0].protocol", "variables[0].name"},
+		},
+		{
+			"conflicting port mappings",
+			func(spec *AppDeploymentSpec) {
+				spec.PortMappings = []PortMapping{
+					{Port: 80, TargetPort: 8080, Protocol: api.ProtocolTCP},
+					{Port: 80, TargetPort: 8080, Protocol: api.ProtocolUDP},
+					{Port: 80, TargetPort: 9090, Protocol: api.ProtocolTCP},
+				}
+			},
+			[]string{"portMappings[2].port"},
+		},
+	}
+
+	for _, c := range cases {
+		spec := newValidationTestSpec()
+		c.modify(spec)
+
+		actual := make([]string, 0)
+		for _, err := range ValidateAppDeploymentSpec(spec) {
+			actual = append(actual, err.Field)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Test Case: %s. Errors of fields %#v, expected %#v", c.info, actual,
+				c.expected)
+		}
+	}
+}
@@ -19,10 +19,12 @@ import (
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
 
 	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/unversioned"
 
 	"k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset/fake"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestGetEvents(t *testing.T) {
@@ -176,3 +178,62 @@ func TestToEventList(t *testing.T) {
 		}
 	}
 }
+
+func TestGetEventList(t *testing.T) {
+	now := unversioned.Now()
+	eventList := &api.EventList{Items: []api.Event{
+		{
+			ObjectMeta: api.ObjectMeta{Name: "ev-1", Namespace: "ns-1"},
+			Type:       api.EventTypeWarning, Reason: "BackOff",
+			LastTimestamp:  unversioned.NewTime(now.Add(-2 * time.Hour)),
+			InvolvedObject: api.ObjectReference{Kind: "Pod", Name: "pod-1", Namespace: "ns-1"},
+		},
+		{
+			ObjectMeta: api.ObjectMeta{Name: "ev-2", Namespace: "ns-1"},
+			Type:       api.EventTypeWarning, Reason: "FailedScheduling",
+			LastTimestamp:  now,
+			InvolvedObject: api.ObjectReference{Kind: "Pod", Name: "pod-2", Namespace: "ns-1"},
+		},
+		{
+			ObjectMeta: api.ObjectMeta{Name: "ev-3", Namespace: "ns-1"},
+			Type:       api.EventTypeNormal, Reason: "ScalingReplicaSet",
+			LastTimestamp: now,
+			InvolvedObject: api.ObjectReference{Kind: "Deployment", Name: "dp-1",
+				Namespace: "ns-1"},
+		},
+	}}
+
+	cases := []struct {
+		info     string
+		filterBy []string
+		expected []string
+	}{
+		{"all events", []string{}, []string{"ev-2", "ev-3", "ev-1"}},
+		{"warnings", []string{"type=Warning"}, []string{"ev-2", "ev-1"}},
+		{"involved kind", []string{"involvedObjectKind=Deployment"}, []string{"ev-3"}},
+		{"recent warnings", []string{"type=Warning", "lastSeen>1h"}, []string{"ev-2"}},
+	}
+
+	for _, c := range cases {
+		fakeClient := fake.NewSimpleClientset(eventList)
+		dsQuery := dataselect.NewDataSelectQuery(dataselect.NoPagination,
+			dataselect.NewSortQuery([]string{"d", "lastSeen"}),
+			dataselect.NewFilterQuery(c.filterBy), dataselect.NoMetrics)
+
+		actual, err := GetEventList(fakeClient, common.NewNamespaceQuery(nil), api.ListOptions{},
+			dsQuery)
+		if err != nil {
+			t.Errorf("Test Case: %s. GetEventList() returned unexpected error: %v", c.info, err)
+			continue
+		}
+
+		names := make([]string, 0)
+		for _, event := range actual.Events {
+			names = append(names, event.ObjectMeta.Name)
+		}
+		if !reflect.DeepEqual(names, c.expected) {
+			t.Errorf("Test Case: %s. GetEventList() returned %v, expected %v", c.info, names,
+				c.expected)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package event
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset/fake"
+	coreclient "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset/typed/core/internalversion"
+	"k8s.io/kubernetes/pkg/client/testing/core"
+	"k8s.io/kubernetes/pkg/watch"
+)
+
+func newWatchedEvent(name, eventType, reason string) *api.Event {
+	return &api.Event{
+		ObjectMeta: api.ObjectMeta{Name: name, Namespace: "ns-1", ResourceVersion: name},
+		Type:       eventType,
+		Reason:     reason,
+		InvolvedObject: api.ObjectReference{
+			Kind: "Pod", Name: "pod-1", Namespace: "ns-1",
+		},
+	}
+}
+
+func newFakeEventWatchClient(watcher watch.Interface) *fake.Clientset {
+	fakeClient := fake.NewSimpleClientset(&api.EventList{})
+	fakeClient.PrependWatchReactor("events",
+		func(action core.Action) (bool, watch.Interface, error) {
+			return true, watcher, nil
+		})
+	return fakeClient
+}
+
+// versionedListClient is a fake client that lists events with the given resource version, which
+// the fake clientset does not set.
+type versionedListClient struct {
+	*fake.Clientset
+	resourceVersion string
+}
+
+func (c *versionedListClient) Core() coreclient.CoreInterface {
+	return &versionedListCore{c.Clientset.Core(), c.resourceVersion}
+}
+
+type versionedListCore struct {
+	coreclient.CoreInterface
+	resourceVersion string
+}
+
+func (c *versionedListCore) Events(namespace string) coreclient.EventInterface {
+	return &versionedListEvents{c.CoreInterface.Events(namespace), c.resourceVersion}
+}
+
+type versionedListEvents struct {
+	coreclient.EventInterface
+	resourceVersion string
+}
+
+func (e *versionedListEvents) List(opts api.ListOptions) (*api.EventList, error) {
+	list, err := e.EventInterface.List(opts)
+	if err != nil {
+		return nil, err
+	}
+	list.ResourceVersion = e.resourceVersion
+	return list, nil
+}
+
+func TestWatchEvents(t *testing.T) {
+	watcher := watch.NewFakeWithChanSize(4, false)
+	watcher.Add(newWatchedEvent("ev-1", api.EventTypeWarning, "BackOff"))
+	watcher.Add(newWatchedEvent("ev-2", api.EventTypeNormal, "Pulled"))
+	watcher.Modify(newWatchedEvent("ev-3", "", "FailedMount"))
+	watcher.Delete(newWatchedEvent("ev-4", api.EventTypeWarning, "BackOff"))
+	fakeClient := newFakeEventWatchClient(watcher)
+
+	stop := make(chan struct{})
+	actual := make([]string, 0)
+	err := WatchEvents(fakeClient, "ns-1", api.ListOptions{},
+		dataselect.NewFilterQuery([]string{"type=Warning"}), stop,
+		func(event common.Event) error {
+			actual = append(actual, event.ObjectMeta.Name)
+			if event.InvolvedObject != (common.InvolvedObject{Kind: "Pod", Name: "pod-1",
+				Namespace: "ns-1"}) {
+				t.Errorf("Unexpected involved object of %s event: %#v", event.ObjectMeta.Name,
+					event.InvolvedObject)
+			}
+			if len(actual) == 2 {
+				close(stop)
+			}
+			return nil
+		})
+
+	if err != nil {
+		t.Fatalf("WatchEvents() returned unexpected error: %v", err)
+	}
+	expected := []string{"ev-1", "ev-3"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("WatchEvents() sent %v, expected %v", actual, expected)
+	}
+	if verb := fakeClient.Actions()[0].GetVerb(); verb != "list" {
+		t.Errorf("Expected events to be listed before watch, got %s", verb)
+	}
+}
+
+func TestWatchEventsWithWatchError(t *testing.T) {
+	watcher := watch.NewFakeWithChanSize(1, false)
+	watcher.Error(&unversioned.Status{Message: "too old resource version"})
+
+	err := WatchEvents(newFakeEventWatchClient(watcher), "", api.ListOptions{},
+		dataselect.NoFilter, make(chan struct{}), func(event common.Event) error {
+			t.Errorf("Unexpected event sent: %#v", event)
+			return nil
+		})
+
+	if err == nil {
+		t.Error("Expected WatchEvents() to return watch error")
+	}
+}
+
+func TestWatchEventsResumesIdleWatch(t *testing.T) {
+	// The first watch is closed by the apiserver before any event is sent.
+	idleWatcher := watch.NewFake()
+	idleWatcher.Stop()
+	watcher := watch.NewFakeWithChanSize(1, false)
+	watcher.Add(newWatchedEvent("ev-1", api.EventTypeWarning, "BackOff"))
+	watchers := []watch.Interface{idleWatcher, watcher}
+
+	fakeClient := fake.NewSimpleClientset(&api.EventList{})
+	fakeClient.PrependWatchReactor("events",
+		func(action core.Action) (bool, watch.Interface, error) {
+			next := watchers[0]
+			watchers = watchers[1:]
+			return true, next, nil
+		})
+
+	stop := make(chan struct{})
+	actual := make([]string, 0)
+	err := WatchEvents(&versionedListClient{fakeClient, "10"}, "ns-1", api.ListOptions{},
+		dataselect.NoFilter, stop, func(event common.Event) error {
+			actual = append(actual, event.ObjectMeta.Name)
+			close(stop)
+			return nil
+		})
+
+	if err != nil {
+		t.Fatalf("WatchEvents() returned unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(actual, []string{"ev-1"}) {
+		t.Errorf("WatchEvents() sent %v, expected [ev-1]", actual)
+	}
+	resourceVersions := make([]string, 0)
+	for _, action := range fakeClient.Actions() {
+		if watchAction, ok := action.(core.WatchAction); ok {
+			resourceVersions = append(resourceVersions,
+				watchAction.GetWatchRestrictions().ResourceVersion)
+		}
+	}
+	if !reflect.DeepEqual(resourceVersions, []string{"10", "10"}) {
+		t.Errorf("Events were watched from resource versions %v, expected [10 10]",
+			resourceVersions)
+	}
+}
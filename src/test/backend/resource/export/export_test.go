@@ -0,0 +1,179 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/deployment"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/pod"
+	"github.com/kubernetes/dashboard/src/app/backend/resource/secret"
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/resource"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/apis/extensions"
+)
+
+var created = unversioned.NewTime(time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC))
+
+func getPodCells() []dataselect.DataCell {
+	return []dataselect.DataCell{
+		pod.PodCell(api.Pod{
+			ObjectMeta: api.ObjectMeta{
+				Name:              "web-1",
+				Namespace:         "default",
+				Labels:            map[string]string{"app": "web", "tier": "frontend"},
+				CreationTimestamp: created,
+			},
+			Spec: api.PodSpec{
+				NodeName: "node-1",
+				Containers: []api.Container{{
+					Name:  "web",
+					Image: "nginx:1.11",
+					Resources: api.ResourceRequirements{Requests: api.ResourceList{
+						api.ResourceCPU: resource.MustParse("100m"),
+					}},
+				}},
+			},
+			Status: api.PodStatus{
+				Phase:             api.PodRunning,
+				PodIP:             "10.0.0.1",
+				ContainerStatuses: []api.ContainerStatus{{RestartCount: 2}},
+			},
+		}),
+		pod.PodCell(api.Pod{
+			ObjectMeta: api.ObjectMeta{
+				Name:              "db, primary",
+				Namespace:         "default",
+				CreationTimestamp: created,
+			},
+			Spec: api.PodSpec{Containers: []api.Container{{Name: "db", Image: "postgres"}}},
+			Status: api.PodStatus{
+				Phase: api.PodPending,
+			},
+		}),
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	cases := []struct {
+		cells    []dataselect.DataCell
+		expected string
+	}{
+		{
+			getPodCells(),
+			"Name,Namespace,Labels,Created,Status,Node,IP,Restarts,CPU Requests," +
+				"Memory Requests,Images\n" +
+				"web-1,default,\"app=web,tier=frontend\",2017-01-02T03:04:05Z,Running,node-1," +
+				"10.0.0.1,2,100m,,nginx:1.11\n" +
+				"\"db, primary\",default,,2017-01-02T03:04:05Z,Pending,,,0,,,postgres\n",
+		},
+		{
+			[]dataselect.DataCell{deployment.DeploymentCell(extensions.Deployment{
+				ObjectMeta: api.ObjectMeta{Name: "web", Namespace: "default",
+					CreationTimestamp: created},
+				Spec: extensions.DeploymentSpec{Replicas: 3},
+				Status: extensions.DeploymentStatus{Replicas: 3,
+					AvailableReplicas: 2},
+			})},
+			"Name,Namespace,Labels,Created,Desired,Current,Available,Images\n" +
+				"web,default,,2017-01-02T03:04:05Z,3,3,2,\n",
+		},
+		{
+			[]dataselect.DataCell{secret.SecretCell(api.Secret{
+				ObjectMeta: api.ObjectMeta{Name: "creds", Namespace: "default",
+					CreationTimestamp: created},
+				Type: api.SecretTypeOpaque,
+				Data: map[string][]byte{"user": []byte("admin"), "password": []byte("hunter2")},
+			})},
+			"Name,Namespace,Labels,Created,Type,Keys\n" +
+				"creds,default,,2017-01-02T03:04:05Z,Opaque,2\n",
+		},
+		{
+			[]dataselect.DataCell{},
+			"Name,Namespace,Labels,Created\n",
+		},
+	}
+
+	for _, c := range cases {
+		buffer := &bytes.Buffer{}
+		if err := WriteCSV(buffer, c.cells); err != nil {
+			t.Fatalf("WriteCSV() returned error: %s", err)
+		}
+		if actual := buffer.String(); actual != c.expected {
+			t.Errorf("WriteCSV() wrote \n%s\nexpected \n%s", actual, c.expected)
+		}
+	}
+}
+
+func TestWriteYAML(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	if err := WriteYAML(buffer, getPodCells()); err != nil {
+		t.Fatalf("WriteYAML() returned error: %s", err)
+	}
+
+	documents := strings.Split(buffer.String(), "---\n")
+	if len(documents) != 2 {
+		t.Fatalf("WriteYAML() wrote %d documents, expected 2:\n%s", len(documents), buffer)
+	}
+	for _, expected := range []string{"apiVersion: v1\n", "kind: Pod\n", "  name: web-1\n",
+		"  - image: nginx:1.11\n"} {
+		if !strings.Contains(documents[0], expected) {
+			t.Errorf("WriteYAML() wrote \n%s\nexpected it to contain %q", documents[0], expected)
+		}
+	}
+}
+
+func TestWriteYAMLWithoutSecretData(t *testing.T) {
+	cells := []dataselect.DataCell{secret.SecretCell(api.Secret{
+		ObjectMeta: api.ObjectMeta{Name: "creds", Namespace: "default"},
+		Type:       api.SecretTypeOpaque,
+		Data:       map[string][]byte{"password": []byte("hunter2")},
+	})}
+
+	buffer := &bytes.Buffer{}
+	if err := WriteYAML(buffer, cells); err != nil {
+		t.Fatalf("WriteYAML() returned error: %s", err)
+	}
+	if !strings.Contains(buffer.String(), "  name: creds\n") {
+		t.Errorf("WriteYAML() wrote \n%s\nexpected it to contain the secret", buffer)
+	}
+	for _, unexpected := range []string{"password", "aHVudGVyMg==", "hunter2"} {
+		if strings.Contains(buffer.String(), unexpected) {
+			t.Errorf("WriteYAML() wrote \n%s\nexpected it not to contain %q", buffer,
+				unexpected)
+		}
+	}
+}
+
+type notExportableCell struct{}
+
+func (notExportableCell) GetProperty(dataselect.PropertyName) dataselect.ComparableValue {
+	return nil
+}
+
+func TestWriteNotExportableCells(t *testing.T) {
+	cells := []dataselect.DataCell{notExportableCell{}}
+	if err := WriteCSV(&bytes.Buffer{}, cells); err == nil {
+		t.Errorf("WriteCSV() exported cells without objects")
+	}
+	if err := WriteYAML(&bytes.Buffer{}, cells); err == nil {
+		t.Errorf("WriteYAML() exported cells without objects")
+	}
+}
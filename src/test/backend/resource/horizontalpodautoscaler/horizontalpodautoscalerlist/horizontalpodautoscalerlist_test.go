@@ -141,7 +141,7 @@ func TestGetHorizontalPodAutoscalerList(t *testing.T) {
 	for _, c := range cases {
 		fakeClient := fake.NewSimpleClientset(c.hpaList)
 
-		actual, _ := GetHorizontalPodAutoscalerList(fakeClient, &common.NamespaceQuery{})
+		actual, _ := GetHorizontalPodAutoscalerList(fakeClient, &common.NamespaceQuery{}, common.ListEverything)
 
 		actions := fakeClient.Actions()
 		if len(actions) != len(c.expectedActions) {
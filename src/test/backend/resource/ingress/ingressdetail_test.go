@@ -0,0 +1,190 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingress
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/service"
+	"k8s.io/kubernetes/pkg/api"
+	k8serrors "k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/apis/extensions"
+	"k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset/fake"
+	"k8s.io/kubernetes/pkg/client/testing/core"
+	"k8s.io/kubernetes/pkg/runtime"
+	"k8s.io/kubernetes/pkg/util/intstr"
+)
+
+func createCertificate(t *testing.T, notAfter time.Time) []byte {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    notAfter.Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestGetIngressDetail(t *testing.T) {
+	notAfter := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	ingress := &extensions.Ingress{
+		ObjectMeta: api.ObjectMeta{Name: "ing-1", Namespace: "ns-1"},
+		Spec: extensions.IngressSpec{
+			Backend: &extensions.IngressBackend{ServiceName: "default",
+				ServicePort: intstr.FromInt(80)},
+			TLS: []extensions.IngressTLS{
+				{Hosts: []string{"foo.com"}, SecretName: "foo-tls"},
+				{Hosts: []string{"bar.com"}, SecretName: "bar-tls"},
+			},
+			Rules: []extensions.IngressRule{{
+				Host: "foo.com",
+				IngressRuleValue: extensions.IngressRuleValue{
+					HTTP: &extensions.HTTPIngressRuleValue{Paths: []extensions.HTTPIngressPath{
+						{Path: "/api", Backend: extensions.IngressBackend{ServiceName: "api",
+							ServicePort: intstr.FromString("http")}},
+						{Path: "/web", Backend: extensions.IngressBackend{ServiceName: "api",
+							ServicePort: intstr.FromInt(8080)}},
+						{Path: "/old", Backend: extensions.IngressBackend{ServiceName: "old",
+							ServicePort: intstr.FromInt(80)}},
+					}},
+				},
+			}},
+		},
+	}
+	apiService := &api.Service{
+		ObjectMeta: api.ObjectMeta{Name: "api", Namespace: "ns-1"},
+		Spec:       api.ServiceSpec{Ports: []api.ServicePort{{Name: "http", Port: 80}}},
+	}
+	apiEndpoints := &api.Endpoints{
+		ObjectMeta: api.ObjectMeta{Name: "api", Namespace: "ns-1"},
+		Subsets: []api.EndpointSubset{{
+			Addresses: []api.EndpointAddress{{IP: "10.0.0.1"}},
+		}},
+	}
+	defaultService := &api.Service{
+		ObjectMeta: api.ObjectMeta{Name: "default", Namespace: "ns-1"},
+		Spec:       api.ServiceSpec{Ports: []api.ServicePort{{Port: 80}}},
+	}
+	secret := &api.Secret{
+		ObjectMeta: api.ObjectMeta{Name: "foo-tls", Namespace: "ns-1"},
+		Data:       map[string][]byte{api.TLSCertKey: createCertificate(t, notAfter)},
+	}
+	fakeClient := fake.NewSimpleClientset(ingress, apiService, apiEndpoints, defaultService,
+		secret)
+
+	actual, err := GetIngressDetail(fakeClient, "ns-1", "ing-1")
+	if err != nil {
+		t.Fatalf("GetIngressDetail() returned unexpected error: %v", err)
+	}
+
+	expectedDefault := &IngressBackend{ServiceName: "default", ServicePort: "80", Port: 80,
+		Status: BackendResolved, EndpointsHealth: service.NoEndpoints}
+	if !reflect.DeepEqual(actual.DefaultBackend, expectedDefault) {
+		t.Errorf("Default backend == %#v, expected %#v", actual.DefaultBackend, expectedDefault)
+	}
+
+	expectedRules := []IngressRule{
+		{Host: "foo.com", Path: "/api", Backend: IngressBackend{ServiceName: "api",
+			ServicePort: "http", Port: 80, Status: BackendResolved,
+			EndpointsHealth: service.EndpointsReady}},
+		{Host: "foo.com", Path: "/web", Backend: IngressBackend{ServiceName: "api",
+			ServicePort: "8080", Status: BackendPortMissing,
+			EndpointsHealth: service.EndpointsReady}},
+		{Host: "foo.com", Path: "/old", Backend: IngressBackend{ServiceName: "old",
+			ServicePort: "80", Status: BackendServiceMissing}},
+	}
+	if !reflect.DeepEqual(actual.Rules, expectedRules) {
+		t.Errorf("Rules == \n%#v, expected \n%#v", actual.Rules, expectedRules)
+	}
+
+	if len(actual.TLS) != 2 {
+		t.Fatalf("Expected 2 TLS configurations, got %#v", actual.TLS)
+	}
+	if expiry := actual.TLS[0].CertificateExpiry; expiry == nil || !expiry.Time.Equal(notAfter) {
+		t.Errorf("Certificate expiry == %v, expected %v", expiry, notAfter)
+	}
+	if actual.TLS[1].CertificateExpiry != nil || actual.TLS[1].Error == "" {
+		t.Errorf("Expected error of TLS configuration with missing secret, got %#v",
+			actual.TLS[1])
+	}
+}
+
+func TestGetIngressDetailWithForbiddenObjects(t *testing.T) {
+	ingress := &extensions.Ingress{
+		ObjectMeta: api.ObjectMeta{Name: "ing-1", Namespace: "ns-1"},
+		Spec: extensions.IngressSpec{
+			Backend: &extensions.IngressBackend{ServiceName: "default",
+				ServicePort: intstr.FromInt(80)},
+			TLS: []extensions.IngressTLS{{Hosts: []string{"foo.com"}, SecretName: "foo-tls"}},
+			Rules: []extensions.IngressRule{{
+				Host: "foo.com",
+				IngressRuleValue: extensions.IngressRuleValue{
+					HTTP: &extensions.HTTPIngressRuleValue{Paths: []extensions.HTTPIngressPath{
+						{Path: "/api", Backend: extensions.IngressBackend{ServiceName: "api",
+							ServicePort: intstr.FromInt(80)}},
+					}},
+				},
+			}},
+		},
+	}
+	defaultService := &api.Service{
+		ObjectMeta: api.ObjectMeta{Name: "default", Namespace: "ns-1"},
+		Spec:       api.ServiceSpec{Ports: []api.ServicePort{{Port: 80}}},
+	}
+	fakeClient := fake.NewSimpleClientset(ingress, defaultService)
+	forbidden := func(action core.Action) (bool, runtime.Object, error) {
+		resource := action.GetResource().Resource
+		if resource == "services" && action.(core.GetAction).GetName() == "default" {
+			return false, nil, nil
+		}
+		return true, nil, k8serrors.NewForbidden(api.Resource(resource),
+			action.(core.GetAction).GetName(), errors.New("access denied"))
+	}
+	fakeClient.PrependReactor("get", "services", forbidden)
+	fakeClient.PrependReactor("get", "endpoints", forbidden)
+	fakeClient.PrependReactor("get", "secrets", forbidden)
+
+	actual, err := GetIngressDetail(fakeClient, "ns-1", "ing-1")
+	if err != nil {
+		t.Fatalf("GetIngressDetail() returned unexpected error: %v", err)
+	}
+
+	if backend := actual.DefaultBackend; backend.Status != BackendResolved ||
+		backend.Port != 80 || backend.EndpointsHealth != "" || backend.Error == "" {
+		t.Errorf("Expected resolved default backend with error of endpoints, got %#v", backend)
+	}
+	if backend := actual.Rules[0].Backend; backend.Status != BackendUnknown ||
+		backend.Error == "" {
+		t.Errorf("Expected backend of unknown status with error, got %#v", backend)
+	}
+	if tls := actual.TLS[0]; tls.CertificateExpiry != nil || tls.Error == "" {
+		t.Errorf("Expected error of TLS configuration with forbidden secret, got %#v", tls)
+	}
+}
@@ -86,10 +86,10 @@ func TestGetJobDetail(t *testing.T) {
 
 	for _, c := range cases {
 		fakeClient := fake.NewSimpleClientset(c.job)
-		fakeHeapsterClient := FakeHeapsterClient{}
+		metricsProvider := client.NewHeapsterMetricsProvider(FakeHeapsterClient{})
 
 		dataselect.DefaultDataSelectWithMetrics.MetricQuery = dataselect.NoMetrics
-		actual, _ := GetJobDetail(fakeClient, fakeHeapsterClient, c.namespace, c.name)
+		actual, _ := GetJobDetail(fakeClient, metricsProvider, c.namespace, c.name)
 
 		actions := fakeClient.Actions()
 		if len(actions) != len(c.expectedActions) {
@@ -146,77 +146,79 @@ var fakeHeapsterClient = FakeHeapster{
 	NodeData: fakeNodeData,
 }
 
-func fakeHeapsterSelector(resourceType common.ResourceKind, namespace string, resourceNames []string) HeapsterSelector {
-	a, _ := NewHeapsterSelectorFromNativeResource(resourceType, namespace, resourceNames)
+var fakeMetricsProvider = client.NewHeapsterMetricsProvider(fakeHeapsterClient)
+
+func fakeMetricSelector(resourceType common.ResourceKind, namespace string, resourceNames []string) MetricSelector {
+	a, _ := NewMetricSelectorFromNativeResource(resourceType, namespace, resourceNames)
 	return a
 }
 
-func TestHeapsterSelector(t *testing.T) {
-	type HeapsterSelectorTestCase struct {
+func TestMetricSelector(t *testing.T) {
+	type MetricSelectorTestCase struct {
 		Info                string
-		Selector            HeapsterSelector
+		Selector            MetricSelector
 		ExpectedDataPoints  DataPoints
 		ExpectedNumRequests int
 	}
-	testCases := []HeapsterSelectorTestCase{
+	testCases := []MetricSelectorTestCase{
 		{
 			"get data for single pod",
-			fakeHeapsterSelector(common.ResourceKindPod, "a", []string{"P1"}),
+			fakeMetricSelector(common.ResourceKindPod, "a", []string{"P1"}),
 			newDps([]int64{0, 5, 10}, 0),
 			1,
 		},
 		{
 			"get data for 3 pods",
-			fakeHeapsterSelector(common.ResourceKindPod, "a", []string{"P1", "P2", "P3"}),
+			fakeMetricSelector(common.ResourceKindPod, "a", []string{"P1", "P2", "P3"}),
 			newDps([]int64{45, 60, 75}, 0),
 			1,
 		},
 		{
 			"get data for 4 pods where 1 pod does not exist - ignore non existing pod",
-			fakeHeapsterSelector(common.ResourceKindPod, "a", []string{"P1", "P2", "P3", "NON_EXISTING"}),
+			fakeMetricSelector(common.ResourceKindPod, "a", []string{"P1", "P2", "P3", "NON_EXISTING"}),
 			newDps([]int64{45, 60, 75}, 0),
 			1,
 		},
 		{
 			"get data for 4 pods where pods have different X timestams available",
-			fakeHeapsterSelector(common.ResourceKindPod, "b", []string{"P1", "P2", "P3", "P4"}),
+			fakeMetricSelector(common.ResourceKindPod, "b", []string{"P1", "P2", "P3", "P4"}),
 			newDps([]int64{1000, 2300, 2700, 1500}, 0),
 			1,
 		},
 		{
 			"ask for non existing namespace - return no data points",
-			fakeHeapsterSelector(common.ResourceKindPod, "NON_EXISTING_NAMESPACE", []string{"P1"}),
+			fakeMetricSelector(common.ResourceKindPod, "NON_EXISTING_NAMESPACE", []string{"P1"}),
 			newDps([]int64{}, 0),
 			1,
 		},
 		{
 			"get data for 0 pods - return no data points",
-			fakeHeapsterSelector(common.ResourceKindPod, "b", []string{}),
+			fakeMetricSelector(common.ResourceKindPod, "b", []string{}),
 			newDps([]int64{}, 0),
 			0,
 		},
 		{
 			"get data for 0 nodes - return no data points",
-			fakeHeapsterSelector(common.ResourceKindNode, "NO_NAMESPACE", []string{}),
+			fakeMetricSelector(common.ResourceKindNode, "NO_NAMESPACE", []string{}),
 			newDps([]int64{}, 0),
 			0,
 		},
 		{
 			"ask for 1 node",
-			fakeHeapsterSelector(common.ResourceKindNode, "NO_NAMESPACE", []string{"N1"}),
+			fakeMetricSelector(common.ResourceKindNode, "NO_NAMESPACE", []string{"N1"}),
 			newDps([]int64{0, 5, 10}, 0),
 			1,
 		},
 		{
 			"ask for 3 nodes",
-			fakeHeapsterSelector(common.ResourceKindNode, "NO_NAMESPACE", []string{"N1", "N2", "N3"}),
+			fakeMetricSelector(common.ResourceKindNode, "NO_NAMESPACE", []string{"N1", "N2", "N3"}),
 			newDps([]int64{45, 60, 75}, 0),
 			3, // change this to 1 when nodes support all in 1 download.
 		},
 	}
 	for _, testCase := range testCases {
 		log.Println("-----------\n\n\n", testCase.Info, int(_NumRequests.get()))
-		metric, err := testCase.Selector.DownloadMetric(fakeHeapsterClient, "").GetMetric()
+		metric, err := testCase.Selector.DownloadMetric(fakeMetricsProvider, "").GetMetric()
 		num_req := fakeHeapsterClient.GetNumberOfRequestsMade()
 		if err != nil {
 			t.Errorf("Test Case: %s. Failed to get metrics - %s", testCase.Info, err)
@@ -235,18 +237,18 @@ func TestHeapsterSelector(t *testing.T) {
 	}
 }
 
-var selectorPool = HeapsterSelectors{
-	fakeHeapsterSelector(common.ResourceKindPod, "a", []string{"P1"}),
-	fakeHeapsterSelector(common.ResourceKindPod, "a", []string{"P2", "P3", "P4"}),
-	fakeHeapsterSelector(common.ResourceKindPod, "a", []string{"P3", "P4"}),
-	fakeHeapsterSelector(common.ResourceKindPod, "b", []string{"P1", "P2", "P3"}),
-	fakeHeapsterSelector(common.ResourceKindPod, "b", []string{"P2", "P3", "P4"}),
-	fakeHeapsterSelector(common.ResourceKindNode, "NO_NAMESPACE", []string{"N1", "N2", "N3"}),
-	fakeHeapsterSelector(common.ResourceKindNode, "NO_NAMESPACE", []string{"N3", "N4"}),
+var selectorPool = MetricSelectors{
+	fakeMetricSelector(common.ResourceKindPod, "a", []string{"P1"}),
+	fakeMetricSelector(common.ResourceKindPod, "a", []string{"P2", "P3", "P4"}),
+	fakeMetricSelector(common.ResourceKindPod, "a", []string{"P3", "P4"}),
+	fakeMetricSelector(common.ResourceKindPod, "b", []string{"P1", "P2", "P3"}),
+	fakeMetricSelector(common.ResourceKindPod, "b", []string{"P2", "P3", "P4"}),
+	fakeMetricSelector(common.ResourceKindNode, "NO_NAMESPACE", []string{"N1", "N2", "N3"}),
+	fakeMetricSelector(common.ResourceKindNode, "NO_NAMESPACE", []string{"N3", "N4"}),
 }
 
-func TestHeapsterSelectors(t *testing.T) {
-	type HeapsterSelectorsTestCase struct {
+func TestMetricSelectors(t *testing.T) {
+	type MetricSelectorsTestCase struct {
 		Info                string
 		SelectorIds         []int
 		AggregationNames    AggregationNames
@@ -256,7 +258,7 @@ func TestHeapsterSelectors(t *testing.T) {
 	}
 
 	MinMaxSumAggregations := AggregationNames{MinAggregation, MaxAggregation, SumAggregation}
-	testCases := []HeapsterSelectorsTestCase{
+	testCases := []MetricSelectorsTestCase{
 		{
 			"ask for 1 resource",
 			[]int{1},
@@ -326,12 +328,12 @@ func TestHeapsterSelectors(t *testing.T) {
 	}
 
 	for _, testCase := range testCases {
-		selectors := HeapsterSelectors{}
+		selectors := MetricSelectors{}
 		for _, selectorId := range testCase.SelectorIds {
 			selectors = append(selectors, selectorPool[selectorId])
 		}
 
-		metrics, err := selectors.DownloadAndAggregate(fakeHeapsterClient, testCase.MetricNames, testCase.AggregationNames).GetMetrics()
+		metrics, err := selectors.DownloadAndAggregate(fakeMetricsProvider, testCase.MetricNames, testCase.AggregationNames).GetMetrics()
 		if err != nil {
 			t.Errorf("Test Case: %s. Failed to get metrics - %s", testCase.Info, err)
 			return
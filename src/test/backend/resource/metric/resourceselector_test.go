@@ -54,7 +54,7 @@ func TestResourceSelector(t *testing.T) {
 	testCases := []struct {
 		Info                   string
 		ResourceSelector       ResourceSelector
-		ExpectedPath           string
+		ExpectedNamespace      string
 		ExpectedTargetResource common.ResourceKind
 		ExpectedResources      []string
 	}{
@@ -65,7 +65,7 @@ func TestResourceSelector(t *testing.T) {
 				ResourceType: common.ResourceKindPod,
 				ResourceName: "foo",
 			},
-			`namespaces/bar/pod-list/`,
+			"bar",
 			common.ResourceKindPod,
 			[]string{"foo"},
 		},
@@ -76,7 +76,7 @@ func TestResourceSelector(t *testing.T) {
 				ResourceType: common.ResourceKindNode,
 				ResourceName: "foon",
 			},
-			`nodes/`,
+			"",
 			common.ResourceKindNode,
 			[]string{"foon"},
 		},
@@ -88,7 +88,7 @@ func TestResourceSelector(t *testing.T) {
 				ResourceName: "baba",
 				Selector:     resource1,
 			},
-			`namespaces/a/pod-list/`,
+			"a",
 			common.ResourceKindPod,
 			[]string{"1", "3"},
 		},
@@ -100,15 +100,15 @@ func TestResourceSelector(t *testing.T) {
 				ResourceName:  "baba",
 				LabelSelector: &unversioned.LabelSelector{MatchLabels: resource1},
 			},
-			`namespaces/a/pod-list/`,
+			"a",
 			common.ResourceKindPod,
 			[]string{"1", "3"},
 		},
 	}
 	for _, testCase := range testCases {
-		sel, err := testCase.ResourceSelector.GetHeapsterSelector(cachedPodList)
+		sel, err := testCase.ResourceSelector.GetMetricSelector(cachedPodList)
 		if err != nil {
-			t.Errorf("Test Case: %s. Failed to get HeapsterSelector. - %s", testCase.Info, err)
+			t.Errorf("Test Case: %s. Failed to get MetricSelector. - %s", testCase.Info, err)
 			return
 		}
 		if !reflect.DeepEqual(sel.Resources, testCase.ExpectedResources) {
@@ -119,9 +119,9 @@ func TestResourceSelector(t *testing.T) {
 			t.Errorf("Test Case: %s. Used invalid target resource type. Got %s, expected %s.",
 				testCase.Info, sel.TargetResourceType, testCase.ExpectedTargetResource)
 		}
-		if sel.Path != testCase.ExpectedPath {
-			t.Errorf("Test Case: %s. Converted to invalid heapster download path. Got %s, expected %s.",
-				testCase.Info, sel.Path, testCase.ExpectedPath)
+		if sel.Namespace != testCase.ExpectedNamespace {
+			t.Errorf("Test Case: %s. Converted to invalid namespace. Got %s, expected %s.",
+				testCase.Info, sel.Namespace, testCase.ExpectedNamespace)
 		}
 
 	}
@@ -0,0 +1,177 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/resource"
+)
+
+func newUsageTestPod(name string, requests, limits api.ResourceList) api.Pod {
+	return api.Pod{
+		ObjectMeta: api.ObjectMeta{Name: name, Namespace: "ns"},
+		Spec: api.PodSpec{Containers: []api.Container{{
+			Name:      "container",
+			Resources: api.ResourceRequirements{Requests: requests, Limits: limits},
+		}}},
+	}
+}
+
+func TestToNamespaceUsage(t *testing.T) {
+	pods := []api.Pod{
+		newUsageTestPod("a", api.ResourceList{
+			api.ResourceCPU:    resource.MustParse("500m"),
+			api.ResourceMemory: resource.MustParse("256Mi"),
+		}, api.ResourceList{
+			api.ResourceCPU: resource.MustParse("1"),
+		}),
+		newUsageTestPod("b", api.ResourceList{
+			api.ResourceCPU:    resource.MustParse("250m"),
+			api.ResourceMemory: resource.MustParse("256Mi"),
+		}, nil),
+		newUsageTestPod("best-effort", nil, nil),
+	}
+	quotas := []api.ResourceQuota{
+		{
+			ObjectMeta: api.ObjectMeta{Name: "compute"},
+			Spec: api.ResourceQuotaSpec{Hard: api.ResourceList{
+				api.ResourceRequestsCPU:  resource.MustParse("1"),
+				api.ResourceLimitsMemory: resource.MustParse("1Gi"),
+				api.ResourcePods:         resource.MustParse("10"),
+			}},
+		},
+		{
+			ObjectMeta: api.ObjectMeta{Name: "objects"},
+			Spec: api.ResourceQuotaSpec{
+				Hard:   api.ResourceList{api.ResourceServices: resource.MustParse("4")},
+				Scopes: []api.ResourceQuotaScope{api.ResourceQuotaScopeNotBestEffort},
+			},
+			Status: api.ResourceQuotaStatus{
+				Used: api.ResourceList{api.ResourceServices: resource.MustParse("1")},
+			},
+		},
+	}
+	limitRanges := []api.LimitRange{{
+		Spec: api.LimitRangeSpec{Limits: []api.LimitRangeItem{{
+			Type:    api.LimitTypeContainer,
+			Default: api.ResourceList{api.ResourceMemory: resource.MustParse("512Mi")},
+			Min:     api.ResourceList{api.ResourceMemory: resource.MustParse("64Mi")},
+		}}},
+	}}
+
+	expected := &NamespaceUsage{
+		Requests: map[api.ResourceName]string{
+			api.ResourceCPU:    "750m",
+			api.ResourceMemory: "512Mi",
+		},
+		Limits: map[api.ResourceName]string{
+			api.ResourceCPU: "1",
+		},
+		Quotas: []QuotaUsage{
+			{
+				Name: "compute",
+				Resources: []QuotaResourceUsage{
+					{Name: api.ResourceLimitsMemory, Hard: "1Gi", Used: "0", Percentage: 0},
+					{Name: api.ResourcePods, Hard: "10", Used: "3", Percentage: 30},
+					{Name: api.ResourceRequestsCPU, Hard: "1", Used: "750m", Percentage: 75},
+				},
+			},
+			{
+				Name:   "objects",
+				Scopes: []api.ResourceQuotaScope{api.ResourceQuotaScopeNotBestEffort},
+				Resources: []QuotaResourceUsage{
+					{Name: api.ResourceServices, Hard: "4", Used: "1", Percentage: 25},
+				},
+			},
+		},
+		PodsWithoutRequests: []PodWithoutRequests{
+			{Name: "best-effort", MissingResources: []api.ResourceName{api.ResourceRequestsCPU}},
+		},
+	}
+
+	actual, err := toNamespaceUsage(pods, quotas, limitRanges)
+	if err != nil {
+		t.Fatalf("toNamespaceUsage() returned error: %s", err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("toNamespaceUsage() == \n%#v\nexpected \n%#v", actual, expected)
+	}
+}
+
+func TestGetMissingResources(t *testing.T) {
+	cases := []struct {
+		pod      api.Pod
+		demanded map[api.ResourceName]bool
+		expected []api.ResourceName
+	}{
+		{
+			newUsageTestPod("a", nil, nil),
+			map[api.ResourceName]bool{},
+			[]api.ResourceName{},
+		},
+		{
+			newUsageTestPod("a", api.ResourceList{
+				api.ResourceCPU: resource.MustParse("100m"),
+			}, nil),
+			map[api.ResourceName]bool{
+				api.ResourceRequestsCPU:  true,
+				api.ResourceLimitsMemory: true,
+			},
+			[]api.ResourceName{api.ResourceLimitsMemory},
+		},
+		{
+			newUsageTestPod("a", nil, nil),
+			map[api.ResourceName]bool{
+				api.ResourceRequestsMemory: true,
+				api.ResourceRequestsCPU:    true,
+			},
+			[]api.ResourceName{api.ResourceRequestsCPU, api.ResourceRequestsMemory},
+		},
+	}
+	for _, c := range cases {
+		actual := GetMissingResources(c.pod, c.demanded)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetMissingResources(%#v) == %#v, expected %#v", c.demanded, actual,
+				c.expected)
+		}
+	}
+}
+
+func TestSumPodMetrics(t *testing.T) {
+	cpu, memory := uint64(100), uint64(1024)
+	metrics := &common.MetricsByPod{MetricsMap: map[string]map[string]common.PodMetrics{
+		"ns": {
+			"a": {CPUUsage: &cpu, MemoryUsage: &memory},
+			"b": {CPUUsage: &cpu},
+		},
+	}}
+
+	cpuUsage, memoryUsage := sumPodMetrics(metrics)
+	if cpuUsage == nil || *cpuUsage != 200 {
+		t.Errorf("sumPodMetrics() returned CPU usage %v, expected 200", cpuUsage)
+	}
+	if memoryUsage == nil || *memoryUsage != 1024 {
+		t.Errorf("sumPodMetrics() returned memory usage %v, expected 1024", memoryUsage)
+	}
+
+	cpuUsage, memoryUsage = sumPodMetrics(nil)
+	if cpuUsage != nil || memoryUsage != nil {
+		t.Errorf("sumPodMetrics(nil) == %v, %v, expected nil", cpuUsage, memoryUsage)
+	}
+}
@@ -90,10 +90,11 @@ func TestGetNodeDetail(t *testing.T) {
 
 	for _, c := range cases {
 		fakeClient := fake.NewSimpleClientset(c.node)
-		fakeHeapsterClient := FakeHeapsterClient{client: fake.NewSimpleClientset()}
+		metricsProvider := client.NewHeapsterMetricsProvider(
+			FakeHeapsterClient{client: fake.NewSimpleClientset()})
 
 		dataselect.StdMetricsDataSelect.MetricQuery = dataselect.NoMetrics
-		actual, _ := GetNodeDetail(fakeClient, fakeHeapsterClient, c.name)
+		actual, _ := GetNodeDetail(fakeClient, metricsProvider, c.name)
 
 		if !reflect.DeepEqual(actual, c.expected) {
 			t.Errorf("GetNodeDetail(client,heapsterClient,%#v, %#v) == \ngot: %#v, \nexpected %#v",
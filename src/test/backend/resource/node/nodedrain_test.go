@@ -0,0 +1,251 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"k8s.io/kubernetes/pkg/api"
+	k8serrors "k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset/fake"
+	"k8s.io/kubernetes/pkg/client/testing/core"
+	"k8s.io/kubernetes/pkg/runtime"
+	"k8s.io/kubernetes/pkg/types"
+)
+
+func newDrainTestPod(name string, annotations map[string]string, volumes ...api.Volume) *api.Pod {
+	return &api.Pod{
+		ObjectMeta: api.ObjectMeta{
+			Name:        name,
+			Namespace:   "ns",
+			UID:         types.UID("uid-" + name),
+			Annotations: annotations,
+		},
+		Spec: api.PodSpec{NodeName: "node-1", Volumes: volumes},
+	}
+}
+
+func createdBy(kind string) map[string]string {
+	return map[string]string{
+		api.CreatedByAnnotation: `{"kind":"SerializedReference","apiVersion":"v1",` +
+			`"reference":{"kind":"` + kind + `","namespace":"ns","name":"owner"}}`,
+	}
+}
+
+// newDrainTestClient returns a fake client that accepts all evictions. The fake eviction client
+// does not pass names of the evicted pods to the object tracker.
+func newDrainTestClient(objects ...runtime.Object) *fake.Clientset {
+	client := fake.NewSimpleClientset(objects...)
+	client.PrependReactor("post", "pods", func(action core.Action) (bool, runtime.Object, error) {
+		return action.GetSubresource() == "eviction", nil, nil
+	})
+	return client
+}
+
+func countEvictions(actions []core.Action) int {
+	evictions := 0
+	for _, action := range actions {
+		if action.GetVerb() == "post" && action.GetSubresource() == "eviction" {
+			evictions++
+		}
+	}
+	return evictions
+}
+
+func TestCordonNode(t *testing.T) {
+	client := fake.NewSimpleClientset(&api.Node{ObjectMeta: api.ObjectMeta{Name: "node-1"}})
+
+	result, err := CordonNode(client, "node-1")
+	if err != nil {
+		t.Fatalf("CordonNode() returned error: %s", err)
+	}
+	if !result.Unschedulable {
+		t.Errorf("CordonNode() returned schedulable node")
+	}
+	node, _ := client.Core().Nodes().Get("node-1")
+	if !node.Spec.Unschedulable {
+		t.Errorf("CordonNode() did not mark the node as unschedulable")
+	}
+
+	result, err = UncordonNode(client, "node-1")
+	if err != nil {
+		t.Fatalf("UncordonNode() returned error: %s", err)
+	}
+	if result.Unschedulable {
+		t.Errorf("UncordonNode() returned unschedulable node")
+	}
+	node, _ = client.Core().Nodes().Get("node-1")
+	if node.Spec.Unschedulable {
+		t.Errorf("UncordonNode() did not mark the node as schedulable")
+	}
+}
+
+func TestCordonMissingNode(t *testing.T) {
+	_, err := CordonNode(fake.NewSimpleClientset(), "node-1")
+	if !k8serrors.IsNotFound(err) {
+		t.Errorf("CordonNode() returned %v, expected not found error", err)
+	}
+}
+
+func TestDrainNode(t *testing.T) {
+	emptyDir := api.Volume{
+		Name:         "cache",
+		VolumeSource: api.VolumeSource{EmptyDir: &api.EmptyDirVolumeSource{}},
+	}
+	cases := []struct {
+		pods     []runtime.Object
+		spec     *DrainSpec
+		expected *DrainResult
+		evicted  int
+	}{
+		{
+			[]runtime.Object{
+				newDrainTestPod("managed", createdBy("ReplicaSet")),
+				newDrainTestPod("mirror", map[string]string{
+					"kubernetes.io/config.mirror": "mirror"}),
+				newDrainTestPod("daemon", createdBy("DaemonSet")),
+			},
+			&DrainSpec{IgnoreDaemonSets: true},
+			&DrainResult{NodeName: "node-1", Complete: true, Pods: []DrainPodStatus{
+				{Name: "mirror", Namespace: "ns", Status: PodIgnored,
+					Reason: "Mirror pod of a static pod"},
+				{Name: "daemon", Namespace: "ns", Status: PodIgnored,
+					Reason: "Managed by a daemon set"},
+				{Name: "managed", Namespace: "ns", Status: PodEvicted},
+			}},
+			1,
+		},
+		{
+			[]runtime.Object{
+				newDrainTestPod("managed", createdBy("ReplicaSet")),
+				newDrainTestPod("daemon", createdBy("DaemonSet")),
+				newDrainTestPod("unmanaged", nil),
+				newDrainTestPod("local", createdBy("ReplicaSet"), emptyDir),
+			},
+			&DrainSpec{},
+			&DrainResult{NodeName: "node-1", Complete: false, Pods: []DrainPodStatus{
+				{Name: "daemon", Namespace: "ns", Status: PodBlocking,
+					Reason: "Managed by a daemon set"},
+				{Name: "unmanaged", Namespace: "ns", Status: PodBlocking,
+					Reason: "Not managed by any controller"},
+				{Name: "local", Namespace: "ns", Status: PodBlocking,
+					Reason: "Has local storage"},
+				{Name: "managed", Namespace: "ns", Status: PodNotEvicted,
+					Reason: "Other pods prevent the drain"},
+			}},
+			0,
+		},
+		{
+			[]runtime.Object{
+				newDrainTestPod("unmanaged", nil),
+				newDrainTestPod("local", createdBy("ReplicaSet"), emptyDir),
+			},
+			&DrainSpec{Force: true, DeleteLocalData: true},
+			&DrainResult{NodeName: "node-1", Complete: true, Pods: []DrainPodStatus{
+				{Name: "unmanaged", Namespace: "ns", Status: PodEvicted},
+				{Name: "local", Namespace: "ns", Status: PodEvicted},
+			}},
+			2,
+		},
+	}
+
+	for _, c := range cases {
+		objects := append(c.pods, &api.Node{ObjectMeta: api.ObjectMeta{Name: "node-1"}})
+		client := newDrainTestClient(objects...)
+		progress := make([]DrainPodStatus, 0)
+
+		actual, err := DrainNode(client, "node-1", c.spec, func(status DrainPodStatus) {
+			progress = append(progress, status)
+		})
+		if err != nil {
+			t.Fatalf("DrainNode(%#v) returned error: %s", c.spec, err)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("DrainNode(%#v) == \n%#v\nexpected \n%#v", c.spec, actual, c.expected)
+		}
+		if len(progress) != len(c.expected.Pods) {
+			t.Errorf("DrainNode(%#v) reported progress of %d pods, expected %d", c.spec,
+				len(progress), len(c.expected.Pods))
+		}
+		if evicted := countEvictions(client.Actions()); evicted != c.evicted {
+			t.Errorf("DrainNode(%#v) evicted %d pods, expected %d", c.spec, evicted, c.evicted)
+		}
+		node, _ := client.Core().Nodes().Get("node-1")
+		if !node.Spec.Unschedulable {
+			t.Errorf("DrainNode(%#v) did not cordon the node", c.spec)
+		}
+	}
+}
+
+func TestDrainNodeWaitsForDeletion(t *testing.T) {
+	client := newDrainTestClient(
+		&api.Node{ObjectMeta: api.ObjectMeta{Name: "node-1"}},
+		newDrainTestPod("managed", createdBy("ReplicaSet")))
+	client.PrependReactor("get", "pods",
+		func(action core.Action) (bool, runtime.Object, error) {
+			return true, nil, k8serrors.NewNotFound(api.Resource("pods"), "managed")
+		})
+	progress := make([]DrainPodStatus, 0)
+
+	actual, err := DrainNode(client, "node-1", &DrainSpec{TimeoutSeconds: 10},
+		func(status DrainPodStatus) {
+			progress = append(progress, status)
+		})
+	if err != nil {
+		t.Fatalf("DrainNode() returned error: %s", err)
+	}
+
+	expected := &DrainResult{NodeName: "node-1", Complete: true, Pods: []DrainPodStatus{
+		{Name: "managed", Namespace: "ns", Status: PodDeleted},
+	}}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("DrainNode() == \n%#v\nexpected \n%#v", actual, expected)
+	}
+	expectedProgress := []DrainPodStatus{
+		{Name: "managed", Namespace: "ns", Status: PodEvicted},
+		{Name: "managed", Namespace: "ns", Status: PodDeleted},
+	}
+	if !reflect.DeepEqual(progress, expectedProgress) {
+		t.Errorf("DrainNode() reported progress \n%#v\nexpected \n%#v", progress,
+			expectedProgress)
+	}
+}
+
+func TestCordonNodeRetriesOnConflict(t *testing.T) {
+	client := fake.NewSimpleClientset(&api.Node{ObjectMeta: api.ObjectMeta{Name: "node-1"}})
+	conflicts := 0
+	client.PrependReactor("update", "nodes", func(action core.Action) (bool, runtime.Object, error) {
+		if conflicts > 0 {
+			return false, nil, nil
+		}
+		conflicts++
+		return true, nil, k8serrors.NewConflict(api.Resource("nodes"), "node-1",
+			errors.New("the object has been modified"))
+	})
+
+	result, err := CordonNode(client, "node-1")
+	if err != nil {
+		t.Fatalf("CordonNode() returned error: %s", err)
+	}
+	if !result.Unschedulable {
+		t.Errorf("CordonNode() returned schedulable node")
+	}
+	node, _ := client.Core().Nodes().Get("node-1")
+	if !node.Spec.Unschedulable {
+		t.Errorf("CordonNode() did not mark the node as unschedulable after a conflict")
+	}
+}
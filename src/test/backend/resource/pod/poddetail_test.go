@@ -71,7 +71,8 @@ func TestToPodDetail(t *testing.T) {
 		fakeClient := fake.NewSimpleClientset(c.pod)
 
 		dataselect.DefaultDataSelectWithMetrics.MetricQuery = dataselect.NoMetrics
-		actual, err := GetPodDetail(fakeClient, FakeHeapsterClient{}, "test-namespace", "test-pod")
+		metricsProvider := client.NewHeapsterMetricsProvider(FakeHeapsterClient{})
+		actual, err := GetPodDetail(fakeClient, metricsProvider, "test-namespace", "test-pod")
 
 		if err != nil {
 			t.Errorf("GetPodDetail(%#v) == \ngot err %#v", c.pod, err)
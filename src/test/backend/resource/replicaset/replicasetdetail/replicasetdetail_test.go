@@ -81,10 +81,11 @@ func TestGetReplicaSetDetail(t *testing.T) {
 
 	for _, c := range cases {
 		fakeClient := fake.NewSimpleClientset(c.replicaSet)
-		fakeHeapsterClient := FakeHeapsterClient{client: fake.NewSimpleClientset()}
+		metricsProvider := client.NewHeapsterMetricsProvider(
+			FakeHeapsterClient{client: fake.NewSimpleClientset()})
 
 		dataselect.DefaultDataSelectWithMetrics.MetricQuery = dataselect.NoMetrics
-		actual, _ := GetReplicaSetDetail(fakeClient, fakeHeapsterClient, c.namespace, c.name)
+		actual, _ := GetReplicaSetDetail(fakeClient, metricsProvider, c.namespace, c.name)
 
 		actions := fakeClient.Actions()
 		if len(actions) != len(c.expectedActions) {
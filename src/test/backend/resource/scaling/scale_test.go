@@ -0,0 +1,121 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scaling
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	k8serrors "k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/runtime"
+)
+
+type fakeResourceVerber struct {
+	raw string
+	put *runtime.Unknown
+}
+
+func (verber *fakeResourceVerber) Get(kind string, namespace string,
+	name string) (runtime.Object, error) {
+	return &runtime.Unknown{Raw: []byte(verber.raw)}, nil
+}
+
+func (verber *fakeResourceVerber) Put(kind string, namespace string, name string,
+	object *runtime.Unknown) error {
+	verber.put = object
+	return nil
+}
+
+func TestGetReplicaCounts(t *testing.T) {
+	cases := []struct {
+		kind     string
+		raw      string
+		expected *ReplicaCounts
+	}{
+		{
+			"deployment",
+			`{"spec": {"replicas": 3}, "status": {"replicas": 2}}`,
+			&ReplicaCounts{Kind: "deployment", DesiredReplicas: 3, ActualReplicas: 2},
+		},
+		{
+			"job",
+			`{"spec": {"parallelism": 4}, "status": {"active": 1}}`,
+			&ReplicaCounts{Kind: "job", DesiredReplicas: 4, ActualReplicas: 1},
+		},
+		{
+			"statefulset",
+			`{"spec": {"replicas": 1}, "status": {}}`,
+			&ReplicaCounts{Kind: "statefulset", DesiredReplicas: 1, ActualReplicas: 0},
+		},
+	}
+
+	for _, c := range cases {
+		actual, err := GetReplicaCounts(&fakeResourceVerber{raw: c.raw}, c.kind, "ns", "name")
+		if err != nil {
+			t.Errorf("GetReplicaCounts(%s) returned unexpected error: %v", c.kind, err)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("GetReplicaCounts(%s) == %#v, expected %#v", c.kind, actual, c.expected)
+		}
+	}
+}
+
+func TestGetReplicaCountsOfNotScalableKind(t *testing.T) {
+	_, err := GetReplicaCounts(&fakeResourceVerber{raw: "{}"}, "service", "ns", "name")
+	if !k8serrors.IsBadRequest(err) {
+		t.Errorf("Expected bad request for kind that can not be scaled, got %v", err)
+	}
+}
+
+func TestScaleResource(t *testing.T) {
+	verber := &fakeResourceVerber{raw: `{"metadata": {"name": "foo", "resourceVersion": "10",
+		"generation": 12345678901}, "spec": {"parallelism": 1}, "status": {"active": 1}}`}
+
+	actual, err := ScaleResource(verber, "job", "ns", "foo", &ScaleSpec{Replicas: 5})
+	if err != nil {
+		t.Fatalf("ScaleResource() returned unexpected error: %v", err)
+	}
+	expected := &ReplicaCounts{Kind: "job", DesiredReplicas: 5, ActualReplicas: 1}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("ScaleResource() == %#v, expected %#v", actual, expected)
+	}
+
+	if !strings.Contains(string(verber.put.Raw), `"generation":12345678901`) {
+		t.Errorf("Expected numbers to be put without loss of precision, got %s", verber.put.Raw)
+	}
+	put := make(map[string]interface{})
+	if err := json.Unmarshal(verber.put.Raw, &put); err != nil {
+		t.Fatal(err)
+	}
+	expectedPut := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":            "foo",
+			"resourceVersion": "10",
+			"generation":      float64(12345678901),
+		},
+		"spec":   map[string]interface{}{"parallelism": float64(5)},
+		"status": map[string]interface{}{"active": float64(1)},
+	}
+	if !reflect.DeepEqual(put, expectedPut) {
+		t.Errorf("Put object %#v, expected %#v", put, expectedPut)
+	}
+
+	_, err = ScaleResource(verber, "job", "ns", "foo", &ScaleSpec{Replicas: -1})
+	if !k8serrors.IsInvalid(err) {
+		t.Errorf("Expected invalid error for negative number of replicas, got %v", err)
+	}
+}
@@ -20,6 +20,8 @@ import (
 
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"k8s.io/kubernetes/pkg/api"
+	k8serrors "k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset/fake"
 )
 
 func TestGetSecretDetail(t *testing.T) {
@@ -29,12 +31,13 @@ func TestGetSecretDetail(t *testing.T) {
 	}{
 		{
 			&api.Secret{
-				Data: map[string][]byte{"app": {0, 1, 2, 3}}, ObjectMeta: api.ObjectMeta{Name: "foo"},
+				Data:       map[string][]byte{"app": {0, 1, 2, 3}, "a": {}},
+				ObjectMeta: api.ObjectMeta{Name: "foo"},
 			},
 			&SecretDetail{
 				TypeMeta:   common.TypeMeta{Kind: "secret"},
 				ObjectMeta: common.ObjectMeta{Name: "foo"},
-				Data:       map[string][]byte{"app": {0, 1, 2, 3}},
+				Keys:       []SecretKey{{Name: "a", Size: 0}, {Name: "app", Size: 4}},
 			},
 		},
 	}
@@ -46,3 +49,36 @@ func TestGetSecretDetail(t *testing.T) {
 		}
 	}
 }
+
+func TestRevealSecretValue(t *testing.T) {
+	rawSecret := &api.Secret{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "bar"},
+		Data: map[string][]byte{
+			"password": []byte("secret"),
+			"keystore": {0xff, 0xfe, 0x00},
+		},
+	}
+
+	cases := []struct {
+		key              string
+		expected         *SecretValue
+		expectedNotFound bool
+	}{
+		{"password", &SecretValue{Key: "password", Value: "secret"}, false},
+		{"keystore", &SecretValue{Key: "keystore", Value: "//4A", Base64: true}, false},
+		{"missing", nil, true},
+	}
+
+	for _, c := range cases {
+		actual, err := RevealSecretValue(fake.NewSimpleClientset(rawSecret), "bar", "foo", c.key)
+		if k8serrors.IsNotFound(err) != c.expectedNotFound ||
+			err != nil && !c.expectedNotFound {
+			t.Errorf("RevealSecretValue(%s) returned error %v, expected not found error: %t",
+				c.key, err, c.expectedNotFound)
+			continue
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("RevealSecretValue(%s) == %#v, expected %#v", c.key, actual, c.expected)
+		}
+	}
+}
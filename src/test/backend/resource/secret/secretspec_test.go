@@ -0,0 +1,113 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kubernetes/dashboard/src/app/backend/cert"
+	"k8s.io/kubernetes/pkg/api"
+)
+
+func TestSecretSpecs(t *testing.T) {
+	certPEM, keyPEM, err := cert.GenerateSelfSignedCertificate([]string{"foo.com"}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherKeyPEM, err := cert.GenerateSelfSignedCertificate([]string{"bar.com"}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		info          string
+		spec          SecretSpec
+		expectedType  api.SecretType
+		expectedData  map[string][]byte
+		expectedError bool
+	}{
+		{
+			"opaque",
+			&OpaqueSecretSpec{
+				Data:       map[string][]byte{"file": {0, 1}, "key": []byte("overridden")},
+				StringData: map[string]string{"key": "value"},
+			},
+			api.SecretTypeOpaque,
+			map[string][]byte{"file": {0, 1}, "key": []byte("value")},
+			false,
+		},
+		{"empty opaque", &OpaqueSecretSpec{}, api.SecretTypeOpaque, map[string][]byte{}, true},
+		{
+			"tls",
+			&TLSSecretSpec{Certificate: string(certPEM), PrivateKey: string(keyPEM)},
+			api.SecretTypeTLS,
+			map[string][]byte{api.TLSCertKey: certPEM, api.TLSPrivateKeyKey: keyPEM},
+			false,
+		},
+		{
+			"tls with mismatched key",
+			&TLSSecretSpec{Certificate: string(certPEM), PrivateKey: string(otherKeyPEM)},
+			api.SecretTypeTLS,
+			map[string][]byte{api.TLSCertKey: certPEM, api.TLSPrivateKeyKey: otherKeyPEM},
+			true,
+		},
+		{
+			"tls without PEM",
+			&TLSSecretSpec{Certificate: "foo", PrivateKey: string(keyPEM)},
+			api.SecretTypeTLS,
+			map[string][]byte{api.TLSCertKey: []byte("foo"), api.TLSPrivateKeyKey: keyPEM},
+			true,
+		},
+		{
+			"basic auth",
+			&BasicAuthSecretSpec{Username: "admin", Password: "pass"},
+			api.SecretTypeBasicAuth,
+			map[string][]byte{api.BasicAuthUsernameKey: []byte("admin"),
+				api.BasicAuthPasswordKey: []byte("pass")},
+			false,
+		},
+		{
+			"ssh auth",
+			&SSHAuthSecretSpec{PrivateKey: string(keyPEM)},
+			api.SecretTypeSSHAuth,
+			map[string][]byte{api.SSHAuthPrivateKey: keyPEM},
+			false,
+		},
+		{
+			"ssh auth without PEM",
+			&SSHAuthSecretSpec{PrivateKey: "foo"},
+			api.SecretTypeSSHAuth,
+			map[string][]byte{api.SSHAuthPrivateKey: []byte("foo")},
+			true,
+		},
+	}
+
+	for _, c := range cases {
+		if err := c.spec.Validate(); (err != nil) != c.expectedError {
+			t.Errorf("Test Case: %s. Validate() returned %v, expected error: %t", c.info, err,
+				c.expectedError)
+		}
+		if actual := c.spec.GetType(); actual != c.expectedType {
+			t.Errorf("Test Case: %s. GetType() == %s, expected %s", c.info, actual,
+				c.expectedType)
+		}
+		if actual := c.spec.GetData(); !reflect.DeepEqual(actual, c.expectedData) {
+			t.Errorf("Test Case: %s. GetData() == %v, expected %v", c.info, actual,
+				c.expectedData)
+		}
+	}
+}
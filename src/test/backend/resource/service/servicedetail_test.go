@@ -54,7 +54,7 @@ func TestGetServiceDetail(t *testing.T) {
 				Name: "svc-1", Namespace: "ns-1", Labels: map[string]string{},
 			}},
 			namespace: "ns-1", name: "svc-1",
-			expectedActions: []string{"get", "get", "list"},
+			expectedActions: []string{"get", "get", "get", "list"},
 			expected: &ServiceDetail{
 				ObjectMeta: common.ObjectMeta{
 					Name:      "svc-1",
@@ -67,15 +67,18 @@ func TestGetServiceDetail(t *testing.T) {
 					Pods:              []pod.Pod{},
 					CumulativeMetrics: make([]metric.Metric, 0),
 				},
+				EndpointSubsets: []EndpointSubset{},
+				EndpointsHealth: NoEndpoints,
 			},
 		},
 	}
 
 	for _, c := range cases {
 		fakeClient := fake.NewSimpleClientset(c.service)
-		fakeHeapsterClient := FakeHeapsterClient{client: fake.NewSimpleClientset()}
+		metricsProvider := client.NewHeapsterMetricsProvider(
+			FakeHeapsterClient{client: fake.NewSimpleClientset()})
 
-		actual, _ := GetServiceDetail(fakeClient, fakeHeapsterClient,
+		actual, _ := GetServiceDetail(fakeClient, metricsProvider,
 			c.namespace, c.name, dataselect.NoDataSelect)
 
 		actions := fakeClient.Actions()
@@ -0,0 +1,101 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset/fake"
+)
+
+func TestGetServiceEndpoints(t *testing.T) {
+	nodeName := "node-1"
+	service := &api.Service{
+		ObjectMeta: api.ObjectMeta{Name: "svc-1", Namespace: "ns-1"},
+		Spec: api.ServiceSpec{Ports: []api.ServicePort{
+			{Name: "http", Port: 80, Protocol: api.ProtocolTCP},
+			{Name: "dns", Port: 53, Protocol: api.ProtocolUDP},
+		}},
+	}
+	endpoints := &api.Endpoints{
+		ObjectMeta: api.ObjectMeta{Name: "svc-1", Namespace: "ns-1"},
+		Subsets: []api.EndpointSubset{{
+			Addresses: []api.EndpointAddress{{
+				IP:        "10.0.0.1",
+				NodeName:  &nodeName,
+				TargetRef: &api.ObjectReference{Kind: "Pod", Name: "pod-1"},
+			}},
+			NotReadyAddresses: []api.EndpointAddress{{IP: "10.0.0.2"}},
+			Ports: []api.EndpointPort{
+				{Name: "http", Port: 8080, Protocol: api.ProtocolTCP},
+				{Name: "dns", Port: 5353, Protocol: api.ProtocolUDP},
+			},
+		}},
+	}
+
+	expected := []EndpointSubset{{
+		ReadyAddresses: []EndpointAddress{
+			{IP: "10.0.0.1", NodeName: "node-1", Pod: "pod-1"},
+		},
+		NotReadyAddresses: []EndpointAddress{{IP: "10.0.0.2"}},
+		Ports: []EndpointPort{
+			{Name: "http", ServicePort: 80, TargetPort: 8080, Protocol: api.ProtocolTCP},
+			{Name: "dns", ServicePort: 53, TargetPort: 5353, Protocol: api.ProtocolUDP},
+		},
+	}}
+
+	actual, err := GetServiceEndpoints(fake.NewSimpleClientset(endpoints), service)
+	if err != nil {
+		t.Fatalf("GetServiceEndpoints() returned unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("GetServiceEndpoints() == \n%#v, expected \n%#v", actual, expected)
+	}
+
+	actual, err = GetServiceEndpoints(fake.NewSimpleClientset(), service)
+	if err != nil || len(actual) != 0 {
+		t.Errorf("Expected no endpoint subsets of service without endpoints, got %#v, %v",
+			actual, err)
+	}
+}
+
+func TestGetEndpointsHealth(t *testing.T) {
+	ready := []EndpointAddress{{IP: "10.0.0.1"}}
+	notReady := []EndpointAddress{{IP: "10.0.0.2"}}
+
+	cases := []struct {
+		info     string
+		subsets  []EndpointSubset
+		expected string
+	}{
+		{"no subsets", []EndpointSubset{}, NoEndpoints},
+		{"only not ready", []EndpointSubset{{NotReadyAddresses: notReady}}, NoEndpoints},
+		{
+			"ready and not ready",
+			[]EndpointSubset{{ReadyAddresses: ready}, {NotReadyAddresses: notReady}},
+			EndpointsPartiallyReady,
+		},
+		{"only ready", []EndpointSubset{{ReadyAddresses: ready}}, EndpointsReady},
+	}
+
+	for _, c := range cases {
+		if actual := GetEndpointsHealth(c.subsets); actual != c.expected {
+			t.Errorf("Test Case: %s. GetEndpointsHealth() == %s, expected %s", c.info, actual,
+				c.expected)
+		}
+	}
+}
@@ -60,7 +60,8 @@ func TestGetServiceList(t *testing.T) {
 	for _, c := range cases {
 		fakeClient := fake.NewSimpleClientset(c.serviceList)
 
-		actual, _ := GetServiceList(fakeClient, common.NewNamespaceQuery(nil), dataselect.NoDataSelect)
+		actual, _ := GetServiceList(fakeClient, common.NewNamespaceQuery(nil), common.ListEverything,
+			dataselect.NoDataSelect)
 
 		actions := fakeClient.Actions()
 		if len(actions) != len(c.expectedActions) {
@@ -18,6 +18,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/kubernetes/dashboard/src/app/backend/client"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/common"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/daemonset/daemonsetlist"
 	"github.com/kubernetes/dashboard/src/app/backend/resource/dataselect"
@@ -333,3 +334,113 @@ func TestGetWorkloadsFromChannels(t *testing.T) {
 		}
 	}
 }
+
+// fakeMetricsProvider returns no samples for any resource.
+type fakeMetricsProvider struct{}
+
+func (fakeMetricsProvider) DownloadMetric(query client.MetricQuery) ([]client.MetricSamples, error) {
+	return make([]client.MetricSamples, len(query.ResourceNames)), nil
+}
+
+func TestGetWorkloadsFromChannelsWithSelectedPods(t *testing.T) {
+	rcList := &api.ReplicationControllerList{
+		Items: []api.ReplicationController{{
+			ObjectMeta: api.ObjectMeta{Name: "rc-name", Namespace: "ns",
+				Labels: map[string]string{"tier": "web"}},
+			Spec: api.ReplicationControllerSpec{
+				Selector: map[string]string{"app": "foo"},
+				Template: &api.PodTemplateSpec{},
+			},
+		}},
+	}
+	selectedPod := api.Pod{
+		ObjectMeta: api.ObjectMeta{Name: "pod-1", Namespace: "ns",
+			Labels: map[string]string{"app": "foo", "tier": "web"}},
+		Status: api.PodStatus{Phase: api.PodRunning},
+	}
+	otherPod := api.Pod{
+		ObjectMeta: api.ObjectMeta{Name: "pod-2", Namespace: "ns",
+			Labels: map[string]string{"app": "foo"}},
+		Status: api.PodStatus{Phase: api.PodRunning},
+	}
+
+	channels := &common.ResourceChannels{
+		ReplicaSetList: common.ReplicaSetListChannel{
+			List:  make(chan *extensions.ReplicaSetList, 1),
+			Error: make(chan error, 1),
+		},
+		JobList: common.JobListChannel{
+			List:  make(chan *batch.JobList, 1),
+			Error: make(chan error, 1),
+		},
+		ReplicationControllerList: common.ReplicationControllerListChannel{
+			List:  make(chan *api.ReplicationControllerList, 1),
+			Error: make(chan error, 1),
+		},
+		DaemonSetList: common.DaemonSetListChannel{
+			List:  make(chan *extensions.DaemonSetList, 1),
+			Error: make(chan error, 1),
+		},
+		DeploymentList: common.DeploymentListChannel{
+			List:  make(chan *extensions.DeploymentList, 1),
+			Error: make(chan error, 1),
+		},
+		StatefulSetList: common.StatefulSetListChannel{
+			List:  make(chan *apps.StatefulSetList, 1),
+			Error: make(chan error, 1),
+		},
+		PodList: common.PodListChannel{
+			List:  make(chan *api.PodList, 6),
+			Error: make(chan error, 6),
+		},
+		EventList: common.EventListChannel{
+			List:  make(chan *api.EventList, 7),
+			Error: make(chan error, 7),
+		},
+	}
+	podChannels := &common.ResourceChannels{
+		PodList: common.PodListChannel{
+			List:  make(chan *api.PodList, 1),
+			Error: make(chan error, 1),
+		},
+		EventList: channels.EventList,
+	}
+
+	channels.ReplicaSetList.List <- &extensions.ReplicaSetList{}
+	channels.ReplicaSetList.Error <- nil
+	channels.JobList.List <- &batch.JobList{}
+	channels.JobList.Error <- nil
+	channels.DaemonSetList.List <- &extensions.DaemonSetList{}
+	channels.DaemonSetList.Error <- nil
+	channels.DeploymentList.List <- &extensions.DeploymentList{}
+	channels.DeploymentList.Error <- nil
+	channels.ReplicationControllerList.List <- rcList
+	channels.ReplicationControllerList.Error <- nil
+	channels.StatefulSetList.List <- &apps.StatefulSetList{}
+	channels.StatefulSetList.Error <- nil
+	for i := 0; i < 6; i++ {
+		channels.PodList.List <- &api.PodList{Items: []api.Pod{selectedPod, otherPod}}
+		channels.PodList.Error <- nil
+	}
+	for i := 0; i < 7; i++ {
+		channels.EventList.List <- &api.EventList{}
+		channels.EventList.Error <- nil
+	}
+	podChannels.PodList.List <- &api.PodList{Items: []api.Pod{selectedPod}}
+	podChannels.PodList.Error <- nil
+
+	actual, err := getWorkloadsFromChannels(channels, podChannels, fakeMetricsProvider{},
+		dataselect.NoMetrics)
+	if err != nil {
+		t.Fatalf("getWorkloadsFromChannels() returned unexpected error: %v", err)
+	}
+	rcs := actual.ReplicationControllerList.ReplicationControllers
+	if len(rcs) != 1 || rcs[0].Pods.Running != 2 {
+		t.Errorf("getWorkloadsFromChannels() returned replication controllers %#v, expected "+
+			"one with 2 running pods", rcs)
+	}
+	pods := actual.PodList.Pods
+	if len(pods) != 1 || pods[0].ObjectMeta.Name != "pod-1" {
+		t.Errorf("getWorkloadsFromChannels() returned pods %#v, expected only pod-1", pods)
+	}
+}
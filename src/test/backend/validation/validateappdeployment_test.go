@@ -0,0 +1,243 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kubernetes/dashboard/src/app/backend/resource/deployment"
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/resource"
+	"k8s.io/kubernetes/pkg/apis/extensions"
+	"k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset/fake"
+	"k8s.io/kubernetes/pkg/runtime"
+)
+
+func newDeploymentValidationTestSpec() *deployment.AppDeploymentSpec {
+	cpu := resource.MustParse("200m")
+	pullSecret := "registry"
+	return &deployment.AppDeploymentSpec{
+		Name:           "foo-name",
+		Namespace:      "foo-namespace",
+		ContainerImage: "foo-image",
+		Replicas:       2,
+		CpuRequirement: &cpu,
+		PortMappings: []deployment.PortMapping{
+			{Port: 80, TargetPort: 8080, Protocol: api.ProtocolTCP},
+		},
+		IsExternal:      true,
+		ImagePullSecret: &pullSecret,
+		Volumes: []deployment.VolumeSpec{
+			{Name: "config", Type: deployment.ConfigMapVolume, SourceName: "foo-config"},
+		},
+	}
+}
+
+func newQuota(name string, hard, used api.ResourceList) *api.ResourceQuota {
+	return &api.ResourceQuota{
+		ObjectMeta: api.ObjectMeta{Name: name, Namespace: "foo-namespace"},
+		Spec:       api.ResourceQuotaSpec{Hard: hard},
+		Status:     api.ResourceQuotaStatus{Hard: hard, Used: used},
+	}
+}
+
+func TestValidateAppDeployment(t *testing.T) {
+	meta := api.ObjectMeta{Name: "foo-name", Namespace: "foo-namespace"}
+	pullSecret := &api.Secret{
+		ObjectMeta: api.ObjectMeta{Name: "registry", Namespace: "foo-namespace"},
+		Type:       api.SecretTypeDockerConfigJson,
+	}
+	configMap := &api.ConfigMap{
+		ObjectMeta: api.ObjectMeta{Name: "foo-config", Namespace: "foo-namespace"},
+	}
+
+	cases := []struct {
+		info     string
+		modify   func(spec *deployment.AppDeploymentSpec)
+		objects  []runtime.Object
+		expected []FieldError
+	}{
+		{
+			"valid spec",
+			func(spec *deployment.AppDeploymentSpec) {},
+			[]runtime.Object{pullSecret, configMap, newQuota("compute", api.ResourceList{
+				api.ResourcePods:        resource.MustParse("10"),
+				api.ResourceRequestsCPU: resource.MustParse("1"),
+			}, api.ResourceList{
+				api.ResourcePods:        resource.MustParse("8"),
+				api.ResourceRequestsCPU: resource.MustParse("600m"),
+			})},
+			[]FieldError{},
+		},
+		{
+			"name collisions",
+			func(spec *deployment.AppDeploymentSpec) {},
+			[]runtime.Object{pullSecret, configMap, &extensions.Deployment{ObjectMeta: meta},
+				&api.Service{ObjectMeta: meta}},
+			[]FieldError{{
+				Type:    "FieldValueInvalid",
+				Field:   "name",
+				Message: "Invalid value: \"foo-name\": a deployment with this name already exists",
+			}, {
+				Type:    "FieldValueInvalid",
+				Field:   "name",
+				Message: "Invalid value: \"foo-name\": a service with this name already exists",
+			}},
+		},
+		{
+			"service name is free without port mappings",
+			func(spec *deployment.AppDeploymentSpec) {
+				spec.PortMappings = nil
+			},
+			[]runtime.Object{pullSecret, configMap, &api.Service{ObjectMeta: meta}},
+			[]FieldError{},
+		},
+		{
+			"conflicting port mappings",
+			func(spec *deployment.AppDeploymentSpec) {
+				spec.PortMappings = append(spec.PortMappings, deployment.PortMapping{
+					Port: 80, TargetPort: 9090, Protocol: api.ProtocolTCP})
+			},
+			[]runtime.Object{pullSecret, configMap},
+			[]FieldError{{
+				Type:    "FieldValueDuplicate",
+				Field:   "portMappings[1].port",
+				Message: "Duplicate value: \"80/TCP\"",
+			}},
+		},
+		{
+			"exceeded quotas",
+			func(spec *deployment.AppDeploymentSpec) {},
+			[]runtime.Object{pullSecret, configMap, newQuota("compute", api.ResourceList{
+				api.ResourcePods:        resource.MustParse("10"),
+				api.ResourceRequestsCPU: resource.MustParse("1"),
+			}, api.ResourceList{
+				api.ResourcePods:        resource.MustParse("9"),
+				api.ResourceRequestsCPU: resource.MustParse("800m"),
+			}), newQuota("objects", api.ResourceList{
+				api.ResourceServicesLoadBalancers: resource.MustParse("0"),
+			}, api.ResourceList{})},
+			[]FieldError{{
+				Type:  "FieldValueForbidden",
+				Field: "replicas",
+				Message: "Forbidden: exceeds quota compute, requested: pods=2, used: pods=9, " +
+					"limited: pods=10",
+			}, {
+				Type:  "FieldValueForbidden",
+				Field: "cpuRequirement",
+				Message: "Forbidden: exceeds quota compute, requested: requests.cpu=400m, " +
+					"used: requests.cpu=800m, limited: requests.cpu=1",
+			}, {
+				Type:  "FieldValueForbidden",
+				Field: "isExternal",
+				Message: "Forbidden: exceeds quota objects, requested: " +
+					"services.loadbalancers=1, used: services.loadbalancers=0, " +
+					"limited: services.loadbalancers=0",
+			}},
+		},
+		{
+			"requirements demanded by quotas",
+			func(spec *deployment.AppDeploymentSpec) {},
+			[]runtime.Object{pullSecret, configMap, newQuota("compute", api.ResourceList{
+				api.ResourceRequestsCPU:    resource.MustParse("1"),
+				api.ResourceLimitsMemory:   resource.MustParse("1Gi"),
+				api.ResourceRequestsMemory: resource.MustParse("1Gi"),
+			}, api.ResourceList{})},
+			[]FieldError{{
+				Type:  "FieldValueRequired",
+				Field: "memoryLimit",
+				Message: "Required value: limits.memory must be set, as quotas or limit " +
+					"ranges of the namespace require it",
+			}, {
+				Type:  "FieldValueRequired",
+				Field: "memoryRequirement",
+				Message: "Required value: requests.memory must be set, as quotas or limit " +
+					"ranges of the namespace require it",
+			}},
+		},
+		{
+			"requirements defaulted by limit ranges",
+			func(spec *deployment.AppDeploymentSpec) {},
+			[]runtime.Object{pullSecret, configMap, newQuota("compute", api.ResourceList{
+				api.ResourceLimitsMemory: resource.MustParse("1Gi"),
+			}, api.ResourceList{}), &api.LimitRange{
+				ObjectMeta: api.ObjectMeta{Name: "defaults", Namespace: "foo-namespace"},
+				Spec: api.LimitRangeSpec{Limits: []api.LimitRangeItem{{
+					Type:    api.LimitTypeContainer,
+					Default: api.ResourceList{api.ResourceMemory: resource.MustParse("512Mi")},
+				}}},
+			}},
+			[]FieldError{},
+		},
+		{
+			"quota scoped to best effort pods",
+			func(spec *deployment.AppDeploymentSpec) {},
+			[]runtime.Object{pullSecret, configMap, &api.ResourceQuota{
+				ObjectMeta: api.ObjectMeta{Name: "best-effort", Namespace: "foo-namespace"},
+				Spec: api.ResourceQuotaSpec{
+					Hard:   api.ResourceList{api.ResourcePods: resource.MustParse("0")},
+					Scopes: []api.ResourceQuotaScope{api.ResourceQuotaScopeBestEffort},
+				},
+			}},
+			[]FieldError{},
+		},
+		{
+			"missing references",
+			func(spec *deployment.AppDeploymentSpec) {},
+			nil,
+			[]FieldError{{
+				Type:    "FieldValueNotFound",
+				Field:   "imagePullSecret",
+				Message: "Not found: \"registry\"",
+			}, {
+				Type:    "FieldValueNotFound",
+				Field:   "volumes[0].sourceName",
+				Message: "Not found: \"foo-config\"",
+			}},
+		},
+		{
+			"image pull secret of wrong type",
+			func(spec *deployment.AppDeploymentSpec) {},
+			[]runtime.Object{configMap, &api.Secret{
+				ObjectMeta: api.ObjectMeta{Name: "registry", Namespace: "foo-namespace"},
+				Type:       api.SecretTypeOpaque,
+			}},
+			[]FieldError{{
+				Type:  "FieldValueInvalid",
+				Field: "imagePullSecret",
+				Message: "Invalid value: \"registry\": must be a secret of type " +
+					"kubernetes.io/dockercfg or kubernetes.io/dockerconfigjson, but is Opaque",
+			}},
+		},
+	}
+
+	for _, c := range cases {
+		spec := newDeploymentValidationTestSpec()
+		c.modify(spec)
+		testClient := fake.NewSimpleClientset(c.objects...)
+
+		actual, err := ValidateAppDeployment(spec, testClient)
+		if err != nil {
+			t.Errorf("%s: ValidateAppDeployment() returned error: %s", c.info, err)
+			continue
+		}
+		expected := &AppDeploymentValidity{Valid: len(c.expected) == 0, Errors: c.expected}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%s: ValidateAppDeployment() == \n%#v\nexpected \n%#v", c.info, actual,
+				expected)
+		}
+	}
+}
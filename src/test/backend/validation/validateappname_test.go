@@ -16,6 +16,7 @@ package validation
 
 import (
 	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/apis/extensions"
 	"k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset/fake"
 	"k8s.io/kubernetes/pkg/runtime"
 
@@ -68,6 +69,15 @@ func TestValidateName(t *testing.T) {
 			}},
 			true,
 		},
+		{
+			spec,
+			[]runtime.Object{&extensions.Deployment{
+				ObjectMeta: api.ObjectMeta{
+					Name: "foo-name", Namespace: "foo-namespace",
+				},
+			}},
+			false,
+		},
 	}
 
 	for _, c := range cases {
@@ -0,0 +1,115 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"reflect"
+	"testing"
+
+	kdClient "github.com/kubernetes/dashboard/src/app/backend/client"
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset/fake"
+)
+
+type FakeRegistryClient struct {
+	manifests   map[string]*kdClient.ImageManifest
+	err         error
+	credentials *kdClient.RegistryCredentials
+}
+
+func (c *FakeRegistryClient) GetManifest(reference string,
+	credentials *kdClient.RegistryCredentials) (*kdClient.ImageManifest, error) {
+	c.credentials = credentials
+	if c.err != nil {
+		return nil, c.err
+	}
+	if manifest, ok := c.manifests[reference]; ok {
+		return manifest, nil
+	}
+	return &kdClient.ImageManifest{Exists: false}, nil
+}
+
+func (c *FakeRegistryClient) ListTags(reference string,
+	credentials *kdClient.RegistryCredentials) ([]string, error) {
+	c.credentials = credentials
+	return []string{"1.0", "1.1"}, c.err
+}
+
+func TestValidateImageManifest(t *testing.T) {
+	pullSecret := "registry"
+	secret := &api.Secret{
+		ObjectMeta: api.ObjectMeta{Name: "registry", Namespace: "foo-namespace"},
+		Type:       api.SecretTypeDockercfg,
+		Data: map[string][]byte{api.DockerConfigKey: []byte(
+			`{"private.registry:5000": {"username": "user", "password": "secret"}}`)},
+	}
+	manifests := map[string]*kdClient.ImageManifest{
+		"private.registry:5000/test:1": {Exists: true, Digest: "sha256:ff"},
+	}
+
+	cases := []struct {
+		spec                *ImageLookupSpec
+		err                 error
+		expected            *ImageManifestValidity
+		expectedCredentials *kdClient.RegistryCredentials
+	}{
+		{
+			&ImageLookupSpec{Reference: "private.registry:5000/test:1",
+				Namespace: "foo-namespace", ImagePullSecret: &pullSecret},
+			nil,
+			&ImageManifestValidity{Valid: true, Digest: "sha256:ff"},
+			&kdClient.RegistryCredentials{Username: "user", Password: "secret"},
+		},
+		{
+			&ImageLookupSpec{Reference: "private.registry:5000/test:2"},
+			nil,
+			&ImageManifestValidity{Valid: false, Reason: "manifest of " +
+				"private.registry:5000/test:2 was not found in the registry"},
+			nil,
+		},
+		{
+			&ImageLookupSpec{Reference: "private.registry:5000/test:1"},
+			kdClient.ErrRegistryUnauthorized,
+			&ImageManifestValidity{Valid: false,
+				Reason: kdClient.ErrRegistryUnauthorized.Error()},
+			nil,
+		},
+		{
+			&ImageLookupSpec{Reference: "Test"},
+			nil,
+			&ImageManifestValidity{Valid: false,
+				Reason: "invalid reference format"},
+			nil,
+		},
+	}
+
+	for _, c := range cases {
+		registryClient := &FakeRegistryClient{manifests: manifests, err: c.err}
+		actual, err := ValidateImageManifest(c.spec, fake.NewSimpleClientset(secret),
+			registryClient)
+		if err != nil {
+			t.Errorf("ValidateImageManifest(%#v) returned error: %s", c.spec, err)
+			continue
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("ValidateImageManifest(%#v) == %#v, expected %#v", c.spec, actual,
+				c.expected)
+		}
+		if !reflect.DeepEqual(registryClient.credentials, c.expectedCredentials) {
+			t.Errorf("ValidateImageManifest(%#v) used credentials %#v, expected %#v", c.spec,
+				registryClient.credentials, c.expectedCredentials)
+		}
+	}
+}
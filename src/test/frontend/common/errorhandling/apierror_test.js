@@ -0,0 +1,33 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import {getErrorMessage} from 'common/errorhandling/apierror';
+
+describe('API error', () => {
+  it('should return message of backend API errors', () => {
+    let err = {data: {code: 403, reason: 'Forbidden', message: 'pods is forbidden'}};
+
+    expect(getErrorMessage(err)).toBe('pods is forbidden');
+  });
+
+  it('should return plain text error data', () => {
+    expect(getErrorMessage({data: 'something is broken'})).toBe('something is broken');
+  });
+
+  it('should return empty message when there is no error data', () => {
+    expect(getErrorMessage(null)).toBe('');
+    expect(getErrorMessage({data: ''})).toBe('');
+    expect(getErrorMessage({data: {code: 500}})).toBe('');
+  });
+});
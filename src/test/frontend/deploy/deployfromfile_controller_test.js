@@ -92,6 +92,27 @@ describe('DeployFromFile controller', () => {
     expect(ctrl.kdHistoryService_.back).toHaveBeenCalled();
   });
 
+  it('should open error dialog and not redirect the page when deployment is rolled back', () => {
+    spyOn(ctrl.errorDialog_, 'open');
+    spyOn(ctrl.kdHistoryService_, 'back');
+    let response = {
+      name: 'foo-name',
+      content: 'foo-content',
+      error: 'service already exists',
+      rolledBack: true,
+    };
+    httpBackend.expectPOST('api/v1/appdeploymentfromfile').respond(422, response);
+    mockResource.and.callFake(resource);
+    // when
+    ctrl.deploy();
+    httpBackend.flush();
+
+    // then
+    expect(ctrl.errorDialog_.open)
+        .toHaveBeenCalledWith(ctrl.i18n.MSG_DEPLOY_ROLLED_BACK_ERROR, 'service already exists');
+    expect(ctrl.kdHistoryService_.back).not.toHaveBeenCalled();
+  });
+
   it('should redirect the page and not open error dialog', () => {
     spyOn(ctrl.errorDialog_, 'open');
     spyOn(ctrl.kdHistoryService_, 'back');
@@ -29,11 +29,21 @@ describe('Secret Detail controller', () => {
     expect(ctrl.secretDetail).toBe(data);
   }));
 
-  it('should format base64', angular.mock.inject(($controller) => {
-    let data = {};
-    /** @type {!SecretDetailController} */
-    let ctrl = $controller(SecretDetailController, {secretDetail: data});
+  it('should fetch secret value when it is shown for the first time',
+     angular.mock.inject(($controller, $httpBackend) => {
+       let data = {objectMeta: {namespace: 'foo', name: 'bar'}, keys: [{name: 'key', size: 5}]};
+       /** @type {!SecretDetailController} */
+       let ctrl = $controller(SecretDetailController, {secretDetail: data});
+       $httpBackend.expectGET('api/v1/secret/foo/bar/key/key')
+           .respond({key: 'key', value: 'value', base64: false});
 
-    expect(ctrl.formatDataValue('SGVsbG8gd29ybGQ=')).toBe('Hello world');
-  }));
+       ctrl.toggleValue('key');
+       $httpBackend.flush();
+       expect(ctrl.values['key']).toBe('value');
+       expect(ctrl.revealed['key']).toBe(true);
+
+       ctrl.toggleValue('key');
+       expect(ctrl.revealed['key']).toBe(false);
+       $httpBackend.verifyNoOutstandingRequest();
+     }));
 });